@@ -4,25 +4,73 @@ import (
 	"context"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/ports"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/service"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
 	commonPorts "github.com/elizabeth-dev/ACME_Test/internal/pkg/ports"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/server"
 	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+// closeTimeout is how long the dependencies have to disconnect once the server has stopped.
+const closeTimeout = 10 * time.Second
+
 func main() {
 	ctx := context.Background()
 
-	app, dependencies := service.NewApplication(ctx)
+	keySet := service.SetupKeySet()
+	verifier := service.SetupTokenVerifier(keySet)
+	app, dependencies, closeApp := service.NewApplication(ctx, keySet)
+
+	srv := ports.NewGrpcServer(app)
+	prober := service.NewHealthProber(dependencies)
+	healthSrv := commonPorts.NewHealthGrpcServer(dependencies, prober)
+
+	signalCtx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	// Health must report NOT_SERVING before the server starts refusing calls, so the server gets its own context.
+	serverCtx, stopServer := context.WithCancel(ctx)
+	drainDelay := service.ShutdownDrainDelay()
+	go prober.Run(serverCtx)
+	go func() {
+		select {
+		case <-signalCtx.Done():
+		case <-serverCtx.Done():
+			// The server failed on its own, so there's nothing left to shut down.
+			return
+		}
+		log.Printf("Shutting down")
 
-	server.RunGRPCServer(
-		func(server *grpc.Server) {
-			srv := ports.NewGrpcServer(app)
-			healthSrv := commonPorts.NewHealthGrpcServer(dependencies)
+		healthSrv.Shutdown()
+		// Load balancers only stop routing calls here once they've seen NOT_SERVING, which takes a few probes.
+		time.Sleep(drainDelay)
+		stopServer()
+	}()
 
+	err := server.RunGRPCServer(
+		serverCtx, func(server *grpc.Server) {
 			apiV1.RegisterUserServiceServer(server, &srv)
 			grpc_health_v1.RegisterHealthServer(server, &healthSrv)
-		},
+		}, auth.NewInterceptor(verifier, ports.AccessPolicies), errors.NewInterceptor(errors.DefaultRegistry),
 	)
+	stopServer()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, closeTimeout)
+	defer cancel()
+
+	if err := closeApp(closeCtx); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Shutdown complete")
 }
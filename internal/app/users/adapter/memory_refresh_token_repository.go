@@ -0,0 +1,118 @@
+package adapter
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"sync"
+	"time"
+)
+
+/*
+MemoryRefreshTokenRepository keeps the refresh tokens in memory, so the service can run without MongoDB.
+
+It's safe for concurrent use, and revoking a token checks and updates it at
+once, as RefreshTokenRepository does.
+*/
+type MemoryRefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*RefreshTokenModel
+}
+
+const MemoryRefreshTokenRepoTag = "MemoryRefreshTokenRepository"
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{tokens: map[string]*RefreshTokenModel{}}
+}
+
+func (r *MemoryRefreshTokenRepository) AddRefreshToken(ctx context.Context, newToken *token.RefreshToken) error {
+	if err := ctx.Err(); err != nil {
+		return &errors.Unknown{Tag: MemoryRefreshTokenRepoTag, Cause: err}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[newToken.Id()] = &RefreshTokenModel{
+		Id:        newToken.Id(),
+		UserId:    newToken.UserId(),
+		FamilyId:  newToken.FamilyId(),
+		CreatedAt: newToken.CreatedAt(),
+		ExpiresAt: newToken.ExpiresAt(),
+		RevokedAt: newToken.RevokedAt(),
+	}
+
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) GetRefreshTokenById(
+	ctx context.Context, tokenId string,
+) (*token.RefreshToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &errors.Unknown{Tag: MemoryRefreshTokenRepoTag, Cause: err}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokenModel, ok := r.tokens[tokenId]
+
+	if !ok {
+		return nil, &token.NotFoundError{Id: tokenId}
+	}
+
+	return token.UnmarshalRefreshTokenFromDB(
+		tokenModel.Id,
+		tokenModel.UserId,
+		tokenModel.FamilyId,
+		tokenModel.CreatedAt,
+		tokenModel.ExpiresAt,
+		tokenModel.RevokedAt,
+	), nil
+}
+
+/*
+RevokeRefreshToken marks a refresh token as revoked, as long as it wasn't already.
+*/
+func (r *MemoryRefreshTokenRepository) RevokeRefreshToken(
+	ctx context.Context, tokenId string, revokedAt time.Time,
+) error {
+	if err := ctx.Err(); err != nil {
+		return &errors.Unknown{Tag: MemoryRefreshTokenRepoTag, Cause: err}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokenModel, ok := r.tokens[tokenId]
+
+	if !ok || !tokenModel.RevokedAt.IsZero() {
+		return &token.NotFoundError{Id: tokenId}
+	}
+
+	tokenModel.RevokedAt = revokedAt
+
+	return nil
+}
+
+/*
+RevokeRefreshTokenFamily revokes every refresh token belonging to the given family that's still active.
+*/
+func (r *MemoryRefreshTokenRepository) RevokeRefreshTokenFamily(
+	ctx context.Context, familyId string, revokedAt time.Time,
+) error {
+	if err := ctx.Err(); err != nil {
+		return &errors.Unknown{Tag: MemoryRefreshTokenRepoTag, Cause: err}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tokenModel := range r.tokens {
+		if tokenModel.FamilyId == familyId && tokenModel.RevokedAt.IsZero() {
+			tokenModel.RevokedAt = revokedAt
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package adapter
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryRefreshTokenRepository(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"call add and get refresh token by id":      testMemoryGetRefreshTokenById,
+		"call get unknown refresh token by id":      testMemoryGetUnknownRefreshTokenById,
+		"call revoke refresh token":                 testMemoryRevokeRefreshToken,
+		"call revoke refresh token already revoked": testMemoryRevokeRefreshTokenAlreadyRevoked,
+		"call revoke refresh token family":          testMemoryRevokeRefreshTokenFamily,
+		"call get refresh token with cancelled ctx": testMemoryGetRefreshTokenWithCancelledContext,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testMemoryGetRefreshTokenById(t *testing.T) {
+	repo := NewMemoryRefreshTokenRepository()
+
+	assert.NoError(t, repo.AddRefreshToken(context.Background(), &token.Token1))
+
+	out, err := repo.GetRefreshTokenById(context.Background(), token.Token1.Id())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &token.Token1, out)
+}
+
+func testMemoryGetUnknownRefreshTokenById(t *testing.T) {
+	repo := NewMemoryRefreshTokenRepository()
+
+	out, err := repo.GetRefreshTokenById(context.Background(), token.Token1.Id())
+
+	assert.Nil(t, out)
+	assert.Equal(t, &token.NotFoundError{Id: token.Token1.Id()}, err)
+}
+
+func testMemoryRevokeRefreshToken(t *testing.T) {
+	repo := NewMemoryRefreshTokenRepository()
+	revokedAt := token.Token1.CreatedAt().Add(time.Minute)
+
+	assert.NoError(t, repo.AddRefreshToken(context.Background(), &token.Token1))
+	assert.NoError(t, repo.RevokeRefreshToken(context.Background(), token.Token1.Id(), revokedAt))
+
+	out, err := repo.GetRefreshTokenById(context.Background(), token.Token1.Id())
+
+	assert.NoError(t, err)
+	assert.Equal(t, revokedAt, out.RevokedAt())
+}
+
+func testMemoryRevokeRefreshTokenAlreadyRevoked(t *testing.T) {
+	repo := NewMemoryRefreshTokenRepository()
+	revokedAt := token.Token1.CreatedAt().Add(time.Minute)
+
+	assert.NoError(t, repo.AddRefreshToken(context.Background(), &token.Token1))
+	assert.NoError(t, repo.RevokeRefreshToken(context.Background(), token.Token1.Id(), revokedAt))
+
+	err := repo.RevokeRefreshToken(context.Background(), token.Token1.Id(), revokedAt.Add(time.Minute))
+
+	assert.Equal(t, &token.NotFoundError{Id: token.Token1.Id()}, err)
+}
+
+func testMemoryRevokeRefreshTokenFamily(t *testing.T) {
+	repo := NewMemoryRefreshTokenRepository()
+	revokedAt := token.Token1.CreatedAt().Add(time.Minute)
+	sibling := token.UnmarshalRefreshTokenFromDB(
+		"sibling", token.Token1.UserId(), token.Token1.FamilyId(), token.Token1.CreatedAt(), token.Token1.ExpiresAt(),
+		time.Time{},
+	)
+	stranger := token.UnmarshalRefreshTokenFromDB(
+		"stranger", token.Token1.UserId(), "other", token.Token1.CreatedAt(), token.Token1.ExpiresAt(), time.Time{},
+	)
+
+	for _, refreshToken := range []*token.RefreshToken{&token.Token1, sibling, stranger} {
+		assert.NoError(t, repo.AddRefreshToken(context.Background(), refreshToken))
+	}
+
+	assert.NoError(t, repo.RevokeRefreshTokenFamily(context.Background(), token.Token1.FamilyId(), revokedAt))
+
+	for id, expected := range map[string]time.Time{
+		token.Token1.Id(): revokedAt,
+		"sibling":         revokedAt,
+		"stranger":        {},
+	} {
+		out, err := repo.GetRefreshTokenById(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, out.RevokedAt(), id)
+	}
+}
+
+func testMemoryGetRefreshTokenWithCancelledContext(t *testing.T) {
+	repo := NewMemoryRefreshTokenRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out, err := repo.GetRefreshTokenById(ctx, token.Token1.Id())
+
+	assert.Nil(t, out)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,371 @@
+package adapter
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/memory_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+MemoryUserRepository keeps the users in memory, for tests and local development without MongoDB.
+
+It behaves as UserRepository does, which the conformance tests make sure of:
+users keep their insertion order, nicknames and emails are unique regardless of
+their case, and times keep only milliseconds, as they would in MongoDB. It's
+safe for concurrent use.
+*/
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users []*UserModel
+}
+
+const MemoryUserRepoTag = "MemoryUserRepository"
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{}
+}
+
+/*
+AddUser stores a copy of the user entity.
+*/
+func (r *MemoryUserRepository) AddUser(ctx context.Context, newUser *user.User) error {
+	if err := ctx.Err(); err != nil {
+		return &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":  MemoryUserRepoTag,
+			"user": newUser,
+		},
+	).Debug("Adding user")
+	userModel := marshalMemoryUser(newUser)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkUniqueness(userModel); err != nil {
+		return err
+	}
+
+	r.users = append(r.users, userModel)
+
+	return nil
+}
+
+/*
+AddUsers stores a copy of every user entity that doesn't collide with a stored one, or an earlier one of the batch.
+*/
+func (r *MemoryUserRepository) AddUsers(ctx context.Context, newUsers []*user.User) ([]error, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":   MemoryUserRepoTag,
+			"users": len(newUsers),
+		},
+	).Debug("Adding users")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	errs := make([]error, len(newUsers))
+
+	for i, newUser := range newUsers {
+		userModel := marshalMemoryUser(newUser)
+
+		if err := r.checkUniqueness(userModel); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		r.users = append(r.users, userModel)
+	}
+
+	return errs, nil
+}
+
+func (r *MemoryUserRepository) GetUserById(
+	ctx context.Context, userId string, projection []string,
+) (*user.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index := r.indexOf(userId)
+
+	if index < 0 {
+		return nil, &user.NotFoundError{Id: userId}
+	}
+
+	return unmarshalUser(r.users[index].project(projection)), nil
+}
+
+/*
+GetUsersByIds returns copies of the users with any of the given ids, in insertion order.
+*/
+func (r *MemoryUserRepository) GetUsersByIds(
+	ctx context.Context, userIds []string, projection []string,
+) ([]*user.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	requested := map[string]bool{}
+	for _, userId := range userIds {
+		requested[userId] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []*user.User
+	for _, userModel := range r.users {
+		if requested[userModel.Id] {
+			users = append(users, unmarshalUser(userModel.project(projection)))
+		}
+	}
+
+	return users, nil
+}
+
+/*
+GetUserByNicknameOrEmail retrieves the first user whose nickname or email matches the given value, regardless of its
+case.
+*/
+func (r *MemoryUserRepository) GetUserByNicknameOrEmail(
+	ctx context.Context, nicknameOrEmail string,
+) (*user.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, userModel := range r.users {
+		if strings.EqualFold(userModel.Nickname, nicknameOrEmail) ||
+			strings.EqualFold(userModel.Email, nicknameOrEmail) {
+			return unmarshalUser(userModel), nil
+		}
+	}
+
+	return nil, &user.NotFoundError{Id: nicknameOrEmail}
+}
+
+/*
+GetUsers retrieves the users matching the filter, with the same semantics as the MongoDB query.
+
+They're all copied out of the store before iterating, so no lock is held while
+the caller consumes them.
+*/
+func (r *MemoryUserRepository) GetUsers(
+	ctx context.Context, filter query_utils.FilterExpression, sorts []query_utils.Sort,
+	pagination query_utils.Pagination, projection []string,
+) (user.UserIterator, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	type matchedUser struct {
+		model    *UserModel
+		document memory_utils.Document
+	}
+
+	r.mu.RLock()
+	var matched []matchedUser
+
+	for _, userModel := range r.users {
+		document := userModel.document()
+
+		if memory_utils.MatchFilterExpression(document, filter) {
+			matched = append(matched, matchedUser{userModel, document})
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(
+		matched, func(i, j int) bool {
+			return memory_utils.CompareDocuments(matched[i].document, matched[j].document, sorts) < 0
+		},
+	)
+
+	start, end, err := memory_utils.Paginate(len(matched), pagination)
+
+	if err != nil {
+		return nil, &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	var users []*user.User
+	for _, match := range matched[start:end] {
+		users = append(users, unmarshalUser(match.model.project(projection)))
+	}
+
+	return user.NewSliceUserIterator(users), nil
+}
+
+/*
+CountUsers counts the users matching the filter, as GetUsers does without pagination.
+*/
+func (r *MemoryUserRepository) CountUsers(ctx context.Context, filter query_utils.FilterExpression) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+
+	for _, userModel := range r.users {
+		if memory_utils.MatchFilterExpression(userModel.document(), filter) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+/*
+UpdateUser fully replaces the stored copy of the user entity, as long as it's still at its version, bumping it.
+*/
+func (r *MemoryUserRepository) UpdateUser(ctx context.Context, userToUpdate *user.User) error {
+	if err := ctx.Err(); err != nil {
+		return &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":  MemoryUserRepoTag,
+			"user": userToUpdate,
+		},
+	).Debug("Updating user")
+	userModel := marshalMemoryUser(userToUpdate)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := r.indexOf(userModel.Id)
+
+	if index < 0 {
+		return &user.NotFoundError{Id: userModel.Id}
+	}
+
+	if r.users[index].Version != userModel.Version {
+		return &user.VersionConflictError{Id: userModel.Id, Version: userModel.Version}
+	}
+
+	if err := r.checkUniqueness(userModel); err != nil {
+		return err
+	}
+
+	userModel.Version++
+	r.users[index] = userModel
+
+	return nil
+}
+
+/*
+RemoveUser removes the user entity given its id.
+*/
+func (r *MemoryUserRepository) RemoveUser(ctx context.Context, userId string) error {
+	if err := ctx.Err(); err != nil {
+		return &errors.Unknown{Tag: MemoryUserRepoTag, Cause: err}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := r.indexOf(userId)
+
+	if index < 0 {
+		return &user.NotFoundError{Id: userId}
+	}
+
+	r.users = append(r.users[:index], r.users[index+1:]...)
+
+	return nil
+}
+
+/*
+indexOf returns the position of the first user with the given id, or -1 if there's none. It must be called with the
+lock held.
+*/
+func (r *MemoryUserRepository) indexOf(userId string) int {
+	for i, userModel := range r.users {
+		if userModel.Id == userId {
+			return i
+		}
+	}
+
+	return -1
+}
+
+/*
+checkUniqueness mimics the unique indexes of UserRepository, ignoring the user being checked. It must be called with
+the lock held.
+
+As in MongoDB, the nickname is checked before the email.
+*/
+func (r *MemoryUserRepository) checkUniqueness(userModel *UserModel) error {
+	for _, stored := range r.users {
+		if stored.Id != userModel.Id && strings.EqualFold(stored.Nickname, userModel.Nickname) {
+			return &user.AlreadyExistsError{Field: "nickname", Value: userModel.Nickname}
+		}
+	}
+
+	for _, stored := range r.users {
+		if stored.Id != userModel.Id && strings.EqualFold(stored.Email, userModel.Email) {
+			return &user.AlreadyExistsError{Field: "email", Value: userModel.Email}
+		}
+	}
+
+	return nil
+}
+
+/*
+document exposes the user model as a memory_utils.Document, under the same field names it has in MongoDB.
+*/
+func (m *UserModel) document() memory_utils.Document {
+	roles := make([]interface{}, len(m.Roles))
+	for i, role := range m.Roles {
+		roles[i] = role
+	}
+
+	return memory_utils.Document{
+		"id":         m.Id,
+		"first_name": m.FirstName,
+		"last_name":  m.LastName,
+		"nickname":   m.Nickname,
+		"password":   m.Password,
+		"email":      m.Email,
+		"country":    m.Country,
+		"roles":      roles,
+		"created_at": m.CreatedAt,
+		"updated_at": m.UpdatedAt,
+	}
+}
+
+/*
+marshalMemoryUser converts a domain user entity into its model, rounding its times as MongoDB would store them.
+*/
+func marshalMemoryUser(user *user.User) *UserModel {
+	userModel := marshalUser(user)
+
+	userModel.CreatedAt = userModel.CreatedAt.Truncate(time.Millisecond).UTC()
+	userModel.UpdatedAt = userModel.UpdatedAt.Truncate(time.Millisecond).UTC()
+
+	return userModel
+}
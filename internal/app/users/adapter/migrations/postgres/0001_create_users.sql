@@ -0,0 +1,26 @@
+-- Nicknames and emails are unique regardless of their case, as in MongoDB. Times are unix milliseconds, and texts use
+-- the "C" collation, so they're compared byte by byte, as in MongoDB.
+CREATE TABLE users
+(
+    seq        BIGSERIAL PRIMARY KEY,
+    id         TEXT COLLATE "C" NOT NULL UNIQUE,
+    first_name TEXT COLLATE "C" NOT NULL,
+    last_name  TEXT COLLATE "C" NOT NULL,
+    nickname   TEXT COLLATE "C" NOT NULL,
+    password   TEXT COLLATE "C" NOT NULL,
+    email      TEXT COLLATE "C" NOT NULL,
+    country    TEXT COLLATE "C" NOT NULL,
+    created_at BIGINT           NOT NULL,
+    updated_at BIGINT           NOT NULL
+);
+
+CREATE UNIQUE INDEX nickname_unique ON users (lower(nickname));
+CREATE UNIQUE INDEX email_unique ON users (lower(email));
+
+CREATE TABLE user_roles
+(
+    user_id  TEXT COLLATE "C" NOT NULL REFERENCES users (id),
+    position INTEGER          NOT NULL,
+    role     TEXT COLLATE "C" NOT NULL,
+    PRIMARY KEY (user_id, position)
+);
@@ -0,0 +1,25 @@
+-- Nicknames and emails are unique regardless of their case, as in MongoDB. Times are unix milliseconds.
+CREATE TABLE users
+(
+    seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+    id         TEXT    NOT NULL UNIQUE,
+    first_name TEXT    NOT NULL,
+    last_name  TEXT    NOT NULL,
+    nickname   TEXT    NOT NULL,
+    password   TEXT    NOT NULL,
+    email      TEXT    NOT NULL,
+    country    TEXT    NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX nickname_unique ON users (lower(nickname));
+CREATE UNIQUE INDEX email_unique ON users (lower(email));
+
+CREATE TABLE user_roles
+(
+    user_id  TEXT    NOT NULL REFERENCES users (id),
+    position INTEGER NOT NULL,
+    role     TEXT    NOT NULL,
+    PRIMARY KEY (user_id, position)
+);
@@ -0,0 +1,12 @@
+-- A null revoked_at means the token hasn't been revoked.
+CREATE TABLE refresh_tokens
+(
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT    NOT NULL,
+    family_id  TEXT    NOT NULL,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    revoked_at INTEGER
+);
+
+CREATE INDEX refresh_tokens_family ON refresh_tokens (family_id);
@@ -0,0 +1,2 @@
+-- Users stored before versions existed start at version 0, as in MongoDB.
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
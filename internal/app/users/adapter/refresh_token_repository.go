@@ -0,0 +1,176 @@
+package adapter
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/helper/mongo_helper"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"time"
+)
+
+/*
+RefreshTokenModel holds the database representation of a refresh token.
+
+A zero RevokedAt means the token hasn't been revoked.
+*/
+type RefreshTokenModel struct {
+	Id        string    `bson:"id"`
+	UserId    string    `bson:"user_id"`
+	FamilyId  string    `bson:"family_id"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	RevokedAt time.Time `bson:"revoked_at"`
+}
+
+type RefreshTokenRepository struct {
+	col mongo_helper.Collection
+}
+
+const RefreshTokenRepoTag = "RefreshTokenRepository"
+
+func NewRefreshTokenRepository(dbClient mongo_helper.Database) RefreshTokenRepository {
+	if dbClient == nil {
+		log.Panicf("[%s] missing dbClient", RefreshTokenRepoTag)
+	}
+
+	return RefreshTokenRepository{col: dbClient.Collection("refresh_token")}
+}
+
+/*
+AddRefreshToken inserts a whole refresh token into the database.
+*/
+func (r *RefreshTokenRepository) AddRefreshToken(ctx context.Context, newToken *token.RefreshToken) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":      RefreshTokenRepoTag,
+			"userId":   newToken.UserId(),
+			"familyId": newToken.FamilyId(),
+		},
+	).Debug("Adding refresh token")
+	tokenModel := r.marshalRefreshToken(newToken)
+
+	if _, err := r.col.InsertOne(ctx, tokenModel); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":      RefreshTokenRepoTag,
+				"userId":   newToken.UserId(),
+				"familyId": newToken.FamilyId(),
+			},
+		).WithError(err).Error("Error inserting refresh token")
+
+		return &errors.Unknown{Tag: RefreshTokenRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetRefreshTokenById(ctx context.Context, tokenId string) (*token.RefreshToken, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag": RefreshTokenRepoTag,
+		},
+	).Debug("Getting refresh token by id")
+	var tokenModel RefreshTokenModel
+
+	if err := r.col.FindOne(ctx, bson.M{"id": tokenId}).Decode(&tokenModel); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &token.NotFoundError{Id: tokenId}
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": RefreshTokenRepoTag,
+			},
+		).WithError(err).Error("Error getting refresh token by id")
+
+		return nil, &errors.Unknown{Tag: RefreshTokenRepoTag, Cause: err}
+	}
+
+	return token.UnmarshalRefreshTokenFromDB(
+		tokenModel.Id,
+		tokenModel.UserId,
+		tokenModel.FamilyId,
+		tokenModel.CreatedAt,
+		tokenModel.ExpiresAt,
+		tokenModel.RevokedAt,
+	), nil
+}
+
+/*
+RevokeRefreshToken marks a refresh token as revoked, as long as it wasn't already.
+
+The check and the update happen in a single query, so when two requests try to
+rotate the same token at once, only one of them succeeds.
+*/
+func (r *RefreshTokenRepository) RevokeRefreshToken(ctx context.Context, tokenId string, revokedAt time.Time) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag": RefreshTokenRepoTag,
+		},
+	).Debug("Revoking refresh token")
+
+	filter := bson.M{"id": tokenId, "revoked_at": time.Time{}}
+	res, err := r.col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revoked_at": revokedAt}})
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": RefreshTokenRepoTag,
+			},
+		).WithError(err).Error("Error revoking refresh token")
+
+		return &errors.Unknown{Tag: RefreshTokenRepoTag, Cause: err}
+	}
+
+	if res.MatchedCount == 0 {
+		return &token.NotFoundError{Id: tokenId}
+	}
+
+	return nil
+}
+
+/*
+RevokeRefreshTokenFamily revokes every refresh token belonging to the given family that's still active.
+*/
+func (r *RefreshTokenRepository) RevokeRefreshTokenFamily(
+	ctx context.Context, familyId string, revokedAt time.Time,
+) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":      RefreshTokenRepoTag,
+			"familyId": familyId,
+		},
+	).Debug("Revoking refresh token family")
+
+	filter := bson.M{"family_id": familyId, "revoked_at": time.Time{}}
+	if _, err := r.col.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked_at": revokedAt}}); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":      RefreshTokenRepoTag,
+				"familyId": familyId,
+			},
+		).WithError(err).Error("Error revoking refresh token family")
+
+		return &errors.Unknown{Tag: RefreshTokenRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+/*
+marshalRefreshToken converts a domain refresh token into its database model.
+*/
+func (r *RefreshTokenRepository) marshalRefreshToken(refreshToken *token.RefreshToken) *RefreshTokenModel {
+	return &RefreshTokenModel{
+		Id:        refreshToken.Id(),
+		UserId:    refreshToken.UserId(),
+		FamilyId:  refreshToken.FamilyId(),
+		CreatedAt: refreshToken.CreatedAt(),
+		ExpiresAt: refreshToken.ExpiresAt(),
+		RevokedAt: refreshToken.RevokedAt(),
+	}
+}
@@ -0,0 +1,290 @@
+package adapter
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	mocks2 "github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"testing"
+	"time"
+)
+
+var marshalledToken = RefreshTokenModel{
+	Id:        token.Token1.Id(),
+	UserId:    token.Token1.UserId(),
+	FamilyId:  token.Token1.FamilyId(),
+	CreatedAt: token.Token1.CreatedAt(),
+	ExpiresAt: token.Token1.ExpiresAt(),
+	RevokedAt: token.Token1.RevokedAt(),
+}
+
+func TestRefreshTokenRepository(t *testing.T) {
+	t.Parallel()
+
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"refresh token repository": {
+			"initialize refresh token repository":                testNewRefreshTokenRepository,
+			"initialize refresh token repository with no client": testNewRefreshTokenRepositoryWithNoClient,
+		},
+		"add refresh token": {
+			"call add refresh token":               testAddRefreshToken,
+			"call add refresh token with db error": testAddRefreshTokenWithDbError,
+		},
+		"get refresh token by id": {
+			"call get refresh token by id":                   testGetRefreshTokenById,
+			"call get refresh token by id with decode error": testGetRefreshTokenByIdWithDecodeError,
+			"call get refresh token by id with no result":    testGetRefreshTokenByIdWithEmptyResponse,
+		},
+		"revoke refresh token": {
+			"call revoke refresh token":                   testRevokeRefreshToken,
+			"call revoke refresh token already revoked":   testRevokeRefreshTokenAlreadyRevoked,
+			"call revoke refresh token with db error":     testRevokeRefreshTokenWithDbError,
+			"call revoke refresh token family":            testRevokeRefreshTokenFamily,
+			"call revoke refresh token family with error": testRevokeRefreshTokenFamilyWithDbError,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							t.Parallel()
+
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+}
+
+func testNewRefreshTokenRepository(t *testing.T) {
+	mockDb := new(mocks2.Database)
+	mockCol := new(mocks2.Collection)
+
+	mockDb.On("Collection", "refresh_token").Return(mockCol)
+
+	out := NewRefreshTokenRepository(mockDb)
+
+	assert.NotNil(t, out)
+	assert.Equal(t, mockCol, out.col)
+}
+
+func testNewRefreshTokenRepositoryWithNoClient(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[RefreshTokenRepository] missing dbClient", func() {
+			NewRefreshTokenRepository(nil)
+		},
+	)
+}
+
+func testAddRefreshToken(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	mockCollection.On("InsertOne", ctx, &marshalledToken).Return(nil, nil)
+
+	err := repo.AddRefreshToken(ctx, &token.Token1)
+
+	mockCollection.AssertNumberOfCalls(t, "InsertOne", 1)
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+}
+
+func testAddRefreshTokenWithDbError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	dbError := errors.New("db error")
+	mockCollection.On("InsertOne", ctx, &marshalledToken).Return(nil, dbError)
+
+	err := repo.AddRefreshToken(ctx, &token.Token1)
+
+	mockCollection.AssertNumberOfCalls(t, "InsertOne", 1)
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &pkgErrors.Unknown{Tag: RefreshTokenRepoTag, Cause: dbError}, err)
+}
+
+func testGetRefreshTokenById(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockSingleResult := new(mocks2.SingleResult)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+	id := token.Token1.Id()
+
+	mockCollection.On("FindOne", ctx, bson.M{"id": id}).Return(mockSingleResult, nil)
+	mockSingleResult.On("Decode", &RefreshTokenModel{}).Run(
+		func(args mock.Arguments) {
+			*args.Get(0).(*RefreshTokenModel) = marshalledToken
+		},
+	).Return(nil)
+
+	out, err := repo.GetRefreshTokenById(ctx, id)
+
+	mockSingleResult.AssertNumberOfCalls(t, "Decode", 1)
+	mockCollection.AssertNumberOfCalls(t, "FindOne", 1)
+	mockSingleResult.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &token.Token1, out)
+}
+
+func testGetRefreshTokenByIdWithDecodeError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockSingleResult := new(mocks2.SingleResult)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+	id := token.Token1.Id()
+
+	decodeErr := errors.New("decode error")
+	mockCollection.On("FindOne", ctx, bson.M{"id": id}).Return(mockSingleResult, nil)
+	mockSingleResult.On("Decode", &RefreshTokenModel{}).Return(decodeErr)
+
+	out, err := repo.GetRefreshTokenById(ctx, id)
+
+	mockSingleResult.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &pkgErrors.Unknown{Tag: RefreshTokenRepoTag, Cause: decodeErr}, err)
+	assert.Nil(t, out)
+}
+
+func testGetRefreshTokenByIdWithEmptyResponse(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockSingleResult := new(mocks2.SingleResult)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+	id := token.Token1.Id()
+
+	mockCollection.On("FindOne", ctx, bson.M{"id": id}).Return(mockSingleResult, nil)
+	mockSingleResult.On("Decode", &RefreshTokenModel{}).Return(mongo.ErrNoDocuments)
+
+	out, err := repo.GetRefreshTokenById(ctx, id)
+
+	mockSingleResult.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &token.NotFoundError{Id: id}, err)
+	assert.Nil(t, out)
+}
+
+func testRevokeRefreshToken(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+	id := token.Token1.Id()
+	now := time.Now()
+
+	mockCollection.On(
+		"UpdateOne", ctx, bson.M{"id": id, "revoked_at": time.Time{}}, bson.M{"$set": bson.M{"revoked_at": now}},
+	).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := repo.RevokeRefreshToken(ctx, id, now)
+
+	mockCollection.AssertNumberOfCalls(t, "UpdateOne", 1)
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+}
+
+func testRevokeRefreshTokenAlreadyRevoked(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+	id := token.Token1.Id()
+	now := time.Now()
+
+	mockCollection.On(
+		"UpdateOne", ctx, bson.M{"id": id, "revoked_at": time.Time{}}, bson.M{"$set": bson.M{"revoked_at": now}},
+	).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+
+	err := repo.RevokeRefreshToken(ctx, id, now)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &token.NotFoundError{Id: id}, err)
+}
+
+func testRevokeRefreshTokenWithDbError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+	id := token.Token1.Id()
+	now := time.Now()
+
+	dbError := errors.New("db error")
+	mockCollection.On(
+		"UpdateOne", ctx, bson.M{"id": id, "revoked_at": time.Time{}}, bson.M{"$set": bson.M{"revoked_at": now}},
+	).Return(nil, dbError)
+
+	err := repo.RevokeRefreshToken(ctx, id, now)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &pkgErrors.Unknown{Tag: RefreshTokenRepoTag, Cause: dbError}, err)
+}
+
+func testRevokeRefreshTokenFamily(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+	familyId := token.Token1.FamilyId()
+	now := time.Now()
+
+	mockCollection.On(
+		"UpdateMany", ctx, bson.M{"family_id": familyId, "revoked_at": time.Time{}},
+		bson.M{"$set": bson.M{"revoked_at": now}},
+	).Return(&mongo.UpdateResult{MatchedCount: 2}, nil)
+
+	err := repo.RevokeRefreshTokenFamily(ctx, familyId, now)
+
+	mockCollection.AssertNumberOfCalls(t, "UpdateMany", 1)
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+}
+
+func testRevokeRefreshTokenFamilyWithDbError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := RefreshTokenRepository{col: mockCollection}
+
+	ctx := context.Background()
+	familyId := token.Token1.FamilyId()
+	now := time.Now()
+
+	dbError := errors.New("db error")
+	mockCollection.On(
+		"UpdateMany", ctx, bson.M{"family_id": familyId, "revoked_at": time.Time{}},
+		bson.M{"$set": bson.M{"revoked_at": now}},
+	).Return(nil, dbError)
+
+	err := repo.RevokeRefreshTokenFamily(ctx, familyId, now)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &pkgErrors.Unknown{Tag: RefreshTokenRepoTag, Cause: dbError}, err)
+}
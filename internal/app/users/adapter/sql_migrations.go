@@ -0,0 +1,27 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/sql_utils"
+	"io/fs"
+)
+
+//go:embed migrations
+var sqlMigrations embed.FS
+
+/*
+MigrateSQLDatabase creates or updates the tables used by the SQL repositories, with the migrations of the dialect.
+
+It's safe to run on every startup, as the applied migrations are skipped.
+*/
+func MigrateSQLDatabase(ctx context.Context, db *sql.DB, dialect sql_utils.Dialect) error {
+	migrations, err := fs.Sub(sqlMigrations, "migrations/"+dialect.String())
+
+	if err != nil {
+		return err
+	}
+
+	return sql_utils.Migrate(ctx, db, dialect, migrations)
+}
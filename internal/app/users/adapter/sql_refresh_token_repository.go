@@ -0,0 +1,200 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/sql_utils"
+	"github.com/sirupsen/logrus"
+	"log"
+	"time"
+)
+
+/*
+SQLRefreshTokenRepository stores the refresh tokens in a SQL database, through database/sql.
+
+The tables must have been created with MigrateSQLDatabase.
+*/
+type SQLRefreshTokenRepository struct {
+	db      *sql.DB
+	dialect sql_utils.Dialect
+}
+
+const SQLRefreshTokenRepoTag = "SQLRefreshTokenRepository"
+
+func NewSQLRefreshTokenRepository(db *sql.DB, dialect sql_utils.Dialect) *SQLRefreshTokenRepository {
+	if db == nil {
+		log.Panicf("[%s] missing db", SQLRefreshTokenRepoTag)
+	}
+
+	return &SQLRefreshTokenRepository{db: db, dialect: dialect}
+}
+
+/*
+AddRefreshToken inserts a whole refresh token into the database.
+*/
+func (r *SQLRefreshTokenRepository) AddRefreshToken(ctx context.Context, newToken *token.RefreshToken) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":      SQLRefreshTokenRepoTag,
+			"userId":   newToken.UserId(),
+			"familyId": newToken.FamilyId(),
+		},
+	).Debug("Adding refresh token")
+	args := sql_utils.NewArgs(r.dialect)
+	insert := fmt.Sprintf(
+		`INSERT INTO refresh_tokens (id, user_id, family_id, created_at, expires_at, revoked_at) `+
+			`VALUES (%s, %s, %s, %s, %s, %s)`,
+		args.Add(newToken.Id()), args.Add(newToken.UserId()), args.Add(newToken.FamilyId()),
+		args.Add(newToken.CreatedAt().UnixMilli()), args.Add(newToken.ExpiresAt().UnixMilli()),
+		args.Add(marshalRevokedAt(newToken.RevokedAt())),
+	)
+
+	if _, err := r.db.ExecContext(ctx, insert, args.Values()...); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":      SQLRefreshTokenRepoTag,
+				"userId":   newToken.UserId(),
+				"familyId": newToken.FamilyId(),
+			},
+		).WithError(err).Error("Error inserting refresh token")
+
+		return &errors.Unknown{Tag: SQLRefreshTokenRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+func (r *SQLRefreshTokenRepository) GetRefreshTokenById(
+	ctx context.Context, tokenId string,
+) (*token.RefreshToken, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag": SQLRefreshTokenRepoTag,
+		},
+	).Debug("Getting refresh token by id")
+	args := sql_utils.NewArgs(r.dialect)
+	query := `SELECT id, user_id, family_id, created_at, expires_at, revoked_at FROM refresh_tokens WHERE id = ` +
+		args.Add(tokenId)
+
+	var tokenModel RefreshTokenModel
+	var createdAt, expiresAt int64
+	var revokedAt sql.NullInt64
+
+	if err := r.db.QueryRowContext(ctx, query, args.Values()...).Scan(
+		&tokenModel.Id, &tokenModel.UserId, &tokenModel.FamilyId, &createdAt, &expiresAt, &revokedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &token.NotFoundError{Id: tokenId}
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": SQLRefreshTokenRepoTag,
+			},
+		).WithError(err).Error("Error getting refresh token by id")
+
+		return nil, &errors.Unknown{Tag: SQLRefreshTokenRepoTag, Cause: err}
+	}
+
+	if revokedAt.Valid {
+		tokenModel.RevokedAt = time.UnixMilli(revokedAt.Int64).UTC()
+	}
+
+	return token.UnmarshalRefreshTokenFromDB(
+		tokenModel.Id,
+		tokenModel.UserId,
+		tokenModel.FamilyId,
+		time.UnixMilli(createdAt).UTC(),
+		time.UnixMilli(expiresAt).UTC(),
+		tokenModel.RevokedAt,
+	), nil
+}
+
+/*
+RevokeRefreshToken marks a refresh token as revoked, as long as it wasn't already.
+
+The check and the update happen in a single query, so when two requests try to
+rotate the same token at once, only one of them succeeds.
+*/
+func (r *SQLRefreshTokenRepository) RevokeRefreshToken(
+	ctx context.Context, tokenId string, revokedAt time.Time,
+) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag": SQLRefreshTokenRepoTag,
+		},
+	).Debug("Revoking refresh token")
+	args := sql_utils.NewArgs(r.dialect)
+	update := fmt.Sprintf(
+		`UPDATE refresh_tokens SET revoked_at = %s WHERE id = %s AND revoked_at IS NULL`,
+		args.Add(revokedAt.UnixMilli()), args.Add(tokenId),
+	)
+
+	res, err := r.db.ExecContext(ctx, update, args.Values()...)
+
+	if err == nil {
+		var updated int64
+
+		if updated, err = res.RowsAffected(); err == nil && updated == 0 {
+			return &token.NotFoundError{Id: tokenId}
+		}
+	}
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": SQLRefreshTokenRepoTag,
+			},
+		).WithError(err).Error("Error revoking refresh token")
+
+		return &errors.Unknown{Tag: SQLRefreshTokenRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+/*
+RevokeRefreshTokenFamily revokes every refresh token belonging to the given family that's still active.
+*/
+func (r *SQLRefreshTokenRepository) RevokeRefreshTokenFamily(
+	ctx context.Context, familyId string, revokedAt time.Time,
+) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":      SQLRefreshTokenRepoTag,
+			"familyId": familyId,
+		},
+	).Debug("Revoking refresh token family")
+	args := sql_utils.NewArgs(r.dialect)
+	update := fmt.Sprintf(
+		`UPDATE refresh_tokens SET revoked_at = %s WHERE family_id = %s AND revoked_at IS NULL`,
+		args.Add(revokedAt.UnixMilli()), args.Add(familyId),
+	)
+
+	if _, err := r.db.ExecContext(ctx, update, args.Values()...); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":      SQLRefreshTokenRepoTag,
+				"familyId": familyId,
+			},
+		).WithError(err).Error("Error revoking refresh token family")
+
+		return &errors.Unknown{Tag: SQLRefreshTokenRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+/*
+marshalRevokedAt stores a zero revocation time, meaning the token hasn't been revoked, as null.
+*/
+func marshalRevokedAt(revokedAt time.Time) interface{} {
+	if revokedAt.IsZero() {
+		return nil
+	}
+
+	return revokedAt.UnixMilli()
+}
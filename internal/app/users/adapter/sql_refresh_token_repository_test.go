@@ -0,0 +1,122 @@
+package adapter
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/sql_utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSQLRefreshTokenRepository(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize refresh token repository with no db": testNewSQLRefreshTokenRepositoryWithNoDb,
+		"call add and get refresh token by id":           testSQLGetRefreshTokenById,
+		"call get unknown refresh token by id":           testSQLGetUnknownRefreshTokenById,
+		"call revoke refresh token":                      testSQLRevokeRefreshToken,
+		"call revoke refresh token already revoked":      testSQLRevokeRefreshTokenAlreadyRevoked,
+		"call revoke refresh token family":               testSQLRevokeRefreshTokenFamily,
+		"call get refresh token with cancelled ctx":      testSQLGetRefreshTokenWithCancelledContext,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+var sqlTokenNow = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newSQLTestToken(id string, familyId string) *token.RefreshToken {
+	return token.UnmarshalRefreshTokenFromDB(id, "1", familyId, sqlTokenNow, sqlTokenNow.Add(time.Hour), time.Time{})
+}
+
+func testNewSQLRefreshTokenRepositoryWithNoDb(t *testing.T) {
+	assert.Panics(
+		t, func() {
+			NewSQLRefreshTokenRepository(nil, sql_utils.SQLite)
+		},
+	)
+}
+
+func testSQLGetRefreshTokenById(t *testing.T) {
+	repo := NewSQLRefreshTokenRepository(openSQLiteDatabase(t), sql_utils.SQLite)
+	refreshToken := newSQLTestToken("token-1", "family-1")
+
+	assert.NoError(t, repo.AddRefreshToken(context.Background(), refreshToken))
+
+	out, err := repo.GetRefreshTokenById(context.Background(), "token-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, refreshToken, out)
+}
+
+func testSQLGetUnknownRefreshTokenById(t *testing.T) {
+	repo := NewSQLRefreshTokenRepository(openSQLiteDatabase(t), sql_utils.SQLite)
+
+	out, err := repo.GetRefreshTokenById(context.Background(), "token-1")
+
+	assert.Nil(t, out)
+	assert.Equal(t, &token.NotFoundError{Id: "token-1"}, err)
+}
+
+func testSQLRevokeRefreshToken(t *testing.T) {
+	repo := NewSQLRefreshTokenRepository(openSQLiteDatabase(t), sql_utils.SQLite)
+	revokedAt := sqlTokenNow.Add(time.Minute)
+
+	assert.NoError(t, repo.AddRefreshToken(context.Background(), newSQLTestToken("token-1", "family-1")))
+	assert.NoError(t, repo.RevokeRefreshToken(context.Background(), "token-1", revokedAt))
+
+	out, err := repo.GetRefreshTokenById(context.Background(), "token-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, revokedAt, out.RevokedAt())
+}
+
+func testSQLRevokeRefreshTokenAlreadyRevoked(t *testing.T) {
+	repo := NewSQLRefreshTokenRepository(openSQLiteDatabase(t), sql_utils.SQLite)
+	revokedAt := sqlTokenNow.Add(time.Minute)
+
+	assert.NoError(t, repo.AddRefreshToken(context.Background(), newSQLTestToken("token-1", "family-1")))
+	assert.NoError(t, repo.RevokeRefreshToken(context.Background(), "token-1", revokedAt))
+
+	err := repo.RevokeRefreshToken(context.Background(), "token-1", revokedAt.Add(time.Minute))
+
+	assert.Equal(t, &token.NotFoundError{Id: "token-1"}, err)
+}
+
+func testSQLRevokeRefreshTokenFamily(t *testing.T) {
+	repo := NewSQLRefreshTokenRepository(openSQLiteDatabase(t), sql_utils.SQLite)
+	revokedAt := sqlTokenNow.Add(time.Minute)
+
+	for id, familyId := range map[string]string{"token-1": "family-1", "sibling": "family-1", "stranger": "other"} {
+		assert.NoError(t, repo.AddRefreshToken(context.Background(), newSQLTestToken(id, familyId)))
+	}
+
+	assert.NoError(t, repo.RevokeRefreshTokenFamily(context.Background(), "family-1", revokedAt))
+
+	for id, expected := range map[string]time.Time{"token-1": revokedAt, "sibling": revokedAt, "stranger": {}} {
+		out, err := repo.GetRefreshTokenById(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, out.RevokedAt(), id)
+	}
+}
+
+func testSQLGetRefreshTokenWithCancelledContext(t *testing.T) {
+	repo := NewSQLRefreshTokenRepository(openSQLiteDatabase(t), sql_utils.SQLite)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out, err := repo.GetRefreshTokenById(ctx, "token-1")
+
+	assert.Nil(t, out)
+	assert.ErrorIs(t, err, context.Canceled)
+}
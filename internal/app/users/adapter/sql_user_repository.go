@@ -0,0 +1,788 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/sql_utils"
+	"github.com/sirupsen/logrus"
+	"log"
+	"strings"
+	"time"
+)
+
+/*
+SQLUserRepository stores the users in a SQL database, through database/sql.
+
+It behaves as UserRepository does, which the conformance tests make sure of.
+The roles are stored in their own table, a row per role, so they can be
+filtered and sorted as MongoDB does with arrays. Unlike MongoDB, only ASCII
+letters are case-insensitive when checking uniqueness. The tables must have
+been created with MigrateSQLDatabase.
+*/
+type SQLUserRepository struct {
+	db      *sql.DB
+	dialect sql_utils.Dialect
+}
+
+const SQLUserRepoTag = "SQLUserRepository"
+
+/*
+userFields lists every field of a user, named as in MongoDB, which are the ones read without a projection.
+*/
+var userFields = []string{
+	"id", "first_name", "last_name", "nickname", "password", "email", "country", "roles", "created_at", "updated_at",
+	"version",
+}
+
+/*
+userTable maps the query fields, named as in MongoDB, to their columns.
+*/
+var userTable = sql_utils.Table{
+	Name:           "users",
+	Key:            "id",
+	InsertionOrder: "seq",
+	Columns: map[string]sql_utils.Column{
+		"id":         {Name: "id", Type: sql_utils.Text},
+		"first_name": {Name: "first_name", Type: sql_utils.Text},
+		"last_name":  {Name: "last_name", Type: sql_utils.Text},
+		"nickname":   {Name: "nickname", Type: sql_utils.Text},
+		"password":   {Name: "password", Type: sql_utils.Text},
+		"email":      {Name: "email", Type: sql_utils.Text},
+		"country":    {Name: "country", Type: sql_utils.Text},
+		"roles": {
+			Name:   "role",
+			Type:   sql_utils.Text,
+			Values: &sql_utils.ValuesTable{Name: "user_roles", ForeignKey: "user_id", Order: "position"},
+		},
+		"created_at": {Name: "created_at", Type: sql_utils.Timestamp},
+		"updated_at": {Name: "updated_at", Type: sql_utils.Timestamp},
+		"version":    {Name: "version", Type: sql_utils.Integer},
+	},
+}
+
+func NewSQLUserRepository(db *sql.DB, dialect sql_utils.Dialect) *SQLUserRepository {
+	if db == nil {
+		log.Panicf("[%s] missing db", SQLUserRepoTag)
+	}
+
+	return &SQLUserRepository{db: db, dialect: dialect}
+}
+
+/*
+AddUser inserts a whole user entity, along with its roles, into the database.
+*/
+func (r *SQLUserRepository) AddUser(ctx context.Context, newUser *user.User) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":  SQLUserRepoTag,
+			"user": newUser,
+		},
+	).Debug("Adding user")
+	userModel := marshalUser(newUser)
+
+	err := r.inTx(
+		ctx, func(tx *sql.Tx) error {
+			return r.insertUser(ctx, tx, userModel)
+		},
+	)
+
+	if err != nil {
+		return mapInsertError(err, userModel)
+	}
+
+	return nil
+}
+
+/*
+AddUsers inserts every user entity in a single transaction, each one behind its own savepoint, so the ones that can't
+be inserted are rolled back on their own without rolling back the rest.
+
+Failing to set, release or roll back to a savepoint, or to commit, leaves us not
+knowing which users could be stored, so the whole batch fails then.
+*/
+func (r *SQLUserRepository) AddUsers(ctx context.Context, newUsers []*user.User) ([]error, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":   SQLUserRepoTag,
+			"users": len(newUsers),
+		},
+	).Debug("Adding users")
+
+	if len(newUsers) == 0 {
+		return nil, nil
+	}
+
+	errs := make([]error, len(newUsers))
+
+	err := r.inTx(
+		ctx, func(tx *sql.Tx) error {
+			for i, newUser := range newUsers {
+				userModel := marshalUser(newUser)
+
+				if _, err := tx.ExecContext(ctx, `SAVEPOINT add_user`); err != nil {
+					return err
+				}
+
+				if err := r.insertUser(ctx, tx, userModel); err != nil {
+					if _, rollbackErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT add_user`); rollbackErr != nil {
+						return rollbackErr
+					}
+
+					errs[i] = mapInsertError(err, userModel)
+				}
+
+				if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT add_user`); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":   SQLUserRepoTag,
+				"users": len(newUsers),
+			},
+		).WithError(err).Error("Error inserting users")
+
+		return nil, &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	return errs, nil
+}
+
+/*
+GetUserById retrieves the user with the given id, reading only the columns of the projection, as GetUsers does.
+*/
+func (r *SQLUserRepository) GetUserById(ctx context.Context, userId string, projection []string) (*user.User, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":        SQLUserRepoTag,
+			"userId":     userId,
+			"projection": projection,
+		},
+	).Debug("Getting user by id")
+	args := sql_utils.NewArgs(r.dialect)
+
+	selectUsers, fields := r.selectUsers(projection)
+	userModels, err := r.queryUsers(ctx, selectUsers+` WHERE users.id = `+args.Add(userId), fields, args)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":    SQLUserRepoTag,
+				"userId": userId,
+			},
+		).WithError(err).Error("Error getting user by id")
+
+		return nil, &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	if len(userModels) == 0 {
+		return nil, &user.NotFoundError{Id: userId}
+	}
+
+	return unmarshalUser(userModels[0]), nil
+}
+
+/*
+GetUsersByIds retrieves the users with any of the given ids at once, with a single IN query, reading only the columns
+of the projection, as GetUserById does.
+*/
+func (r *SQLUserRepository) GetUsersByIds(
+	ctx context.Context, userIds []string, projection []string,
+) ([]*user.User, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":        SQLUserRepoTag,
+			"userIds":    userIds,
+			"projection": projection,
+		},
+	).Debug("Getting users by ids")
+
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+
+	args := sql_utils.NewArgs(r.dialect)
+	placeholders := make([]string, len(userIds))
+
+	for i, userId := range userIds {
+		placeholders[i] = args.Add(userId)
+	}
+
+	selectUsers, fields := r.selectUsers(projection)
+	query := fmt.Sprintf(`%s WHERE users.id IN (%s) ORDER BY users.seq`, selectUsers, strings.Join(placeholders, ", "))
+	userModels, err := r.queryUsers(ctx, query, fields, args)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     SQLUserRepoTag,
+				"userIds": userIds,
+			},
+		).WithError(err).Error("Error getting users by ids")
+
+		return nil, &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	var users []*user.User
+	for _, userModel := range userModels {
+		users = append(users, unmarshalUser(userModel))
+	}
+
+	return users, nil
+}
+
+/*
+GetUserByNicknameOrEmail retrieves the first user whose nickname or email matches the given value, regardless of its
+case, through the same lower() expressions the unique indexes are built on.
+*/
+func (r *SQLUserRepository) GetUserByNicknameOrEmail(
+	ctx context.Context, nicknameOrEmail string,
+) (*user.User, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":             SQLUserRepoTag,
+			"nicknameOrEmail": nicknameOrEmail,
+		},
+	).Debug("Getting user by nickname or email")
+	args := sql_utils.NewArgs(r.dialect)
+	selectUsers, fields := r.selectUsers(nil)
+	query := fmt.Sprintf(
+		`%s WHERE lower(users.nickname) = lower(%s) OR lower(users.email) = lower(%s) ORDER BY users.seq LIMIT 1`,
+		selectUsers, args.Add(nicknameOrEmail), args.Add(nicknameOrEmail),
+	)
+
+	userModels, err := r.queryUsers(ctx, query, fields, args)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":             SQLUserRepoTag,
+				"nicknameOrEmail": nicknameOrEmail,
+			},
+		).WithError(err).Error("Error getting user by nickname or email")
+
+		return nil, &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	if len(userModels) == 0 {
+		return nil, &user.NotFoundError{Id: nicknameOrEmail}
+	}
+
+	return unmarshalUser(userModels[0]), nil
+}
+
+/*
+GetUsers retrieves the users matching the filter, with the same semantics as the MongoDB query.
+
+As with the MongoDB cursor, the rows are read as the caller iterates through
+them, so the users never need to be held in memory at once. Only the columns of
+the projection are read, along with the roles of each user in the same row, so
+the query holds a single connection however long the caller takes.
+*/
+func (r *SQLUserRepository) GetUsers(
+	ctx context.Context, filter query_utils.FilterExpression, sort []query_utils.Sort,
+	pagination query_utils.Pagination, projection []string,
+) (user.UserIterator, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":        SQLUserRepoTag,
+			"filter":     filter,
+			"sort":       sort,
+			"pagination": pagination,
+			"projection": projection,
+		},
+	).Debug("Getting users")
+	args := sql_utils.NewArgs(r.dialect)
+
+	where := sql_utils.MapFilterExpressionToWhere(userTable, filter, args)
+	orderBy := sql_utils.MapSortToOrderBy(userTable, sort)
+	limit, err := sql_utils.MapPaginationToLimit(pagination, args)
+
+	if err != nil {
+		return nil, &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	selectUsers, fields := r.selectUsers(projection)
+	query := strings.Join([]string{selectUsers, where, orderBy, limit}, " ")
+	rows, err := r.db.QueryContext(ctx, query, args.Values()...)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":   SQLUserRepoTag,
+				"query": query,
+			},
+		).WithError(err).Error("Error getting users")
+
+		return nil, &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	return &sqlUserRows{rows: rows, fields: fields}, nil
+}
+
+/*
+sqlUserRows iterates through the users of a query selecting the given fields, as returned by selectUsers.
+*/
+type sqlUserRows struct {
+	rows    *sql.Rows
+	fields  []string
+	current *user.User
+	err     error
+}
+
+func (i *sqlUserRows) Next(context.Context) bool {
+	if i.err != nil || !i.rows.Next() {
+		if i.err == nil {
+			i.err = i.wrapError(i.rows.Err())
+		}
+
+		return false
+	}
+
+	userModel, err := scanUser(i.rows, i.fields)
+
+	if err != nil {
+		i.err = i.wrapError(err)
+
+		return false
+	}
+
+	i.current = unmarshalUser(userModel)
+
+	return true
+}
+
+/*
+wrapError logs and wraps an error reading the rows, or returns nil if there's none.
+*/
+func (i *sqlUserRows) wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	logrus.WithFields(
+		logrus.Fields{
+			"tag": SQLUserRepoTag,
+		},
+	).WithError(err).Error("Error reading users")
+
+	return &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+}
+
+func (i *sqlUserRows) User() *user.User {
+	return i.current
+}
+
+func (i *sqlUserRows) Err() error {
+	return i.err
+}
+
+func (i *sqlUserRows) Close(context.Context) error {
+	if err := i.rows.Close(); err != nil {
+		return &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+/*
+CountUsers counts the users matching the filter, as GetUsers does without pagination.
+*/
+func (r *SQLUserRepository) CountUsers(ctx context.Context, filter query_utils.FilterExpression) (int64, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":    SQLUserRepoTag,
+			"filter": filter,
+		},
+	).Debug("Counting users")
+	args := sql_utils.NewArgs(r.dialect)
+
+	where := sql_utils.MapFilterExpressionToWhere(userTable, filter, args)
+	query := strings.Join([]string{`SELECT count(*) FROM users`, where}, " ")
+
+	var count int64
+
+	if err := r.db.QueryRowContext(ctx, query, args.Values()...).Scan(&count); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":   SQLUserRepoTag,
+				"query": query,
+			},
+		).WithError(err).Error("Error counting users")
+
+		return 0, &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	return count, nil
+}
+
+/*
+UpdateUser fully updates a user entity in the database, replacing its roles, as long as the stored one is still at its
+version, bumping it.
+
+When no row matches, the user is looked up by its id alone to tell whether it's
+missing, or at another version.
+*/
+func (r *SQLUserRepository) UpdateUser(ctx context.Context, userToUpdate *user.User) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":  SQLUserRepoTag,
+			"user": userToUpdate,
+		},
+	).Debug("Updating user")
+	userModel := marshalUser(userToUpdate)
+
+	err := r.inTx(
+		ctx, func(tx *sql.Tx) error {
+			args := sql_utils.NewArgs(r.dialect)
+			update := fmt.Sprintf(
+				`UPDATE users SET first_name = %s, last_name = %s, nickname = %s, password = %s, email = %s, `+
+					`country = %s, created_at = %s, updated_at = %s, version = version + 1 WHERE id = %s AND version = %s`,
+				args.Add(userModel.FirstName), args.Add(userModel.LastName), args.Add(userModel.Nickname),
+				args.Add(userModel.Password), args.Add(userModel.Email), args.Add(userModel.Country),
+				args.Add(userModel.CreatedAt.UnixMilli()), args.Add(userModel.UpdatedAt.UnixMilli()),
+				args.Add(userModel.Id), args.Add(userModel.Version),
+			)
+
+			res, err := tx.ExecContext(ctx, update, args.Values()...)
+
+			if err != nil {
+				return err
+			}
+
+			updated, err := res.RowsAffected()
+
+			if err != nil {
+				return err
+			}
+
+			if updated == 0 {
+				return r.versionConflictOrNotFound(ctx, tx, userModel)
+			}
+
+			if err := r.deleteRoles(ctx, tx, userModel.Id); err != nil {
+				return err
+			}
+
+			return r.insertRoles(ctx, tx, userModel)
+		},
+	)
+
+	if err != nil {
+		if errors.As(err, new(*user.NotFoundError)) || errors.As(err, new(*user.VersionConflictError)) {
+			return err
+		}
+
+		if existsErr := mapUniqueViolation(err, userModel); existsErr != nil {
+			return existsErr
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":       SQLUserRepoTag,
+				"userModel": userModel,
+			},
+		).WithError(err).Error("Error updating user")
+
+		return &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+/*
+RemoveUser removes a user entity, along with its roles, from the database given its id.
+*/
+func (r *SQLUserRepository) RemoveUser(ctx context.Context, userId string) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":    SQLUserRepoTag,
+			"userId": userId,
+		},
+	).Debug("Removing user")
+
+	err := r.inTx(
+		ctx, func(tx *sql.Tx) error {
+			if err := r.deleteRoles(ctx, tx, userId); err != nil {
+				return err
+			}
+
+			args := sql_utils.NewArgs(r.dialect)
+			res, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = `+args.Add(userId), args.Values()...)
+
+			if err != nil {
+				return err
+			}
+
+			if deleted, err := res.RowsAffected(); err != nil || deleted == 0 {
+				return notFoundOr(err, userId)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		if errors.As(err, new(*user.NotFoundError)) {
+			return err
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":    SQLUserRepoTag,
+				"userId": userId,
+			},
+		).WithError(err).Error("Error removing user")
+
+		return &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+/*
+selectUsers returns the SELECT of the users reading the fields of the projection, or every field if it's empty, along
+with the fields in the order they're selected, to be read with scanUser.
+*/
+func (r *SQLUserRepository) selectUsers(projection []string) (string, []string) {
+	fields := projection
+
+	if len(fields) == 0 {
+		fields = userFields
+	}
+
+	return `SELECT ` + sql_utils.MapProjectionToColumns(userTable, fields, r.dialect) + ` FROM users`, fields
+}
+
+/*
+queryUsers runs a query built from selectUsers, reading every user found.
+*/
+func (r *SQLUserRepository) queryUsers(
+	ctx context.Context, query string, fields []string, args *sql_utils.Args,
+) ([]*UserModel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args.Values()...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var userModels []*UserModel
+
+	for rows.Next() {
+		userModel, err := scanUser(rows, fields)
+
+		if err != nil {
+			return nil, err
+		}
+
+		userModels = append(userModels, userModel)
+	}
+
+	return userModels, rows.Err()
+}
+
+/*
+scanUser reads the current row of a query built from selectUsers with the given fields, leaving the rest unset.
+*/
+func scanUser(rows *sql.Rows, fields []string) (*UserModel, error) {
+	var userModel UserModel
+	var createdAt, updatedAt int64
+	var roles sql.NullString
+	destinations := make([]interface{}, len(fields))
+
+	for i, field := range fields {
+		switch field {
+		case "id":
+			destinations[i] = &userModel.Id
+		case "first_name":
+			destinations[i] = &userModel.FirstName
+		case "last_name":
+			destinations[i] = &userModel.LastName
+		case "nickname":
+			destinations[i] = &userModel.Nickname
+		case "password":
+			destinations[i] = &userModel.Password
+		case "email":
+			destinations[i] = &userModel.Email
+		case "country":
+			destinations[i] = &userModel.Country
+		case "roles":
+			destinations[i] = &roles
+		case "created_at":
+			destinations[i] = &createdAt
+		case "updated_at":
+			destinations[i] = &updatedAt
+		case "version":
+			destinations[i] = &userModel.Version
+		default:
+			destinations[i] = new(interface{})
+		}
+	}
+
+	if err := rows.Scan(destinations...); err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		switch field {
+		case "roles":
+			if roles.String != "" {
+				userModel.Roles = strings.Split(roles.String, ",")
+			}
+		case "created_at":
+			userModel.CreatedAt = time.UnixMilli(createdAt).UTC()
+		case "updated_at":
+			userModel.UpdatedAt = time.UnixMilli(updatedAt).UTC()
+		}
+	}
+
+	return &userModel, nil
+}
+
+func (r *SQLUserRepository) insertRoles(ctx context.Context, tx *sql.Tx, userModel *UserModel) error {
+	for position, role := range userModel.Roles {
+		args := sql_utils.NewArgs(r.dialect)
+		insert := fmt.Sprintf(
+			`INSERT INTO user_roles (user_id, position, role) VALUES (%s, %s, %s)`,
+			args.Add(userModel.Id), args.Add(position), args.Add(role),
+		)
+
+		if _, err := tx.ExecContext(ctx, insert, args.Values()...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+insertUser inserts the user model, along with its roles, in the transaction.
+*/
+func (r *SQLUserRepository) insertUser(ctx context.Context, tx *sql.Tx, userModel *UserModel) error {
+	args := sql_utils.NewArgs(r.dialect)
+	insert := fmt.Sprintf(
+		`INSERT INTO users (id, first_name, last_name, nickname, password, email, country, created_at, `+
+			`updated_at, version) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)`,
+		args.Add(userModel.Id), args.Add(userModel.FirstName), args.Add(userModel.LastName),
+		args.Add(userModel.Nickname), args.Add(userModel.Password), args.Add(userModel.Email),
+		args.Add(userModel.Country), args.Add(userModel.CreatedAt.UnixMilli()),
+		args.Add(userModel.UpdatedAt.UnixMilli()), args.Add(userModel.Version),
+	)
+
+	if _, err := tx.ExecContext(ctx, insert, args.Values()...); err != nil {
+		return err
+	}
+
+	return r.insertRoles(ctx, tx, userModel)
+}
+
+/*
+mapInsertError returns the domain error of a user that couldn't be inserted, or an unknown one if it didn't collide
+with a stored user.
+*/
+func mapInsertError(err error, userModel *UserModel) error {
+	if existsErr := mapUniqueViolation(err, userModel); existsErr != nil {
+		return existsErr
+	}
+
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":       SQLUserRepoTag,
+			"userModel": userModel,
+		},
+	).WithError(err).Error("Error inserting user")
+
+	return &errors.Unknown{Tag: SQLUserRepoTag, Cause: err}
+}
+
+func (r *SQLUserRepository) deleteRoles(ctx context.Context, tx *sql.Tx, userId string) error {
+	args := sql_utils.NewArgs(r.dialect)
+	_, err := tx.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = `+args.Add(userId), args.Values()...)
+
+	return err
+}
+
+/*
+inTx runs the function in a transaction, committing it only if the function succeeds.
+*/
+func (r *SQLUserRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+/*
+versionConflictOrNotFound tells why the update of the user matched no row: it's at another version if it exists, or
+it's missing otherwise.
+*/
+func (r *SQLUserRepository) versionConflictOrNotFound(ctx context.Context, tx *sql.Tx, userModel *UserModel) error {
+	args := sql_utils.NewArgs(r.dialect)
+	var count int64
+
+	row := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE id = `+args.Add(userModel.Id), args.Values()...)
+
+	if err := row.Scan(&count); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return &user.NotFoundError{Id: userModel.Id}
+	}
+
+	return &user.VersionConflictError{Id: userModel.Id, Version: userModel.Version}
+}
+
+/*
+notFoundOr returns the error getting the affected rows if there's one, or the user not being found otherwise.
+*/
+func notFoundOr(err error, userId string) error {
+	if err != nil {
+		return err
+	}
+
+	return &user.NotFoundError{Id: userId}
+}
+
+/*
+mapUniqueViolation translates a violation of the unique indexes into the domain error naming the conflicting field.
+
+It returns nil for any other error.
+*/
+func mapUniqueViolation(err error, userModel *UserModel) error {
+	index, ok := sql_utils.UniqueViolation(err)
+
+	if !ok {
+		return nil
+	}
+
+	switch index {
+	case nicknameIndex:
+		return &user.AlreadyExistsError{Field: "nickname", Value: userModel.Nickname}
+	case emailIndex:
+		return &user.AlreadyExistsError{Field: "email", Value: userModel.Email}
+	}
+
+	return nil
+}
@@ -29,8 +29,51 @@ type UserModel struct {
 	Password  string    `bson:"password"`
 	Email     string    `bson:"email"`
 	Country   string    `bson:"country"`
+	Roles     []string  `bson:"roles"`
 	CreatedAt time.Time `bson:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at"`
+	Version   int64     `bson:"version"`
+}
+
+/*
+project returns a copy of the user model holding just the fields of the projection, named as in MongoDB, as a
+projection there would, or the model itself if the projection is empty.
+*/
+func (m *UserModel) project(projection []string) *UserModel {
+	if len(projection) == 0 {
+		return m
+	}
+
+	projected := &UserModel{}
+
+	for _, field := range projection {
+		switch field {
+		case "id":
+			projected.Id = m.Id
+		case "first_name":
+			projected.FirstName = m.FirstName
+		case "last_name":
+			projected.LastName = m.LastName
+		case "nickname":
+			projected.Nickname = m.Nickname
+		case "password":
+			projected.Password = m.Password
+		case "email":
+			projected.Email = m.Email
+		case "country":
+			projected.Country = m.Country
+		case "roles":
+			projected.Roles = m.Roles
+		case "created_at":
+			projected.CreatedAt = m.CreatedAt
+		case "updated_at":
+			projected.UpdatedAt = m.UpdatedAt
+		case "version":
+			projected.Version = m.Version
+		}
+	}
+
+	return projected
 }
 
 type UserRepository struct {
@@ -39,12 +82,51 @@ type UserRepository struct {
 
 const UserRepoTag = "UserRepository"
 
-func NewUserRepository(dbClient mongo_helper.Database) UserRepository {
+const (
+	nicknameIndex = "nickname_unique"
+	emailIndex    = "email_unique"
+)
+
+/*
+caseInsensitiveCollation compares strings ignoring their case, as its strength is 2, so "John" and "john" are equal.
+*/
+var caseInsensitiveCollation = &options.Collation{Locale: "en", Strength: 2}
+
+/*
+userIndexes keeps nicknames and emails unique, regardless of their case.
+
+Queries can only use these indexes when they use the same collation.
+*/
+var userIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "nickname", Value: 1}},
+		Options: options.Index().SetName(nicknameIndex).SetUnique(true).SetCollation(caseInsensitiveCollation),
+	},
+	{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetName(emailIndex).SetUnique(true).SetCollation(caseInsensitiveCollation),
+	},
+}
+
+/*
+NewUserRepository builds the repository, making sure the unique indexes exist.
+
+Creating an index that already exists is a no-op, so this is safe to run on
+every startup. It panics if the indexes can't be created, like when there are
+already duplicated users stored, as we can't guarantee uniqueness without them.
+*/
+func NewUserRepository(ctx context.Context, dbClient mongo_helper.Database) UserRepository {
 	if dbClient == nil {
 		log.Panicf("[%s] missing dbClient", UserRepoTag)
 	}
 
-	return UserRepository{col: dbClient.Collection("user")}
+	col := dbClient.Collection("user")
+
+	if err := col.CreateIndexes(ctx, userIndexes); err != nil {
+		log.Panicf("[%s] couldn't create indexes: %v", UserRepoTag, err)
+	}
+
+	return UserRepository{col: col}
 }
 
 /*
@@ -57,9 +139,13 @@ func (r *UserRepository) AddUser(ctx context.Context, newUser *user.User) error
 			"user": newUser,
 		},
 	).Debug("Adding user")
-	userModel := r.marshalUser(newUser)
+	userModel := marshalUser(newUser)
 
 	if _, err := r.col.InsertOne(ctx, userModel); err != nil {
+		if existsErr := mapDuplicateKeyError(err, userModel); existsErr != nil {
+			return existsErr
+		}
+
 		logrus.WithFields(
 			logrus.Fields{
 				"tag":       UserRepoTag,
@@ -73,17 +159,92 @@ func (r *UserRepository) AddUser(ctx context.Context, newUser *user.User) error
 	return nil
 }
 
-func (r *UserRepository) GetUserById(ctx context.Context, userId string) (*user.User, error) {
+/*
+AddUsers inserts every user entity with a single unordered InsertMany, so the ones that can't be inserted don't stop
+the rest.
+
+MongoDB reports the failed documents by their index in the batch, which is how
+their errors are matched back to the users. A write concern error leaves us not
+knowing which users were stored, so the whole batch fails then.
+*/
+func (r *UserRepository) AddUsers(ctx context.Context, newUsers []*user.User) ([]error, error) {
 	logrus.WithFields(
 		logrus.Fields{
-			"tag":    UserRepoTag,
-			"userId": userId,
+			"tag":   UserRepoTag,
+			"users": len(newUsers),
+		},
+	).Debug("Adding users")
+
+	if len(newUsers) == 0 {
+		return nil, nil
+	}
+
+	userModels := make([]*UserModel, len(newUsers))
+	documents := make([]interface{}, len(newUsers))
+
+	for i, newUser := range newUsers {
+		userModels[i] = marshalUser(newUser)
+		documents[i] = userModels[i]
+	}
+
+	errs := make([]error, len(newUsers))
+	_, err := r.col.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+
+	if err == nil {
+		return errs, nil
+	}
+
+	var bulkErr mongo.BulkWriteException
+
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":   UserRepoTag,
+				"users": len(newUsers),
+			},
+		).WithError(err).Error("Error inserting users")
+
+		return nil, &errors.Unknown{Tag: UserRepoTag, Cause: err}
+	}
+
+	for _, writeErr := range bulkErr.WriteErrors {
+		userModel := userModels[writeErr.Index]
+
+		if existsErr := mapDuplicateKeyError(writeErr.WriteError, userModel); existsErr != nil {
+			errs[writeErr.Index] = existsErr
+			continue
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":       UserRepoTag,
+				"userModel": userModel,
+			},
+		).WithError(writeErr.WriteError).Error("Error inserting user")
+
+		errs[writeErr.Index] = &errors.Unknown{Tag: UserRepoTag, Cause: writeErr.WriteError}
+	}
+
+	return errs, nil
+}
+
+func (r *UserRepository) GetUserById(ctx context.Context, userId string, projection []string) (*user.User, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":        UserRepoTag,
+			"userId":     userId,
+			"projection": projection,
 		},
 	).Debug("Getting user by id")
 	var userModel UserModel
+	opts := options.FindOne()
+
+	if len(projection) > 0 {
+		opts.SetProjection(mongo_utils.MapProjectionToBson(projection))
+	}
 
-	if err := r.col.FindOne(ctx, bson.M{"id": userId}).Decode(&userModel); err != nil {
-		if err == mongo.ErrNoDocuments {
+	if err := r.col.FindOne(ctx, bson.M{"id": userId}, opts).Decode(&userModel); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, &user.NotFoundError{Id: userId}
 		}
 
@@ -97,37 +258,127 @@ func (r *UserRepository) GetUserById(ctx context.Context, userId string) (*user.
 		return nil, &errors.Unknown{Tag: UserRepoTag, Cause: err}
 	}
 
-	dbUser := user.UnmarshalUserFromDB(
-		userModel.Id,
-		userModel.FirstName,
-		userModel.LastName,
-		userModel.Nickname,
-		userModel.Password,
-		userModel.Email,
-		userModel.Country,
-		userModel.CreatedAt,
-		userModel.UpdatedAt,
-	)
+	return unmarshalUser(&userModel), nil
+}
+
+/*
+GetUsersByIds retrieves the users with any of the given ids at once, with a single $in query.
+*/
+func (r *UserRepository) GetUsersByIds(
+	ctx context.Context, userIds []string, projection []string,
+) ([]*user.User, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":        UserRepoTag,
+			"userIds":    userIds,
+			"projection": projection,
+		},
+	).Debug("Getting users by ids")
+
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+
+	opts := options.Find()
+
+	if len(projection) > 0 {
+		opts.SetProjection(mongo_utils.MapProjectionToBson(projection))
+	}
+
+	cur, err := r.col.Find(ctx, bson.M{"id": bson.M{"$in": userIds}}, opts)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     UserRepoTag,
+				"userIds": userIds,
+			},
+		).WithError(err).Error("Error getting users by ids")
+
+		return nil, &errors.Unknown{Tag: UserRepoTag, Cause: err}
+	}
+
+	it := &userCursor{cur: cur}
+	var users []*user.User
+
+	for it.Next(ctx) {
+		users = append(users, it.User())
+	}
+
+	err = it.Err()
+
+	if closeErr := it.Close(ctx); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+/*
+GetUserByNicknameOrEmail retrieves the user whose nickname or email matches the given value, regardless of its case.
+
+Nicknames and emails share the same lookup so users can log in with either of
+them. The lookup uses the collation of their unique indexes, so it matches the
+same users uniqueness does, and can be served by those indexes.
+*/
+func (r *UserRepository) GetUserByNicknameOrEmail(ctx context.Context, nicknameOrEmail string) (*user.User, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":             UserRepoTag,
+			"nicknameOrEmail": nicknameOrEmail,
+		},
+	).Debug("Getting user by nickname or email")
+	var userModel UserModel
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"nickname": nicknameOrEmail},
+			bson.M{"email": nicknameOrEmail},
+		},
+	}
+
+	opts := options.FindOne().SetCollation(caseInsensitiveCollation)
+
+	if err := r.col.FindOne(ctx, filter, opts).Decode(&userModel); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, &user.NotFoundError{Id: nicknameOrEmail}
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":             UserRepoTag,
+				"nicknameOrEmail": nicknameOrEmail,
+			},
+		).WithError(err).Error("Error getting user by nickname or email")
 
-	return dbUser, nil
+		return nil, &errors.Unknown{Tag: UserRepoTag, Cause: err}
+	}
+
+	return unmarshalUser(&userModel), nil
 }
 
 /*
-GetUsers retrieves the user entities from the database.
+GetUsers retrieves the user entities from the database, streaming them from the cursor as they're decoded.
 */
 func (r *UserRepository) GetUsers(
-	ctx context.Context, queryFilters []query_utils.Filter, sort []query_utils.Sort, pagination query_utils.Pagination,
-) ([]*user.User, error) {
+	ctx context.Context, queryFilter query_utils.FilterExpression, sort []query_utils.Sort,
+	pagination query_utils.Pagination, projection []string,
+) (user.UserIterator, error) {
 	logrus.WithFields(
 		logrus.Fields{
 			"tag":        UserRepoTag,
-			"filters":    queryFilters,
+			"filter":     queryFilter,
 			"sort":       sort,
 			"pagination": pagination,
+			"projection": projection,
 		},
 	).Debug("Getting users")
 
-	filter := mongo_utils.MapFilterToBson(queryFilters)
+	filter := mongo_utils.MapFilterExpressionToBson(queryFilter)
 	opts := &options.FindOptions{
 		Limit: &pagination.Limit,
 		Skip:  &pagination.Offset,
@@ -137,12 +388,16 @@ func (r *UserRepository) GetUsers(
 		opts.SetSort(mongo_utils.MapSortToBson(sort))
 	}
 
+	if len(projection) > 0 {
+		opts.SetProjection(mongo_utils.MapProjectionToBson(projection))
+	}
+
 	cur, err := r.col.Find(ctx, filter, opts)
 	if err != nil {
 		logrus.WithFields(
 			logrus.Fields{
 				"tag":     UserRepoTag,
-				"filter":  queryFilters,
+				"filter":  queryFilter,
 				"options": opts,
 			},
 		).WithError(err).Error("Error getting users")
@@ -150,42 +405,99 @@ func (r *UserRepository) GetUsers(
 		return nil, &errors.Unknown{Tag: UserRepoTag, Cause: err}
 	}
 
-	var users []*user.User
-	for cur.Next(ctx) {
-		var userModel UserModel
-
-		if err := cur.Decode(&userModel); err != nil {
-			logrus.WithFields(
-				logrus.Fields{
-					"tag":     UserRepoTag,
-					"filter":  queryFilters,
-					"options": opts,
-				},
-			).WithError(err).Error("Error decoding user")
-
-			return nil, &errors.Unknown{Tag: UserRepoTag, Cause: err}
-		}
+	return &userCursor{cur: cur}, nil
+}
 
-		dbUser := user.UnmarshalUserFromDB(
-			userModel.Id,
-			userModel.FirstName,
-			userModel.LastName,
-			userModel.Nickname,
-			userModel.Password,
-			userModel.Email,
-			userModel.Country,
-			userModel.CreatedAt,
-			userModel.UpdatedAt,
-		)
+/*
+userCursor iterates through the users of a MongoDB cursor, only reading the next batch from the server once the
+current one has been consumed.
+*/
+type userCursor struct {
+	cur     mongo_helper.Cursor
+	current *user.User
+	err     error
+}
 
-		users = append(users, dbUser)
+func (c *userCursor) Next(ctx context.Context) bool {
+	if c.err != nil || !c.cur.Next(ctx) {
+		return false
 	}
 
-	return users, nil
+	var userModel UserModel
+
+	if err := c.cur.Decode(&userModel); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": UserRepoTag,
+			},
+		).WithError(err).Error("Error decoding user")
+
+		c.err = &errors.Unknown{Tag: UserRepoTag, Cause: err}
+
+		return false
+	}
+
+	c.current = unmarshalUser(&userModel)
+
+	return true
+}
+
+func (c *userCursor) User() *user.User {
+	return c.current
+}
+
+func (c *userCursor) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+
+	if err := c.cur.Err(); err != nil {
+		return &errors.Unknown{Tag: UserRepoTag, Cause: err}
+	}
+
+	return nil
+}
+
+func (c *userCursor) Close(ctx context.Context) error {
+	if err := c.cur.Close(ctx); err != nil {
+		return &errors.Unknown{Tag: UserRepoTag, Cause: err}
+	}
+
+	return nil
 }
 
 /*
-UpdateUser fully updates a user entity in the database.
+CountUsers counts the user entities matching the filter, as GetUsers does without pagination.
+*/
+func (r *UserRepository) CountUsers(ctx context.Context, queryFilter query_utils.FilterExpression) (int64, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":    UserRepoTag,
+			"filter": queryFilter,
+		},
+	).Debug("Counting users")
+
+	count, err := r.col.CountDocuments(ctx, mongo_utils.MapFilterExpressionToBson(queryFilter))
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":    UserRepoTag,
+				"filter": queryFilter,
+			},
+		).WithError(err).Error("Error counting users")
+
+		return 0, &errors.Unknown{Tag: UserRepoTag, Cause: err}
+	}
+
+	return count, nil
+}
+
+/*
+UpdateUser fully updates a user entity in the database, as long as the stored one is still at its version, bumping it.
+
+When no user matches, it's looked up by its id alone to tell whether it's
+missing, or at another version.
 */
 func (r *UserRepository) UpdateUser(ctx context.Context, userToUpdate *user.User) error {
 	logrus.WithFields(
@@ -194,12 +506,17 @@ func (r *UserRepository) UpdateUser(ctx context.Context, userToUpdate *user.User
 			"user": userToUpdate,
 		},
 	).Debug("Updating user")
-	userModel := r.marshalUser(userToUpdate)
+	userModel := marshalUser(userToUpdate)
+	userModel.Version++
 
-	filter := bson.M{"id": userToUpdate.Id()}
+	filter := bson.M{"id": userToUpdate.Id(), "version": versionFilter(userToUpdate.Version())}
 	res, err := r.col.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: userModel}})
 
 	if err != nil {
+		if existsErr := mapDuplicateKeyError(err, userModel); existsErr != nil {
+			return existsErr
+		}
+
 		logrus.WithFields(
 			logrus.Fields{
 				"tag":       UserRepoTag,
@@ -211,11 +528,40 @@ func (r *UserRepository) UpdateUser(ctx context.Context, userToUpdate *user.User
 		return &errors.Unknown{Tag: UserRepoTag, Cause: err}
 	}
 
-	if res.MatchedCount == 0 {
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	count, err := r.col.CountDocuments(ctx, bson.M{"id": userToUpdate.Id()})
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":    UserRepoTag,
+				"userId": userToUpdate.Id(),
+			},
+		).WithError(err).Error("Error checking the user to update")
+
+		return &errors.Unknown{Tag: UserRepoTag, Cause: err}
+	}
+
+	if count == 0 {
 		return &user.NotFoundError{Id: userToUpdate.Id()}
 	}
 
-	return nil
+	return &user.VersionConflictError{Id: userToUpdate.Id(), Version: userToUpdate.Version()}
+}
+
+/*
+versionFilter matches the users at the given version. Users stored before versions existed have none, which is read
+as version 0.
+*/
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+
+	return version
 }
 
 /*
@@ -251,7 +597,7 @@ func (r *UserRepository) RemoveUser(ctx context.Context, userId string) error {
 /*
 marshalUser converts a domain user entity into its database user model.
 */
-func (r *UserRepository) marshalUser(user *user.User) *UserModel {
+func marshalUser(user *user.User) *UserModel {
 	return &UserModel{
 		Id:        user.Id(),
 		FirstName: user.FirstName(),
@@ -260,7 +606,77 @@ func (r *UserRepository) marshalUser(user *user.User) *UserModel {
 		Password:  user.Password(),
 		Email:     user.Email(),
 		Country:   user.Country(),
+		Roles:     marshalRoles(user.Roles()),
 		CreatedAt: user.CreatedAt(),
 		UpdatedAt: user.UpdatedAt(),
+		Version:   user.Version(),
+	}
+}
+
+/*
+unmarshalUser converts a database user model into its domain user entity.
+*/
+func unmarshalUser(userModel *UserModel) *user.User {
+	return user.UnmarshalUserFromDB(
+		userModel.Id,
+		userModel.FirstName,
+		userModel.LastName,
+		userModel.Nickname,
+		userModel.Password,
+		userModel.Email,
+		userModel.Country,
+		unmarshalRoles(userModel.Roles),
+		userModel.CreatedAt,
+		userModel.UpdatedAt,
+		userModel.Version,
+	)
+}
+
+/*
+mapDuplicateKeyError translates a violation of our unique indexes into the domain error naming the conflicting field.
+
+It returns nil for any other error.
+*/
+func mapDuplicateKeyError(err error, userModel *UserModel) error {
+	index, ok := mongo_utils.DuplicateKeyIndex(err)
+
+	if !ok {
+		return nil
+	}
+
+	switch index {
+	case nicknameIndex:
+		return &user.AlreadyExistsError{Field: "nickname", Value: userModel.Nickname}
+	case emailIndex:
+		return &user.AlreadyExistsError{Field: "email", Value: userModel.Email}
 	}
+
+	return nil
+}
+
+func marshalRoles(roles []user.Role) []string {
+	out := make([]string, len(roles))
+	for i, role := range roles {
+		out[i] = string(role)
+	}
+
+	return out
+}
+
+/*
+unmarshalRoles converts the stored roles into domain roles.
+
+Users stored before roles existed have none, so they get the base user role.
+*/
+func unmarshalRoles(roles []string) []user.Role {
+	if len(roles) == 0 {
+		return []user.Role{user.RoleUser}
+	}
+
+	out := make([]user.Role, len(roles))
+	for i, role := range roles {
+		out[i] = user.Role(role)
+	}
+
+	return out
 }
@@ -0,0 +1,899 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/helper/mongo_helper"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/sql_utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+TestUserRepositoryConformance runs the same suite against every user.UserRepository implementation, so they can
+replace each other.
+
+The MongoDB and Postgres ones need a server, so they only run when 'MONGODB_URI'
+or 'POSTGRES_DSN' are set. Each test gets its own database or schema, which is
+dropped afterwards.
+*/
+func TestUserRepositoryConformance(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepository := range map[string]func(t *testing.T) user.UserRepository{
+		"memory":   newConformanceMemoryRepository,
+		"mongodb":  newConformanceMongoRepository,
+		"sqlite":   newConformanceSQLiteRepository,
+		"postgres": newConformancePostgresRepository,
+	} {
+		newRepository := newRepository
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				testUserRepositoryConformance(t, newRepository)
+			},
+		)
+	}
+}
+
+func newConformanceMemoryRepository(*testing.T) user.UserRepository {
+	return NewMemoryUserRepository()
+}
+
+func newConformanceMongoRepository(t *testing.T) user.UserRepository {
+	mongoUri := os.Getenv("MONGODB_URI")
+
+	if mongoUri == "" {
+		t.Skip("'MONGODB_URI' is not set")
+	}
+
+	ctx := context.Background()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoUri))
+	require.NoError(t, err)
+
+	db := client.Database("conformance_" + uuid.NewString())
+
+	t.Cleanup(
+		func() {
+			_ = db.Drop(ctx)
+			_ = client.Disconnect(ctx)
+		},
+	)
+
+	repo := NewUserRepository(ctx, &mongo_helper.MongoDatabase{Db: db})
+
+	return &repo
+}
+
+func newConformanceSQLiteRepository(t *testing.T) user.UserRepository {
+	return NewSQLUserRepository(openSQLiteDatabase(t), sql_utils.SQLite)
+}
+
+func newConformancePostgresRepository(t *testing.T) user.UserRepository {
+	postgresDsn := os.Getenv("POSTGRES_DSN")
+
+	if postgresDsn == "" {
+		t.Skip("'POSTGRES_DSN' is not set")
+	}
+
+	ctx := context.Background()
+	schema := "conformance_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+	admin, err := sql.Open(sql_utils.Postgres.DriverName(), postgresDsn)
+	require.NoError(t, err)
+
+	_, err = admin.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+
+	db, err := sql.Open(sql_utils.Postgres.DriverName(), withSearchPath(t, postgresDsn, schema))
+	require.NoError(t, err)
+
+	t.Cleanup(
+		func() {
+			_ = db.Close()
+			_, _ = admin.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE")
+			_ = admin.Close()
+		},
+	)
+
+	require.NoError(t, MigrateSQLDatabase(ctx, db, sql_utils.Postgres))
+
+	return NewSQLUserRepository(db, sql_utils.Postgres)
+}
+
+/*
+withSearchPath sets the schema used by the connections of the DSN, either as an URL or as key/value pairs.
+*/
+func withSearchPath(t *testing.T, dsn string, schema string) string {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return dsn + " search_path=" + schema
+	}
+
+	dsnUrl, err := url.Parse(dsn)
+	require.NoError(t, err)
+
+	query := dsnUrl.Query()
+	query.Set("search_path", schema)
+	dsnUrl.RawQuery = query.Encode()
+
+	return dsnUrl.String()
+}
+
+/*
+openSQLiteDatabase creates a migrated SQLite database in a temporary file, which is removed after the test.
+*/
+func openSQLiteDatabase(t *testing.T) *sql.DB {
+	dsn := "file:" + filepath.Join(t.TempDir(), "users.db") + "?_txlock=immediate&_pragma=busy_timeout(5000)"
+
+	db, err := sql.Open(sql_utils.SQLite.DriverName(), dsn)
+	require.NoError(t, err)
+
+	// A single connection, as SQLite is often set up, so nothing can wait on a second one while holding the first.
+	db.SetMaxOpenConns(1)
+
+	t.Cleanup(
+		func() {
+			_ = db.Close()
+		},
+	)
+
+	require.NoError(t, MigrateSQLDatabase(context.Background(), db, sql_utils.SQLite))
+
+	return db
+}
+
+func testUserRepositoryConformance(t *testing.T, newRepository func(t *testing.T) user.UserRepository) {
+	for name, test := range map[string]func(t *testing.T, repo user.UserRepository){
+		"should add and get a user by id":                     testConformanceGetUserById,
+		"should not find an unknown id":                       testConformanceGetUnknownUserById,
+		"should get users by ids":                             testConformanceGetUsersByIds,
+		"should project users got by id":                      testConformanceGetUsersByIdProjected,
+		"should add the users of a batch that don't collide":  testConformanceAddUsers,
+		"should reject duplicated nicknames of any case":      testConformanceDuplicatedNickname,
+		"should reject duplicated emails of any case":         testConformanceDuplicatedEmail,
+		"should get a user by nickname or email":              testConformanceGetUserByNicknameOrEmail,
+		"should get a user by nickname or email of any case":  testConformanceGetUserByMixedCaseNicknameOrEmail,
+		"should get users in insertion order":                 testConformanceGetUsers,
+		"should get many users along with their roles":        testConformanceGetManyUsers,
+		"should filter users":                                 testConformanceGetUsersFiltered,
+		"should filter users by lists, patterns and presence": testConformanceGetUsersFilteredRicher,
+		"should filter users by boolean expressions":          testConformanceGetUsersByExpression,
+		"should apply every filter of a field":                testConformanceGetUsersSameField,
+		"should sort users":                                   testConformanceGetUsersSorted,
+		"should paginate users":                               testConformanceGetUsersPaginated,
+		"should paginate users by keyset":                     testConformanceGetUsersByKeyset,
+		"should project users":                                testConformanceGetUsersProjected,
+		"should return no users when nothing matches":         testConformanceGetNoUsers,
+		"should count the matching users":                     testConformanceCountUsers,
+		"should update a user":                                testConformanceUpdateUser,
+		"should not update an unknown user":                   testConformanceUpdateUnknownUser,
+		"should not update a user into a duplicate":           testConformanceUpdateDuplicatedUser,
+		"should not update a user from a stale version":       testConformanceUpdateStaleUser,
+		"should remove a user":                                testConformanceRemoveUser,
+		"should not remove an unknown user":                   testConformanceRemoveUnknownUser,
+		"should not share the stored users with the caller":   testConformanceCopies,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t, newRepository(t))
+			},
+		)
+	}
+}
+
+var conformanceNow = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+/*
+conformanceUsers are added in this order by seedConformanceUsers. Their times are already rounded to milliseconds, as
+MongoDB stores them.
+*/
+func conformanceUsers() []*user.User {
+	return []*user.User{
+		user.UnmarshalUserFromDB(
+			"1", "John", "Doe", "john", "hash", "john@doe.com", "US", []user.Role{user.RoleUser},
+			conformanceNow, conformanceNow, 1,
+		),
+		user.UnmarshalUserFromDB(
+			"2", "Jane", "Doe", "jane", "hash", "jane@doe.com", "ES", []user.Role{user.RoleUser, user.RoleAdmin},
+			conformanceNow.Add(time.Hour), conformanceNow.Add(time.Hour), 1,
+		),
+		user.UnmarshalUserFromDB(
+			"3", "Alice", "Smith", "alice", "hash", "alice@smith.com", "US", []user.Role{user.RoleUser},
+			conformanceNow.Add(2*time.Hour), conformanceNow.Add(2*time.Hour), 1,
+		),
+	}
+}
+
+func seedConformanceUsers(t *testing.T, repo user.UserRepository) []*user.User {
+	users := conformanceUsers()
+
+	for _, u := range users {
+		require.NoError(t, repo.AddUser(context.Background(), u))
+	}
+
+	return users
+}
+
+/*
+readUsers drains the iterator returned by GetUsers, checking it neither fails nor fails to close.
+*/
+func readUsers(t *testing.T, it user.UserIterator) (users []*user.User) {
+	require.NotNil(t, it)
+
+	for it.Next(context.Background()) {
+		users = append(users, it.User())
+	}
+
+	assert.NoError(t, it.Err())
+	assert.NoError(t, it.Close(context.Background()))
+
+	return users
+}
+
+func userIds(users []*user.User) (ids []string) {
+	for _, u := range users {
+		ids = append(ids, u.Id())
+	}
+
+	return ids
+}
+
+func testConformanceGetUserById(t *testing.T, repo user.UserRepository) {
+	users := seedConformanceUsers(t, repo)
+
+	out, err := repo.GetUserById(context.Background(), "2", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, marshalUser(users[1]), marshalUser(out))
+}
+
+func testConformanceGetUnknownUserById(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	out, err := repo.GetUserById(context.Background(), "4", nil)
+
+	assert.Nil(t, out)
+	assert.Equal(t, &user.NotFoundError{Id: "4"}, err)
+}
+
+func testConformanceGetUsersByIds(t *testing.T, repo user.UserRepository) {
+	users := seedConformanceUsers(t, repo)
+
+	out, err := repo.GetUsersByIds(context.Background(), []string{"3", "4", "1", "3"}, nil)
+
+	// Every stored user is returned once, whatever the order and the repeated ids.
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1", "3"}, userIds(out))
+
+	for _, u := range out {
+		id, _ := strconv.Atoi(u.Id())
+		assert.Equal(t, marshalUser(users[id-1]), marshalUser(u))
+	}
+
+	out, err = repo.GetUsersByIds(context.Background(), nil, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func testConformanceGetUsersByIdProjected(t *testing.T, repo user.UserRepository) {
+	users := seedConformanceUsers(t, repo)
+	projection := []string{"id", "nickname"}
+
+	byId, err := repo.GetUserById(context.Background(), "2", projection)
+	require.NoError(t, err)
+
+	byIds, err := repo.GetUsersByIds(context.Background(), []string{"2"}, projection)
+	require.NoError(t, err)
+	require.Len(t, byIds, 1)
+
+	for _, u := range []*user.User{byId, byIds[0]} {
+		assert.Equal(t, users[1].Id(), u.Id())
+		assert.Equal(t, users[1].Nickname(), u.Nickname())
+		assert.Empty(t, u.FirstName())
+		assert.Empty(t, u.Password())
+		assert.True(t, u.CreatedAt().IsZero())
+	}
+}
+
+func testConformanceAddUsers(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+	newUser := func(id string, nickname string, email string) *user.User {
+		return user.UnmarshalUserFromDB(
+			id, "John", "Doe", nickname, "hash", email, "US", []user.Role{user.RoleUser}, conformanceNow,
+			conformanceNow, 1,
+		)
+	}
+
+	// The second user collides with a stored one, and the fourth one with another one of the batch.
+	batch := []*user.User{
+		newUser("4", "bob", "bob@doe.com"),
+		newUser("5", "JOHN", "other@doe.com"),
+		newUser("6", "carol", "carol@doe.com"),
+		newUser("7", "dave", "Bob@Doe.com"),
+	}
+
+	errs, err := repo.AddUsers(context.Background(), batch)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []error{
+			nil,
+			&user.AlreadyExistsError{Field: "nickname", Value: "JOHN"},
+			nil,
+			&user.AlreadyExistsError{Field: "email", Value: "Bob@Doe.com"},
+		}, errs,
+	)
+
+	out, err := repo.GetUsers(context.Background(), query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3", "4", "6"}, userIds(readUsers(t, out)))
+
+	stored, err := repo.GetUserById(context.Background(), "6", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, marshalUser(batch[2]), marshalUser(stored))
+}
+
+func testConformanceDuplicatedNickname(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	duplicated := user.UnmarshalUserFromDB(
+		"4", "John", "Doe", "JOHN", "hash", "other@doe.com", "US", []user.Role{user.RoleUser},
+		conformanceNow, conformanceNow, 1,
+	)
+
+	err := repo.AddUser(context.Background(), duplicated)
+
+	assert.Equal(t, &user.AlreadyExistsError{Field: "nickname", Value: "JOHN"}, err)
+}
+
+func testConformanceDuplicatedEmail(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	duplicated := user.UnmarshalUserFromDB(
+		"4", "John", "Doe", "other", "hash", "John@Doe.com", "US", []user.Role{user.RoleUser},
+		conformanceNow, conformanceNow, 1,
+	)
+
+	err := repo.AddUser(context.Background(), duplicated)
+
+	assert.Equal(t, &user.AlreadyExistsError{Field: "email", Value: "John@Doe.com"}, err)
+}
+
+func testConformanceGetUserByNicknameOrEmail(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	byNickname, err := repo.GetUserByNicknameOrEmail(context.Background(), "jane")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", byNickname.Id())
+
+	byEmail, err := repo.GetUserByNicknameOrEmail(context.Background(), "alice@smith.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", byEmail.Id())
+
+	_, err = repo.GetUserByNicknameOrEmail(context.Background(), "nobody")
+	assert.Equal(t, &user.NotFoundError{Id: "nobody"}, err)
+}
+
+func testConformanceGetUserByMixedCaseNicknameOrEmail(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	// As with uniqueness, lookups ignore the case, so users can log in however they type it.
+	byNickname, err := repo.GetUserByNicknameOrEmail(context.Background(), "JaNe")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", byNickname.Id())
+
+	byEmail, err := repo.GetUserByNicknameOrEmail(context.Background(), "Alice@Smith.COM")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", byEmail.Id())
+}
+
+func testConformanceGetUsers(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	out, err := repo.GetUsers(context.Background(), query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, userIds(readUsers(t, out)))
+}
+
+func testConformanceGetManyUsers(t *testing.T, repo user.UserRepository) {
+	// Enough users for the SQL repository to read them, and load their roles, in several chunks.
+	const count = 1234
+	users := make([]*user.User, count)
+
+	for i := range users {
+		roles := []user.Role{user.RoleUser}
+		if i%2 == 0 {
+			roles = append(roles, user.RoleAdmin)
+		}
+
+		id := strconv.Itoa(i)
+		users[i] = user.UnmarshalUserFromDB(
+			id, "John", "Doe", "john"+id, "hash", "john"+id+"@doe.com", "US", roles, conformanceNow, conformanceNow, 1,
+		)
+	}
+
+	for start := 0; start < count; start += 500 {
+		end := start + 500
+		if end > count {
+			end = count
+		}
+
+		errs, err := repo.AddUsers(context.Background(), users[start:end])
+		require.NoError(t, err)
+		require.Equal(t, make([]error, end-start), errs)
+	}
+
+	out, err := repo.GetUsers(context.Background(), query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, users, readUsers(t, out))
+}
+
+func testConformanceGetUsersFiltered(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	for _, test := range []struct {
+		filter   query_utils.Filter
+		expected []string
+	}{
+		{query_utils.Filter{Field: "country", Operator: operators.EQUALS, Value: "US"}, []string{"1", "3"}},
+		{query_utils.Filter{Field: "country", Operator: operators.NOT_EQUALS, Value: "US"}, []string{"2"}},
+		{query_utils.Filter{Field: "first_name", Operator: operators.GREATER_THAN, Value: "Jane"}, []string{"1"}},
+		{query_utils.Filter{Field: "first_name", Operator: operators.LESS_THAN_EQ, Value: "Jane"}, []string{"2", "3"}},
+		{
+			query_utils.Filter{Field: "created_at", Operator: operators.GREATER_THAN_EQ, Value: conformanceNow.Add(time.Hour)},
+			[]string{"2", "3"},
+		},
+		{query_utils.Filter{Field: "roles", Operator: operators.EQUALS, Value: "admin"}, []string{"2"}},
+		{query_utils.Filter{Field: "roles", Operator: operators.NOT_EQUALS, Value: "admin"}, []string{"1", "3"}},
+		{query_utils.Filter{Field: "country", Operator: operators.EQUALS, Value: int64(1)}, nil},
+		{query_utils.Filter{Field: "unknown", Operator: operators.EQUALS, Value: "US"}, nil},
+		{query_utils.Filter{Field: "unknown", Operator: operators.NOT_EQUALS, Value: "US"}, []string{"1", "2", "3"}},
+	} {
+		out, err := repo.GetUsers(
+			context.Background(), query_utils.FilterExpression{Filters: []query_utils.Filter{test.filter}}, nil,
+			query_utils.Pagination{}, nil,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, userIds(readUsers(t, out)), test.filter)
+	}
+}
+
+func testConformanceGetUsersFilteredRicher(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	for _, test := range []struct {
+		filter   query_utils.Filter
+		expected []string
+	}{
+		{query_utils.Filter{Field: "country", Operator: operators.IN, Value: []interface{}{"ES", "FR"}}, []string{"2"}},
+		{query_utils.Filter{Field: "country", Operator: operators.NOT_IN, Value: []interface{}{"ES"}}, []string{"1", "3"}},
+		{query_utils.Filter{Field: "country", Operator: operators.IN, Value: []interface{}{int64(1)}}, nil},
+		{query_utils.Filter{Field: "roles", Operator: operators.IN, Value: []interface{}{"admin", "root"}}, []string{"2"}},
+		{query_utils.Filter{Field: "roles", Operator: operators.NOT_IN, Value: []interface{}{"admin"}}, []string{"1", "3"}},
+		{query_utils.Filter{Field: "unknown", Operator: operators.IN, Value: []interface{}{nil}}, []string{"1", "2", "3"}},
+		{query_utils.Filter{Field: "nickname", Operator: operators.STARTS_WITH, Value: "ja"}, []string{"2"}},
+		{query_utils.Filter{Field: "nickname", Operator: operators.STARTS_WITH, Value: "J"}, nil},
+		{query_utils.Filter{Field: "email", Operator: operators.CONTAINS, Value: "@DOE."}, []string{"1", "2"}},
+		{query_utils.Filter{Field: "email", Operator: operators.CONTAINS, Value: "doe.*"}, nil},
+		{query_utils.Filter{Field: "last_name", Operator: operators.REGEX, Value: "^(Do|Sm)"}, []string{"1", "2", "3"}},
+		{query_utils.Filter{Field: "roles", Operator: operators.REGEX, Value: "^adm"}, []string{"2"}},
+		{query_utils.Filter{Field: "created_at", Operator: operators.REGEX, Value: "2022"}, nil},
+		{query_utils.Filter{Field: "country", Operator: operators.EXISTS, Value: true}, []string{"1", "2", "3"}},
+		{query_utils.Filter{Field: "unknown", Operator: operators.EXISTS, Value: true}, nil},
+		{query_utils.Filter{Field: "unknown", Operator: operators.EXISTS, Value: false}, []string{"1", "2", "3"}},
+	} {
+		out, err := repo.GetUsers(
+			context.Background(), query_utils.FilterExpression{Filters: []query_utils.Filter{test.filter}}, nil,
+			query_utils.Pagination{}, nil,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, userIds(readUsers(t, out)), test.filter)
+	}
+}
+
+func testConformanceGetUsersByExpression(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	isAdmin := query_utils.Filter{Field: "roles", Operator: operators.EQUALS, Value: "admin"}
+	isJohn := query_utils.Filter{Field: "nickname", Operator: operators.EQUALS, Value: "john"}
+	fromUS := query_utils.Filter{Field: "country", Operator: operators.EQUALS, Value: "US"}
+
+	for _, test := range []struct {
+		name       string
+		expression query_utils.FilterExpression
+		expected   []string
+	}{
+		{
+			"admin OR john",
+			query_utils.FilterExpression{Operator: operators.OR, Filters: []query_utils.Filter{isAdmin, isJohn}},
+			[]string{"1", "2"},
+		},
+		{
+			"NOT (admin OR john)",
+			query_utils.FilterExpression{Operator: operators.NOT, Filters: []query_utils.Filter{isAdmin, isJohn}},
+			[]string{"3"},
+		},
+		{
+			"from US AND NOT john",
+			query_utils.FilterExpression{
+				Filters: []query_utils.Filter{fromUS},
+				Expressions: []query_utils.FilterExpression{
+					{Operator: operators.NOT, Filters: []query_utils.Filter{isJohn}},
+				},
+			},
+			[]string{"3"},
+		},
+		{
+			"admin OR (from US AND NOT (john))",
+			query_utils.FilterExpression{
+				Operator: operators.OR,
+				Filters:  []query_utils.Filter{isAdmin},
+				Expressions: []query_utils.FilterExpression{
+					{
+						Filters: []query_utils.Filter{fromUS},
+						Expressions: []query_utils.FilterExpression{
+							{Operator: operators.NOT, Filters: []query_utils.Filter{isJohn}},
+						},
+					},
+				},
+			},
+			[]string{"2", "3"},
+		},
+		{"empty OR", query_utils.FilterExpression{Operator: operators.OR}, nil},
+		{"empty NOT", query_utils.FilterExpression{Operator: operators.NOT}, []string{"1", "2", "3"}},
+		{
+			"unknown field OR john",
+			query_utils.FilterExpression{
+				Operator: operators.OR,
+				Filters: []query_utils.Filter{
+					{Field: "unknown", Operator: operators.EQUALS, Value: "US"}, isJohn,
+				},
+			},
+			[]string{"1"},
+		},
+	} {
+		out, err := repo.GetUsers(context.Background(), test.expression, nil, query_utils.Pagination{}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, userIds(readUsers(t, out)), test.name)
+	}
+}
+
+func testConformanceGetUsersSameField(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	for _, test := range []struct {
+		filters  []query_utils.Filter
+		expected []string
+	}{
+		{
+			[]query_utils.Filter{
+				{Field: "created_at", Operator: operators.GREATER_THAN_EQ, Value: conformanceNow.Add(time.Hour)},
+				{Field: "created_at", Operator: operators.LESS_THAN, Value: conformanceNow.Add(2 * time.Hour)},
+			},
+			[]string{"2"},
+		},
+		{
+			[]query_utils.Filter{
+				{Field: "country", Operator: operators.EQUALS, Value: "ES"},
+				{Field: "country", Operator: operators.EQUALS, Value: "US"},
+			},
+			nil,
+		},
+		{
+			[]query_utils.Filter{
+				{Field: "roles", Operator: operators.EQUALS, Value: "user"},
+				{Field: "roles", Operator: operators.EQUALS, Value: "admin"},
+			},
+			[]string{"2"},
+		},
+	} {
+		out, err := repo.GetUsers(
+			context.Background(), query_utils.FilterExpression{Filters: test.filters}, nil, query_utils.Pagination{}, nil,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, userIds(readUsers(t, out)), test.filters)
+	}
+}
+
+func testConformanceGetUsersSorted(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	for _, test := range []struct {
+		sort     []query_utils.Sort
+		expected []string
+	}{
+		{[]query_utils.Sort{{Field: "first_name", Direction: operators.ASC}}, []string{"3", "2", "1"}},
+		{[]query_utils.Sort{{Field: "created_at", Direction: operators.DESC}}, []string{"3", "2", "1"}},
+		{
+			[]query_utils.Sort{
+				{Field: "country", Direction: operators.DESC},
+				{Field: "nickname", Direction: operators.ASC},
+			},
+			[]string{"3", "1", "2"},
+		},
+	} {
+		out, err := repo.GetUsers(
+			context.Background(), query_utils.FilterExpression{}, test.sort, query_utils.Pagination{}, nil,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, userIds(readUsers(t, out)), test.sort)
+	}
+}
+
+func testConformanceGetUsersPaginated(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	sort := []query_utils.Sort{{Field: "id", Direction: operators.DESC}}
+
+	out, err := repo.GetUsers(
+		context.Background(), query_utils.FilterExpression{}, sort, query_utils.Pagination{Limit: 1, Offset: 1}, nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2"}, userIds(readUsers(t, out)))
+
+	out, err = repo.GetUsers(
+		context.Background(), query_utils.FilterExpression{}, sort, query_utils.Pagination{Limit: 5, Offset: 2}, nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, userIds(readUsers(t, out)))
+}
+
+func testConformanceGetUsersByKeyset(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	for _, test := range []struct {
+		sort     []query_utils.Sort
+		values   func(u *user.User) []interface{}
+		expected []string
+	}{
+		{
+			[]query_utils.Sort{
+				{Field: "country", Direction: operators.DESC},
+				{Field: "id", Direction: operators.ASC},
+			},
+			func(u *user.User) []interface{} { return []interface{}{u.Country(), u.Id()} },
+			[]string{"1", "3", "2"},
+		},
+		{
+			[]query_utils.Sort{
+				{Field: "created_at", Direction: operators.DESC},
+				{Field: "id", Direction: operators.ASC},
+			},
+			func(u *user.User) []interface{} { return []interface{}{u.CreatedAt(), u.Id()} },
+			[]string{"3", "2", "1"},
+		},
+	} {
+		var ids []string
+		filter := query_utils.FilterExpression{}
+
+		for page := 0; page <= len(test.expected); page++ {
+			out, err := repo.GetUsers(context.Background(), filter, test.sort, query_utils.Pagination{Limit: 1}, nil)
+			require.NoError(t, err)
+
+			users := readUsers(t, out)
+
+			if len(users) == 0 {
+				break
+			}
+
+			ids = append(ids, userIds(users)...)
+			filter = query_utils.FilterExpression{
+				Expressions: []query_utils.FilterExpression{
+					query_utils.KeysetFilter(test.sort, test.values(users[0])),
+				},
+			}
+		}
+
+		assert.Equal(t, test.expected, ids, test.sort)
+	}
+}
+
+func testConformanceGetUsersProjected(t *testing.T, repo user.UserRepository) {
+	users := seedConformanceUsers(t, repo)
+
+	out, err := repo.GetUsers(
+		context.Background(), query_utils.FilterExpression{}, nil, query_utils.Pagination{},
+		[]string{"id", "nickname", "created_at"},
+	)
+
+	assert.NoError(t, err)
+
+	projected := readUsers(t, out)
+	require.Len(t, projected, len(users))
+
+	for i, u := range projected {
+		assert.Equal(t, users[i].Id(), u.Id())
+		assert.Equal(t, users[i].Nickname(), u.Nickname())
+		assert.True(t, users[i].CreatedAt().Equal(u.CreatedAt()))
+		assert.Empty(t, u.FirstName())
+		assert.Empty(t, u.Email())
+		assert.Empty(t, u.Password())
+		assert.True(t, u.UpdatedAt().IsZero())
+	}
+}
+
+func testConformanceGetNoUsers(t *testing.T, repo user.UserRepository) {
+	out, err := repo.GetUsers(context.Background(), query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, readUsers(t, out))
+}
+
+func testConformanceCountUsers(t *testing.T, repo user.UserRepository) {
+	count, err := repo.CountUsers(context.Background(), query_utils.FilterExpression{})
+
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+
+	seedConformanceUsers(t, repo)
+
+	for _, test := range []struct {
+		filter   query_utils.FilterExpression
+		expected int64
+	}{
+		{query_utils.FilterExpression{}, 3},
+		{
+			query_utils.FilterExpression{
+				Filters: []query_utils.Filter{{Field: "country", Operator: operators.EQUALS, Value: "US"}},
+			},
+			2,
+		},
+		{
+			query_utils.FilterExpression{
+				Operator: operators.OR,
+				Filters: []query_utils.Filter{
+					{Field: "nickname", Operator: operators.EQUALS, Value: "jane"},
+					{Field: "roles", Operator: operators.EQUALS, Value: "admin"},
+				},
+			},
+			1,
+		},
+		{
+			query_utils.FilterExpression{
+				Filters: []query_utils.Filter{{Field: "country", Operator: operators.EQUALS, Value: "FR"}},
+			},
+			0,
+		},
+	} {
+		count, err := repo.CountUsers(context.Background(), test.filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, count, test.filter)
+	}
+}
+
+func testConformanceUpdateUser(t *testing.T, repo user.UserRepository) {
+	users := seedConformanceUsers(t, repo)
+
+	updated := user.UnmarshalUserFromDB(
+		"2", "Janet", "Doe", "JANE", "hash", "janet@doe.com", "FR", []user.Role{user.RoleUser},
+		users[1].CreatedAt(), conformanceNow.Add(3*time.Hour), users[1].Version(),
+	)
+
+	assert.NoError(t, repo.UpdateUser(context.Background(), updated))
+
+	out, err := repo.GetUserById(context.Background(), "2", nil)
+
+	expected := marshalUser(updated)
+	expected.Version++
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, marshalUser(out))
+}
+
+func testConformanceUpdateStaleUser(t *testing.T, repo user.UserRepository) {
+	users := seedConformanceUsers(t, repo)
+
+	first := user.UnmarshalUserFromDB(
+		"2", "Janet", "Doe", "jane", "hash", "jane@doe.com", "ES", []user.Role{user.RoleUser},
+		users[1].CreatedAt(), conformanceNow.Add(3*time.Hour), users[1].Version(),
+	)
+	second := user.UnmarshalUserFromDB(
+		"2", "Jane", "Smith", "jane", "hash", "jane@doe.com", "ES", []user.Role{user.RoleUser},
+		users[1].CreatedAt(), conformanceNow.Add(4*time.Hour), users[1].Version(),
+	)
+
+	require.NoError(t, repo.UpdateUser(context.Background(), first))
+
+	err := repo.UpdateUser(context.Background(), second)
+
+	assert.Equal(t, &user.VersionConflictError{Id: "2", Version: users[1].Version()}, err)
+
+	out, err := repo.GetUserById(context.Background(), "2", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Janet", out.FirstName())
+	assert.Equal(t, "Doe", out.LastName())
+	assert.Equal(t, users[1].Version()+1, out.Version())
+}
+
+func testConformanceUpdateUnknownUser(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	unknown := user.UnmarshalUserFromDB(
+		"4", "Bob", "Doe", "bob", "hash", "bob@doe.com", "US", []user.Role{user.RoleUser},
+		conformanceNow, conformanceNow, 1,
+	)
+
+	err := repo.UpdateUser(context.Background(), unknown)
+
+	assert.Equal(t, &user.NotFoundError{Id: "4"}, err)
+}
+
+func testConformanceUpdateDuplicatedUser(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	duplicated := user.UnmarshalUserFromDB(
+		"2", "Jane", "Doe", "jane", "hash", "ALICE@smith.com", "ES", []user.Role{user.RoleUser},
+		conformanceNow, conformanceNow, 1,
+	)
+
+	err := repo.UpdateUser(context.Background(), duplicated)
+
+	assert.Equal(t, &user.AlreadyExistsError{Field: "email", Value: "ALICE@smith.com"}, err)
+}
+
+func testConformanceRemoveUser(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	assert.NoError(t, repo.RemoveUser(context.Background(), "2"))
+
+	out, err := repo.GetUsers(context.Background(), query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, userIds(readUsers(t, out)))
+}
+
+func testConformanceRemoveUnknownUser(t *testing.T, repo user.UserRepository) {
+	seedConformanceUsers(t, repo)
+
+	err := repo.RemoveUser(context.Background(), "4")
+
+	assert.Equal(t, &user.NotFoundError{Id: "4"}, err)
+}
+
+func testConformanceCopies(t *testing.T, repo user.UserRepository) {
+	users := seedConformanceUsers(t, repo)
+
+	// Neither the added users nor the retrieved ones are tied to the stored ones.
+	require.NoError(t, users[0].GrantRole(user.RoleAdmin))
+
+	out, err := repo.GetUserById(context.Background(), "1", nil)
+	require.NoError(t, err)
+	require.NoError(t, out.GrantRole(user.RoleAdmin))
+
+	stored, err := repo.GetUserById(context.Background(), "1", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []user.Role{user.RoleUser}, stored.Roles())
+}
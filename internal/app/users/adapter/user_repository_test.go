@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -25,37 +26,81 @@ var marshalledUser = UserModel{
 	Password:  user.User1.Password(),
 	Email:     user.User1.Email(),
 	Country:   user.User1.Country(),
+	Roles:     []string{"user"},
 	CreatedAt: user.User1.CreatedAt(),
 	UpdatedAt: user.User1.UpdatedAt(),
+	Version:   user.User1.Version(),
 }
 
+/*
+updateUserFilter and updateUserSet are what updating User1 sends to MongoDB, bumping its version.
+*/
+var updateUserFilter = bson.M{"id": user.User1.Id(), "version": user.User1.Version()}
+var updateUserSet = func() bson.D {
+	updatedUser := marshalledUser
+	updatedUser.Version++
+
+	return bson.D{{Key: "$set", Value: &updatedUser}}
+}()
+
 func TestUserRepository(t *testing.T) {
 	t.Parallel()
 
 	for name, testGroup := range map[string]map[string]func(t *testing.T){
 		"user repository": {
-			"initialize user repository":                testNewUserRepository,
-			"initialize user repository with no client": testNewUserRepositoryWithNoClient,
+			"initialize user repository":                  testNewUserRepository,
+			"initialize user repository with no client":   testNewUserRepositoryWithNoClient,
+			"initialize user repository with index error": testNewUserRepositoryWithIndexError,
 		},
 		"add user": {
-			"call add user":                  testAddUser,
-			"call create user with db error": testAddUserWithDbError,
+			"call add user":                         testAddUser,
+			"call create user with db error":        testAddUserWithDbError,
+			"call add user with duplicate nickname": testAddUserWithDuplicateNickname,
+		},
+		"add users": {
+			"call add users":                          testAddUsers,
+			"call add users without users":            testAddUsersWithoutUsers,
+			"call add users with write errors":        testAddUsersWithWriteErrors,
+			"call add users with db error":            testAddUsersWithDbError,
+			"call add users with write concern error": testAddUsersWithWriteConcernError,
 		},
 		"get user by id": {
 			"call get user by id":                     testGetUserById,
 			"call get user by id with decode error":   testGetUserByIdWithDecodeError,
 			"call get user by id with empty response": testGetUserByIdWithEmptyResponse,
+			"call get user by id with projection":     testGetUserByIdWithProjection,
+		},
+		"get user by nickname or email": {
+			"call get user by nickname or email":                     testGetUserByNicknameOrEmail,
+			"call get user by nickname or email with decode error":   testGetUserByNicknameOrEmailWithDecodeError,
+			"call get user by nickname or email with empty response": testGetUserByNicknameOrEmailWithEmptyResponse,
 		},
 		"get users": {
 			"call get users":                   testGetUsers,
 			"call get users with no params":    testGetUsersWithNoParams,
 			"call get users with db error":     testGetUsersWithDbError,
 			"call get users with decode error": testGetUsersWithDecodeError,
+			"call get users with cursor error": testGetUsersWithCursorError,
+			"call get users with projection":   testGetUsersWithProjection,
+		},
+		"get users by ids": {
+			"call get users by ids":                   testGetUsersByIds,
+			"call get users by ids without ids":       testGetUsersByIdsWithoutIds,
+			"call get users by ids with db error":     testGetUsersByIdsWithDbError,
+			"call get users by ids with cursor error": testGetUsersByIdsWithCursorError,
+		},
+		"count users": {
+			"call count users":               testCountUsers,
+			"call count users with db error": testCountUsersWithDbError,
 		},
 		"update user": {
-			"call update user":                testUpdateUser,
-			"call update user with not found": testUpdateUserNotFound,
-			"call update user with db error":  testUpdateUserWithDbError,
+			"call update user":                       testUpdateUser,
+			"call update user with not found":        testUpdateUserNotFound,
+			"call update user with db error":         testUpdateUserWithDbError,
+			"call update user with duplicate email":  testUpdateUserWithDuplicateEmail,
+			"call update user with version conflict": testUpdateUserWithVersionConflict,
+			"call update user with count error":      testUpdateUserWithCountError,
+			"call update user without version":       testUpdateUserWithoutVersion,
 		},
 		"remove user": {
 			"call remove user":                testRemoveUser,
@@ -91,9 +136,14 @@ func testNewUserRepository(t *testing.T) {
 	mockDb := new(mocks2.Database)
 	mockCol := new(mocks2.Collection)
 
+	ctx := context.Background()
+
 	mockDb.On("Collection", mock.Anything).Return(mockCol)
+	mockCol.On("CreateIndexes", ctx, userIndexes).Return(nil)
+
+	out := NewUserRepository(ctx, mockDb)
 
-	out := NewUserRepository(mockDb)
+	mockCol.AssertExpectations(t)
 
 	assert.NotNil(t, out)
 	assert.Equal(t, mockCol, out.col)
@@ -102,11 +152,38 @@ func testNewUserRepository(t *testing.T) {
 func testNewUserRepositoryWithNoClient(t *testing.T) {
 	assert.PanicsWithValue(
 		t, "[UserRepository] missing dbClient", func() {
-			NewUserRepository(nil)
+			NewUserRepository(context.Background(), nil)
 		},
 	)
 }
 
+func testNewUserRepositoryWithIndexError(t *testing.T) {
+	mockDb := new(mocks2.Database)
+	mockCol := new(mocks2.Collection)
+
+	ctx := context.Background()
+
+	mockDb.On("Collection", mock.Anything).Return(mockCol)
+	mockCol.On("CreateIndexes", ctx, userIndexes).Return(errors.New("duplicated users"))
+
+	assert.PanicsWithValue(
+		t, "[UserRepository] couldn't create indexes: duplicated users", func() {
+			NewUserRepository(ctx, mockDb)
+		},
+	)
+}
+
+func duplicateKeyError(index string) error {
+	return mongo.WriteException{
+		WriteErrors: []mongo.WriteError{
+			{
+				Code:    11000,
+				Message: "E11000 duplicate key error collection: test.user index: " + index + " dup key: { }",
+			},
+		},
+	}
+}
+
 func testAddUser(t *testing.T) {
 	mockCollection := new(mocks2.Collection)
 	repo := UserRepository{col: mockCollection}
@@ -147,6 +224,134 @@ func testAddUserWithDbError(t *testing.T) {
 	)
 }
 
+func testAddUserWithDuplicateNickname(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	mockCollection.On("InsertOne", ctx, &marshalledUser).Return(nil, duplicateKeyError(nicknameIndex))
+
+	err := repo.AddUser(ctx, &user.User1)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &user.AlreadyExistsError{Field: "nickname", Value: user.User1.Nickname()}, err)
+}
+
+/*
+addUsersDocuments is what adding User1 and Admin1 at once sends to MongoDB.
+*/
+var addUsersDocuments = []interface{}{&marshalledUser, marshalUser(&user.Admin1)}
+
+func testAddUsers(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	mockCollection.On("InsertMany", ctx, addUsersDocuments, options.InsertMany().SetOrdered(false)).Return(
+		[]interface{}{nil, nil}, nil,
+	)
+
+	errs, err := repo.AddUsers(ctx, []*user.User{&user.User1, &user.Admin1})
+
+	mockCollection.AssertNumberOfCalls(t, "InsertMany", 1)
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []error{nil, nil}, errs)
+}
+
+func testAddUsersWithoutUsers(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	errs, err := repo.AddUsers(context.Background(), nil)
+
+	mockCollection.AssertNotCalled(t, "InsertMany", mock.Anything, mock.Anything, mock.Anything)
+
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func testAddUsersWithWriteErrors(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	otherError := mongo.WriteError{Index: 0, Code: 121, Message: "Document failed validation"}
+	bulkErr := mongo.BulkWriteException{
+		WriteErrors: []mongo.BulkWriteError{
+			{WriteError: otherError},
+			{
+				WriteError: mongo.WriteError{
+					Index:   1,
+					Code:    11000,
+					Message: "E11000 duplicate key error collection: test.user index: " + emailIndex + " dup key: { }",
+				},
+			},
+		},
+	}
+
+	mockCollection.On("InsertMany", ctx, addUsersDocuments, options.InsertMany().SetOrdered(false)).Return(
+		nil, bulkErr,
+	)
+
+	errs, err := repo.AddUsers(ctx, []*user.User{&user.User1, &user.Admin1})
+
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []error{
+			&pkgErrors.Unknown{Tag: UserRepoTag, Cause: otherError},
+			&user.AlreadyExistsError{Field: "email", Value: user.Admin1.Email()},
+		}, errs,
+	)
+}
+
+func testAddUsersWithDbError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	dbError := errors.New("db error")
+	mockCollection.On("InsertMany", ctx, addUsersDocuments, options.InsertMany().SetOrdered(false)).Return(
+		nil, dbError,
+	)
+
+	errs, err := repo.AddUsers(ctx, []*user.User{&user.User1, &user.Admin1})
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Nil(t, errs)
+	assert.Equal(t, &pkgErrors.Unknown{Tag: UserRepoTag, Cause: dbError}, err)
+}
+
+func testAddUsersWithWriteConcernError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	bulkErr := mongo.BulkWriteException{
+		WriteConcernError: &mongo.WriteConcernError{Code: 64, Message: "waiting for replication timed out"},
+	}
+	mockCollection.On("InsertMany", ctx, addUsersDocuments, options.InsertMany().SetOrdered(false)).Return(
+		nil, bulkErr,
+	)
+
+	errs, err := repo.AddUsers(ctx, []*user.User{&user.User1, &user.Admin1})
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Nil(t, errs)
+	assert.Equal(t, &pkgErrors.Unknown{Tag: UserRepoTag, Cause: bulkErr}, err)
+}
+
 func testGetUserById(t *testing.T) {
 	mockCollection := new(mocks2.Collection)
 	mockSingleResult := new(mocks2.SingleResult)
@@ -155,14 +360,14 @@ func testGetUserById(t *testing.T) {
 	ctx := context.Background()
 	id := "1234"
 
-	mockCollection.On("FindOne", ctx, bson.M{"id": id}).Return(mockSingleResult, nil)
+	mockCollection.On("FindOne", ctx, bson.M{"id": id}, options.FindOne()).Return(mockSingleResult, nil)
 	mockSingleResult.On("Decode", &UserModel{}).Run(
 		func(args mock.Arguments) {
 			*args.Get(0).(*UserModel) = marshalledUser
 		},
 	).Return(nil)
 
-	out, err := repo.GetUserById(ctx, id)
+	out, err := repo.GetUserById(ctx, id, nil)
 
 	mockSingleResult.AssertNumberOfCalls(t, "Decode", 1)
 	mockCollection.AssertNumberOfCalls(t, "FindOne", 1)
@@ -173,6 +378,26 @@ func testGetUserById(t *testing.T) {
 	assert.Equal(t, &user.User1, out)
 }
 
+func testGetUserByIdWithProjection(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockSingleResult := new(mocks2.SingleResult)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	id := "1234"
+	opts := options.FindOne().SetProjection(bson.M{"nickname": 1})
+
+	mockCollection.On("FindOne", ctx, bson.M{"id": id}, opts).Return(mockSingleResult, nil)
+	mockSingleResult.On("Decode", &UserModel{}).Return(nil)
+
+	_, err := repo.GetUserById(ctx, id, []string{"nickname"})
+
+	mockCollection.AssertExpectations(t)
+	mockSingleResult.AssertExpectations(t)
+
+	assert.NoError(t, err)
+}
+
 func testGetUserByIdWithDecodeError(t *testing.T) {
 	mockCollection := new(mocks2.Collection)
 	mockSingleResult := new(mocks2.SingleResult)
@@ -182,10 +407,10 @@ func testGetUserByIdWithDecodeError(t *testing.T) {
 	id := "1234"
 
 	decodeErr := errors.New("decode error")
-	mockCollection.On("FindOne", ctx, bson.M{"id": id}).Return(mockSingleResult, nil)
+	mockCollection.On("FindOne", ctx, bson.M{"id": id}, options.FindOne()).Return(mockSingleResult, nil)
 	mockSingleResult.On("Decode", &UserModel{}).Return(decodeErr)
 
-	out, err := repo.GetUserById(ctx, id)
+	out, err := repo.GetUserById(ctx, id, nil)
 
 	mockSingleResult.AssertNumberOfCalls(t, "Decode", 1)
 	mockCollection.AssertNumberOfCalls(t, "FindOne", 1)
@@ -209,10 +434,10 @@ func testGetUserByIdWithEmptyResponse(t *testing.T) {
 	ctx := context.Background()
 	id := "1234"
 
-	mockCollection.On("FindOne", ctx, bson.M{"id": id}).Return(mockSingleResult, nil)
+	mockCollection.On("FindOne", ctx, bson.M{"id": id}, options.FindOne()).Return(mockSingleResult, nil)
 	mockSingleResult.On("Decode", &UserModel{}).Return(mongo.ErrNoDocuments)
 
-	out, err := repo.GetUserById(ctx, id)
+	out, err := repo.GetUserById(ctx, id, nil)
 
 	mockSingleResult.AssertNumberOfCalls(t, "Decode", 1)
 	mockCollection.AssertNumberOfCalls(t, "FindOne", 1)
@@ -223,6 +448,91 @@ func testGetUserByIdWithEmptyResponse(t *testing.T) {
 	assert.Nil(t, out)
 }
 
+func testGetUserByNicknameOrEmail(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockSingleResult := new(mocks2.SingleResult)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	nickname := "john-123"
+	filter := bson.M{"$or": bson.A{bson.M{"nickname": nickname}, bson.M{"email": nickname}}}
+
+	mockCollection.On("FindOne", ctx, filter, options.FindOne().SetCollation(caseInsensitiveCollation)).Return(
+		mockSingleResult, nil,
+	)
+	mockSingleResult.On("Decode", &UserModel{}).Run(
+		func(args mock.Arguments) {
+			*args.Get(0).(*UserModel) = marshalledUser
+		},
+	).Return(nil)
+
+	out, err := repo.GetUserByNicknameOrEmail(ctx, nickname)
+
+	mockSingleResult.AssertNumberOfCalls(t, "Decode", 1)
+	mockCollection.AssertNumberOfCalls(t, "FindOne", 1)
+	mockSingleResult.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &user.User1, out)
+}
+
+func testGetUserByNicknameOrEmailWithDecodeError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockSingleResult := new(mocks2.SingleResult)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	email := "me@john.com"
+	filter := bson.M{"$or": bson.A{bson.M{"nickname": email}, bson.M{"email": email}}}
+
+	decodeErr := errors.New("decode error")
+	mockCollection.On("FindOne", ctx, filter, options.FindOne().SetCollation(caseInsensitiveCollation)).Return(
+		mockSingleResult, nil,
+	)
+	mockSingleResult.On("Decode", &UserModel{}).Return(decodeErr)
+
+	out, err := repo.GetUserByNicknameOrEmail(ctx, email)
+
+	mockSingleResult.AssertNumberOfCalls(t, "Decode", 1)
+	mockCollection.AssertNumberOfCalls(t, "FindOne", 1)
+	mockSingleResult.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(
+		t, err, &pkgErrors.Unknown{
+			Tag:   UserRepoTag,
+			Cause: decodeErr,
+		},
+	)
+	assert.Nil(t, out)
+}
+
+func testGetUserByNicknameOrEmailWithEmptyResponse(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockSingleResult := new(mocks2.SingleResult)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	email := "me@john.com"
+	filter := bson.M{"$or": bson.A{bson.M{"nickname": email}, bson.M{"email": email}}}
+
+	mockCollection.On("FindOne", ctx, filter, options.FindOne().SetCollation(caseInsensitiveCollation)).Return(
+		mockSingleResult, nil,
+	)
+	mockSingleResult.On("Decode", &UserModel{}).Return(mongo.ErrNoDocuments)
+
+	out, err := repo.GetUserByNicknameOrEmail(ctx, email)
+
+	mockSingleResult.AssertNumberOfCalls(t, "Decode", 1)
+	mockCollection.AssertNumberOfCalls(t, "FindOne", 1)
+	mockSingleResult.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &user.NotFoundError{Id: email}, err)
+	assert.Nil(t, out)
+}
+
 func testGetUsers(t *testing.T) {
 	mockCollection := new(mocks2.Collection)
 	mockCursor := new(mocks2.Cursor)
@@ -261,17 +571,19 @@ func testGetUsers(t *testing.T) {
 		},
 	).Return(nil).Once()
 	mockCursor.On("Next", ctx).Return(false)
+	mockCursor.On("Err").Return(nil)
+	mockCursor.On("Close", ctx).Return(nil).Once()
+
+	out, err := repo.GetUsers(ctx, query_utils.FilterExpression{Filters: filters}, sort, pagination, nil)
 
-	out, err := repo.GetUsers(ctx, filters, sort, pagination)
+	assert.NoError(t, err)
+	assert.Equal(t, []*user.User{&user.User1}, readUsers(t, out))
 
 	mockCursor.AssertNumberOfCalls(t, "Next", 2)
 	mockCursor.AssertNumberOfCalls(t, "Decode", 1)
 	mockCollection.AssertNumberOfCalls(t, "Find", 1)
 	mockCursor.AssertExpectations(t)
 	mockCollection.AssertExpectations(t)
-
-	assert.NoError(t, err)
-	assert.Equal(t, []*user.User{&user.User1}, out)
 }
 
 func testGetUsersWithNoParams(t *testing.T) {
@@ -296,17 +608,19 @@ func testGetUsersWithNoParams(t *testing.T) {
 		},
 	).Return(nil).Once()
 	mockCursor.On("Next", ctx).Return(false)
+	mockCursor.On("Err").Return(nil)
+	mockCursor.On("Close", ctx).Return(nil).Once()
+
+	out, err := repo.GetUsers(ctx, query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
 
-	out, err := repo.GetUsers(ctx, nil, nil, query_utils.Pagination{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*user.User{&user.User1}, readUsers(t, out))
 
 	mockCursor.AssertNumberOfCalls(t, "Next", 2)
 	mockCursor.AssertNumberOfCalls(t, "Decode", 1)
 	mockCollection.AssertNumberOfCalls(t, "Find", 1)
 	mockCursor.AssertExpectations(t)
 	mockCollection.AssertExpectations(t)
-
-	assert.NoError(t, err)
-	assert.Equal(t, []*user.User{&user.User1}, out)
 }
 
 func testGetUsersWithDbError(t *testing.T) {
@@ -326,7 +640,7 @@ func testGetUsersWithDbError(t *testing.T) {
 	dbError := errors.New("db error")
 	mockCollection.On("Find", ctx, bson.M{}, &findOptions).Return(nil, dbError)
 
-	out, err := repo.GetUsers(ctx, nil, nil, query_utils.Pagination{})
+	out, err := repo.GetUsers(ctx, query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
 
 	mockCursor.AssertNumberOfCalls(t, "Next", 0)
 	mockCursor.AssertNumberOfCalls(t, "Decode", 0)
@@ -361,24 +675,186 @@ func testGetUsersWithDecodeError(t *testing.T) {
 	mockCollection.On("Find", ctx, bson.M{}, &findOptions).Return(mockCursor, nil)
 	mockCursor.On("Next", ctx).Return(true).Once()
 	mockCursor.On("Decode", &UserModel{}).Return(decodeError)
+	mockCursor.On("Close", ctx).Return(nil).Once()
 
-	out, err := repo.GetUsers(ctx, nil, nil, query_utils.Pagination{})
+	out, err := repo.GetUsers(ctx, query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
+	require.NoError(t, err)
+
+	assert.False(t, out.Next(ctx))
+	assert.False(t, out.Next(ctx))
+	assert.Equal(
+		t, out.Err(), &pkgErrors.Unknown{
+			Tag:   UserRepoTag,
+			Cause: decodeError,
+		},
+	)
+	assert.NoError(t, out.Close(ctx))
 
 	mockCursor.AssertNumberOfCalls(t, "Next", 1)
 	mockCursor.AssertNumberOfCalls(t, "Decode", 1)
 	mockCollection.AssertNumberOfCalls(t, "Find", 1)
 	mockCursor.AssertExpectations(t)
 	mockCollection.AssertExpectations(t)
+}
 
-	assert.Equal(
-		t, err, &pkgErrors.Unknown{
-			Tag:   UserRepoTag,
-			Cause: decodeError,
+func testGetUsersWithCursorError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockCursor := new(mocks2.Cursor)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	zero := int64(0)
+
+	findOptions := options.FindOptions{
+		Limit: &zero,
+		Skip:  &zero,
+	}
+
+	cursorError := errors.New("cursor error")
+	closeError := errors.New("close error")
+	mockCollection.On("Find", ctx, bson.M{}, &findOptions).Return(mockCursor, nil)
+	mockCursor.On("Next", ctx).Return(false)
+	mockCursor.On("Err").Return(cursorError)
+	mockCursor.On("Close", ctx).Return(closeError).Once()
+
+	out, err := repo.GetUsers(ctx, query_utils.FilterExpression{}, nil, query_utils.Pagination{}, nil)
+	require.NoError(t, err)
+
+	assert.False(t, out.Next(ctx))
+	assert.Equal(t, out.Err(), &pkgErrors.Unknown{Tag: UserRepoTag, Cause: cursorError})
+	assert.Equal(t, out.Close(ctx), &pkgErrors.Unknown{Tag: UserRepoTag, Cause: closeError})
+
+	mockCursor.AssertNumberOfCalls(t, "Decode", 0)
+	mockCollection.AssertNumberOfCalls(t, "Find", 1)
+	mockCursor.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+}
+
+func testGetUsersByIds(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockCursor := new(mocks2.Cursor)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	ids := []string{user.User1.Id(), "unknown"}
+
+	mockCollection.On("Find", ctx, bson.M{"id": bson.M{"$in": ids}}, options.Find()).Return(mockCursor, nil)
+	mockCursor.On("Next", ctx).Return(true).Once()
+	mockCursor.On("Decode", &UserModel{}).Run(
+		func(args mock.Arguments) {
+			*args.Get(0).(*UserModel) = marshalledUser
 		},
-	)
+	).Return(nil).Once()
+	mockCursor.On("Next", ctx).Return(false)
+	mockCursor.On("Err").Return(nil)
+	mockCursor.On("Close", ctx).Return(nil).Once()
+
+	out, err := repo.GetUsersByIds(ctx, ids, nil)
+
+	mockCursor.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*user.User{&user.User1}, out)
+}
+
+func testGetUsersByIdsWithoutIds(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	out, err := repo.GetUsersByIds(context.Background(), nil, nil)
+
+	mockCollection.AssertNumberOfCalls(t, "Find", 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func testGetUsersByIdsWithDbError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	ids := []string{user.User1.Id()}
+
+	dbError := errors.New("db error")
+	mockCollection.On("Find", ctx, bson.M{"id": bson.M{"$in": ids}}, options.Find()).Return(nil, dbError)
+
+	out, err := repo.GetUsersByIds(ctx, ids, nil)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &pkgErrors.Unknown{Tag: UserRepoTag, Cause: dbError}, err)
 	assert.Nil(t, out)
 }
 
+func testGetUsersByIdsWithCursorError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockCursor := new(mocks2.Cursor)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	ids := []string{user.User1.Id()}
+
+	cursorError := errors.New("cursor error")
+	mockCollection.On("Find", ctx, bson.M{"id": bson.M{"$in": ids}}, options.Find()).Return(mockCursor, nil)
+	mockCursor.On("Next", ctx).Return(false)
+	mockCursor.On("Err").Return(cursorError)
+	mockCursor.On("Close", ctx).Return(nil).Once()
+
+	out, err := repo.GetUsersByIds(ctx, ids, nil)
+
+	// The cursor is closed even when reading it failed.
+	mockCursor.AssertExpectations(t)
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &pkgErrors.Unknown{Tag: UserRepoTag, Cause: cursorError}, err)
+	assert.Nil(t, out)
+}
+
+func testCountUsers(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	filters := []query_utils.Filter{
+		{
+			Field:    "country",
+			Operator: operators.EQUALS,
+			Value:    "ES",
+		},
+	}
+
+	mockCollection.On("CountDocuments", ctx, mongo_utils.MapFilterToBson(filters)).Return(int64(2), nil)
+
+	out, err := repo.CountUsers(ctx, query_utils.FilterExpression{Filters: filters})
+
+	mockCollection.AssertNumberOfCalls(t, "CountDocuments", 1)
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), out)
+}
+
+func testCountUsersWithDbError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	dbError := errors.New("db error")
+	mockCollection.On("CountDocuments", ctx, bson.M{}).Return(int64(0), dbError)
+
+	out, err := repo.CountUsers(ctx, query_utils.FilterExpression{})
+
+	mockCollection.AssertNumberOfCalls(t, "CountDocuments", 1)
+	mockCollection.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, dbError)
+	assert.Zero(t, out)
+}
+
 func testUpdateUser(t *testing.T) {
 	mockCollection := new(mocks2.Collection)
 	repo := UserRepository{col: mockCollection}
@@ -386,7 +862,7 @@ func testUpdateUser(t *testing.T) {
 	ctx := context.Background()
 
 	mockCollection.On(
-		"UpdateOne", ctx, bson.M{"id": user.User1.Id()}, bson.D{{Key: "$set", Value: &marshalledUser}},
+		"UpdateOne", ctx, updateUserFilter, updateUserSet,
 	).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
 
 	err := repo.UpdateUser(ctx, &user.User1)
@@ -404,8 +880,9 @@ func testUpdateUserNotFound(t *testing.T) {
 	ctx := context.Background()
 
 	mockCollection.On(
-		"UpdateOne", ctx, bson.M{"id": user.User1.Id()}, bson.D{{Key: "$set", Value: &marshalledUser}},
+		"UpdateOne", ctx, updateUserFilter, updateUserSet,
 	).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+	mockCollection.On("CountDocuments", ctx, bson.M{"id": user.User1.Id()}).Return(int64(0), nil)
 
 	err := repo.UpdateUser(ctx, &user.User1)
 
@@ -415,6 +892,68 @@ func testUpdateUserNotFound(t *testing.T) {
 	assert.Equal(t, &user.NotFoundError{Id: user.User1.Id()}, err)
 }
 
+func testUpdateUserWithVersionConflict(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	mockCollection.On(
+		"UpdateOne", ctx, updateUserFilter, updateUserSet,
+	).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+	mockCollection.On("CountDocuments", ctx, bson.M{"id": user.User1.Id()}).Return(int64(1), nil)
+
+	err := repo.UpdateUser(ctx, &user.User1)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &user.VersionConflictError{Id: user.User1.Id(), Version: user.User1.Version()}, err)
+}
+
+func testUpdateUserWithCountError(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	dbError := errors.New("db error")
+	mockCollection.On(
+		"UpdateOne", ctx, updateUserFilter, updateUserSet,
+	).Return(&mongo.UpdateResult{MatchedCount: 0}, nil)
+	mockCollection.On("CountDocuments", ctx, bson.M{"id": user.User1.Id()}).Return(int64(0), dbError)
+
+	err := repo.UpdateUser(ctx, &user.User1)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &pkgErrors.Unknown{Tag: UserRepoTag, Cause: dbError}, err)
+}
+
+func testUpdateUserWithoutVersion(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+	legacyUser := user.UnmarshalUserFromDB(
+		user.User1.Id(), user.User1.FirstName(), user.User1.LastName(), user.User1.Nickname(), user.User1.Password(),
+		user.User1.Email(), user.User1.Country(), user.User1.Roles(), user.User1.CreatedAt(), user.User1.UpdatedAt(), 0,
+	)
+	updatedUser := marshalledUser
+	updatedUser.Version = 1
+
+	// Users stored before versions existed have no version field at all.
+	mockCollection.On(
+		"UpdateOne", ctx, bson.M{"id": user.User1.Id(), "version": bson.M{"$in": bson.A{0, nil}}},
+		bson.D{{Key: "$set", Value: &updatedUser}},
+	).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := repo.UpdateUser(ctx, legacyUser)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.NoError(t, err)
+}
+
 func testUpdateUserWithDbError(t *testing.T) {
 	mockCollection := new(mocks2.Collection)
 	repo := UserRepository{col: mockCollection}
@@ -423,7 +962,7 @@ func testUpdateUserWithDbError(t *testing.T) {
 
 	dbError := errors.New("db error")
 	mockCollection.On(
-		"UpdateOne", ctx, bson.M{"id": user.User1.Id()}, bson.D{{Key: "$set", Value: &marshalledUser}},
+		"UpdateOne", ctx, updateUserFilter, updateUserSet,
 	).Return(
 		nil, dbError,
 	)
@@ -441,6 +980,25 @@ func testUpdateUserWithDbError(t *testing.T) {
 	)
 }
 
+func testUpdateUserWithDuplicateEmail(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	mockCollection.On(
+		"UpdateOne", ctx, updateUserFilter, updateUserSet,
+	).Return(
+		nil, duplicateKeyError(emailIndex),
+	)
+
+	err := repo.UpdateUser(ctx, &user.User1)
+
+	mockCollection.AssertExpectations(t)
+
+	assert.Equal(t, &user.AlreadyExistsError{Field: "email", Value: user.User1.Email()}, err)
+}
+
 func testRemoveUser(t *testing.T) {
 	mockCollection := new(mocks2.Collection)
 	repo := UserRepository{col: mockCollection}
@@ -496,9 +1054,38 @@ func testRemoveUserWithDbError(t *testing.T) {
 }
 
 func testMarshalUser(t *testing.T) {
-	repo := UserRepository{}
-
-	out := repo.marshalUser(&user.User1)
+	out := marshalUser(&user.User1)
 
 	assert.Equal(t, &marshalledUser, out)
 }
+
+func testGetUsersWithProjection(t *testing.T) {
+	mockCollection := new(mocks2.Collection)
+	mockCursor := new(mocks2.Cursor)
+	repo := UserRepository{col: mockCollection}
+
+	ctx := context.Background()
+
+	zero := int64(0)
+
+	findOptions := options.FindOptions{
+		Limit:      &zero,
+		Skip:       &zero,
+		Projection: bson.M{"id": 1, "nickname": 1},
+	}
+
+	mockCollection.On("Find", ctx, bson.M{}, &findOptions).Return(mockCursor, nil)
+	mockCursor.On("Next", ctx).Return(false)
+	mockCursor.On("Err").Return(nil)
+	mockCursor.On("Close", ctx).Return(nil).Once()
+
+	out, err := repo.GetUsers(
+		ctx, query_utils.FilterExpression{}, nil, query_utils.Pagination{}, []string{"id", "nickname"},
+	)
+
+	assert.NoError(t, err)
+	assert.Empty(t, readUsers(t, out))
+
+	mockCollection.AssertExpectations(t)
+	mockCursor.AssertExpectations(t)
+}
@@ -14,9 +14,23 @@ type Commands struct {
 	CreateUser command.ICreateUserHandler
 	RemoveUser command.IRemoveUserHandler
 	UpdateUser command.IUpdateUserHandler
+
+	Login         command.ILoginHandler
+	RefreshTokens command.IRefreshTokensHandler
+	RevokeToken   command.IRevokeTokenHandler
+
+	GrantRole  command.IGrantRoleHandler
+	RevokeRole command.IRevokeRoleHandler
+
+	ImportUsers command.IImportUsersHandler
 }
 
 type Queries struct {
-	GetUsers    query.IGetUsersHandler
-	GetUserById query.IGetUserByIdHandler
+	GetUsers      query.IGetUsersHandler
+	CountUsers    query.ICountUsersHandler
+	GetUserById   query.IGetUserByIdHandler
+	GetUsersByIds query.IGetUsersByIdsHandler
+	Authenticate  query.IAuthenticateHandler
+
+	GetSigningKeys query.IGetSigningKeysHandler
 }
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+)
+
+/*
+getActor retrieves the user performing a command.
+
+The actor comes from an already verified access token, but the user may have
+been removed since it was issued. In that case, they're no longer allowed to do
+anything.
+*/
+func getActor(ctx context.Context, userRepo user.UserRepository, actorId string, targetId string) (*user.User, error) {
+	actor, err := userRepo.GetUserById(ctx, actorId, nil)
+
+	if err != nil {
+		if errors.As(err, new(*user.NotFoundError)) {
+			return nil, &user.ForbiddenError{ActorId: actorId, UserId: targetId}
+		}
+
+		return nil, err
+	}
+
+	return actor, nil
+}
+
+/*
+getAdminActor retrieves the user performing a command only admins can perform.
+*/
+func getAdminActor(ctx context.Context, userRepo user.UserRepository, actorId string, targetId string) (
+	*user.User, error,
+) {
+	actor, err := getActor(ctx, userRepo, actorId, targetId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !actor.IsAdmin() {
+		return nil, &user.ForbiddenError{ActorId: actorId, UserId: targetId}
+	}
+
+	return actor, nil
+}
+
+/*
+authorizeActor checks that the user performing a command is allowed to modify the target user.
+*/
+func authorizeActor(ctx context.Context, userRepo user.UserRepository, actorId string, target *user.User) error {
+	actor := target
+
+	if actorId != target.Id() {
+		var err error
+		if actor, err = getActor(ctx, userRepo, actorId, target.Id()); err != nil {
+			return err
+		}
+	}
+
+	return actor.CanModify(target)
+}
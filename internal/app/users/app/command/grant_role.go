@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The GrantRole command gives a role to a user. Only admins can grant roles.
+*/
+type GrantRole struct {
+	ActorId string
+	UserId  string
+	Role    string
+}
+
+type IGrantRoleHandler interface {
+	Handle(ctx context.Context, cmd GrantRole) error
+}
+
+type GrantRoleHandler struct {
+	userRepo user.UserRepository
+}
+
+const grantRoleTag = "command/grant_role"
+
+func NewGrantRoleHandler(userRepo user.UserRepository) *GrantRoleHandler {
+	if userRepo == nil {
+		panic("[command/grant_role] nil userRepo")
+	}
+
+	return &GrantRoleHandler{userRepo}
+}
+
+func (h *GrantRoleHandler) Handle(ctx context.Context, cmd GrantRole) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag": grantRoleTag,
+			"cmd": cmd,
+		},
+	).Debug("Granting role")
+
+	if _, err := getAdminActor(ctx, h.userRepo, cmd.ActorId, cmd.UserId); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": grantRoleTag,
+				"cmd": cmd,
+			},
+		).WithError(err).Info("Actor not allowed to grant roles")
+
+		return err
+	}
+
+	role, err := user.ParseRole(cmd.Role)
+
+	if err != nil {
+		return err
+	}
+
+	targetUser, err := h.userRepo.GetUserById(ctx, cmd.UserId, nil)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": grantRoleTag,
+				"cmd": cmd,
+			},
+		).WithError(err).Error("Error getting user to grant role")
+
+		return err
+	}
+
+	if err := targetUser.GrantRole(role); err != nil {
+		return err
+	}
+
+	if err := h.userRepo.UpdateUser(ctx, targetUser); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": grantRoleTag,
+				"cmd": cmd,
+			},
+		).WithError(err).Error("Error saving granted role")
+
+		return err
+	}
+
+	return nil
+}
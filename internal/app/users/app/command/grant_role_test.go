@@ -0,0 +1,149 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestGrantRole(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize grant role handler":                   testNewGrantRoleHandler,
+		"initialize grant role handler without repo":      testNewGrantRoleHandlerWithoutRepo,
+		"handle grant role command":                       testHandleGrantRole,
+		"handle grant role command as regular user":       testHandleGrantRoleAsRegularUser,
+		"handle grant role command with unknown role":     testHandleGrantRoleWithUnknownRole,
+		"handle grant role command with nonexistent user": testHandleGrantRoleWithNonexistentUser,
+		"handle grant role command with repo error":       testHandleGrantRoleWithRepoError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testNewGrantRoleHandler(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+
+	newHandler := NewGrantRoleHandler(mockRepo)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &GrantRoleHandler{mockRepo}, newHandler)
+}
+
+func testNewGrantRoleHandlerWithoutRepo(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[command/grant_role] nil userRepo", func() {
+			NewGrantRoleHandler(nil)
+		},
+	)
+}
+
+func testHandleGrantRole(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GrantRoleHandler{mockRepo}
+
+	ctx := context.Background()
+	targetUser := user.User1
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("GetUserById", ctx, targetUser.Id(), []string(nil)).Return(&targetUser, nil)
+	mockRepo.On(
+		"UpdateUser", ctx, mock.MatchedBy(
+			func(_user *user.User) bool {
+				return _user.Id() == targetUser.Id() && _user.HasRole(user.RoleModerator)
+			},
+		),
+	).Return(nil)
+
+	err := handler.Handle(ctx, GrantRole{ActorId: user.Admin1.Id(), UserId: targetUser.Id(), Role: "moderator"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 1)
+
+	assert.NoError(t, err)
+}
+
+func testHandleGrantRoleAsRegularUser(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GrantRoleHandler{mockRepo}
+
+	ctx := context.Background()
+	actorId := user.User1.Id()
+
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(&user.User1, nil)
+
+	err := handler.Handle(ctx, GrantRole{ActorId: actorId, UserId: actorId, Role: "admin"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+
+	assert.Equal(t, &user.ForbiddenError{ActorId: actorId, UserId: actorId}, err)
+}
+
+func testHandleGrantRoleWithUnknownRole(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GrantRoleHandler{mockRepo}
+
+	ctx := context.Background()
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+
+	err := handler.Handle(ctx, GrantRole{ActorId: user.Admin1.Id(), UserId: user.User1.Id(), Role: "superuser"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{Domain: "User", Field: "role", Value: "superuser", Reason: pkgErrors.ReasonUnknownValue},
+		err,
+	)
+}
+
+func testHandleGrantRoleWithNonexistentUser(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GrantRoleHandler{mockRepo}
+
+	ctx := context.Background()
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("GetUserById", ctx, "nonexistent", []string(nil)).Return(nil, &user.NotFoundError{Id: "nonexistent"})
+
+	err := handler.Handle(ctx, GrantRole{ActorId: user.Admin1.Id(), UserId: "nonexistent", Role: "moderator"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+
+	assert.Equal(t, &user.NotFoundError{Id: "nonexistent"}, err)
+}
+
+func testHandleGrantRoleWithRepoError(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GrantRoleHandler{mockRepo}
+
+	ctx := context.Background()
+	targetUser := user.User1
+
+	dbErr := errors.New("db is down")
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("GetUserById", ctx, targetUser.Id(), []string(nil)).Return(&targetUser, nil)
+	mockRepo.On("UpdateUser", ctx, mock.Anything).Return(dbErr)
+
+	err := handler.Handle(ctx, GrantRole{ActorId: user.Admin1.Id(), UserId: targetUser.Id(), Role: "moderator"})
+
+	mockRepo.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, dbErr)
+}
@@ -0,0 +1,226 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"runtime"
+	"sync"
+)
+
+/*
+MaxImportBatchSize is the most users an ImportUsers command can take, as they're all inserted at once.
+*/
+const MaxImportBatchSize = 500
+
+/*
+The ImportUsers command registers a batch of users coming from another platform. Only admins can import users.
+
+Every user is validated and inserted on its own, so the ones that fail don't
+stop the rest. Their results come back in the same order, with the generated
+id of the imported users, or the error of the others.
+*/
+type ImportUsers struct {
+	ActorId string
+	Users   []ImportedUser
+}
+
+/*
+ImportedUser holds either the plaintext password of the user, which is hashed as CreateUser does, or its bcrypt hash,
+which is stored as it is. The hash is used when both are given.
+*/
+type ImportedUser struct {
+	FirstName    string
+	LastName     string
+	Nickname     string
+	Password     string
+	PasswordHash string
+	Email        string
+	Country      string
+}
+
+type ImportUserResult struct {
+	Id  string
+	Err error
+}
+
+type IImportUsersHandler interface {
+	Handle(ctx context.Context, cmd ImportUsers) ([]ImportUserResult, error)
+}
+
+type ImportUsersHandler struct {
+	userRepo user.UserRepository
+}
+
+const importUsersTag = "command/import_users"
+
+func NewImportUsersHandler(userRepo user.UserRepository) *ImportUsersHandler {
+	if userRepo == nil {
+		panic("[command/import_users] nil userRepo")
+	}
+
+	return &ImportUsersHandler{userRepo}
+}
+
+/*
+Handle creates every user before inserting them all at once. Hashing plaintext passwords is what takes the longest, so
+the users are created in parallel, as many at a time as CPUs there are.
+*/
+func (h *ImportUsersHandler) Handle(ctx context.Context, cmd ImportUsers) ([]ImportUserResult, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":     importUsersTag,
+			"actorId": cmd.ActorId,
+			"users":   len(cmd.Users),
+		},
+	).Debug("Importing users")
+
+	if _, err := getAdminActor(ctx, h.userRepo, cmd.ActorId, ""); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     importUsersTag,
+				"actorId": cmd.ActorId,
+			},
+		).WithError(err).Info("Actor not allowed to import users")
+
+		return nil, err
+	}
+
+	if len(cmd.Users) > MaxImportBatchSize {
+		return nil, &errors.InvalidField{
+			Domain: "User",
+			Field:  "users",
+			Value:  len(cmd.Users),
+			Reason: errors.ReasonTooLong,
+		}
+	}
+
+	results := make([]ImportUserResult, len(cmd.Users))
+	newUsers, err := createImportedUsers(ctx, cmd.Users, results)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":   importUsersTag,
+				"users": len(cmd.Users),
+			},
+		).WithError(err).Info("Import cancelled while creating users")
+
+		return nil, &errors.Unknown{Tag: importUsersTag, Cause: err}
+	}
+
+	var batch []*user.User
+	var batchIndexes []int
+
+	for i, newUser := range newUsers {
+		if newUser != nil {
+			batch = append(batch, newUser)
+			batchIndexes = append(batchIndexes, i)
+		}
+	}
+
+	if len(batch) == 0 {
+		return results, nil
+	}
+
+	errs, err := h.userRepo.AddUsers(ctx, batch)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":   importUsersTag,
+				"users": len(batch),
+			},
+		).WithError(err).Error("Error calling repo AddUsers")
+
+		return nil, err
+	}
+
+	for i, index := range batchIndexes {
+		if errs[i] != nil {
+			results[index].Err = errs[i]
+			continue
+		}
+
+		results[index].Id = batch[i].Id()
+	}
+
+	return results, nil
+}
+
+/*
+createImportedUsers creates the domain users, leaving them nil for the ones that fail, whose error is set in their
+result.
+
+Hashing a batch takes minutes of CPU, so no more users are created once the
+context is done, and its error is returned instead.
+*/
+func createImportedUsers(
+	ctx context.Context, importedUsers []ImportedUser, results []ImportUserResult,
+) ([]*user.User, error) {
+	newUsers := make([]*user.User, len(importedUsers))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+
+schedule:
+	for i, importedUser := range importedUsers {
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break schedule
+		}
+
+		wg.Add(1)
+
+		go func(i int, importedUser ImportedUser) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			newUser, err := importedUser.create(uuid.NewString())
+
+			if err != nil {
+				logrus.WithFields(
+					logrus.Fields{
+						"tag":      importUsersTag,
+						"index":    i,
+						"nickname": importedUser.Nickname,
+					},
+				).WithError(err).Info("Error creating imported user")
+
+				results[i].Err = err
+
+				return
+			}
+
+			newUsers[i] = newUser
+		}(i, importedUser)
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return newUsers, nil
+}
+
+func (u ImportedUser) create(id string) (*user.User, error) {
+	if u.PasswordHash != "" {
+		return user.CreateUserWithPasswordHash(
+			id, u.FirstName, u.LastName, u.Nickname, u.PasswordHash, u.Email, u.Country,
+		)
+	}
+
+	return user.CreateUser(id, u.FirstName, u.LastName, u.Nickname, u.Password, u.Email, u.Country)
+}
@@ -0,0 +1,256 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+const importedPasswordHash = "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+
+func TestImportUsers(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize import users handler":                  testNewImportUsersHandler,
+		"initialize import users handler without repo":     testNewImportUsersHandlerWithoutRepo,
+		"handle import users command":                      testHandleImportUsers,
+		"handle import users command with invalid users":   testHandleImportUsersWithInvalidUsers,
+		"handle import users command with duplicate users": testHandleImportUsersWithDuplicateUsers,
+		"handle import users command as regular user":      testHandleImportUsersAsRegularUser,
+		"handle import users command with too many users":  testHandleImportUsersWithTooManyUsers,
+		"handle import users command with repo error":      testHandleImportUsersWithRepoError,
+		"handle import users command once cancelled":       testHandleImportUsersOnceCancelled,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func importedUser(nickname string) ImportedUser {
+	return ImportedUser{
+		FirstName:    "John",
+		LastName:     "Doe",
+		Nickname:     nickname,
+		PasswordHash: importedPasswordHash,
+		Email:        nickname + "@john.com",
+		Country:      "US",
+	}
+}
+
+func testNewImportUsersHandler(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+
+	newHandler := NewImportUsersHandler(mockRepo)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &ImportUsersHandler{mockRepo}, newHandler)
+}
+
+func testNewImportUsersHandlerWithoutRepo(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[command/import_users] nil userRepo", func() {
+			NewImportUsersHandler(nil)
+		},
+	)
+}
+
+func testHandleImportUsers(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := ImportUsersHandler{mockRepo}
+
+	ctx := context.Background()
+	withPassword := importedUser("jane")
+	withPassword.PasswordHash = ""
+	withPassword.Password = "password"
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On(
+		"AddUsers", ctx, mock.MatchedBy(
+			func(users []*user.User) bool {
+				return len(users) == 2 && users[0].Nickname() == "john" &&
+					users[0].Password() == importedPasswordHash && users[1].Nickname() == "jane" &&
+					users[1].Authenticate("password") == nil
+			},
+		),
+	).Return([]error{nil, nil}, nil)
+
+	out, err := handler.Handle(
+		ctx, ImportUsers{ActorId: user.Admin1.Id(), Users: []ImportedUser{importedUser("john"), withPassword}},
+	)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "AddUsers", 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+
+	for _, result := range out {
+		assert.NotEmpty(t, result.Id)
+		assert.NoError(t, result.Err)
+	}
+
+	assert.NotEqual(t, out[0].Id, out[1].Id)
+}
+
+func testHandleImportUsersWithInvalidUsers(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := ImportUsersHandler{mockRepo}
+
+	ctx := context.Background()
+	withoutPassword := importedUser("jane")
+	withoutPassword.PasswordHash = ""
+	withInvalidHash := importedUser("alice")
+	withInvalidHash.PasswordHash = "password"
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On(
+		"AddUsers", ctx, mock.MatchedBy(
+			func(users []*user.User) bool {
+				return len(users) == 1 && users[0].Nickname() == "john"
+			},
+		),
+	).Return([]error{nil}, nil)
+
+	out, err := handler.Handle(
+		ctx, ImportUsers{
+			ActorId: user.Admin1.Id(),
+			Users:   []ImportedUser{withoutPassword, importedUser("john"), withInvalidHash},
+		},
+	)
+
+	mockRepo.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 3)
+	assert.Equal(
+		t, ImportUserResult{
+			Err: &pkgErrors.InvalidField{Domain: "User", Field: "password", Reason: pkgErrors.ReasonRequired},
+		}, out[0],
+	)
+	assert.NotEmpty(t, out[1].Id)
+	assert.NoError(t, out[1].Err)
+	assert.Equal(
+		t, ImportUserResult{
+			Err: &pkgErrors.InvalidField{
+				Domain: "User", Field: "password_hash", Reason: pkgErrors.ReasonInvalidFormat,
+			},
+		}, out[2],
+	)
+}
+
+func testHandleImportUsersWithDuplicateUsers(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := ImportUsersHandler{mockRepo}
+
+	ctx := context.Background()
+	existsErr := &user.AlreadyExistsError{Field: "nickname", Value: "john"}
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("AddUsers", ctx, mock.Anything).Return([]error{existsErr, nil}, nil)
+
+	out, err := handler.Handle(
+		ctx, ImportUsers{
+			ActorId: user.Admin1.Id(),
+			Users:   []ImportedUser{importedUser("john"), importedUser("jane")},
+		},
+	)
+
+	mockRepo.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ImportUserResult{Err: existsErr}, out[0])
+	assert.NotEmpty(t, out[1].Id)
+	assert.NoError(t, out[1].Err)
+}
+
+func testHandleImportUsersAsRegularUser(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := ImportUsersHandler{mockRepo}
+
+	ctx := context.Background()
+	actorId := user.User1.Id()
+
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(&user.User1, nil)
+
+	out, err := handler.Handle(ctx, ImportUsers{ActorId: actorId, Users: []ImportedUser{importedUser("john")}})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "AddUsers", 0)
+
+	assert.Nil(t, out)
+	assert.Equal(t, &user.ForbiddenError{ActorId: actorId}, err)
+}
+
+func testHandleImportUsersWithTooManyUsers(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := ImportUsersHandler{mockRepo}
+
+	ctx := context.Background()
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+
+	out, err := handler.Handle(
+		ctx, ImportUsers{ActorId: user.Admin1.Id(), Users: make([]ImportedUser, MaxImportBatchSize+1)},
+	)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "AddUsers", 0)
+
+	assert.Nil(t, out)
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User", Field: "users", Value: MaxImportBatchSize + 1, Reason: pkgErrors.ReasonTooLong,
+		}, err,
+	)
+}
+
+func testHandleImportUsersWithRepoError(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := ImportUsersHandler{mockRepo}
+
+	ctx := context.Background()
+	dbErr := &pkgErrors.Unknown{Tag: "UserRepository", Cause: errors.New("db is down")}
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("AddUsers", ctx, mock.Anything).Return(nil, dbErr)
+
+	out, err := handler.Handle(ctx, ImportUsers{ActorId: user.Admin1.Id(), Users: []ImportedUser{importedUser("john")}})
+
+	mockRepo.AssertExpectations(t)
+
+	assert.Nil(t, out)
+	assert.Equal(t, dbErr, err)
+}
+
+func testHandleImportUsersOnceCancelled(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := ImportUsersHandler{mockRepo}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+
+	users := []ImportedUser{importedUser("john"), importedUser("jane")}
+	users[1].Password, users[1].PasswordHash = "secret-passphrase", ""
+
+	out, err := handler.Handle(ctx, ImportUsers{ActorId: user.Admin1.Id(), Users: users})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "AddUsers", 0)
+
+	assert.Nil(t, out)
+	assert.Equal(t, &pkgErrors.Unknown{Tag: importUsersTag, Cause: context.Canceled}, err)
+}
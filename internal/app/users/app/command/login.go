@@ -0,0 +1,92 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The Login command checks a user's credentials and issues a new pair of access and refresh tokens.
+
+Each login starts a new refresh token family.
+*/
+type Login struct {
+	NicknameOrEmail string
+	Password        string
+}
+
+type ILoginHandler interface {
+	Handle(ctx context.Context, cmd Login) (*Tokens, error)
+}
+
+type LoginHandler struct {
+	userRepo user.UserRepository
+	tokenIssuer
+}
+
+const loginTag = "command/login"
+
+func NewLoginHandler(
+	userRepo user.UserRepository, tokenRepo token.RefreshTokenRepository, keySet *auth.KeySet, config TokenConfig,
+) *LoginHandler {
+	if userRepo == nil {
+		panic("[command/login] nil userRepo")
+	}
+
+	if tokenRepo == nil {
+		panic("[command/login] nil tokenRepo")
+	}
+
+	if keySet == nil {
+		panic("[command/login] nil keySet")
+	}
+
+	return &LoginHandler{userRepo, tokenIssuer{keySet, tokenRepo, config}}
+}
+
+func (h *LoginHandler) Handle(ctx context.Context, cmd Login) (*Tokens, error) {
+	// The password is left out of the logs on purpose.
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":             loginTag,
+			"nicknameOrEmail": cmd.NicknameOrEmail,
+		},
+	).Debug("Logging user in")
+
+	loginUser, err := user.AuthenticateByNicknameOrEmail(ctx, h.userRepo, cmd.NicknameOrEmail, cmd.Password)
+
+	if err != nil {
+		if errors.As(err, new(*user.InvalidCredentialsError)) {
+			return nil, err
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":             loginTag,
+				"nicknameOrEmail": cmd.NicknameOrEmail,
+			},
+		).WithError(err).Error("Error getting user to log in")
+
+		return nil, err
+	}
+
+	tokens, err := h.issue(ctx, loginUser, uuid.NewString())
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":    loginTag,
+				"userId": loginUser.Id(),
+			},
+		).WithError(err).Error("Error issuing tokens")
+
+		return nil, err
+	}
+
+	return tokens, nil
+}
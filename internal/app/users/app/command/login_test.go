@@ -0,0 +1,219 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+	"time"
+)
+
+var testTokenConfig = TokenConfig{
+	Issuer:          "users",
+	AccessTokenTTL:  time.Minute,
+	RefreshTokenTTL: time.Hour,
+}
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize login handler":                   testNewLoginHandler,
+		"initialize login handler without deps":      testNewLoginHandlerWithoutDeps,
+		"handle login command":                       testHandleLogin,
+		"handle login command with unknown user":     testHandleLoginWithUnknownUser,
+		"handle login command with wrong password":   testHandleLoginWithWrongPassword,
+		"handle login command with user repo error":  testHandleLoginWithUserRepoError,
+		"handle login command with token repo error": testHandleLoginWithTokenRepoError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+				test(t)
+			},
+		)
+	}
+}
+
+func newTestKeySet(t *testing.T) *auth.KeySet {
+	keySet, err := auth.GenerateKeySet(1024)
+	require.NoError(t, err)
+
+	return keySet
+}
+
+func hashedUser1(t *testing.T, password string) *user.User {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	return user.UnmarshalUserFromDB(
+		user.User1.Id(),
+		user.User1.FirstName(),
+		user.User1.LastName(),
+		user.User1.Nickname(),
+		string(hashedPassword),
+		user.User1.Email(),
+		user.User1.Country(),
+		user.User1.Roles(),
+		user.User1.CreatedAt(),
+		user.User1.UpdatedAt(),
+		user.User1.Version(),
+	)
+}
+
+func assertValidTokens(t *testing.T, keySet *auth.KeySet, userId string, tokens *Tokens) {
+	claims, err := keySet.Verify(tokens.AccessToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, userId, claims.Subject)
+	assert.Equal(t, testTokenConfig.Issuer, claims.Issuer)
+	assert.Equal(t, tokens.AccessTokenExpiresAt.Unix(), claims.ExpiresAt)
+	assert.Equal(t, []string{"user"}, claims.Roles)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.True(t, tokens.RefreshTokenExpiresAt.After(tokens.AccessTokenExpiresAt))
+}
+
+func testNewLoginHandler(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	keySet := newTestKeySet(t)
+
+	newHandler := NewLoginHandler(mockUserRepo, mockTokenRepo, keySet, testTokenConfig)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &LoginHandler{mockUserRepo, tokenIssuer{keySet, mockTokenRepo, testTokenConfig}}, newHandler)
+}
+
+func testNewLoginHandlerWithoutDeps(t *testing.T) {
+	keySet := newTestKeySet(t)
+
+	assert.PanicsWithValue(
+		t, "[command/login] nil userRepo", func() {
+			NewLoginHandler(nil, new(mocks.RefreshTokenRepository), keySet, testTokenConfig)
+		},
+	)
+	assert.PanicsWithValue(
+		t, "[command/login] nil tokenRepo", func() {
+			NewLoginHandler(new(mocks.UserRepository), nil, keySet, testTokenConfig)
+		},
+	)
+	assert.PanicsWithValue(
+		t, "[command/login] nil keySet", func() {
+			NewLoginHandler(new(mocks.UserRepository), new(mocks.RefreshTokenRepository), nil, testTokenConfig)
+		},
+	)
+}
+
+func testHandleLogin(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	keySet := newTestKeySet(t)
+	handler := NewLoginHandler(mockUserRepo, mockTokenRepo, keySet, testTokenConfig)
+
+	ctx := context.Background()
+	nickname := user.User1.Nickname()
+
+	mockUserRepo.On("GetUserByNicknameOrEmail", ctx, nickname).Return(hashedUser1(t, "password"), nil)
+	mockTokenRepo.On(
+		"AddRefreshToken", ctx, mock.MatchedBy(
+			func(refreshToken *token.RefreshToken) bool {
+				return refreshToken.UserId() == user.User1.Id() && refreshToken.FamilyId() != ""
+			},
+		),
+	).Return(nil)
+
+	out, err := handler.Handle(ctx, Login{NicknameOrEmail: nickname, Password: "password"})
+
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "AddRefreshToken", 1)
+
+	assert.NoError(t, err)
+	assertValidTokens(t, keySet, user.User1.Id(), out)
+}
+
+func testHandleLoginWithUnknownUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewLoginHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	email := "unknown@john.com"
+
+	mockUserRepo.On("GetUserByNicknameOrEmail", ctx, email).Return(nil, &user.NotFoundError{Id: email})
+
+	out, err := handler.Handle(ctx, Login{NicknameOrEmail: email, Password: "password"})
+
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "AddRefreshToken", 0)
+
+	assert.Equal(t, &user.InvalidCredentialsError{}, err)
+	assert.Nil(t, out)
+}
+
+func testHandleLoginWithWrongPassword(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewLoginHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	nickname := user.User1.Nickname()
+
+	mockUserRepo.On("GetUserByNicknameOrEmail", ctx, nickname).Return(hashedUser1(t, "password"), nil)
+
+	out, err := handler.Handle(ctx, Login{NicknameOrEmail: nickname, Password: "wrong"})
+
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "AddRefreshToken", 0)
+
+	assert.Equal(t, &user.InvalidCredentialsError{}, err)
+	assert.Nil(t, out)
+}
+
+func testHandleLoginWithUserRepoError(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewLoginHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	nickname := user.User1.Nickname()
+
+	dbErr := errors.New("db is down")
+	mockUserRepo.On("GetUserByNicknameOrEmail", ctx, nickname).Return(nil, dbErr)
+
+	out, err := handler.Handle(ctx, Login{NicknameOrEmail: nickname, Password: "password"})
+
+	mockUserRepo.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, out)
+}
+
+func testHandleLoginWithTokenRepoError(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewLoginHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	nickname := user.User1.Nickname()
+
+	dbErr := errors.New("db is down")
+	mockUserRepo.On("GetUserByNicknameOrEmail", ctx, nickname).Return(hashedUser1(t, "password"), nil)
+	mockTokenRepo.On("AddRefreshToken", ctx, mock.Anything).Return(dbErr)
+
+	out, err := handler.Handle(ctx, Login{NicknameOrEmail: nickname, Password: "password"})
+
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, out)
+}
@@ -0,0 +1,156 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The RefreshTokens command exchanges a refresh token for a new pair of access and refresh tokens.
+
+Refresh tokens are single-use: the one sent is revoked, and the new one joins
+its family. If an already rotated token is ever sent again, it has most likely
+been stolen, so we revoke the whole family, logging out both the attacker and
+the legitimate user.
+*/
+type RefreshTokens struct {
+	RefreshToken string
+}
+
+type IRefreshTokensHandler interface {
+	Handle(ctx context.Context, cmd RefreshTokens) (*Tokens, error)
+}
+
+type RefreshTokensHandler struct {
+	userRepo user.UserRepository
+	tokenIssuer
+}
+
+const refreshTokensTag = "command/refresh_tokens"
+
+func NewRefreshTokensHandler(
+	userRepo user.UserRepository, tokenRepo token.RefreshTokenRepository, keySet *auth.KeySet, config TokenConfig,
+) *RefreshTokensHandler {
+	if userRepo == nil {
+		panic("[command/refresh_tokens] nil userRepo")
+	}
+
+	if tokenRepo == nil {
+		panic("[command/refresh_tokens] nil tokenRepo")
+	}
+
+	if keySet == nil {
+		panic("[command/refresh_tokens] nil keySet")
+	}
+
+	return &RefreshTokensHandler{userRepo, tokenIssuer{keySet, tokenRepo, config}}
+}
+
+func (h *RefreshTokensHandler) Handle(ctx context.Context, cmd RefreshTokens) (*Tokens, error) {
+	tokenId := token.HashValue(cmd.RefreshToken)
+
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":     refreshTokensTag,
+			"tokenId": tokenId,
+		},
+	).Debug("Refreshing tokens")
+
+	refreshToken, err := h.tokenRepo.GetRefreshTokenById(ctx, tokenId)
+
+	if err != nil {
+		if errors.As(err, new(*token.NotFoundError)) {
+			return nil, &token.InvalidTokenError{Reason: "unknown token"}
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     refreshTokensTag,
+				"tokenId": tokenId,
+			},
+		).WithError(err).Error("Error getting refresh token")
+
+		return nil, err
+	}
+
+	if refreshToken.IsRevoked() {
+		return nil, h.revokeFamily(ctx, refreshToken)
+	}
+
+	if err := refreshToken.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := h.tokenRepo.RevokeRefreshToken(ctx, tokenId, nowFunc()); err != nil {
+		// Someone else rotated this same token in the meantime.
+		if errors.As(err, new(*token.NotFoundError)) {
+			return nil, h.revokeFamily(ctx, refreshToken)
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     refreshTokensTag,
+				"tokenId": tokenId,
+			},
+		).WithError(err).Error("Error revoking rotated refresh token")
+
+		return nil, err
+	}
+
+	// The user may have been removed since the token was issued.
+	tokenUser, err := h.userRepo.GetUserById(ctx, refreshToken.UserId(), nil)
+
+	if err != nil {
+		if errors.As(err, new(*user.NotFoundError)) {
+			return nil, &token.InvalidTokenError{Reason: "unknown user"}
+		}
+
+		return nil, err
+	}
+
+	tokens, err := h.issue(ctx, tokenUser, refreshToken.FamilyId())
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":      refreshTokensTag,
+				"userId":   refreshToken.UserId(),
+				"familyId": refreshToken.FamilyId(),
+			},
+		).WithError(err).Error("Error issuing tokens")
+
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+/*
+revokeFamily handles the reuse of an already rotated refresh token.
+*/
+func (h *RefreshTokensHandler) revokeFamily(ctx context.Context, refreshToken *token.RefreshToken) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":      refreshTokensTag,
+			"userId":   refreshToken.UserId(),
+			"familyId": refreshToken.FamilyId(),
+		},
+	).Warn("Refresh token reuse detected, revoking the whole family")
+
+	if err := h.tokenRepo.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyId(), nowFunc()); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":      refreshTokensTag,
+				"familyId": refreshToken.FamilyId(),
+			},
+		).WithError(err).Error("Error revoking refresh token family")
+
+		return err
+	}
+
+	return &token.InvalidTokenError{Reason: "token reused"}
+}
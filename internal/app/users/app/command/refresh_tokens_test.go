@@ -0,0 +1,267 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokens(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize refresh tokens handler":                      testNewRefreshTokensHandler,
+		"initialize refresh tokens handler without deps":         testNewRefreshTokensHandlerWithoutDeps,
+		"handle refresh tokens command":                          testHandleRefreshTokens,
+		"handle refresh tokens command with unknown token":       testHandleRefreshTokensWithUnknownToken,
+		"handle refresh tokens command with reused token":        testHandleRefreshTokensWithReusedToken,
+		"handle refresh tokens command with concurrent rotation": testHandleRefreshTokensWithConcurrentRotation,
+		"handle refresh tokens command with expired token":       testHandleRefreshTokensWithExpiredToken,
+		"handle refresh tokens command with removed user":        testHandleRefreshTokensWithRemovedUser,
+		"handle refresh tokens command with token repo error":    testHandleRefreshTokensWithTokenRepoError,
+		"handle refresh tokens command with family revoke error": testHandleRefreshTokensWithFamilyRevokeError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+				test(t)
+			},
+		)
+	}
+}
+
+const refreshValue = "token-1"
+
+func testNewRefreshTokensHandler(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	keySet := newTestKeySet(t)
+
+	newHandler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, keySet, testTokenConfig)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(
+		t, &RefreshTokensHandler{mockUserRepo, tokenIssuer{keySet, mockTokenRepo, testTokenConfig}}, newHandler,
+	)
+}
+
+func testNewRefreshTokensHandlerWithoutDeps(t *testing.T) {
+	keySet := newTestKeySet(t)
+
+	assert.PanicsWithValue(
+		t, "[command/refresh_tokens] nil userRepo", func() {
+			NewRefreshTokensHandler(nil, new(mocks.RefreshTokenRepository), keySet, testTokenConfig)
+		},
+	)
+	assert.PanicsWithValue(
+		t, "[command/refresh_tokens] nil tokenRepo", func() {
+			NewRefreshTokensHandler(new(mocks.UserRepository), nil, keySet, testTokenConfig)
+		},
+	)
+	assert.PanicsWithValue(
+		t, "[command/refresh_tokens] nil keySet", func() {
+			NewRefreshTokensHandler(new(mocks.UserRepository), new(mocks.RefreshTokenRepository), nil, testTokenConfig)
+		},
+	)
+}
+
+func testHandleRefreshTokens(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	keySet := newTestKeySet(t)
+	handler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, keySet, testTokenConfig)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(&token.Token1, nil)
+	mockTokenRepo.On("RevokeRefreshToken", ctx, tokenId, mock.Anything).Return(nil)
+	mockUserRepo.On("GetUserById", ctx, token.Token1.UserId(), []string(nil)).Return(&user.User1, nil)
+	mockTokenRepo.On(
+		"AddRefreshToken", ctx, mock.MatchedBy(
+			func(refreshToken *token.RefreshToken) bool {
+				return refreshToken.UserId() == token.Token1.UserId() &&
+					refreshToken.FamilyId() == token.Token1.FamilyId() &&
+					refreshToken.Id() != tokenId
+			},
+		),
+	).Return(nil)
+
+	out, err := handler.Handle(ctx, RefreshTokens{RefreshToken: refreshValue})
+
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "RevokeRefreshTokenFamily", 0)
+
+	assert.NoError(t, err)
+	assertValidTokens(t, keySet, token.Token1.UserId(), out)
+	assert.NotEqual(t, refreshValue, out.RefreshToken)
+}
+
+func testHandleRefreshTokensWithUnknownToken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	tokenId := token.HashValue("unknown")
+
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(nil, &token.NotFoundError{Id: tokenId})
+
+	out, err := handler.Handle(ctx, RefreshTokens{RefreshToken: "unknown"})
+
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "RevokeRefreshToken", 0)
+
+	assert.Equal(t, &token.InvalidTokenError{Reason: "unknown token"}, err)
+	assert.Nil(t, out)
+}
+
+func testHandleRefreshTokensWithReusedToken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+	revokedToken := token.UnmarshalRefreshTokenFromDB(
+		tokenId,
+		token.Token1.UserId(),
+		token.Token1.FamilyId(),
+		token.Token1.CreatedAt(),
+		token.Token1.ExpiresAt(),
+		time.Now(),
+	)
+
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(revokedToken, nil)
+	mockTokenRepo.On("RevokeRefreshTokenFamily", ctx, token.Token1.FamilyId(), mock.Anything).Return(nil)
+
+	out, err := handler.Handle(ctx, RefreshTokens{RefreshToken: refreshValue})
+
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "RevokeRefreshToken", 0)
+	mockTokenRepo.AssertNumberOfCalls(t, "AddRefreshToken", 0)
+
+	assert.Equal(t, &token.InvalidTokenError{Reason: "token reused"}, err)
+	assert.Nil(t, out)
+}
+
+func testHandleRefreshTokensWithConcurrentRotation(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(&token.Token1, nil)
+	mockTokenRepo.On("RevokeRefreshToken", ctx, tokenId, mock.Anything).Return(&token.NotFoundError{Id: tokenId})
+	mockTokenRepo.On("RevokeRefreshTokenFamily", ctx, token.Token1.FamilyId(), mock.Anything).Return(nil)
+
+	out, err := handler.Handle(ctx, RefreshTokens{RefreshToken: refreshValue})
+
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "AddRefreshToken", 0)
+
+	assert.Equal(t, &token.InvalidTokenError{Reason: "token reused"}, err)
+	assert.Nil(t, out)
+}
+
+func testHandleRefreshTokensWithExpiredToken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+	expiredToken := token.UnmarshalRefreshTokenFromDB(
+		tokenId,
+		token.Token1.UserId(),
+		token.Token1.FamilyId(),
+		token.Token1.CreatedAt().Add(-2*time.Hour),
+		token.Token1.CreatedAt().Add(-time.Hour),
+		time.Time{},
+	)
+
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(expiredToken, nil)
+
+	out, err := handler.Handle(ctx, RefreshTokens{RefreshToken: refreshValue})
+
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "RevokeRefreshToken", 0)
+
+	assert.IsType(t, &token.InvalidTokenError{}, err)
+	assert.Nil(t, out)
+}
+
+func testHandleRefreshTokensWithRemovedUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+	userId := token.Token1.UserId()
+
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(&token.Token1, nil)
+	mockTokenRepo.On("RevokeRefreshToken", ctx, tokenId, mock.Anything).Return(nil)
+	mockUserRepo.On("GetUserById", ctx, userId, []string(nil)).Return(nil, &user.NotFoundError{Id: userId})
+
+	out, err := handler.Handle(ctx, RefreshTokens{RefreshToken: refreshValue})
+
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "AddRefreshToken", 0)
+
+	assert.Equal(t, &token.InvalidTokenError{Reason: "unknown user"}, err)
+	assert.Nil(t, out)
+}
+
+func testHandleRefreshTokensWithTokenRepoError(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+
+	dbErr := errors.New("db is down")
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(&token.Token1, nil)
+	mockTokenRepo.On("RevokeRefreshToken", ctx, tokenId, mock.Anything).Return(dbErr)
+
+	out, err := handler.Handle(ctx, RefreshTokens{RefreshToken: refreshValue})
+
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "RevokeRefreshTokenFamily", 0)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, out)
+}
+
+func testHandleRefreshTokensWithFamilyRevokeError(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRefreshTokensHandler(mockUserRepo, mockTokenRepo, newTestKeySet(t), testTokenConfig)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+
+	dbErr := errors.New("db is down")
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(&token.Token1, nil)
+	mockTokenRepo.On("RevokeRefreshToken", ctx, tokenId, mock.Anything).Return(&token.NotFoundError{Id: tokenId})
+	mockTokenRepo.On("RevokeRefreshTokenFamily", ctx, token.Token1.FamilyId(), mock.Anything).Return(dbErr)
+
+	out, err := handler.Handle(ctx, RefreshTokens{RefreshToken: refreshValue})
+
+	mockTokenRepo.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, out)
+}
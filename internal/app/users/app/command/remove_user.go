@@ -8,10 +8,16 @@ import (
 
 /*
 The RemoveUser command removes a user from our platform given its id.
+
+Users can only remove themselves, unless the actor performing the removal is an admin.
 */
+type RemoveUser struct {
+	ActorId string
+	Id      string
+}
 
 type IRemoveUserHandler interface {
-	Handle(ctx context.Context, userId string) error
+	Handle(ctx context.Context, cmd RemoveUser) error
 }
 
 type RemoveUserHandler struct {
@@ -28,15 +34,18 @@ func NewRemoveUserHandler(userRepo user.UserRepository) *RemoveUserHandler {
 	return &RemoveUserHandler{userRepo}
 }
 
-func (h *RemoveUserHandler) Handle(ctx context.Context, userId string) error {
+func (h *RemoveUserHandler) Handle(ctx context.Context, cmd RemoveUser) error {
+	userId := cmd.Id
+
 	logrus.WithFields(
 		logrus.Fields{
-			"tag":    removeUserTag,
-			"userId": userId,
+			"tag":     removeUserTag,
+			"userId":  userId,
+			"actorId": cmd.ActorId,
 		},
 	).Debug("Removing user")
 
-	_, err := h.userRepo.GetUserById(ctx, userId)
+	userToRemove, err := h.userRepo.GetUserById(ctx, userId, nil)
 
 	if err != nil {
 		logrus.WithFields(
@@ -49,6 +58,18 @@ func (h *RemoveUserHandler) Handle(ctx context.Context, userId string) error {
 		return err
 	}
 
+	if err := authorizeActor(ctx, h.userRepo, cmd.ActorId, userToRemove); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     removeUserTag,
+				"userId":  userId,
+				"actorId": cmd.ActorId,
+			},
+		).WithError(err).Info("Actor not allowed to remove user")
+
+		return err
+	}
+
 	if err := h.userRepo.RemoveUser(ctx, userId); err != nil {
 		logrus.WithFields(
 			logrus.Fields{
@@ -18,6 +18,9 @@ func TestRemoveUser(t *testing.T) {
 		"handle remove user command":                      testHandleRemoveUser,
 		"handle remove user command with error on get":    testHandleRemoveUserWithGetError,
 		"handle remove user command with error on remove": testHandleRemoveUserWithRemoveError,
+		"handle remove user command as admin":             testHandleRemoveUserAsAdmin,
+		"handle remove user command as another user":      testHandleRemoveUserAsAnotherUser,
+		"handle remove user command as removed actor":     testHandleRemoveUserAsRemovedActor,
 	} {
 		test := test
 		t.Run(
@@ -57,10 +60,10 @@ func testHandleRemoveUser(t *testing.T) {
 
 	removeId := user.User1.Id()
 
-	mockRepo.On("GetUserById", ctx, removeId).Return(&user.User1, nil)
+	mockRepo.On("GetUserById", ctx, removeId, []string(nil)).Return(&user.User1, nil)
 	mockRepo.On("RemoveUser", ctx, removeId).Return(nil)
 
-	err := handler.Handle(ctx, removeId)
+	err := handler.Handle(ctx, RemoveUser{ActorId: removeId, Id: removeId})
 
 	mockRepo.AssertExpectations(t)
 	mockRepo.AssertNumberOfCalls(t, "RemoveUser", 1)
@@ -77,9 +80,9 @@ func testHandleRemoveUserWithGetError(t *testing.T) {
 	removeId := user.User1.Id()
 
 	dbErr := errors.New("db is down")
-	mockRepo.On("GetUserById", ctx, removeId).Return(nil, dbErr)
+	mockRepo.On("GetUserById", ctx, removeId, []string(nil)).Return(nil, dbErr)
 
-	err := handler.Handle(ctx, removeId)
+	err := handler.Handle(ctx, RemoveUser{ActorId: removeId, Id: removeId})
 
 	mockRepo.AssertNumberOfCalls(t, "GetUserById", 1)
 	mockRepo.AssertNumberOfCalls(t, "RemoveUser", 0)
@@ -95,11 +98,11 @@ func testHandleRemoveUserWithRemoveError(t *testing.T) {
 	ctx := context.Background()
 	removeId := user.User1.Id()
 
-	mockRepo.On("GetUserById", ctx, removeId).Return(&user.User1, nil)
+	mockRepo.On("GetUserById", ctx, removeId, []string(nil)).Return(&user.User1, nil)
 	dbErr := errors.New("db is down")
 	mockRepo.On("RemoveUser", ctx, removeId).Return(dbErr)
 
-	err := handler.Handle(ctx, removeId)
+	err := handler.Handle(ctx, RemoveUser{ActorId: removeId, Id: removeId})
 
 	mockRepo.AssertNumberOfCalls(t, "GetUserById", 1)
 	mockRepo.AssertNumberOfCalls(t, "RemoveUser", 1)
@@ -107,3 +110,61 @@ func testHandleRemoveUserWithRemoveError(t *testing.T) {
 
 	assert.ErrorIs(t, err, dbErr)
 }
+
+func testHandleRemoveUserAsAdmin(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RemoveUserHandler{mockRepo}
+
+	ctx := context.Background()
+	removeId := user.User1.Id()
+	actorId := user.Admin1.Id()
+
+	mockRepo.On("GetUserById", ctx, removeId, []string(nil)).Return(&user.User1, nil)
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("RemoveUser", ctx, removeId).Return(nil)
+
+	err := handler.Handle(ctx, RemoveUser{ActorId: actorId, Id: removeId})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "RemoveUser", 1)
+
+	assert.NoError(t, err)
+}
+
+func testHandleRemoveUserAsAnotherUser(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RemoveUserHandler{mockRepo}
+
+	ctx := context.Background()
+	removeId := user.Admin1.Id()
+	actorId := user.User1.Id()
+
+	mockRepo.On("GetUserById", ctx, removeId, []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(&user.User1, nil)
+
+	err := handler.Handle(ctx, RemoveUser{ActorId: actorId, Id: removeId})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "RemoveUser", 0)
+
+	assert.Equal(t, &user.ForbiddenError{ActorId: actorId, UserId: removeId}, err)
+}
+
+func testHandleRemoveUserAsRemovedActor(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RemoveUserHandler{mockRepo}
+
+	ctx := context.Background()
+	removeId := user.User1.Id()
+	actorId := "removed"
+
+	mockRepo.On("GetUserById", ctx, removeId, []string(nil)).Return(&user.User1, nil)
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(nil, &user.NotFoundError{Id: actorId})
+
+	err := handler.Handle(ctx, RemoveUser{ActorId: actorId, Id: removeId})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "RemoveUser", 0)
+
+	assert.Equal(t, &user.ForbiddenError{ActorId: actorId, UserId: removeId}, err)
+}
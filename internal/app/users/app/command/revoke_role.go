@@ -0,0 +1,97 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The RevokeRole command takes a role away from a user. Only admins can revoke roles.
+
+Admins can't revoke their own admin role, so the platform can't be accidentally
+left without anyone able to manage it.
+*/
+type RevokeRole struct {
+	ActorId string
+	UserId  string
+	Role    string
+}
+
+type IRevokeRoleHandler interface {
+	Handle(ctx context.Context, cmd RevokeRole) error
+}
+
+type RevokeRoleHandler struct {
+	userRepo user.UserRepository
+}
+
+const revokeRoleTag = "command/revoke_role"
+
+func NewRevokeRoleHandler(userRepo user.UserRepository) *RevokeRoleHandler {
+	if userRepo == nil {
+		panic("[command/revoke_role] nil userRepo")
+	}
+
+	return &RevokeRoleHandler{userRepo}
+}
+
+func (h *RevokeRoleHandler) Handle(ctx context.Context, cmd RevokeRole) error {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag": revokeRoleTag,
+			"cmd": cmd,
+		},
+	).Debug("Revoking role")
+
+	if _, err := getAdminActor(ctx, h.userRepo, cmd.ActorId, cmd.UserId); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": revokeRoleTag,
+				"cmd": cmd,
+			},
+		).WithError(err).Info("Actor not allowed to revoke roles")
+
+		return err
+	}
+
+	role, err := user.ParseRole(cmd.Role)
+
+	if err != nil {
+		return err
+	}
+
+	if role == user.RoleAdmin && cmd.ActorId == cmd.UserId {
+		return &user.ForbiddenError{ActorId: cmd.ActorId, UserId: cmd.UserId}
+	}
+
+	targetUser, err := h.userRepo.GetUserById(ctx, cmd.UserId, nil)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": revokeRoleTag,
+				"cmd": cmd,
+			},
+		).WithError(err).Error("Error getting user to revoke role")
+
+		return err
+	}
+
+	if err := targetUser.RevokeRole(role); err != nil {
+		return err
+	}
+
+	if err := h.userRepo.UpdateUser(ctx, targetUser); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": revokeRoleTag,
+				"cmd": cmd,
+			},
+		).WithError(err).Error("Error saving revoked role")
+
+		return err
+	}
+
+	return nil
+}
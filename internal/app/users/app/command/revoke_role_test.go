@@ -0,0 +1,171 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestRevokeRole(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize revoke role handler":                    testNewRevokeRoleHandler,
+		"initialize revoke role handler without repo":       testNewRevokeRoleHandlerWithoutRepo,
+		"handle revoke role command":                        testHandleRevokeRole,
+		"handle revoke role command as regular user":        testHandleRevokeRoleAsRegularUser,
+		"handle revoke role command on own admin role":      testHandleRevokeOwnAdminRole,
+		"handle revoke role command on base user role":      testHandleRevokeBaseRole,
+		"handle revoke role command with removed actor":     testHandleRevokeRoleWithRemovedActor,
+		"handle revoke role command with repo error on get": testHandleRevokeRoleWithRepoError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func moderatorUser1() *user.User {
+	moderator := user.User1
+	_ = moderator.GrantRole(user.RoleModerator)
+
+	return &moderator
+}
+
+func testNewRevokeRoleHandler(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+
+	newHandler := NewRevokeRoleHandler(mockRepo)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &RevokeRoleHandler{mockRepo}, newHandler)
+}
+
+func testNewRevokeRoleHandlerWithoutRepo(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[command/revoke_role] nil userRepo", func() {
+			NewRevokeRoleHandler(nil)
+		},
+	)
+}
+
+func testHandleRevokeRole(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RevokeRoleHandler{mockRepo}
+
+	ctx := context.Background()
+	targetUser := moderatorUser1()
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("GetUserById", ctx, targetUser.Id(), []string(nil)).Return(targetUser, nil)
+	mockRepo.On(
+		"UpdateUser", ctx, mock.MatchedBy(
+			func(_user *user.User) bool {
+				return _user.Id() == targetUser.Id() && !_user.HasRole(user.RoleModerator)
+			},
+		),
+	).Return(nil)
+
+	err := handler.Handle(ctx, RevokeRole{ActorId: user.Admin1.Id(), UserId: targetUser.Id(), Role: "moderator"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 1)
+
+	assert.NoError(t, err)
+}
+
+func testHandleRevokeRoleAsRegularUser(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RevokeRoleHandler{mockRepo}
+
+	ctx := context.Background()
+	actorId := user.User1.Id()
+
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(&user.User1, nil)
+
+	err := handler.Handle(ctx, RevokeRole{ActorId: actorId, UserId: user.Admin1.Id(), Role: "admin"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+
+	assert.Equal(t, &user.ForbiddenError{ActorId: actorId, UserId: user.Admin1.Id()}, err)
+}
+
+func testHandleRevokeOwnAdminRole(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RevokeRoleHandler{mockRepo}
+
+	ctx := context.Background()
+	actorId := user.Admin1.Id()
+
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(&user.Admin1, nil)
+
+	err := handler.Handle(ctx, RevokeRole{ActorId: actorId, UserId: actorId, Role: "admin"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+
+	assert.Equal(t, &user.ForbiddenError{ActorId: actorId, UserId: actorId}, err)
+}
+
+func testHandleRevokeBaseRole(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RevokeRoleHandler{mockRepo}
+
+	ctx := context.Background()
+	targetUser := user.User1
+
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("GetUserById", ctx, targetUser.Id(), []string(nil)).Return(&targetUser, nil)
+
+	err := handler.Handle(ctx, RevokeRole{ActorId: user.Admin1.Id(), UserId: targetUser.Id(), Role: "user"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{Domain: "User", Field: "role", Value: "user", Reason: user.ReasonBaseRole}, err,
+	)
+}
+
+func testHandleRevokeRoleWithRemovedActor(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RevokeRoleHandler{mockRepo}
+
+	ctx := context.Background()
+
+	mockRepo.On("GetUserById", ctx, "removed", []string(nil)).Return(nil, &user.NotFoundError{Id: "removed"})
+
+	err := handler.Handle(ctx, RevokeRole{ActorId: "removed", UserId: user.User1.Id(), Role: "moderator"})
+
+	mockRepo.AssertExpectations(t)
+
+	assert.Equal(t, &user.ForbiddenError{ActorId: "removed", UserId: user.User1.Id()}, err)
+}
+
+func testHandleRevokeRoleWithRepoError(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := RevokeRoleHandler{mockRepo}
+
+	ctx := context.Background()
+
+	dbErr := errors.New("db is down")
+	mockRepo.On("GetUserById", ctx, user.Admin1.Id(), []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On("GetUserById", ctx, user.User1.Id(), []string(nil)).Return(nil, dbErr)
+
+	err := handler.Handle(ctx, RevokeRole{ActorId: user.Admin1.Id(), UserId: user.User1.Id(), Role: "moderator"})
+
+	mockRepo.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, dbErr)
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The RevokeToken command revokes a refresh token, along with every other token of its family.
+
+Following RFC 7009, revoking an unknown or already revoked token isn't an error:
+the client only cares about the token not being usable anymore.
+*/
+type RevokeToken struct {
+	RefreshToken string
+}
+
+type IRevokeTokenHandler interface {
+	Handle(ctx context.Context, cmd RevokeToken) error
+}
+
+type RevokeTokenHandler struct {
+	tokenRepo token.RefreshTokenRepository
+}
+
+const revokeTokenTag = "command/revoke_token"
+
+func NewRevokeTokenHandler(tokenRepo token.RefreshTokenRepository) *RevokeTokenHandler {
+	if tokenRepo == nil {
+		panic("[command/revoke_token] nil tokenRepo")
+	}
+
+	return &RevokeTokenHandler{tokenRepo}
+}
+
+func (h *RevokeTokenHandler) Handle(ctx context.Context, cmd RevokeToken) error {
+	tokenId := token.HashValue(cmd.RefreshToken)
+
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":     revokeTokenTag,
+			"tokenId": tokenId,
+		},
+	).Debug("Revoking refresh token")
+
+	refreshToken, err := h.tokenRepo.GetRefreshTokenById(ctx, tokenId)
+
+	if err != nil {
+		if errors.As(err, new(*token.NotFoundError)) {
+			return nil
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     revokeTokenTag,
+				"tokenId": tokenId,
+			},
+		).WithError(err).Error("Error getting refresh token to revoke")
+
+		return err
+	}
+
+	if err := h.tokenRepo.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyId(), nowFunc()); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":      revokeTokenTag,
+				"familyId": refreshToken.FamilyId(),
+			},
+		).WithError(err).Error("Error revoking refresh token family")
+
+		return err
+	}
+
+	return nil
+}
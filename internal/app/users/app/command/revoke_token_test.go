@@ -0,0 +1,119 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestRevokeToken(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize revoke token handler":                      testNewRevokeTokenHandler,
+		"initialize revoke token handler without repository":   testNewRevokeTokenHandlerWithoutRepository,
+		"handle revoke token command":                          testHandleRevokeToken,
+		"handle revoke token command with unknown token":       testHandleRevokeTokenWithUnknownToken,
+		"handle revoke token command with repository error":    testHandleRevokeTokenWithRepositoryError,
+		"handle revoke token command with family revoke error": testHandleRevokeTokenWithFamilyRevokeError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+				test(t)
+			},
+		)
+	}
+}
+
+func testNewRevokeTokenHandler(t *testing.T) {
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+
+	newHandler := NewRevokeTokenHandler(mockTokenRepo)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &RevokeTokenHandler{mockTokenRepo}, newHandler)
+}
+
+func testNewRevokeTokenHandlerWithoutRepository(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[command/revoke_token] nil tokenRepo", func() {
+			NewRevokeTokenHandler(nil)
+		},
+	)
+}
+
+func testHandleRevokeToken(t *testing.T) {
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRevokeTokenHandler(mockTokenRepo)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(&token.Token1, nil)
+	mockTokenRepo.On("RevokeRefreshTokenFamily", ctx, token.Token1.FamilyId(), mock.Anything).Return(nil)
+
+	err := handler.Handle(ctx, RevokeToken{RefreshToken: "token-1"})
+
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "RevokeRefreshTokenFamily", 1)
+
+	assert.NoError(t, err)
+}
+
+func testHandleRevokeTokenWithUnknownToken(t *testing.T) {
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRevokeTokenHandler(mockTokenRepo)
+
+	ctx := context.Background()
+	tokenId := token.HashValue("unknown")
+
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(nil, &token.NotFoundError{Id: tokenId})
+
+	err := handler.Handle(ctx, RevokeToken{RefreshToken: "unknown"})
+
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNumberOfCalls(t, "RevokeRefreshTokenFamily", 0)
+
+	assert.NoError(t, err)
+}
+
+func testHandleRevokeTokenWithRepositoryError(t *testing.T) {
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRevokeTokenHandler(mockTokenRepo)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+
+	dbErr := errors.New("db is down")
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(nil, dbErr)
+
+	err := handler.Handle(ctx, RevokeToken{RefreshToken: "token-1"})
+
+	mockTokenRepo.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, dbErr)
+}
+
+func testHandleRevokeTokenWithFamilyRevokeError(t *testing.T) {
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	handler := NewRevokeTokenHandler(mockTokenRepo)
+
+	ctx := context.Background()
+	tokenId := token.Token1.Id()
+
+	dbErr := errors.New("db is down")
+	mockTokenRepo.On("GetRefreshTokenById", ctx, tokenId).Return(&token.Token1, nil)
+	mockTokenRepo.On("RevokeRefreshTokenFamily", ctx, token.Token1.FamilyId(), mock.Anything).Return(dbErr)
+
+	err := handler.Handle(ctx, RevokeToken{RefreshToken: "token-1"})
+
+	mockTokenRepo.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, dbErr)
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/google/uuid"
+	"time"
+)
+
+var nowFunc = time.Now
+
+/*
+Tokens holds a freshly issued pair of access and refresh tokens.
+
+Commands shouldn't return any data, but just like generated ids, tokens only
+exist at the moment they're issued: there's no way to query them afterwards.
+*/
+type Tokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+type TokenConfig struct {
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+/*
+tokenIssuer holds the logic shared by every command that hands out tokens.
+*/
+type tokenIssuer struct {
+	keySet    *auth.KeySet
+	tokenRepo token.RefreshTokenRepository
+	config    TokenConfig
+}
+
+/*
+issue signs a new access token for the user and stores a new refresh token in the given family.
+
+The access token carries the roles the user has right now, so role changes only
+reach the tokens issued after them.
+*/
+func (i *tokenIssuer) issue(ctx context.Context, tokenUser *user.User, familyId string) (*Tokens, error) {
+	refreshToken, refreshValue, err := token.IssueRefreshToken(tokenUser.Id(), familyId, i.config.RefreshTokenTTL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := nowFunc()
+	accessExpiresAt := now.Add(i.config.AccessTokenTTL)
+
+	accessToken, err := i.keySet.Sign(
+		auth.Claims{
+			Issuer:    i.config.Issuer,
+			Subject:   tokenUser.Id(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: accessExpiresAt.Unix(),
+			Id:        uuid.NewString(),
+			Roles:     marshalRoles(tokenUser.Roles()),
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.tokenRepo.AddRefreshToken(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshValue,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt(),
+	}, nil
+}
+
+func marshalRoles(roles []user.Role) []string {
+	out := make([]string, len(roles))
+	for i, role := range roles {
+		out[i] = string(role)
+	}
+
+	return out
+}
@@ -8,15 +8,20 @@ import (
 
 /*
 The UpdateUser command updates the given properties for a user in our platform, and leaves the rest of the properties untouched
+
+The properties to update are given as a patch, which can't clear them, as they're
+all required. Users can only update themselves, unless the actor performing the
+update is an admin.
+
+When ExpectedVersion is given, the user is only updated if it's still at that
+version, so clients can't overwrite changes they haven't seen. Either way, the
+user is only stored if nobody else updated it since it was read.
 */
 type UpdateUser struct {
-	Id        string
-	FirstName *string
-	LastName  *string
-	Nickname  *string
-	Password  *string
-	Email     *string
-	Country   *string
+	ActorId         string
+	Id              string
+	ExpectedVersion *int64
+	Patch           user.Patch
 }
 
 type IUpdateUserHandler interface {
@@ -40,30 +45,61 @@ func NewUpdateUserHandler(userRepo user.UserRepository) *UpdateUserHandler {
 func (h *UpdateUserHandler) Handle(ctx context.Context, cmd UpdateUser) error {
 	logrus.WithFields(
 		logrus.Fields{
-			"tag": updateUserTag,
-			"cmd": cmd,
+			"tag":     updateUserTag,
+			"actorId": cmd.ActorId,
+			"id":      cmd.Id,
+			"fields":  cmd.Patch.Fields(),
 		},
 	).Debug("Updating user")
 
-	userToUpdate, err := h.userRepo.GetUserById(ctx, cmd.Id)
+	userToUpdate, err := h.userRepo.GetUserById(ctx, cmd.Id, nil)
 	if err != nil {
 		logrus.WithFields(
 			logrus.Fields{
-				"tag": updateUserTag,
-				"cmd": cmd,
+				"tag":     updateUserTag,
+				"actorId": cmd.ActorId,
+				"id":      cmd.Id,
+				"fields":  cmd.Patch.Fields(),
 			},
 		).WithError(err).Error("Error getting user to update")
 
 		return err
 	}
 
-	if err := userToUpdate.Update(
-		cmd.FirstName, cmd.LastName, cmd.Nickname, cmd.Password, cmd.Email, cmd.Country,
-	); err != nil {
+	if err := authorizeActor(ctx, h.userRepo, cmd.ActorId, userToUpdate); err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     updateUserTag,
+				"actorId": cmd.ActorId,
+				"id":      cmd.Id,
+				"fields":  cmd.Patch.Fields(),
+			},
+		).WithError(err).Info("Actor not allowed to update user")
+
+		return err
+	}
+
+	if cmd.ExpectedVersion != nil && *cmd.ExpectedVersion != userToUpdate.Version() {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     updateUserTag,
+				"actorId": cmd.ActorId,
+				"id":      cmd.Id,
+				"fields":  cmd.Patch.Fields(),
+				"version": userToUpdate.Version(),
+			},
+		).Info("User to update is at another version")
+
+		return &user.VersionConflictError{Id: cmd.Id, Version: *cmd.ExpectedVersion}
+	}
+
+	if err := userToUpdate.Update(cmd.Patch); err != nil {
 		logrus.WithFields(
 			logrus.Fields{
 				"tag":          updateUserTag,
-				"cmd":          cmd,
+				"actorId":      cmd.ActorId,
+				"id":           cmd.Id,
+				"fields":       cmd.Patch.Fields(),
 				"userToUpdate": userToUpdate,
 			},
 		).WithError(err).Error("Error updating user")
@@ -75,7 +111,9 @@ func (h *UpdateUserHandler) Handle(ctx context.Context, cmd UpdateUser) error {
 		logrus.WithFields(
 			logrus.Fields{
 				"tag":          updateUserTag,
-				"cmd":          cmd,
+				"actorId":      cmd.ActorId,
+				"id":           cmd.Id,
+				"fields":       cmd.Patch.Fields(),
 				"userToUpdate": userToUpdate,
 			},
 		).WithError(err).Error("Error updating user")
@@ -21,6 +21,11 @@ func TestUpdateUser(t *testing.T) {
 		"handle update user command with user error":             testHandleUpdateUserWithUserError,
 		"handle update user command with repo error on get user": testHandleUpdateUserWithRepoErrorOnGetUserById,
 		"handle update user command with repo error on update":   testHandleUpdateUserWithRepoErrorOnUpdate,
+		"handle update user command as admin":                    testHandleUpdateUserAsAdmin,
+		"handle update user command as another user":             testHandleUpdateUserAsAnotherUser,
+		"handle update user command with expected version":       testHandleUpdateUserWithExpectedVersion,
+		"handle update user command with stale version":          testHandleUpdateUserWithStaleVersion,
+		"handle update user command with version in the mask":    testHandleUpdateUserWithVersionInMask,
 	} {
 		test := test
 		t.Run(
@@ -60,26 +65,23 @@ func testHandleUpdateUser(t *testing.T) {
 	id := "123"
 	previousUser := user.User1
 
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
 	updateCommand := UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+		ActorId: previousUser.Id(),
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": "updated",
+			"last_name":  "updated",
+			"nickname":   "updated",
+			"password":   "secret-passphrase",
+			"email":      "updated@john.com",
+			"country":    "ES",
+		},
 	}
 
 	updatedUser := previousUser
-	_ = updatedUser.Update(&firstName, &lastName, &nickname, &password, &email, &country)
+	_ = updatedUser.Update(updateCommand.Patch)
 
-	mockRepo.On("GetUserById", ctx, id).Return(&previousUser, nil)
+	mockRepo.On("GetUserById", ctx, id, []string(nil)).Return(&previousUser, nil)
 	mockRepo.On(
 		"UpdateUser", ctx, mock.MatchedBy(
 			func(_user *user.User) bool {
@@ -114,23 +116,20 @@ func testHandleUpdateUserWithUserError(t *testing.T) {
 	id := "123"
 	previousUser := user.User1
 
-	firstName := ""
-	lastName := ""
-	nickname := ""
-	password := ""
-	email := ""
-	country := ""
 	updateCommand := UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+		ActorId: previousUser.Id(),
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": "",
+			"last_name":  "",
+			"nickname":   "",
+			"password":   "",
+			"email":      "",
+			"country":    "",
+		},
 	}
 
-	mockRepo.On("GetUserById", ctx, id).Return(&previousUser, nil)
+	mockRepo.On("GetUserById", ctx, id, []string(nil)).Return(&previousUser, nil)
 
 	err := handler.Handle(ctx, updateCommand)
 
@@ -150,27 +149,24 @@ func testHandleUpdateUserWithRepoErrorOnGetUserById(t *testing.T) {
 	id := "123"
 	previousUser := user.User1
 
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
 	updateCommand := UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+		ActorId: previousUser.Id(),
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": "updated",
+			"last_name":  "updated",
+			"nickname":   "updated",
+			"password":   "secret-passphrase",
+			"email":      "updated@john.com",
+			"country":    "ES",
+		},
 	}
 
 	updatedUser := previousUser
-	_ = updatedUser.Update(&firstName, &lastName, &nickname, &password, &email, &country)
+	_ = updatedUser.Update(updateCommand.Patch)
 
 	dbErr := errors.New("db is down")
-	mockRepo.On("GetUserById", ctx, id).Return(nil, dbErr)
+	mockRepo.On("GetUserById", ctx, id, []string(nil)).Return(nil, dbErr)
 
 	err := handler.Handle(ctx, updateCommand)
 
@@ -189,27 +185,24 @@ func testHandleUpdateUserWithRepoErrorOnUpdate(t *testing.T) {
 	id := "123"
 	previousUser := user.User1
 
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
 	updateCommand := UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+		ActorId: previousUser.Id(),
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": "updated",
+			"last_name":  "updated",
+			"nickname":   "updated",
+			"password":   "secret-passphrase",
+			"email":      "updated@john.com",
+			"country":    "ES",
+		},
 	}
 
 	updatedUser := previousUser
-	_ = updatedUser.Update(&firstName, &lastName, &nickname, &password, &email, &country)
+	_ = updatedUser.Update(updateCommand.Patch)
 
 	dbErr := errors.New("db is down")
-	mockRepo.On("GetUserById", ctx, id).Return(&previousUser, nil)
+	mockRepo.On("GetUserById", ctx, id, []string(nil)).Return(&previousUser, nil)
 	mockRepo.On(
 		"UpdateUser", ctx, mock.MatchedBy(
 			func(_user *user.User) bool {
@@ -235,3 +228,146 @@ func testHandleUpdateUserWithRepoErrorOnUpdate(t *testing.T) {
 
 	assert.ErrorIs(t, err, dbErr)
 }
+
+func testHandleUpdateUserAsAdmin(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := UpdateUserHandler{mockRepo}
+
+	ctx := context.Background()
+	previousUser := user.User1
+	actorId := user.Admin1.Id()
+
+	lastName := "updated"
+	updateCommand := UpdateUser{
+		ActorId: actorId,
+		Id:      previousUser.Id(),
+		Patch:   user.Patch{"last_name": lastName},
+	}
+
+	mockRepo.On("GetUserById", ctx, previousUser.Id(), []string(nil)).Return(&previousUser, nil)
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(&user.Admin1, nil)
+	mockRepo.On(
+		"UpdateUser", ctx, mock.MatchedBy(
+			func(_user *user.User) bool {
+				return _user.Id() == previousUser.Id() && _user.LastName() == lastName
+			},
+		),
+	).Return(nil)
+
+	err := handler.Handle(ctx, updateCommand)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 1)
+
+	assert.NoError(t, err)
+}
+
+func testHandleUpdateUserAsAnotherUser(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := UpdateUserHandler{mockRepo}
+
+	ctx := context.Background()
+	previousUser := user.Admin1
+	actorId := user.User1.Id()
+
+	lastName := "updated"
+	updateCommand := UpdateUser{
+		ActorId: actorId,
+		Id:      previousUser.Id(),
+		Patch:   user.Patch{"last_name": lastName},
+	}
+
+	mockRepo.On("GetUserById", ctx, previousUser.Id(), []string(nil)).Return(&previousUser, nil)
+	mockRepo.On("GetUserById", ctx, actorId, []string(nil)).Return(&user.User1, nil)
+
+	err := handler.Handle(ctx, updateCommand)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+
+	assert.Equal(t, &user.ForbiddenError{ActorId: actorId, UserId: previousUser.Id()}, err)
+	assert.Equal(t, "Doe", previousUser.LastName())
+}
+
+func testHandleUpdateUserWithExpectedVersion(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := UpdateUserHandler{mockRepo}
+
+	ctx := context.Background()
+	previousUser := user.User1
+	version := previousUser.Version()
+
+	updateCommand := UpdateUser{
+		ActorId:         previousUser.Id(),
+		Id:              previousUser.Id(),
+		ExpectedVersion: &version,
+		Patch:           user.Patch{"first_name": "updated"},
+	}
+
+	mockRepo.On("GetUserById", ctx, previousUser.Id(), []string(nil)).Return(&previousUser, nil)
+	mockRepo.On(
+		"UpdateUser", ctx, mock.MatchedBy(
+			func(_user *user.User) bool {
+				return _user.FirstName() == "updated" && _user.Version() == version
+			},
+		),
+	).Return(nil)
+
+	err := handler.Handle(ctx, updateCommand)
+
+	mockRepo.AssertExpectations(t)
+
+	assert.NoError(t, err)
+}
+
+func testHandleUpdateUserWithStaleVersion(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := UpdateUserHandler{mockRepo}
+
+	ctx := context.Background()
+	previousUser := user.User1
+	version := previousUser.Version() - 1
+
+	updateCommand := UpdateUser{
+		ActorId:         previousUser.Id(),
+		Id:              previousUser.Id(),
+		ExpectedVersion: &version,
+		Patch:           user.Patch{"first_name": "updated"},
+	}
+
+	mockRepo.On("GetUserById", ctx, previousUser.Id(), []string(nil)).Return(&previousUser, nil)
+
+	err := handler.Handle(ctx, updateCommand)
+
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+	mockRepo.AssertExpectations(t)
+
+	assert.Equal(t, &user.VersionConflictError{Id: previousUser.Id(), Version: version}, err)
+	assert.Equal(t, user.User1.FirstName(), previousUser.FirstName())
+}
+
+func testHandleUpdateUserWithVersionInMask(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := UpdateUserHandler{mockRepo}
+
+	ctx := context.Background()
+	previousUser := user.User1
+
+	// A "version" path in the update mask maps to an empty value, as the request has no such field.
+	updateCommand := UpdateUser{
+		ActorId: previousUser.Id(),
+		Id:      previousUser.Id(),
+		Patch:   user.Patch{"first_name": "updated", "version": ""},
+	}
+
+	mockRepo.On("GetUserById", ctx, previousUser.Id(), []string(nil)).Return(&previousUser, nil)
+
+	err := handler.Handle(ctx, updateCommand)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateUser", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{Domain: "User", Field: "version", Reason: pkgErrors.ReasonImmutable}, err,
+	)
+}
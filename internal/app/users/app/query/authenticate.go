@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The Authenticate query checks a set of credentials and returns the identity of the user they belong to.
+
+Users can identify themselves either with their nickname or their email. The
+result never includes the password hash, so clients no longer need to fetch it
+to verify a login on their side.
+*/
+type Authenticate struct {
+	NicknameOrEmail string
+	Password        string
+}
+
+type IAuthenticateHandler interface {
+	Handle(ctx context.Context, query Authenticate) (*User, error)
+}
+
+type AuthenticateHandler struct {
+	userRepo user.UserRepository
+}
+
+const authenticateTag = "query/authenticate"
+
+func NewAuthenticateHandler(userRepo user.UserRepository) *AuthenticateHandler {
+	if userRepo == nil {
+		panic("[query/authenticate] nil userRepo")
+	}
+
+	return &AuthenticateHandler{userRepo}
+}
+
+func (h *AuthenticateHandler) Handle(ctx context.Context, query Authenticate) (*User, error) {
+	// The password is left out of the logs on purpose.
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":             authenticateTag,
+			"nicknameOrEmail": query.NicknameOrEmail,
+		},
+	).Debug("Authenticating user")
+
+	userResult, err := user.AuthenticateByNicknameOrEmail(ctx, h.userRepo, query.NicknameOrEmail, query.Password)
+
+	if err != nil {
+		if errors.As(err, new(*user.InvalidCredentialsError)) {
+			logrus.WithFields(
+				logrus.Fields{
+					"tag":             authenticateTag,
+					"nicknameOrEmail": query.NicknameOrEmail,
+				},
+			).WithError(err).Debug("Invalid credentials")
+
+			return nil, err
+		}
+
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":             authenticateTag,
+				"nicknameOrEmail": query.NicknameOrEmail,
+			},
+		).WithError(err).Error("Error getting user to authenticate")
+
+		return nil, err
+	}
+
+	return &User{
+		Id:        userResult.Id(),
+		FirstName: userResult.FirstName(),
+		LastName:  userResult.LastName(),
+		Nickname:  userResult.Nickname(),
+		Email:     userResult.Email(),
+		Country:   userResult.Country(),
+		Roles:     marshalRoles(userResult.Roles()),
+		CreatedAt: userResult.CreatedAt(),
+		UpdatedAt: userResult.UpdatedAt(),
+		Version:   userResult.Version(),
+	}, nil
+}
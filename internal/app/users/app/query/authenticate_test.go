@@ -0,0 +1,156 @@
+package query
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize authenticate handler":               testNewAuthenticateHandler,
+		"initialize authenticate handler without repo":  testNewAuthenticateHandlerWithoutRepo,
+		"handle authenticate query":                     testHandleAuthenticate,
+		"handle authenticate query with unknown user":   testHandleAuthenticateWithUnknownUser,
+		"handle authenticate query with wrong password": testHandleAuthenticateWithWrongPassword,
+		"handle authenticate query with repo error":     testHandleAuthenticateWithRepoError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func hashedUser1(t *testing.T, password string) *user.User {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	return user.UnmarshalUserFromDB(
+		user.User1.Id(),
+		user.User1.FirstName(),
+		user.User1.LastName(),
+		user.User1.Nickname(),
+		string(hashedPassword),
+		user.User1.Email(),
+		user.User1.Country(),
+		user.User1.Roles(),
+		user.User1.CreatedAt(),
+		user.User1.UpdatedAt(),
+		user.User1.Version(),
+	)
+}
+
+func testNewAuthenticateHandler(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+
+	newHandler := NewAuthenticateHandler(mockRepo)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &AuthenticateHandler{mockRepo}, newHandler)
+	assert.Same(t, mockRepo, newHandler.userRepo)
+}
+
+func testNewAuthenticateHandlerWithoutRepo(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[query/authenticate] nil userRepo", func() {
+			NewAuthenticateHandler(nil)
+		},
+	)
+}
+
+func testHandleAuthenticate(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := AuthenticateHandler{mockRepo}
+
+	ctx := context.Background()
+	nickname := user.User1.Nickname()
+
+	mockRepo.On("GetUserByNicknameOrEmail", ctx, nickname).Return(hashedUser1(t, "password"), nil)
+
+	got, err := handler.Handle(ctx, Authenticate{NicknameOrEmail: nickname, Password: "password"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "GetUserByNicknameOrEmail", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &User{
+			Id:        user.User1.Id(),
+			FirstName: user.User1.FirstName(),
+			LastName:  user.User1.LastName(),
+			Nickname:  user.User1.Nickname(),
+			Email:     user.User1.Email(),
+			Country:   user.User1.Country(),
+			Roles:     []string{"user"},
+			CreatedAt: user.User1.CreatedAt(),
+			UpdatedAt: user.User1.UpdatedAt(),
+			Version:   user.User1.Version(),
+		}, got,
+	)
+}
+
+func testHandleAuthenticateWithUnknownUser(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := AuthenticateHandler{mockRepo}
+
+	ctx := context.Background()
+	email := "unknown@john.com"
+
+	mockRepo.On("GetUserByNicknameOrEmail", ctx, email).Return(nil, &user.NotFoundError{Id: email})
+
+	got, err := handler.Handle(ctx, Authenticate{NicknameOrEmail: email, Password: "password"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "GetUserByNicknameOrEmail", 1)
+
+	assert.Equal(t, &user.InvalidCredentialsError{}, err)
+	assert.Nil(t, got)
+}
+
+func testHandleAuthenticateWithWrongPassword(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := AuthenticateHandler{mockRepo}
+
+	ctx := context.Background()
+	email := user.User1.Email()
+
+	mockRepo.On("GetUserByNicknameOrEmail", ctx, email).Return(hashedUser1(t, "password"), nil)
+
+	got, err := handler.Handle(ctx, Authenticate{NicknameOrEmail: email, Password: "wrong-password"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "GetUserByNicknameOrEmail", 1)
+
+	assert.Equal(t, &user.InvalidCredentialsError{}, err)
+	assert.Nil(t, got)
+}
+
+func testHandleAuthenticateWithRepoError(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := AuthenticateHandler{mockRepo}
+
+	ctx := context.Background()
+	nickname := user.User1.Nickname()
+
+	dbErr := errors.New("db is down")
+	mockRepo.On("GetUserByNicknameOrEmail", ctx, nickname).Return(nil, dbErr)
+
+	got, err := handler.Handle(ctx, Authenticate{NicknameOrEmail: nickname, Password: "password"})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "GetUserByNicknameOrEmail", 1)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, got)
+}
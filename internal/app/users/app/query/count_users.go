@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The CountUsers query returns how many users match the given filters, which work as the ones of the GetUsers query.
+*/
+type CountUsers struct {
+	Filters []query_utils.Filter
+	Filter  *query_utils.FilterExpression
+}
+
+type ICountUsersHandler interface {
+	Handle(ctx context.Context, query CountUsers) (int64, error)
+}
+
+type CountUsersHandler struct {
+	userRepo user.UserRepository
+}
+
+const countUsersTag = "query/count_users"
+
+func NewCountUsersHandler(userRepo user.UserRepository) *CountUsersHandler {
+	if userRepo == nil {
+		panic("[query/count_users] nil userRepo")
+	}
+
+	return &CountUsersHandler{userRepo}
+}
+
+func (h *CountUsersHandler) Handle(ctx context.Context, query CountUsers) (int64, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":   countUsersTag,
+			"query": query,
+		},
+	).Debug("Counting users")
+
+	filter, _, err := mapUserQuery(query.Filters, query.Filter, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := h.userRepo.CountUsers(ctx, filter)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":   countUsersTag,
+				"query": query,
+			},
+		).WithError(err).Error("Error counting users")
+
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,137 @@
+package query
+
+import (
+	"context"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCountUsers(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize count users handler":              testNewCountUsersHandler,
+		"initialize count users handler without repo": testNewCountUsersHandlerWithoutRepo,
+		"handle count users query":                    testHandleCountUsers,
+		"handle count users query with repo error":    testHandleCountUsersWithRepoError,
+		"handle count users query with hidden field":  testHandleCountUsersWithHiddenField,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+				test(t)
+			},
+		)
+	}
+}
+
+func testNewCountUsersHandler(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+
+	newHandler := NewCountUsersHandler(mockRepo)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &CountUsersHandler{mockRepo}, newHandler)
+	assert.Same(t, mockRepo, newHandler.userRepo)
+}
+
+func testNewCountUsersHandlerWithoutRepo(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[query/count_users] nil userRepo", func() {
+			NewCountUsersHandler(nil)
+		},
+	)
+}
+
+func testHandleCountUsers(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := CountUsersHandler{mockRepo}
+
+	ctx := context.Background()
+	filters := []query_utils.Filter{
+		{
+			Field:    "country",
+			Operator: operators.EQUALS,
+			Value:    "ES",
+		},
+	}
+	expression := query_utils.FilterExpression{
+		Operator: operators.NOT,
+		Filters: []query_utils.Filter{
+			{
+				Field:    "nickname",
+				Operator: operators.EQUALS,
+				Value:    "john",
+			},
+		},
+	}
+
+	mockRepo.On(
+		"CountUsers", ctx, query_utils.FilterExpression{
+			Operator:    operators.AND,
+			Filters:     filters,
+			Expressions: []query_utils.FilterExpression{expression},
+		},
+	).Return(int64(2), nil)
+
+	out, err := handler.Handle(ctx, CountUsers{Filters: filters, Filter: &expression})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "CountUsers", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), out)
+}
+
+func testHandleCountUsersWithRepoError(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := CountUsersHandler{mockRepo}
+
+	ctx := context.Background()
+	dbErr := errors.New("db is down")
+
+	mockRepo.On("CountUsers", ctx, query_utils.FilterExpression{}).Return(int64(0), dbErr)
+
+	out, err := handler.Handle(ctx, CountUsers{})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "CountUsers", 1)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Zero(t, out)
+}
+
+func testHandleCountUsersWithHiddenField(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := CountUsersHandler{mockRepo}
+
+	out, err := handler.Handle(
+		context.Background(), CountUsers{
+			Filters: []query_utils.Filter{
+				{
+					Field:    "password",
+					Operator: operators.EQUALS,
+					Value:    "123",
+				},
+			},
+		},
+	)
+
+	mockRepo.AssertNumberOfCalls(t, "CountUsers", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "filters[0].field",
+			Value:  "password",
+			Reason: pkgErrors.ReasonUnknownValue,
+		}, err,
+	)
+	assert.Zero(t, out)
+}
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The GetSigningKeys query returns the public keys other services can verify our access tokens with.
+*/
+
+type IGetSigningKeysHandler interface {
+	Handle(ctx context.Context) ([]SigningKey, error)
+}
+
+type GetSigningKeysHandler struct {
+	keySet *auth.KeySet
+}
+
+const getSigningKeysTag = "query/get_signing_keys"
+
+func NewGetSigningKeysHandler(keySet *auth.KeySet) *GetSigningKeysHandler {
+	if keySet == nil {
+		panic("[query/get_signing_keys] nil keySet")
+	}
+
+	return &GetSigningKeysHandler{keySet}
+}
+
+func (h *GetSigningKeysHandler) Handle(ctx context.Context) ([]SigningKey, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag": getSigningKeysTag,
+		},
+	).Debug("Getting signing keys")
+
+	var keys []SigningKey
+	for _, jwk := range h.keySet.PublicKeys() {
+		keys = append(
+			keys, SigningKey{
+				Kty: jwk.Kty,
+				Kid: jwk.Kid,
+				Use: jwk.Use,
+				Alg: jwk.Alg,
+				N:   jwk.N,
+				E:   jwk.E,
+			},
+		)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,68 @@
+package query
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetSigningKeys(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize get signing keys handler":              testNewGetSigningKeysHandler,
+		"initialize get signing keys handler without keys": testNewGetSigningKeysHandlerWithoutKeys,
+		"handle get signing keys query":                    testHandleGetSigningKeys,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+				test(t)
+			},
+		)
+	}
+}
+
+func testNewGetSigningKeysHandler(t *testing.T) {
+	keySet, err := auth.GenerateKeySet(1024)
+	require.NoError(t, err)
+
+	newHandler := NewGetSigningKeysHandler(keySet)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &GetSigningKeysHandler{keySet}, newHandler)
+}
+
+func testNewGetSigningKeysHandlerWithoutKeys(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[query/get_signing_keys] nil keySet", func() {
+			NewGetSigningKeysHandler(nil)
+		},
+	)
+}
+
+func testHandleGetSigningKeys(t *testing.T) {
+	keySet, err := auth.GenerateKeySet(1024)
+	require.NoError(t, err)
+	handler := NewGetSigningKeysHandler(keySet)
+
+	out, err := handler.Handle(context.Background())
+
+	jwks := keySet.PublicKeys()
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []SigningKey{
+			{
+				Kty: jwks[0].Kty,
+				Kid: jwks[0].Kid,
+				Use: jwks[0].Use,
+				Alg: jwks[0].Alg,
+				N:   jwks[0].N,
+				E:   jwks[0].E,
+			},
+		}, out,
+	)
+}
@@ -8,10 +8,17 @@ import (
 
 /*
 The GetUserById query returns a single user matching the provided Id.
+
+ReadMask names the fields to read, as in GetUsers, or every one of them if it's
+empty. The rest are left unset.
 */
+type GetUserById struct {
+	Id       string
+	ReadMask []string
+}
 
 type IGetUserByIdHandler interface {
-	Handle(ctx context.Context, userId string) (*User, error)
+	Handle(ctx context.Context, query GetUserById) (*User, error)
 }
 
 type GetUserByIdHandler struct {
@@ -28,20 +35,27 @@ func NewGetUserByIdHandler(userRepo user.UserRepository) *GetUserByIdHandler {
 	return &GetUserByIdHandler{userRepo}
 }
 
-func (h *GetUserByIdHandler) Handle(ctx context.Context, userId string) (*User, error) {
+func (h *GetUserByIdHandler) Handle(ctx context.Context, query GetUserById) (*User, error) {
 	logrus.WithFields(
 		logrus.Fields{
-			"tag":    getUserByIdTag,
-			"userId": userId,
+			"tag":   getUserByIdTag,
+			"query": query,
 		},
 	).Debug("Getting user by id")
-	userResult, err := h.userRepo.GetUserById(ctx, userId)
+
+	projection, err := UserFields.MapProjection("read_mask.paths", query.ReadMask)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userResult, err := h.userRepo.GetUserById(ctx, query.Id, projection)
 
 	if err != nil {
 		logrus.WithFields(
 			logrus.Fields{
-				"tag":    getUserByIdTag,
-				"userId": userId,
+				"tag":   getUserByIdTag,
+				"query": query,
 			},
 		).WithError(err).Error("Error getting user by id")
 
@@ -54,9 +68,10 @@ func (h *GetUserByIdHandler) Handle(ctx context.Context, userId string) (*User,
 		LastName:  userResult.LastName(),
 		Nickname:  userResult.Nickname(),
 		Email:     userResult.Email(),
-		Password:  userResult.Password(),
 		Country:   userResult.Country(),
+		Roles:     marshalRoles(userResult.Roles()),
 		CreatedAt: userResult.CreatedAt(),
 		UpdatedAt: userResult.UpdatedAt(),
+		Version:   userResult.Version(),
 	}, nil
 }
@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
 	"github.com/elizabeth-dev/ACME_Test/test/mocks"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,8 @@ func TestGetUserById(t *testing.T) {
 		"initialize get user by id handler without repo": testNewGetUserByIdHandlerWithoutRepo,
 		"handle get user by id query":                    testHandleGetUserById,
 		"handle get user by id query with repo error":    testHandleGetUserByIdWithRepoError,
+		"handle get user by id query with read mask":     testHandleGetUserByIdWithReadMask,
+		"handle get user by id query with hidden field":  testHandleGetUserByIdWithHiddenReadMask,
 	} {
 		test := test
 		t.Run(
@@ -55,9 +58,9 @@ func testHandleGetUserById(t *testing.T) {
 	ctx := context.Background()
 	id := "1234"
 
-	mockRepo.On("GetUserById", ctx, id).Return(&user.User1, nil)
+	mockRepo.On("GetUserById", ctx, id, []string(nil)).Return(&user.User1, nil)
 
-	got, err := handler.Handle(ctx, id)
+	got, err := handler.Handle(ctx, GetUserById{Id: id})
 
 	mockRepo.AssertExpectations(t)
 	mockRepo.AssertNumberOfCalls(t, "GetUserById", 1)
@@ -69,11 +72,12 @@ func testHandleGetUserById(t *testing.T) {
 			FirstName: user.User1.FirstName(),
 			LastName:  user.User1.LastName(),
 			Nickname:  user.User1.Nickname(),
-			Password:  user.User1.Password(),
 			Email:     user.User1.Email(),
 			Country:   user.User1.Country(),
+			Roles:     []string{"user"},
 			CreatedAt: user.User1.CreatedAt(),
 			UpdatedAt: user.User1.UpdatedAt(),
+			Version:   user.User1.Version(),
 		}, got,
 	)
 }
@@ -86,9 +90,9 @@ func testHandleGetUserByIdWithRepoError(t *testing.T) {
 	id := "1234"
 
 	dbErr := errors.New("db is down")
-	mockRepo.On("GetUserById", ctx, id).Return(nil, dbErr)
+	mockRepo.On("GetUserById", ctx, id, []string(nil)).Return(nil, dbErr)
 
-	got, err := handler.Handle(ctx, id)
+	got, err := handler.Handle(ctx, GetUserById{Id: id})
 
 	mockRepo.AssertExpectations(t)
 	mockRepo.AssertNumberOfCalls(t, "GetUserById", 1)
@@ -96,3 +100,39 @@ func testHandleGetUserByIdWithRepoError(t *testing.T) {
 	assert.ErrorIs(t, err, dbErr)
 	assert.Nil(t, got)
 }
+
+func testHandleGetUserByIdWithReadMask(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUserByIdHandler{mockRepo}
+
+	ctx := context.Background()
+	id := "1234"
+
+	mockRepo.On("GetUserById", ctx, id, []string{"nickname", "created_at"}).Return(&user.User1, nil)
+
+	got, err := handler.Handle(ctx, GetUserById{Id: id, ReadMask: []string{"nickname", "created_at", "nickname"}})
+
+	mockRepo.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.User1.Nickname(), got.Nickname)
+}
+
+func testHandleGetUserByIdWithHiddenReadMask(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUserByIdHandler{mockRepo}
+
+	got, err := handler.Handle(context.Background(), GetUserById{Id: "1234", ReadMask: []string{"password"}})
+
+	mockRepo.AssertNumberOfCalls(t, "GetUserById", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "read_mask.paths[0]",
+			Value:  "password",
+			Reason: pkgErrors.ReasonUnknownValue,
+		}, err,
+	)
+	assert.Nil(t, got)
+}
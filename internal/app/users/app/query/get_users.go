@@ -3,43 +3,69 @@ package query
 import (
 	"context"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
 	"github.com/sirupsen/logrus"
 )
 
 /*
-The GetUsers query returns a list of users matching the given parameters.
+The GetUsers query streams the users matching the given parameters.
 
 The possible parameters include:
-- Filters: a list of AND filters to apply to the query. If an OR filter is needed, multiple queries should be made.
+- Filters: a list of AND filters to apply to the query.
+- Filter: an optional filter expression, applied along with Filters, for queries needing OR or NOT.
 - Sort: a list of fields to sort by, from most priority to least priority.
 - Pagination: the pagination parameters to apply to the query.
+- PageToken: the NextPageToken of a previous page of the same query, to get the next one, without an offset.
+- IncludeTotalCount: whether to count every user matching the filters too, which takes another query.
+- ReadMask: the fields to read, or every one of them if empty.
+
+The filters, the sort and the read mask can only target the fields declared in
+UserFields, named as in the API. Paginated queries are sorted by id after the
+given sort, so every user has a fixed position, and a full page comes with the
+token of the next one. As the token is made of the sort fields, the users hold
+them too, even if the read mask leaves them out, and every other field is left
+empty.
 */
 type GetUsers struct {
-	Filters    []query_utils.Filter
-	Sort       []query_utils.Sort
-	Pagination query_utils.Pagination
+	Filters           []query_utils.Filter
+	Filter            *query_utils.FilterExpression
+	Sort              []query_utils.Sort
+	Pagination        query_utils.Pagination
+	PageToken         string
+	IncludeTotalCount bool
+	ReadMask          []string
 }
 
 type IGetUsersHandler interface {
-	Handle(ctx context.Context, query GetUsers) ([]*User, error)
+	Handle(ctx context.Context, query GetUsers) (UserStream, error)
 }
 
 type GetUsersHandler struct {
-	userRepo user.UserRepository
+	userRepo   user.UserRepository
+	pageTokens *query_utils.PageTokenSigner
 }
 
 const getUsersTag = "query/get_users"
 
-func NewGetUsersHandler(userRepo user.UserRepository) *GetUsersHandler {
+/*
+userTiebreaker is the storage field sorting the users with the same values of the requested sort fields.
+*/
+const userTiebreaker = "id"
+
+func NewGetUsersHandler(userRepo user.UserRepository, pageTokens *query_utils.PageTokenSigner) *GetUsersHandler {
 	if userRepo == nil {
 		panic("[query/get_users] nil userRepo")
 	}
 
-	return &GetUsersHandler{userRepo}
+	if pageTokens == nil {
+		panic("[query/get_users] nil pageTokens")
+	}
+
+	return &GetUsersHandler{userRepo, pageTokens}
 }
 
-func (h *GetUsersHandler) Handle(ctx context.Context, query GetUsers) ([]*User, error) {
+func (h *GetUsersHandler) Handle(ctx context.Context, query GetUsers) (UserStream, error) {
 	logrus.WithFields(
 		logrus.Fields{
 			"tag":   getUsersTag,
@@ -47,7 +73,73 @@ func (h *GetUsersHandler) Handle(ctx context.Context, query GetUsers) ([]*User,
 		},
 	).Debug("Getting users")
 
-	usersResult, err := h.userRepo.GetUsers(ctx, query.Filters, query.Sort, query.Pagination)
+	filter, sort, err := mapUserQuery(query.Filters, query.Filter, query.Sort)
+
+	if err != nil {
+		return nil, err
+	}
+
+	projection, err := UserFields.MapProjection("read_mask.paths", query.ReadMask)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := query.Pagination.Limit
+	if pageSize < 0 {
+		pageSize = -pageSize
+	}
+
+	pagination := query.Pagination
+
+	if pageSize > 0 || query.PageToken != "" {
+		sort = query_utils.WithTiebreaker(sort, userTiebreaker)
+	}
+
+	fingerprint, err := query_utils.QueryFingerprint(filter, sort)
+
+	if err != nil {
+		return nil, &errors.Unknown{Tag: getUsersTag, Cause: err}
+	}
+
+	var keyset *query_utils.FilterExpression
+
+	if query.PageToken != "" {
+		expression, err := h.keysetFilter(query, sort, fingerprint)
+
+		if err != nil {
+			return nil, err
+		}
+
+		keyset = &expression
+	}
+
+	page := UserPage{}
+
+	// The total count spans every page, so it's counted before skipping to the page token.
+	if query.IncludeTotalCount {
+		if page.TotalCount, err = h.userRepo.CountUsers(ctx, filter); err != nil {
+			logrus.WithFields(
+				logrus.Fields{
+					"tag":   getUsersTag,
+					"query": query,
+				},
+			).WithError(err).Error("Error counting users")
+
+			return nil, err
+		}
+	}
+
+	if keyset != nil {
+		filter.Expressions = append(filter.Expressions, *keyset)
+	}
+
+	if pageSize > 0 {
+		// One more user than requested tells whether there's a next page.
+		pagination.Limit = pageSize + 1
+	}
+
+	users, err := h.userRepo.GetUsers(ctx, filter, sort, pagination, withSortFields(projection, sort))
 
 	if err != nil {
 		logrus.WithFields(
@@ -60,22 +152,73 @@ func (h *GetUsersHandler) Handle(ctx context.Context, query GetUsers) ([]*User,
 		return nil, err
 	}
 
-	var users []*User
-	for _, u := range usersResult {
-		users = append(
-			users, &User{
-				Id:        u.Id(),
-				FirstName: u.FirstName(),
-				LastName:  u.LastName(),
-				Nickname:  u.Nickname(),
-				Password:  u.Password(),
-				Email:     u.Email(),
-				Country:   u.Country(),
-				CreatedAt: u.CreatedAt(),
-				UpdatedAt: u.UpdatedAt(),
-			},
-		)
+	return &userStream{
+		users:       users,
+		pageSize:    pageSize,
+		pageTokens:  h.pageTokens,
+		sort:        sort,
+		fingerprint: fingerprint,
+		page:        page,
+	}, nil
+}
+
+/*
+keysetFilter checks the page token of the query was issued for the same query, returning the filter matching the users
+after it.
+*/
+func (h *GetUsersHandler) keysetFilter(
+	query GetUsers, sort []query_utils.Sort, fingerprint string,
+) (query_utils.FilterExpression, error) {
+	if query.Pagination.Offset != 0 {
+		return query_utils.FilterExpression{}, &errors.InvalidField{
+			Domain: "User",
+			Field:  "pagination.offset",
+			Value:  query.Pagination.Offset,
+			Reason: errors.ReasonOutOfRange,
+		}
+	}
+
+	pageToken, err := h.pageTokens.Verify(query.PageToken)
+
+	if err != nil {
+		return query_utils.FilterExpression{}, err
+	}
+
+	if pageToken.Query != fingerprint || len(pageToken.Values) != len(sort) {
+		return query_utils.FilterExpression{}, &errors.InvalidField{
+			Domain: "User",
+			Field:  "page_token",
+			Reason: query_utils.ReasonQueryMismatch,
+		}
+	}
+
+	return query_utils.KeysetFilter(sort, pageToken.Values), nil
+}
+
+/*
+withSortFields adds the sort fields missing from the projection, as the page tokens are made of them, unless the
+projection is empty and every field is read anyway.
+*/
+func withSortFields(projection []string, sort []query_utils.Sort) []string {
+	if len(projection) == 0 {
+		return projection
+	}
+
+	for _, s := range sort {
+		if !containsField(projection, s.Field) {
+			projection = append(projection, s.Field)
+		}
+	}
+
+	return projection
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
 	}
 
-	return users, nil
+	return false
 }
@@ -0,0 +1,140 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+MaxBatchSize is the most ids a GetUsersByIds query can take, so a single query can't hold an unbounded list.
+*/
+const MaxBatchSize = 100
+
+/*
+The GetUsersByIds query returns the users with the given ids, reading them all at once.
+
+There's a result for every id, in the same order, even when ids repeat. Ids
+nobody has get a result without user, instead of failing the whole query.
+ReadMask names the fields to read for every user, as in GetUsers.
+*/
+type GetUsersByIds struct {
+	Ids      []string
+	ReadMask []string
+}
+
+type IGetUsersByIdsHandler interface {
+	Handle(ctx context.Context, query GetUsersByIds) ([]*UserResult, error)
+}
+
+type GetUsersByIdsHandler struct {
+	userRepo user.UserRepository
+}
+
+const getUsersByIdsTag = "query/get_users_by_ids"
+
+func NewGetUsersByIdsHandler(userRepo user.UserRepository) *GetUsersByIdsHandler {
+	if userRepo == nil {
+		panic("[query/get_users_by_ids] nil userRepo")
+	}
+
+	return &GetUsersByIdsHandler{userRepo}
+}
+
+func (h *GetUsersByIdsHandler) Handle(ctx context.Context, query GetUsersByIds) ([]*UserResult, error) {
+	logrus.WithFields(
+		logrus.Fields{
+			"tag":   getUsersByIdsTag,
+			"query": query,
+		},
+	).Debug("Getting users by ids")
+
+	if err := validateIds(query.Ids); err != nil {
+		return nil, err
+	}
+
+	projection, err := UserFields.MapProjection("read_mask.paths", query.ReadMask)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(query.Ids) == 0 {
+		return []*UserResult{}, nil
+	}
+
+	// The users are matched to their ids, so the id is always read.
+	if len(projection) > 0 && !containsField(projection, "id") {
+		projection = append(projection, "id")
+	}
+
+	users, err := h.userRepo.GetUsersByIds(ctx, query.Ids, projection)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":   getUsersByIdsTag,
+				"query": query,
+			},
+		).WithError(err).Error("Error getting users by ids")
+
+		return nil, err
+	}
+
+	byId := map[string]*User{}
+	for _, u := range users {
+		byId[u.Id()] = &User{
+			Id:        u.Id(),
+			FirstName: u.FirstName(),
+			LastName:  u.LastName(),
+			Nickname:  u.Nickname(),
+			Email:     u.Email(),
+			Country:   u.Country(),
+			Roles:     marshalRoles(u.Roles()),
+			CreatedAt: u.CreatedAt(),
+			UpdatedAt: u.UpdatedAt(),
+			Version:   u.Version(),
+		}
+	}
+
+	results := make([]*UserResult, len(query.Ids))
+	for i, id := range query.Ids {
+		results[i] = &UserResult{Id: id, User: byId[id]}
+	}
+
+	return results, nil
+}
+
+/*
+validateIds rejects more than MaxBatchSize ids, or empty ones, naming them by their position, like "ids[0]".
+*/
+func validateIds(ids []string) error {
+	if len(ids) > MaxBatchSize {
+		return &errors.InvalidField{Domain: "User", Field: "ids", Reason: errors.ReasonTooLong}
+	}
+
+	var invalidFields []error
+
+	for i, id := range ids {
+		if id == "" {
+			invalidFields = append(
+				invalidFields, &errors.InvalidField{
+					Domain: "User",
+					Field:  fmt.Sprintf("ids[%d]", i),
+					Reason: errors.ReasonRequired,
+				},
+			)
+		}
+	}
+
+	switch len(invalidFields) {
+	case 0:
+		return nil
+	case 1:
+		return invalidFields[0]
+	default:
+		return &errors.MultipleInvalidFields{Errors: invalidFields}
+	}
+}
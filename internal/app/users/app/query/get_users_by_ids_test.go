@@ -0,0 +1,208 @@
+package query
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGetUsersByIds(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"initialize get users by ids handler":              testNewGetUsersByIdsHandler,
+		"initialize get users by ids handler without repo": testNewGetUsersByIdsHandlerWithoutRepo,
+		"handle get users by ids query":                    testHandleGetUsersByIds,
+		"handle get users by ids query without ids":        testHandleGetUsersByIdsWithoutIds,
+		"handle get users by ids query with empty ids":     testHandleGetUsersByIdsWithEmptyIds,
+		"handle get users by ids query with too many ids":  testHandleGetUsersByIdsWithTooManyIds,
+		"handle get users by ids query with repo error":    testHandleGetUsersByIdsWithRepoError,
+		"handle get users by ids query with read mask":     testHandleGetUsersByIdsWithReadMask,
+		"handle get users by ids query with hidden field":  testHandleGetUsersByIdsWithHiddenReadMask,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testNewGetUsersByIdsHandler(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+
+	newHandler := NewGetUsersByIdsHandler(mockRepo)
+
+	assert.NotNil(t, newHandler)
+	assert.Equal(t, &GetUsersByIdsHandler{mockRepo}, newHandler)
+	assert.Same(t, mockRepo, newHandler.userRepo)
+}
+
+func testNewGetUsersByIdsHandlerWithoutRepo(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[query/get_users_by_ids] nil userRepo", func() {
+			NewGetUsersByIdsHandler(nil)
+		},
+	)
+}
+
+func testHandleGetUsersByIds(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersByIdsHandler{mockRepo}
+
+	ctx := context.Background()
+	ids := []string{user.Admin1.Id(), "unknown", user.User1.Id(), user.Admin1.Id()}
+
+	// The repository returns the users in its own order.
+	mockRepo.On("GetUsersByIds", ctx, ids, []string(nil)).Return([]*user.User{&user.User1, &user.Admin1}, nil)
+
+	got, err := handler.Handle(ctx, GetUsersByIds{Ids: ids})
+
+	mockRepo.AssertExpectations(t)
+
+	admin := &User{
+		Id:        user.Admin1.Id(),
+		FirstName: user.Admin1.FirstName(),
+		LastName:  user.Admin1.LastName(),
+		Nickname:  user.Admin1.Nickname(),
+		Email:     user.Admin1.Email(),
+		Country:   user.Admin1.Country(),
+		Roles:     []string{"user", "admin"},
+		CreatedAt: user.Admin1.CreatedAt(),
+		UpdatedAt: user.Admin1.UpdatedAt(),
+		Version:   user.Admin1.Version(),
+	}
+	john := &User{
+		Id:        user.User1.Id(),
+		FirstName: user.User1.FirstName(),
+		LastName:  user.User1.LastName(),
+		Nickname:  user.User1.Nickname(),
+		Email:     user.User1.Email(),
+		Country:   user.User1.Country(),
+		Roles:     []string{"user"},
+		CreatedAt: user.User1.CreatedAt(),
+		UpdatedAt: user.User1.UpdatedAt(),
+		Version:   user.User1.Version(),
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []*UserResult{
+			{Id: user.Admin1.Id(), User: admin},
+			{Id: "unknown"},
+			{Id: user.User1.Id(), User: john},
+			{Id: user.Admin1.Id(), User: admin},
+		}, got,
+	)
+}
+
+func testHandleGetUsersByIdsWithoutIds(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersByIdsHandler{mockRepo}
+
+	got, err := handler.Handle(context.Background(), GetUsersByIds{})
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsersByIds", 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func testHandleGetUsersByIdsWithEmptyIds(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersByIdsHandler{mockRepo}
+
+	got, err := handler.Handle(context.Background(), GetUsersByIds{Ids: []string{"", "1", ""}})
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsersByIds", 0)
+
+	assert.Equal(
+		t, &pkgErrors.MultipleInvalidFields{
+			Errors: []error{
+				&pkgErrors.InvalidField{Domain: "User", Field: "ids[0]", Reason: pkgErrors.ReasonRequired},
+				&pkgErrors.InvalidField{Domain: "User", Field: "ids[2]", Reason: pkgErrors.ReasonRequired},
+			},
+		}, err,
+	)
+	assert.Nil(t, got)
+}
+
+func testHandleGetUsersByIdsWithTooManyIds(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersByIdsHandler{mockRepo}
+
+	ids := strings.Split(strings.Repeat("1,", MaxBatchSize), ",")
+
+	got, err := handler.Handle(context.Background(), GetUsersByIds{Ids: ids})
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsersByIds", 0)
+
+	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "ids", Reason: pkgErrors.ReasonTooLong}, err)
+	assert.Nil(t, got)
+}
+
+func testHandleGetUsersByIdsWithRepoError(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersByIdsHandler{mockRepo}
+
+	ctx := context.Background()
+	ids := []string{user.User1.Id()}
+	repoErr := errors.New("repo error")
+
+	mockRepo.On("GetUsersByIds", ctx, ids, []string(nil)).Return(nil, repoErr)
+
+	got, err := handler.Handle(ctx, GetUsersByIds{Ids: ids})
+
+	mockRepo.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, repoErr)
+	assert.Nil(t, got)
+}
+
+func testHandleGetUsersByIdsWithReadMask(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersByIdsHandler{mockRepo}
+
+	ctx := context.Background()
+	ids := []string{user.User1.Id()}
+
+	// The id is read too, to match the users to their ids.
+	mockRepo.On("GetUsersByIds", ctx, ids, []string{"nickname", "id"}).Return([]*user.User{&user.User1}, nil)
+
+	got, err := handler.Handle(ctx, GetUsersByIds{Ids: ids, ReadMask: []string{"nickname"}})
+
+	mockRepo.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, user.User1.Nickname(), got[0].User.Nickname)
+}
+
+func testHandleGetUsersByIdsWithHiddenReadMask(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersByIdsHandler{mockRepo}
+
+	got, err := handler.Handle(
+		context.Background(), GetUsersByIds{Ids: []string{user.User1.Id()}, ReadMask: []string{"password"}},
+	)
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsersByIds", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "read_mask.paths[0]",
+			Value:  "password",
+			Reason: pkgErrors.ReasonUnknownValue,
+		}, err,
+	)
+	assert.Nil(t, got)
+}
@@ -3,23 +3,43 @@ package query
 import (
 	"context"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
 	"github.com/elizabeth-dev/ACME_Test/test/mocks"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
+var testPageTokens = query_utils.NewPageTokenSigner("User", []byte("secret"))
+
 func TestGetUsers(t *testing.T) {
 	t.Parallel()
 
 	for name, test := range map[string]func(t *testing.T){
-		"initialize get users handler":              testNewGetUsersHandler,
-		"initialize get users handler without repo": testNewGetUsersHandlerWithoutRepo,
-		"handle get users query":                    testHandleGetUsers,
-		"handle get users query without parameters": testHandleGetUsersWithoutParameters,
-		"handle get users query with repo error":    testHandleGetUsersWithRepoError,
+		"initialize get users handler":                    testNewGetUsersHandler,
+		"initialize get users handler without repo":       testNewGetUsersHandlerWithoutRepo,
+		"handle get users query":                          testHandleGetUsers,
+		"handle get users query without parameters":       testHandleGetUsersWithoutParameters,
+		"handle get users query with repo error":          testHandleGetUsersWithRepoError,
+		"handle get users query with hidden field":        testHandleGetUsersWithHiddenField,
+		"handle get users query with expression":          testHandleGetUsersWithExpression,
+		"handle get users query with bad expression":      testHandleGetUsersWithInvalidExpression,
+		"initialize get users handler without pageTokens": testNewGetUsersHandlerWithoutPageTokens,
+		"handle get users query with next page":           testHandleGetUsersWithNextPage,
+		"handle get users query with last page":           testHandleGetUsersWithLastPage,
+		"handle get users query with page token":          testHandleGetUsersWithPageToken,
+		"handle get users query with bad page token":      testHandleGetUsersWithInvalidPageToken,
+		"handle get users query with other query's token": testHandleGetUsersWithOtherQueryPageToken,
+		"handle get users query with token and offset":    testHandleGetUsersWithPageTokenAndOffset,
+		"handle get users query with total count":         testHandleGetUsersWithTotalCount,
+		"handle get users query with count error":         testHandleGetUsersWithCountError,
+		"handle get users query with iteration error":     testHandleGetUsersWithIterationError,
+		"handle get users query with read mask":           testHandleGetUsersWithReadMask,
+		"handle get users query with hidden read mask":    testHandleGetUsersWithHiddenReadMask,
 	} {
 		test := test
 		t.Run(
@@ -31,27 +51,59 @@ func TestGetUsers(t *testing.T) {
 	}
 }
 
+/*
+readUserStream reads the whole stream, returning its users and the page they belong to.
+*/
+func readUserStream(t *testing.T, stream UserStream) ([]*User, UserPage) {
+	var users []*User
+
+	for stream.Next(context.Background()) {
+		users = append(users, stream.User())
+	}
+
+	assert.NoError(t, stream.Err())
+	assert.NoError(t, stream.Close(context.Background()))
+
+	return users, stream.Page()
+}
+
+func queryFingerprint(t *testing.T, filter query_utils.FilterExpression, sort []query_utils.Sort) string {
+	fingerprint, err := query_utils.QueryFingerprint(filter, sort)
+	require.NoError(t, err)
+
+	return fingerprint
+}
+
 func testNewGetUsersHandler(t *testing.T) {
 	mockRepo := new(mocks.UserRepository)
 
-	newHandler := NewGetUsersHandler(mockRepo)
+	newHandler := NewGetUsersHandler(mockRepo, testPageTokens)
 
 	assert.NotNil(t, newHandler)
-	assert.Equal(t, &GetUsersHandler{mockRepo}, newHandler)
+	assert.Equal(t, &GetUsersHandler{mockRepo, testPageTokens}, newHandler)
 	assert.Same(t, mockRepo, newHandler.userRepo)
+	assert.Same(t, testPageTokens, newHandler.pageTokens)
 }
 
 func testNewGetUsersHandlerWithoutRepo(t *testing.T) {
 	assert.PanicsWithValue(
 		t, "[query/get_users] nil userRepo", func() {
-			NewGetUsersHandler(nil)
+			NewGetUsersHandler(nil, testPageTokens)
+		},
+	)
+}
+
+func testNewGetUsersHandlerWithoutPageTokens(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[query/get_users] nil pageTokens", func() {
+			NewGetUsersHandler(new(mocks.UserRepository), nil)
 		},
 	)
 }
 
 func testHandleGetUsers(t *testing.T) {
 	mockRepo := new(mocks.UserRepository)
-	handler := GetUsersHandler{mockRepo}
+	handler := GetUsersHandler{mockRepo, testPageTokens}
 
 	ctx := context.Background()
 	filters := []query_utils.Filter{
@@ -72,7 +124,9 @@ func testHandleGetUsers(t *testing.T) {
 		Offset: 0,
 	}
 
-	mockRepo.On("GetUsers", ctx, filters, sort, pagination).Return([]*user.User{&user.User1}, nil)
+	mockRepo.On("GetUsers", ctx, query_utils.FilterExpression{Filters: filters}, sort, pagination, []string(nil)).Return(
+		user.NewSliceUserIterator([]*user.User{&user.User1}), nil,
+	)
 
 	out, err := handler.Handle(
 		ctx, GetUsers{
@@ -85,7 +139,8 @@ func testHandleGetUsers(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockRepo.AssertNumberOfCalls(t, "GetUsers", 1)
 
-	assert.NoError(t, err)
+	require.NoError(t, err)
+	users, page := readUserStream(t, out)
 	assert.Equal(
 		t, []*User{
 			{
@@ -93,19 +148,21 @@ func testHandleGetUsers(t *testing.T) {
 				FirstName: user.User1.FirstName(),
 				LastName:  user.User1.LastName(),
 				Nickname:  user.User1.Nickname(),
-				Password:  user.User1.Password(),
 				Email:     user.User1.Email(),
 				Country:   user.User1.Country(),
+				Roles:     []string{"user"},
 				CreatedAt: user.User1.CreatedAt(),
 				UpdatedAt: user.User1.UpdatedAt(),
+				Version:   user.User1.Version(),
 			},
-		}, out,
+		}, users,
 	)
+	assert.Empty(t, page.NextPageToken)
 }
 
 func testHandleGetUsersWithoutParameters(t *testing.T) {
 	mockRepo := new(mocks.UserRepository)
-	handler := GetUsersHandler{mockRepo}
+	handler := GetUsersHandler{mockRepo, testPageTokens}
 
 	ctx := context.Background()
 	var filters []query_utils.Filter
@@ -115,7 +172,9 @@ func testHandleGetUsersWithoutParameters(t *testing.T) {
 		Offset: 0,
 	}
 
-	mockRepo.On("GetUsers", ctx, filters, sort, pagination).Return([]*user.User{&user.User1}, nil)
+	mockRepo.On("GetUsers", ctx, query_utils.FilterExpression{Filters: filters}, sort, pagination, []string(nil)).Return(
+		user.NewSliceUserIterator([]*user.User{&user.User1}), nil,
+	)
 
 	out, err := handler.Handle(
 		ctx, GetUsers{
@@ -128,7 +187,8 @@ func testHandleGetUsersWithoutParameters(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockRepo.AssertNumberOfCalls(t, "GetUsers", 1)
 
-	assert.NoError(t, err)
+	require.NoError(t, err)
+	users, page := readUserStream(t, out)
 	assert.Equal(
 		t, []*User{
 			{
@@ -136,19 +196,21 @@ func testHandleGetUsersWithoutParameters(t *testing.T) {
 				FirstName: user.User1.FirstName(),
 				LastName:  user.User1.LastName(),
 				Nickname:  user.User1.Nickname(),
-				Password:  user.User1.Password(),
 				Email:     user.User1.Email(),
 				Country:   user.User1.Country(),
+				Roles:     []string{"user"},
 				CreatedAt: user.User1.CreatedAt(),
 				UpdatedAt: user.User1.UpdatedAt(),
+				Version:   user.User1.Version(),
 			},
-		}, out,
+		}, users,
 	)
+	assert.Empty(t, page.NextPageToken)
 }
 
 func testHandleGetUsersWithRepoError(t *testing.T) {
 	mockRepo := new(mocks.UserRepository)
-	handler := GetUsersHandler{mockRepo}
+	handler := GetUsersHandler{mockRepo, testPageTokens}
 
 	ctx := context.Background()
 	filters := []query_utils.Filter{
@@ -170,7 +232,9 @@ func testHandleGetUsersWithRepoError(t *testing.T) {
 	}
 
 	dbErr := errors.New("db is down")
-	mockRepo.On("GetUsers", ctx, filters, sort, pagination).Return(nil, dbErr)
+	mockRepo.On(
+		"GetUsers", ctx, query_utils.FilterExpression{Filters: filters}, sort, pagination, []string(nil),
+	).Return(nil, dbErr)
 
 	out, err := handler.Handle(
 		ctx, GetUsers{
@@ -186,3 +250,436 @@ func testHandleGetUsersWithRepoError(t *testing.T) {
 	assert.ErrorIs(t, err, dbErr)
 	assert.Nil(t, out)
 }
+
+func testHandleGetUsersWithHiddenField(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	out, err := handler.Handle(
+		context.Background(), GetUsers{
+			Filters: []query_utils.Filter{
+				{
+					Field:    "password",
+					Operator: operators.EQUALS,
+					Value:    "123",
+				},
+			},
+		},
+	)
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsers", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "filters[0].field",
+			Value:  "password",
+			Reason: pkgErrors.ReasonUnknownValue,
+		}, err,
+	)
+	assert.Nil(t, out)
+}
+
+func testHandleGetUsersWithExpression(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	ctx := context.Background()
+	filters := []query_utils.Filter{
+		{
+			Field:    "country",
+			Operator: operators.EQUALS,
+			Value:    "ES",
+		},
+	}
+	expression := query_utils.FilterExpression{
+		Operator: operators.OR,
+		Filters: []query_utils.Filter{
+			{
+				Field:    "nickname",
+				Operator: operators.EQUALS,
+				Value:    "john",
+			},
+			{
+				Field:    "roles",
+				Operator: operators.EQUALS,
+				Value:    "admin",
+			},
+		},
+	}
+	pagination := query_utils.Pagination{}
+
+	mockRepo.On(
+		"GetUsers", ctx, query_utils.FilterExpression{
+			Operator:    operators.AND,
+			Filters:     filters,
+			Expressions: []query_utils.FilterExpression{expression},
+		}, []query_utils.Sort(nil), pagination, []string(nil),
+	).Return(user.NewSliceUserIterator([]*user.User{&user.User1}), nil)
+
+	out, err := handler.Handle(
+		ctx, GetUsers{
+			Filters:    filters,
+			Filter:     &expression,
+			Pagination: pagination,
+		},
+	)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "GetUsers", 1)
+
+	require.NoError(t, err)
+	users, _ := readUserStream(t, out)
+	assert.Len(t, users, 1)
+}
+
+func testHandleGetUsersWithInvalidExpression(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	out, err := handler.Handle(
+		context.Background(), GetUsers{
+			Filter: &query_utils.FilterExpression{
+				Operator: operators.NOT,
+				Filters: []query_utils.Filter{
+					{
+						Field:    "password",
+						Operator: operators.EQUALS,
+						Value:    "123",
+					},
+				},
+			},
+		},
+	)
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsers", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "filter.filters[0].field",
+			Value:  "password",
+			Reason: pkgErrors.ReasonUnknownValue,
+		}, err,
+	)
+	assert.Nil(t, out)
+}
+
+func testHandleGetUsersWithNextPage(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	ctx := context.Background()
+	sort := []query_utils.Sort{{Field: "country", Direction: operators.DESC}}
+	tiebrokenSort := []query_utils.Sort{
+		{Field: "country", Direction: operators.DESC},
+		{Field: "id", Direction: operators.ASC},
+	}
+	filter := query_utils.FilterExpression{Operator: operators.AND}
+
+	mockRepo.On("GetUsers", ctx, filter, tiebrokenSort, query_utils.Pagination{Limit: 2, Offset: 1}, []string(nil)).Return(
+		user.NewSliceUserIterator([]*user.User{&user.User1, &user.Admin1}), nil,
+	)
+
+	out, err := handler.Handle(ctx, GetUsers{Sort: sort, Pagination: query_utils.Pagination{Limit: -1, Offset: 1}})
+
+	mockRepo.AssertExpectations(t)
+
+	require.NoError(t, err)
+	users, page := readUserStream(t, out)
+	assert.Len(t, users, 1)
+	assert.Equal(t, user.User1.Id(), users[0].Id)
+	assert.True(t, page.HasMore)
+
+	nextPage, err := testPageTokens.Verify(page.NextPageToken)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, query_utils.PageToken{
+			Query:  queryFingerprint(t, filter, tiebrokenSort),
+			Values: []interface{}{user.User1.Country(), user.User1.Id()},
+		}, nextPage,
+	)
+}
+
+func testHandleGetUsersWithLastPage(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	ctx := context.Background()
+
+	mockRepo.On(
+		"GetUsers", ctx, query_utils.FilterExpression{Operator: operators.AND},
+		[]query_utils.Sort{{Field: "id", Direction: operators.ASC}}, query_utils.Pagination{Limit: 3}, []string(nil),
+	).Return(user.NewSliceUserIterator([]*user.User{&user.User1, &user.Admin1}), nil)
+
+	out, err := handler.Handle(ctx, GetUsers{Pagination: query_utils.Pagination{Limit: 2}})
+
+	mockRepo.AssertExpectations(t)
+
+	require.NoError(t, err)
+	users, page := readUserStream(t, out)
+	assert.Len(t, users, 2)
+	assert.Empty(t, page.NextPageToken)
+	assert.False(t, page.HasMore)
+}
+
+func testHandleGetUsersWithPageToken(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	ctx := context.Background()
+	filters := []query_utils.Filter{{Field: "country", Operator: operators.NOT_EQUALS, Value: "FR"}}
+	sort := []query_utils.Sort{
+		{Field: "created_at", Direction: operators.DESC},
+		{Field: "id", Direction: operators.ASC},
+	}
+	filter := query_utils.FilterExpression{Operator: operators.AND, Filters: filters}
+	createdAt := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	pageToken, err := testPageTokens.Sign(
+		query_utils.PageToken{
+			Query:  queryFingerprint(t, filter, sort),
+			Values: []interface{}{createdAt, "1"},
+		},
+	)
+	assert.NoError(t, err)
+
+	mockRepo.On(
+		"GetUsers", ctx, query_utils.FilterExpression{
+			Operator: operators.AND,
+			Filters:  filters,
+			Expressions: []query_utils.FilterExpression{
+				query_utils.KeysetFilter(sort, []interface{}{createdAt, "1"}),
+			},
+		}, sort, query_utils.Pagination{Limit: 2}, []string(nil),
+	).Return(user.NewSliceUserIterator([]*user.User{&user.Admin1}), nil)
+
+	out, err := handler.Handle(
+		ctx, GetUsers{
+			Filters:    filters,
+			Sort:       sort[:1],
+			Pagination: query_utils.Pagination{Limit: 1},
+			PageToken:  pageToken,
+		},
+	)
+
+	mockRepo.AssertExpectations(t)
+
+	require.NoError(t, err)
+	users, page := readUserStream(t, out)
+	assert.Len(t, users, 1)
+	assert.Empty(t, page.NextPageToken)
+}
+
+func testHandleGetUsersWithInvalidPageToken(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	out, err := handler.Handle(context.Background(), GetUsers{PageToken: "forged"})
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsers", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "page_token",
+			Reason: pkgErrors.ReasonInvalidFormat,
+		}, err,
+	)
+	assert.Nil(t, out)
+}
+
+func testHandleGetUsersWithOtherQueryPageToken(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	pageToken, err := testPageTokens.Sign(
+		query_utils.PageToken{
+			Query: queryFingerprint(
+				t, query_utils.FilterExpression{Operator: operators.AND},
+				[]query_utils.Sort{{Field: "id", Direction: operators.ASC}},
+			),
+			Values: []interface{}{"1"},
+		},
+	)
+	assert.NoError(t, err)
+
+	out, err := handler.Handle(
+		context.Background(), GetUsers{
+			Sort:      []query_utils.Sort{{Field: "nickname", Direction: operators.ASC}},
+			PageToken: pageToken,
+		},
+	)
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsers", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "page_token",
+			Reason: query_utils.ReasonQueryMismatch,
+		}, err,
+	)
+	assert.Nil(t, out)
+}
+
+func testHandleGetUsersWithPageTokenAndOffset(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	out, err := handler.Handle(
+		context.Background(), GetUsers{
+			Pagination: query_utils.Pagination{Limit: 1, Offset: 1},
+			PageToken:  "token",
+		},
+	)
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsers", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "pagination.offset",
+			Value:  int64(1),
+			Reason: pkgErrors.ReasonOutOfRange,
+		}, err,
+	)
+	assert.Nil(t, out)
+}
+
+func testHandleGetUsersWithTotalCount(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	ctx := context.Background()
+	sort := []query_utils.Sort{{Field: "id", Direction: operators.ASC}}
+	filter := query_utils.FilterExpression{Operator: operators.AND}
+
+	pageToken, err := testPageTokens.Sign(
+		query_utils.PageToken{Query: queryFingerprint(t, filter, sort), Values: []interface{}{"1"}},
+	)
+	assert.NoError(t, err)
+
+	// The count ignores the page token, while the users start after it.
+	mockRepo.On("CountUsers", ctx, filter).Return(int64(2), nil)
+	mockRepo.On(
+		"GetUsers", ctx, query_utils.FilterExpression{
+			Operator:    operators.AND,
+			Expressions: []query_utils.FilterExpression{query_utils.KeysetFilter(sort, []interface{}{"1"})},
+		}, sort, query_utils.Pagination{Limit: 2}, []string(nil),
+	).Return(user.NewSliceUserIterator([]*user.User{&user.Admin1}), nil)
+
+	out, err := handler.Handle(
+		ctx, GetUsers{Pagination: query_utils.Pagination{Limit: 1}, PageToken: pageToken, IncludeTotalCount: true},
+	)
+
+	mockRepo.AssertExpectations(t)
+
+	require.NoError(t, err)
+	users, page := readUserStream(t, out)
+	assert.Len(t, users, 1)
+	assert.False(t, page.HasMore)
+	assert.Equal(t, int64(2), page.TotalCount)
+}
+
+func testHandleGetUsersWithCountError(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	ctx := context.Background()
+	dbErr := errors.New("db is down")
+
+	mockRepo.On("CountUsers", ctx, query_utils.FilterExpression{Operator: operators.AND}).Return(int64(0), dbErr)
+
+	out, err := handler.Handle(ctx, GetUsers{IncludeTotalCount: true})
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "GetUsers", 0)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, out)
+}
+
+func testHandleGetUsersWithIterationError(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	mockIterator := new(mocks.UserIterator)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	ctx := context.Background()
+	dbErr := errors.New("db is down")
+
+	mockRepo.On(
+		"GetUsers", ctx, query_utils.FilterExpression{}, []query_utils.Sort(nil), query_utils.Pagination{}, []string(nil),
+	).Return(
+		mockIterator, nil,
+	)
+	mockIterator.On("Next", ctx).Return(true).Once()
+	mockIterator.On("User").Return(&user.User1)
+	mockIterator.On("Next", ctx).Return(false)
+	mockIterator.On("Err").Return(dbErr)
+	mockIterator.On("Close", ctx).Return(nil)
+
+	out, err := handler.Handle(ctx, GetUsers{})
+	require.NoError(t, err)
+
+	assert.True(t, out.Next(ctx))
+	assert.Equal(t, user.User1.Id(), out.User().Id)
+	assert.False(t, out.Next(ctx))
+	assert.False(t, out.Next(ctx))
+	assert.ErrorIs(t, out.Err(), dbErr)
+	assert.NoError(t, out.Close(ctx))
+
+	mockRepo.AssertExpectations(t)
+	mockIterator.AssertExpectations(t)
+	mockIterator.AssertNumberOfCalls(t, "Next", 2)
+}
+
+func testHandleGetUsersWithReadMask(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	ctx := context.Background()
+	sort := []query_utils.Sort{{Field: "country", Direction: operators.DESC}}
+
+	// The sort fields are read too, to make the page token.
+	mockRepo.On(
+		"GetUsers", ctx, query_utils.FilterExpression{Operator: operators.AND},
+		query_utils.WithTiebreaker(sort, "id"), query_utils.Pagination{Limit: 2},
+		[]string{"nickname", "id", "country"},
+	).Return(user.NewSliceUserIterator([]*user.User{&user.User1, &user.Admin1}), nil)
+
+	out, err := handler.Handle(
+		ctx, GetUsers{
+			Sort:       sort,
+			Pagination: query_utils.Pagination{Limit: 1},
+			ReadMask:   []string{"nickname", "id", "nickname"},
+		},
+	)
+
+	mockRepo.AssertExpectations(t)
+
+	require.NoError(t, err)
+	users, page := readUserStream(t, out)
+	assert.Len(t, users, 1)
+	assert.True(t, page.HasMore)
+}
+
+func testHandleGetUsersWithHiddenReadMask(t *testing.T) {
+	mockRepo := new(mocks.UserRepository)
+	handler := GetUsersHandler{mockRepo, testPageTokens}
+
+	out, err := handler.Handle(context.Background(), GetUsers{ReadMask: []string{"id", "password"}})
+
+	mockRepo.AssertNumberOfCalls(t, "GetUsers", 0)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{
+			Domain: "User",
+			Field:  "read_mask.paths[1]",
+			Value:  "password",
+			Reason: pkgErrors.ReasonUnknownValue,
+		}, err,
+	)
+	assert.Nil(t, out)
+}
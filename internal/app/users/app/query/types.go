@@ -1,15 +1,67 @@
 package query
 
-import "time"
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"time"
+)
 
+/*
+User is the read model returned by the user queries.
+
+It deliberately doesn't carry the password hash, as query results end up being
+exposed by the ports, and secrets should never leave the domain layer.
+*/
 type User struct {
 	Id        string
 	FirstName string
 	LastName  string
 	Nickname  string
-	Password  string
 	Email     string
 	Country   string
+	Roles     []string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Version   int64
+}
+
+/*
+UserResult is the result for one of the ids of the GetUsersByIds query. User is nil when nobody has the id.
+*/
+type UserResult struct {
+	Id   string
+	User *User
+}
+
+/*
+UserPage describes the page of users streamed by the GetUsers query.
+
+HasMore tells whether there are more users after the page, which can be got
+with NextPageToken. TotalCount is the number of users matching the filters,
+across every page, as long as the query asked for it.
+*/
+type UserPage struct {
+	NextPageToken string
+	HasMore       bool
+	TotalCount    int64
+}
+
+func marshalRoles(roles []user.Role) []string {
+	out := make([]string, len(roles))
+	for i, role := range roles {
+		out[i] = string(role)
+	}
+
+	return out
+}
+
+/*
+SigningKey is the read model for a public token signing key, following the JSON Web Key format.
+*/
+type SigningKey struct {
+	Kty string
+	Kid string
+	Use string
+	Alg string
+	N   string
+	E   string
 }
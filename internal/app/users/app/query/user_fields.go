@@ -0,0 +1,82 @@
+package query
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+)
+
+/*
+UserFields declares the user fields that can be filtered and sorted on, named as in the API.
+
+The password is left out on purpose, as it should never be queried. Roles can
+only be filtered, as sorting by a list of values is rarely what callers mean.
+The version can only be read, to update the user from it.
+*/
+var UserFields = query_utils.NewFieldRegistry(
+	"User", map[string]query_utils.Field{
+		"id":         {Storage: "id", Type: query_utils.StringValue, Filterable: true, Sortable: true},
+		"first_name": {Storage: "first_name", Type: query_utils.StringValue, Filterable: true, Sortable: true},
+		"last_name":  {Storage: "last_name", Type: query_utils.StringValue, Filterable: true, Sortable: true},
+		"nickname":   {Storage: "nickname", Type: query_utils.StringValue, Filterable: true, Sortable: true},
+		"email":      {Storage: "email", Type: query_utils.StringValue, Filterable: true, Sortable: true},
+		"country":    {Storage: "country", Type: query_utils.StringValue, Filterable: true, Sortable: true},
+		"roles":      {Storage: "roles", Type: query_utils.StringValue, Filterable: true, MultiValued: true},
+		"created_at": {Storage: "created_at", Type: query_utils.TimestampValue, Filterable: true, Sortable: true},
+		"updated_at": {Storage: "updated_at", Type: query_utils.TimestampValue, Filterable: true, Sortable: true},
+		"version":    {Storage: "version", Type: query_utils.IntValue},
+	},
+)
+
+/*
+mapUserQuery validates the filters, the filter expression and the sort of a user query against UserFields, returning
+the filter expression and the sort to pass to the repository, with the storage field names.
+*/
+func mapUserQuery(
+	filters []query_utils.Filter, expression *query_utils.FilterExpression, sort []query_utils.Sort,
+) (query_utils.FilterExpression, []query_utils.Sort, error) {
+	mappedFilters, mappedSort, err := UserFields.Map(filters, sort)
+
+	if err != nil {
+		return query_utils.FilterExpression{}, nil, err
+	}
+
+	filter := query_utils.FilterExpression{Operator: operators.AND, Filters: mappedFilters}
+
+	if expression != nil {
+		mappedExpression, err := UserFields.MapExpression(*expression)
+
+		if err != nil {
+			return query_utils.FilterExpression{}, nil, err
+		}
+
+		filter.Expressions = []query_utils.FilterExpression{mappedExpression}
+	}
+
+	return filter, mappedSort, nil
+}
+
+/*
+sortValue returns the value of the user field stored as the given sortable field, to build the page tokens.
+*/
+func sortValue(u *User, field string) interface{} {
+	switch field {
+	case "id":
+		return u.Id
+	case "first_name":
+		return u.FirstName
+	case "last_name":
+		return u.LastName
+	case "nickname":
+		return u.Nickname
+	case "email":
+		return u.Email
+	case "country":
+		return u.Country
+	case "created_at":
+		return u.CreatedAt
+	case "updated_at":
+		return u.UpdatedAt
+	default:
+		return nil
+	}
+}
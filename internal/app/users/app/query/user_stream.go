@@ -0,0 +1,115 @@
+package query
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+)
+
+/*
+UserStream streams the users of the GetUsers query as the repository reads them, only reading the next one once the
+caller asks for it.
+
+Next advances to the next user, returning false once the page is over, or when
+reading failed, as Err then tells. Page describes the page once it's over.
+Close releases what the repository holds, like a database cursor, and must
+always be called, even after an error.
+*/
+type UserStream interface {
+	Next(ctx context.Context) bool
+	User() *User
+	Err() error
+	Page() UserPage
+	Close(ctx context.Context) error
+}
+
+/*
+userStream reads up to pageSize users, taking one more from the repository as a sign there's a next page.
+*/
+type userStream struct {
+	users       user.UserIterator
+	pageSize    int64
+	pageTokens  *query_utils.PageTokenSigner
+	sort        []query_utils.Sort
+	fingerprint string
+
+	read    int64
+	current *User
+	page    UserPage
+	done    bool
+	err     error
+}
+
+func (s *userStream) Next(ctx context.Context) bool {
+	if s.done {
+		return false
+	}
+
+	if !s.users.Next(ctx) {
+		s.done = true
+		s.err = s.users.Err()
+
+		return false
+	}
+
+	if s.pageSize > 0 && s.read == s.pageSize {
+		s.done = true
+		s.page.HasMore = true
+		s.page.NextPageToken, s.err = s.nextPageToken()
+
+		return false
+	}
+
+	u := s.users.User()
+	s.read++
+	s.current = &User{
+		Id:        u.Id(),
+		FirstName: u.FirstName(),
+		LastName:  u.LastName(),
+		Nickname:  u.Nickname(),
+		Email:     u.Email(),
+		Country:   u.Country(),
+		Roles:     marshalRoles(u.Roles()),
+		CreatedAt: u.CreatedAt(),
+		UpdatedAt: u.UpdatedAt(),
+		Version:   u.Version(),
+	}
+
+	return true
+}
+
+/*
+nextPageToken signs the position of the last user of the page.
+*/
+func (s *userStream) nextPageToken() (string, error) {
+	nextPage := query_utils.PageToken{Query: s.fingerprint}
+
+	for _, sort := range s.sort {
+		nextPage.Values = append(nextPage.Values, sortValue(s.current, sort.Field))
+	}
+
+	token, err := s.pageTokens.Sign(nextPage)
+
+	if err != nil {
+		return "", &errors.Unknown{Tag: getUsersTag, Cause: err}
+	}
+
+	return token, nil
+}
+
+func (s *userStream) User() *User {
+	return s.current
+}
+
+func (s *userStream) Err() error {
+	return s.err
+}
+
+func (s *userStream) Page() UserPage {
+	return s.page
+}
+
+func (s *userStream) Close(ctx context.Context) error {
+	return s.users.Close(ctx)
+}
@@ -0,0 +1,13 @@
+package token
+
+import "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+
+/*
+Registers the errors of the token domain, so every port reports them with the same code.
+
+NotFoundError is left out on purpose: it would give away token ids, so the
+commands turn it into an InvalidTokenError before it reaches any port.
+*/
+func init() {
+	errors.Register[*InvalidTokenError](errors.CodeUnauthenticated, "")
+}
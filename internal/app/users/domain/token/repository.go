@@ -0,0 +1,27 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type NotFoundError struct {
+	Id string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("Refresh token with id %s not found", e.Id)
+}
+
+/* RefreshTokenRepository
+Same as with the UserRepository, naming it just "Repository" would mess up the mock generation with Mockery.
+*/
+
+type RefreshTokenRepository interface {
+	AddRefreshToken(ctx context.Context, token *RefreshToken) error
+	GetRefreshTokenById(ctx context.Context, tokenId string) (*RefreshToken, error)
+	// RevokeRefreshToken must only succeed if the token wasn't revoked yet, returning a NotFoundError otherwise.
+	RevokeRefreshToken(ctx context.Context, tokenId string, revokedAt time.Time) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyId string, revokedAt time.Time) error
+}
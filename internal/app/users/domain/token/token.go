@@ -0,0 +1,201 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"time"
+)
+
+var nowFunc = time.Now
+var randRead = rand.Read
+
+const domain = "RefreshToken"
+
+// 32 random bytes give refresh tokens 256 bits of entropy, way beyond brute-force reach.
+const refreshTokenBytes = 32
+
+/*
+InvalidTokenError is returned whenever a refresh token can't be used, for whatever reason.
+
+The reason is kept for logging purposes, but it shouldn't be exposed to clients.
+*/
+type InvalidTokenError struct {
+	Reason string
+}
+
+func (e *InvalidTokenError) Error() string {
+	return "Invalid refresh token"
+}
+
+/*
+A RefreshToken holds our domain model for a refresh token issued to a user.
+
+We never store the token value itself, only its SHA-256 hash, which also acts as
+its id. This way, a leak of the database doesn't give away usable tokens. Unlike
+passwords, tokens are long random strings, so a fast hash is enough here.
+
+Every refresh token belongs to a family: the chain of tokens obtained by
+rotating the one issued on login. If a token that was already rotated is used
+again, we can assume it was stolen, and revoke the whole family.
+*/
+type RefreshToken struct {
+	id        string
+	userId    string
+	familyId  string
+	createdAt time.Time
+	expiresAt time.Time
+	revokedAt time.Time
+}
+
+func (t *RefreshToken) Id() string {
+	return t.id
+}
+
+func (t *RefreshToken) UserId() string {
+	return t.userId
+}
+
+func (t *RefreshToken) FamilyId() string {
+	return t.familyId
+}
+
+func (t *RefreshToken) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+func (t *RefreshToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+func (t *RefreshToken) RevokedAt() time.Time {
+	return t.revokedAt
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return !t.revokedAt.IsZero()
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return !nowFunc().Before(t.expiresAt)
+}
+
+/*
+Validate checks whether the token can still be exchanged for a new pair of tokens.
+*/
+func (t *RefreshToken) Validate() error {
+	if t.IsRevoked() {
+		return &InvalidTokenError{Reason: "token revoked"}
+	}
+
+	if t.IsExpired() {
+		return &InvalidTokenError{Reason: "token expired"}
+	}
+
+	return nil
+}
+
+/*
+IssueRefreshToken generates a new refresh token for the given user and returns it along with its plain value.
+
+The plain value is returned only here, as it's the only moment we know it. From
+then on, the token can only be found by hashing the value the client sends.
+*/
+func IssueRefreshToken(userId string, familyId string, ttl time.Duration) (*RefreshToken, string, error) {
+	var invalidFields []error
+
+	if userId == "" {
+		invalidFields = append(
+			invalidFields, &errors.InvalidField{
+				Domain: domain,
+				Field:  "user_id",
+				Value:  userId,
+				Reason: errors.ReasonRequired,
+			},
+		)
+	}
+
+	if familyId == "" {
+		invalidFields = append(
+			invalidFields, &errors.InvalidField{
+				Domain: domain,
+				Field:  "family_id",
+				Value:  familyId,
+				Reason: errors.ReasonRequired,
+			},
+		)
+	}
+
+	if ttl <= 0 {
+		invalidFields = append(
+			invalidFields, &errors.InvalidField{
+				Domain: domain,
+				Field:  "ttl",
+				Value:  ttl,
+				Reason: errors.ReasonOutOfRange,
+			},
+		)
+	}
+
+	if len(invalidFields) == 1 {
+		return nil, "", invalidFields[0]
+	}
+
+	if len(invalidFields) > 1 {
+		return nil, "", &errors.MultipleInvalidFields{Errors: invalidFields}
+	}
+
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := randRead(raw); err != nil {
+		return nil, "", &errors.Unknown{
+			Tag:   domain,
+			Cause: err,
+		}
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(raw)
+	now := nowFunc()
+
+	return &RefreshToken{
+		id:        HashValue(value),
+		userId:    userId,
+		familyId:  familyId,
+		createdAt: now,
+		expiresAt: now.Add(ttl),
+	}, value, nil
+}
+
+/*
+HashValue returns the id of the refresh token with the given plain value.
+*/
+func HashValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+UnmarshalRefreshTokenFromDB is the method we use to unmarshal data from the database model to the domain model.
+
+As with users, data coming from the database is trusted, so no validation is
+applied here.
+*/
+func UnmarshalRefreshTokenFromDB(
+	id string,
+	userId string,
+	familyId string,
+	createdAt time.Time,
+	expiresAt time.Time,
+	revokedAt time.Time,
+) *RefreshToken {
+	return &RefreshToken{
+		id:        id,
+		userId:    userId,
+		familyId:  familyId,
+		createdAt: createdAt,
+		expiresAt: expiresAt,
+		revokedAt: revokedAt,
+	}
+}
@@ -0,0 +1,12 @@
+package token
+
+import "time"
+
+var token1Now = time.Now()
+var Token1 = RefreshToken{
+	id:        HashValue("token-1"),
+	userId:    "1",
+	familyId:  "family-1",
+	createdAt: token1Now,
+	expiresAt: token1Now.Add(time.Hour),
+}
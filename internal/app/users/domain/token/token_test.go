@@ -0,0 +1,161 @@
+package token
+
+import (
+	"crypto/rand"
+	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func setNow(t time.Time) {
+	nowFunc = func() time.Time {
+		return t
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"getters": {
+			"should return the correct values": testGetters,
+		},
+		"issue refresh token": {
+			"issue refresh token":                     testIssueRefreshToken,
+			"issue refresh token with invalid field":  testIssueRefreshTokenWithInvalidField,
+			"issue refresh token with invalid fields": testIssueRefreshTokenWithInvalidFields,
+			"issue refresh token with rand error":     testIssueRefreshTokenWithRandError,
+		},
+		"validate refresh token": {
+			"validate active token":  testValidateActiveToken,
+			"validate revoked token": testValidateRevokedToken,
+			"validate expired token": testValidateExpiredToken,
+		},
+		"unmarshal refresh token": {
+			"unmarshal refresh token": testUnmarshalRefreshToken,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+
+	// Set the stubbed functions back to their original values so they don't affect other tests.
+	nowFunc = time.Now
+	randRead = rand.Read
+}
+
+func testGetters(t *testing.T) {
+	refreshToken := Token1
+	refreshToken.revokedAt = time.Now()
+
+	assert.Equal(t, refreshToken.id, refreshToken.Id())
+	assert.Equal(t, refreshToken.userId, refreshToken.UserId())
+	assert.Equal(t, refreshToken.familyId, refreshToken.FamilyId())
+	assert.Equal(t, refreshToken.createdAt, refreshToken.CreatedAt())
+	assert.Equal(t, refreshToken.expiresAt, refreshToken.ExpiresAt())
+	assert.Equal(t, refreshToken.revokedAt, refreshToken.RevokedAt())
+	assert.True(t, refreshToken.IsRevoked())
+}
+
+func testIssueRefreshToken(t *testing.T) {
+	now := time.Now()
+	setNow(now)
+
+	got, value, err := IssueRefreshToken("1", "family-1", time.Hour)
+
+	assert.NoError(t, err)
+	assert.Len(t, value, 43)
+	assert.Equal(
+		t, &RefreshToken{
+			id:        HashValue(value),
+			userId:    "1",
+			familyId:  "family-1",
+			createdAt: now,
+			expiresAt: now.Add(time.Hour),
+		}, got,
+	)
+	assert.False(t, got.IsRevoked())
+}
+
+func testIssueRefreshTokenWithInvalidField(t *testing.T) {
+	got, value, err := IssueRefreshToken("", "family-1", time.Hour)
+
+	assert.Equal(
+		t, &pkgErrors.InvalidField{Domain: domain, Field: "user_id", Value: "", Reason: pkgErrors.ReasonRequired}, err,
+	)
+	assert.Nil(t, got)
+	assert.Empty(t, value)
+}
+
+func testIssueRefreshTokenWithInvalidFields(t *testing.T) {
+	got, value, err := IssueRefreshToken("1", "", 0)
+
+	assert.IsType(t, &pkgErrors.MultipleInvalidFields{}, err)
+	assert.Len(t, err.(*pkgErrors.MultipleInvalidFields).Errors, 2)
+	assert.Nil(t, got)
+	assert.Empty(t, value)
+}
+
+func testIssueRefreshTokenWithRandError(t *testing.T) {
+	randErr := errors.New("no entropy")
+	randRead = func(b []byte) (int, error) {
+		return 0, randErr
+	}
+
+	got, value, err := IssueRefreshToken("1", "family-1", time.Hour)
+
+	assert.Equal(t, &pkgErrors.Unknown{Tag: domain, Cause: randErr}, err)
+	assert.Nil(t, got)
+	assert.Empty(t, value)
+
+	randRead = rand.Read
+}
+
+func testValidateActiveToken(t *testing.T) {
+	setNow(Token1.createdAt)
+	refreshToken := Token1
+
+	assert.NoError(t, refreshToken.Validate())
+}
+
+func testValidateRevokedToken(t *testing.T) {
+	setNow(Token1.createdAt)
+	refreshToken := Token1
+	refreshToken.revokedAt = Token1.createdAt
+
+	assert.Equal(t, &InvalidTokenError{Reason: "token revoked"}, refreshToken.Validate())
+}
+
+func testValidateExpiredToken(t *testing.T) {
+	setNow(Token1.expiresAt)
+	refreshToken := Token1
+
+	err := refreshToken.Validate()
+
+	assert.Equal(t, &InvalidTokenError{Reason: "token expired"}, err)
+	assert.Equal(t, "Invalid refresh token", err.Error())
+}
+
+func testUnmarshalRefreshToken(t *testing.T) {
+	now := time.Now()
+
+	out := UnmarshalRefreshTokenFromDB("id", "user", "family", now, now.Add(time.Hour), now)
+
+	assert.Equal(t, "id", out.id)
+	assert.Equal(t, "user", out.userId)
+	assert.Equal(t, "family", out.familyId)
+	assert.Equal(t, now, out.createdAt)
+	assert.Equal(t, now.Add(time.Hour), out.expiresAt)
+	assert.Equal(t, now, out.revokedAt)
+}
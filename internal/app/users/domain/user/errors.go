@@ -0,0 +1,18 @@
+package user
+
+import "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+
+/*
+Registers the errors of the user domain, so every port reports them with the same code.
+
+The ones naming the users they're about are reported with a fixed message, as
+their ids, nicknames or emails may come from whoever is asking, and telling them
+back would help to find out which users exist. They're still logged whole.
+*/
+func init() {
+	errors.Register[*NotFoundError](errors.CodeNotFound, "User not found")
+	errors.Register[*AlreadyExistsError](errors.CodeAlreadyExists, "")
+	errors.Register[*ForbiddenError](errors.CodePermissionDenied, "Not allowed to modify the user")
+	errors.Register[*InvalidCredentialsError](errors.CodeUnauthenticated, "")
+	errors.Register[*VersionConflictError](errors.CodeAborted, "User is no longer at the expected version")
+}
@@ -0,0 +1,62 @@
+package user
+
+import "context"
+
+/*
+UserIterator walks through the users found by a repository as they're read, so they never need to be held in memory
+at once, and the next one is only read when the caller is ready for it.
+
+Next advances to the next user, returning false once there are no more, or
+when reading failed, as Err then tells. Close releases whatever the iterator
+holds, like a database cursor, and must always be called, even after an error.
+*/
+type UserIterator interface {
+	Next(ctx context.Context) bool
+	User() *User
+	Err() error
+	Close(ctx context.Context) error
+}
+
+/*
+SliceUserIterator iterates through users that are already in memory, for repositories that can't stream them.
+*/
+type SliceUserIterator struct {
+	users   []*User
+	current *User
+	err     error
+}
+
+func NewSliceUserIterator(users []*User) *SliceUserIterator {
+	return &SliceUserIterator{users: users}
+}
+
+/*
+Next advances to the next user, as long as the context isn't done.
+*/
+func (i *SliceUserIterator) Next(ctx context.Context) bool {
+	if i.err != nil || len(i.users) == 0 {
+		return false
+	}
+
+	if i.err = ctx.Err(); i.err != nil {
+		return false
+	}
+
+	i.current, i.users = i.users[0], i.users[1:]
+
+	return true
+}
+
+func (i *SliceUserIterator) User() *User {
+	return i.current
+}
+
+func (i *SliceUserIterator) Err() error {
+	return i.err
+}
+
+func (i *SliceUserIterator) Close(context.Context) error {
+	i.users = nil
+
+	return nil
+}
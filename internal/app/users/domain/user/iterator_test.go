@@ -0,0 +1,52 @@
+package user
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSliceUserIterator(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should iterate through the users": testSliceUserIterator,
+		"should stop once cancelled":       testSliceUserIteratorCancelled,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testSliceUserIterator(t *testing.T) {
+	ctx := context.Background()
+	it := NewSliceUserIterator([]*User{&User1, &Admin1})
+
+	assert.True(t, it.Next(ctx))
+	assert.Equal(t, &User1, it.User())
+	assert.True(t, it.Next(ctx))
+	assert.Equal(t, &Admin1, it.User())
+	assert.False(t, it.Next(ctx))
+	assert.NoError(t, it.Err())
+	assert.NoError(t, it.Close(ctx))
+}
+
+func testSliceUserIteratorCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewSliceUserIterator([]*User{&User1, &Admin1})
+
+	assert.True(t, it.Next(ctx))
+
+	cancel()
+
+	assert.False(t, it.Next(ctx))
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+	assert.NoError(t, it.Close(ctx))
+	assert.False(t, it.Next(context.Background()))
+}
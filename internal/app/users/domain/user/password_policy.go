@@ -0,0 +1,37 @@
+package user
+
+/*
+A PasswordPolicy holds the rules every new password must comply with.
+
+MaxLength can't go over 72, as bcrypt ignores anything past the 72nd byte.
+*/
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	ForbidNickname   bool
+}
+
+/*
+DefaultPasswordPolicy follows the NIST guidelines, favoring length over composition rules.
+*/
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:      8,
+	MaxLength:      72,
+	ForbidNickname: true,
+}
+
+var passwordPolicy = DefaultPasswordPolicy
+
+/*
+SetPasswordPolicy replaces the policy passwords are checked against.
+
+It's meant to be called once on startup, before serving any request, so it isn't
+safe for concurrent use.
+*/
+func SetPasswordPolicy(policy PasswordPolicy) {
+	passwordPolicy = policy
+}
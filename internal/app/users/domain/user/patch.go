@@ -0,0 +1,199 @@
+package user
+
+import (
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"sort"
+)
+
+/*
+Patch holds the new values of the user fields to update, keyed by their names, like "first_name".
+
+Fields left out of the patch are untouched. Every updatable field is required, so
+setting any of them to an empty string is rejected.
+*/
+type Patch map[string]string
+
+/*
+Fields returns the names of the fields in the patch, sorted, which is all of it that should be logged, as the patch may
+hold a new password.
+*/
+func (p Patch) Fields() []string {
+	fields := make([]string, 0, len(p))
+	for field := range p {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
+
+/*
+String formats the patch with the password redacted, as patches end up in the logs.
+*/
+func (p Patch) String() string {
+	redacted := make(map[string]string, len(p))
+	for field, value := range p {
+		redacted[field] = value
+	}
+
+	if _, ok := redacted["password"]; ok {
+		redacted["password"] = "[REDACTED]"
+	}
+
+	return fmt.Sprint(redacted)
+}
+
+/*
+fieldUpdate validates the new value of an updatable field, and sets it on the user.
+
+Validation gets the whole patch, as some rules depend on other fields, like the
+password not containing the nickname the user will end up with.
+*/
+type fieldUpdate struct {
+	validate func(u *User, patch Patch, value string) []error
+	set      func(u *User, value string) error
+}
+
+/*
+updatableFields lists the fields Update can change, in the order their errors are reported.
+*/
+var updatableFields = []string{"first_name", "last_name", "nickname", "password", "email", "country"}
+
+var fieldUpdates = map[string]fieldUpdate{
+	"first_name": {
+		validate: validateRequiredUpdate("first_name"),
+		set:      func(u *User, value string) error { u.firstName = value; return nil },
+	},
+	"last_name": {
+		validate: validateRequiredUpdate("last_name"),
+		set:      func(u *User, value string) error { u.lastName = value; return nil },
+	},
+	"nickname": {
+		validate: func(_ *User, _ Patch, value string) []error { return errorList(validateNickname(value)) },
+		set:      func(u *User, value string) error { u.nickname = value; return nil },
+	},
+	"password": {
+		validate: func(u *User, patch Patch, value string) []error {
+			nickname, ok := patch["nickname"]
+			if !ok {
+				nickname = u.nickname
+			}
+
+			return validatePassword(value, nickname)
+		},
+		set: func(u *User, value string) error {
+			hashedPassword, err := hashPassword(value)
+
+			if err != nil {
+				return err
+			}
+
+			u.password = hashedPassword
+
+			return nil
+		},
+	},
+	"email": {
+		validate: func(_ *User, _ Patch, value string) []error { return errorList(validateEmail(value)) },
+		set:      func(u *User, value string) error { u.email = value; return nil },
+	},
+	"country": {
+		validate: func(_ *User, _ Patch, value string) []error { return errorList(validateCountry(value)) },
+		set:      func(u *User, value string) error { u.country = value; return nil },
+	},
+}
+
+/*
+immutableFields can't be changed by Update. Roles do change, but only through GrantRole and RevokeRole, and the version
+is bumped by every update.
+*/
+var immutableFields = map[string]bool{
+	"id": true, "roles": true, "created_at": true, "updated_at": true, "version": true,
+}
+
+/*
+Update applies the patch to the user, validating every field first, so the user is left untouched if any is invalid.
+
+Patches naming unknown fields, or fields that can't be updated, like the id or
+the creation time, are rejected as a whole.
+*/
+func (u *User) Update(patch Patch) error {
+	invalidFields := checkPatchFields(patch)
+
+	for _, field := range updatableFields {
+		if value, ok := patch[field]; ok {
+			invalidFields = append(invalidFields, fieldUpdates[field].validate(u, patch, value)...)
+		}
+	}
+
+	if len(invalidFields) == 1 {
+		return invalidFields[0]
+	}
+
+	if len(invalidFields) > 1 {
+		return &errors.MultipleInvalidFields{Errors: invalidFields}
+	}
+
+	if len(patch) == 0 {
+		return nil
+	}
+
+	updated := *u
+
+	for _, field := range updatableFields {
+		if value, ok := patch[field]; ok {
+			if err := fieldUpdates[field].set(&updated, value); err != nil {
+				return &errors.Unknown{
+					Tag:   domain,
+					Cause: err,
+				}
+			}
+		}
+	}
+
+	updated.updatedAt = nowFunc()
+	*u = updated
+
+	return nil
+}
+
+/*
+checkPatchFields rejects the fields of the patch that Update can't change, in alphabetical order.
+*/
+func checkPatchFields(patch Patch) []error {
+	var fields []string
+	for field := range patch {
+		if _, ok := fieldUpdates[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+
+	sort.Strings(fields)
+
+	var invalidFields []error
+	for _, field := range fields {
+		if immutableFields[field] {
+			invalidFields = append(invalidFields, invalidField(field, nil, errors.ReasonImmutable))
+		} else {
+			invalidFields = append(invalidFields, invalidField(field, nil, errors.ReasonUnknownValue))
+		}
+	}
+
+	return invalidFields
+}
+
+func validateRequiredUpdate(field string) func(*User, Patch, string) []error {
+	return func(_ *User, _ Patch, value string) []error {
+		return errorList(validateRequired(field, value))
+	}
+}
+
+func errorList(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	return []error{err}
+}
@@ -14,16 +14,62 @@ func (e *NotFoundError) Error() string {
 	return fmt.Sprintf("User with id %s not found", e.Id)
 }
 
+/*
+AlreadyExistsError is returned when a user would end up sharing a unique field, like the nickname or the email, with
+another user.
+*/
+type AlreadyExistsError struct {
+	Field string
+	Value string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("User with %s %s already exists", e.Field, e.Value)
+}
+
+/*
+VersionConflictError is returned when a user is updated from a version that is no longer the stored one, because
+somebody else updated it in the meantime.
+
+The update should be retried from the stored user, reading it again.
+*/
+type VersionConflictError struct {
+	Id      string
+	Version int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("User with id %s is no longer at version %d", e.Id, e.Version)
+}
+
 /* UserRepository
 Disclaimer: this should be called just "Repository". But doing so would mess up the mock generation with Mockery.
+
+AddUsers inserts several users at once, as AddUser would, but without stopping at
+the ones that can't be inserted. It returns an error for each user, nil for the
+inserted ones, or a single error if none of them could be inserted.
+
+GetUsersByIds returns the users with any of the given ids, in no particular order,
+leaving out the ids nobody has.
+
+GetUserById, GetUsersByIds and GetUsers only read the fields in the projection,
+named as they're stored, leaving the rest unset, or every field if it's empty.
+
+UpdateUser only stores the user if the stored one is still at its version, bumping
+it, and returns a VersionConflictError otherwise.
 */
 
 type UserRepository interface {
 	AddUser(ctx context.Context, user *User) error
-	GetUserById(ctx context.Context, userId string) (*User, error)
+	AddUsers(ctx context.Context, users []*User) ([]error, error)
+	GetUserById(ctx context.Context, userId string, projection []string) (*User, error)
+	GetUsersByIds(ctx context.Context, userIds []string, projection []string) ([]*User, error)
+	GetUserByNicknameOrEmail(ctx context.Context, nicknameOrEmail string) (*User, error)
 	GetUsers(
-		ctx context.Context, filters []query_utils.Filter, sort []query_utils.Sort, pagination query_utils.Pagination,
-	) ([]*User, error)
+		ctx context.Context, filter query_utils.FilterExpression, sort []query_utils.Sort,
+		pagination query_utils.Pagination, projection []string,
+	) (UserIterator, error)
+	CountUsers(ctx context.Context, filter query_utils.FilterExpression) (int64, error)
 	UpdateUser(ctx context.Context, user *User) error
 	RemoveUser(ctx context.Context, userId string) error
 }
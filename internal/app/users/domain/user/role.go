@@ -0,0 +1,118 @@
+package user
+
+import (
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+)
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+/*
+ForbiddenError is returned when a user attempts to change data they have no rights over.
+*/
+type ForbiddenError struct {
+	ActorId string
+	UserId  string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("User %s is not allowed to modify user %s", e.ActorId, e.UserId)
+}
+
+/*
+ParseRole validates that the given string is one of the roles our platform knows about.
+*/
+func ParseRole(role string) (Role, error) {
+	switch Role(role) {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return Role(role), nil
+	}
+
+	return "", invalidField("role", role, errors.ReasonUnknownValue)
+}
+
+func (u *User) Roles() []Role {
+	return append([]Role(nil), u.roles...)
+}
+
+func (u *User) HasRole(role Role) bool {
+	for _, r := range u.roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u *User) IsAdmin() bool {
+	return u.HasRole(RoleAdmin)
+}
+
+/*
+CanModify tells whether this user is allowed to change the data of the target user.
+
+Users can always change their own data, while only admins can change anybody else's.
+*/
+func (u *User) CanModify(target *User) error {
+	if u.id == target.id || u.IsAdmin() {
+		return nil
+	}
+
+	return &ForbiddenError{ActorId: u.id, UserId: target.id}
+}
+
+/*
+GrantRole adds the given role to the user. Granting a role the user already has is a no-op.
+*/
+func (u *User) GrantRole(role Role) error {
+	if _, err := ParseRole(string(role)); err != nil {
+		return err
+	}
+
+	if u.HasRole(role) {
+		return nil
+	}
+
+	u.roles = append(u.roles, role)
+	u.updatedAt = nowFunc()
+
+	return nil
+}
+
+/*
+RevokeRole removes the given role from the user. Revoking a role the user doesn't have is a no-op.
+
+Every user keeps the base user role, so it can't be revoked.
+*/
+func (u *User) RevokeRole(role Role) error {
+	if _, err := ParseRole(string(role)); err != nil {
+		return err
+	}
+
+	if role == RoleUser {
+		return invalidField("role", string(role), ReasonBaseRole)
+	}
+
+	if !u.HasRole(role) {
+		return nil
+	}
+
+	roles := make([]Role, 0, len(u.roles)-1)
+	for _, r := range u.roles {
+		if r != role {
+			roles = append(roles, r)
+		}
+	}
+
+	u.roles = roles
+	u.updatedAt = nowFunc()
+
+	return nil
+}
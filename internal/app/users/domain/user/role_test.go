@@ -0,0 +1,193 @@
+package user
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRoles(t *testing.T) {
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"parse role": {
+			"parse known roles":  testParseRole,
+			"parse unknown role": testParseUnknownRole,
+		},
+		"check roles": {
+			"check user roles":                 testHasRole,
+			"roles getter is a copy":           testRolesGetterIsCopy,
+			"modify own data":                  testCanModifySelf,
+			"modify other user data":           testCanModifyOtherUser,
+			"modify data of any user as admin": testCanModifyAsAdmin,
+		},
+		"grant role": {
+			"grant role":              testGrantRole,
+			"grant role already held": testGrantRoleAlreadyHeld,
+			"grant unknown role":      testGrantUnknownRole,
+		},
+		"revoke role": {
+			"revoke role":           testRevokeRole,
+			"revoke role not held":  testRevokeRoleNotHeld,
+			"revoke base user role": testRevokeBaseRole,
+			"revoke unknown role":   testRevokeUnknownRole,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+
+	nowFunc = time.Now
+}
+
+func userWithRoles(id string, roles ...Role) *User {
+	now := time.Now().Add(-time.Hour)
+
+	return UnmarshalUserFromDB(id, "John", "Doe", "john-"+id, "password", id+"@john.com", "US", roles, now, now, 1)
+}
+
+func testParseRole(t *testing.T) {
+	for _, role := range []Role{RoleUser, RoleModerator, RoleAdmin} {
+		out, err := ParseRole(string(role))
+
+		assert.NoError(t, err)
+		assert.Equal(t, role, out)
+	}
+}
+
+func testParseUnknownRole(t *testing.T) {
+	out, err := ParseRole("superuser")
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: domain, Field: "role", Value: "superuser", Reason: errors.ReasonUnknownValue},
+		err,
+	)
+	assert.Empty(t, out)
+}
+
+func testHasRole(t *testing.T) {
+	user := userWithRoles("1", RoleUser, RoleModerator)
+
+	assert.True(t, user.HasRole(RoleUser))
+	assert.True(t, user.HasRole(RoleModerator))
+	assert.False(t, user.HasRole(RoleAdmin))
+	assert.False(t, user.IsAdmin())
+}
+
+func testRolesGetterIsCopy(t *testing.T) {
+	user := userWithRoles("1", RoleUser)
+
+	roles := user.Roles()
+	roles[0] = RoleAdmin
+
+	assert.False(t, user.IsAdmin())
+}
+
+func testCanModifySelf(t *testing.T) {
+	user := userWithRoles("1", RoleUser)
+
+	assert.NoError(t, user.CanModify(userWithRoles("1", RoleUser)))
+}
+
+func testCanModifyOtherUser(t *testing.T) {
+	user := userWithRoles("1", RoleUser, RoleModerator)
+
+	err := user.CanModify(userWithRoles("2", RoleUser))
+
+	assert.Equal(t, &ForbiddenError{ActorId: "1", UserId: "2"}, err)
+	assert.Equal(t, "User 1 is not allowed to modify user 2", err.Error())
+}
+
+func testCanModifyAsAdmin(t *testing.T) {
+	admin := userWithRoles("1", RoleUser, RoleAdmin)
+
+	assert.NoError(t, admin.CanModify(userWithRoles("2", RoleUser, RoleAdmin)))
+}
+
+func testGrantRole(t *testing.T) {
+	now := time.Now()
+	setNow(now)
+	user := userWithRoles("1", RoleUser)
+
+	err := user.GrantRole(RoleModerator)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleUser, RoleModerator}, user.roles)
+	assert.Equal(t, now, user.updatedAt)
+}
+
+func testGrantRoleAlreadyHeld(t *testing.T) {
+	user := userWithRoles("1", RoleUser, RoleAdmin)
+	updatedAt := user.updatedAt
+
+	err := user.GrantRole(RoleAdmin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleUser, RoleAdmin}, user.roles)
+	assert.Equal(t, updatedAt, user.updatedAt)
+}
+
+func testGrantUnknownRole(t *testing.T) {
+	user := userWithRoles("1", RoleUser)
+
+	err := user.GrantRole("superuser")
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: domain, Field: "role", Value: "superuser", Reason: errors.ReasonUnknownValue},
+		err,
+	)
+	assert.Equal(t, []Role{RoleUser}, user.roles)
+}
+
+func testRevokeRole(t *testing.T) {
+	now := time.Now()
+	setNow(now)
+	user := userWithRoles("1", RoleUser, RoleModerator, RoleAdmin)
+
+	err := user.RevokeRole(RoleModerator)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleUser, RoleAdmin}, user.roles)
+	assert.Equal(t, now, user.updatedAt)
+}
+
+func testRevokeRoleNotHeld(t *testing.T) {
+	user := userWithRoles("1", RoleUser)
+	updatedAt := user.updatedAt
+
+	err := user.RevokeRole(RoleAdmin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleUser}, user.roles)
+	assert.Equal(t, updatedAt, user.updatedAt)
+}
+
+func testRevokeBaseRole(t *testing.T) {
+	user := userWithRoles("1", RoleUser, RoleAdmin)
+
+	err := user.RevokeRole(RoleUser)
+
+	assert.Equal(t, &errors.InvalidField{Domain: domain, Field: "role", Value: "user", Reason: ReasonBaseRole}, err)
+	assert.Equal(t, []Role{RoleUser, RoleAdmin}, user.roles)
+}
+
+func testRevokeUnknownRole(t *testing.T) {
+	user := userWithRoles("1", RoleUser)
+
+	err := user.RevokeRole("superuser")
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: domain, Field: "role", Value: "superuser", Reason: errors.ReasonUnknownValue},
+		err,
+	)
+}
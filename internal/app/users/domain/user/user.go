@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
 	"time"
@@ -11,6 +12,27 @@ var hashFunc = bcrypt.GenerateFromPassword
 
 const domain = "User"
 
+// passwordCost is the bcrypt cost of the password hashes, see hashPassword.
+const passwordCost = 14
+
+/*
+dummyPasswordHash is a hash of a password nobody has, at our cost, which passwords are checked against when there's no
+user to check them against.
+*/
+const dummyPasswordHash = "$2a$14$GSpGXXObOUuzQg7VLE0/6eQpplc7NRM3/wclm79GK5KtzIo0uToja"
+
+/*
+InvalidCredentialsError is returned when a login attempt doesn't match the stored credentials.
+
+It intentionally doesn't tell apart an unknown user from a wrong password, so it
+can't be used to find out which nicknames or emails are registered.
+*/
+type InvalidCredentialsError struct{}
+
+func (e *InvalidCredentialsError) Error() string {
+	return "Invalid credentials"
+}
+
 /*
 A User holds our domain model for a user entity.
 
@@ -29,8 +51,10 @@ type User struct {
 	password  string
 	email     string
 	country   string
+	roles     []Role
 	createdAt time.Time
 	updatedAt time.Time
+	version   int64
 }
 
 func (u *User) Id() string {
@@ -69,133 +93,63 @@ func (u *User) UpdatedAt() time.Time {
 	return u.updatedAt
 }
 
-func (u *User) Update(
-	firstName *string, lastName *string, nickname *string, password *string, email *string, country *string,
-) error {
-	/* Validation */
-	var invalidFields []error
-	if firstName != nil {
-		if *firstName == "" {
-			invalidFields = append(
-				invalidFields, &errors.InvalidField{
-					Domain: domain,
-					Field:  "first_name",
-					Value:  *firstName,
-				},
-			)
-		}
-	}
-
-	if lastName != nil {
-		if *lastName == "" {
-			invalidFields = append(
-				invalidFields, &errors.InvalidField{
-					Domain: domain,
-					Field:  "last_name",
-					Value:  *lastName,
-				},
-			)
-		}
-	}
-
-	if nickname != nil {
-		if *nickname == "" {
-			invalidFields = append(
-				invalidFields, &errors.InvalidField{
-					Domain: domain,
-					Field:  "nickname",
-					Value:  *nickname,
-				},
-			)
-		}
-	}
-
-	if password != nil {
-		if *password == "" {
-			invalidFields = append(
-				invalidFields, &errors.InvalidField{
-					Domain: domain,
-					Field:  "password",
-					Value:  *password,
-				},
-			)
-		}
-	}
+/*
+Version is the revision of the stored user this one was read from, which repositories bump on every update.
 
-	if email != nil {
-		if *email == "" {
-			invalidFields = append(
-				invalidFields, &errors.InvalidField{
-					Domain: domain,
-					Field:  "email",
-					Value:  *email,
-				},
-			)
-		}
-	}
+Updates only succeed while the stored user is still at this version, so
+concurrent updates can't silently overwrite each other.
+*/
+func (u *User) Version() int64 {
+	return u.version
+}
 
-	if country != nil {
-		if *country == "" {
-			invalidFields = append(
-				invalidFields, &errors.InvalidField{
-					Domain: domain,
-					Field:  "country",
-					Value:  *country,
-				},
-			)
-		}
-	}
+/*
+Authenticate checks the given plaintext password against the stored bcrypt hash.
 
-	if len(invalidFields) == 1 {
-		return invalidFields[0]
+The comparison is delegated to bcrypt, which compares the resulting hashes in
+constant time, so the response time doesn't depend on how much of the password
+matched.
+*/
+func (u *User) Authenticate(password string) error {
+	if password == "" {
+		return &InvalidCredentialsError{}
 	}
 
-	if len(invalidFields) > 1 {
-		return &errors.MultipleInvalidFields{Errors: invalidFields}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.password), []byte(password)); err != nil {
+		return &InvalidCredentialsError{}
 	}
 
-	/* Update */
-
-	if firstName != nil {
-		u.firstName = *firstName
-		u.updatedAt = nowFunc()
-	}
+	return nil
+}
 
-	if lastName != nil {
-		u.lastName = *lastName
-		u.updatedAt = nowFunc()
-	}
+/*
+AuthenticateByNicknameOrEmail finds the user with the given nickname or email, and checks the password against it.
 
-	if nickname != nil {
-		u.nickname = *nickname
-		u.updatedAt = nowFunc()
-	}
+Unknown users fail with an InvalidCredentialsError, as wrong passwords do. Their
+password is still checked against a dummy hash, so both take as long, and the
+response time doesn't tell which nicknames or emails are registered either.
+*/
+func AuthenticateByNicknameOrEmail(
+	ctx context.Context, userRepo UserRepository, nicknameOrEmail string, password string,
+) (*User, error) {
+	found, err := userRepo.GetUserByNicknameOrEmail(ctx, nicknameOrEmail)
 
-	if password != nil {
-		hashedPassword, err := hashPassword(*password)
+	if err != nil {
+		if errors.As(err, new(*NotFoundError)) {
+			dummy := &User{password: dummyPasswordHash}
+			_ = dummy.Authenticate(password)
 
-		if err != nil {
-			return &errors.Unknown{
-				Tag:   domain,
-				Cause: err,
-			}
+			return nil, &InvalidCredentialsError{}
 		}
 
-		u.password = hashedPassword
-		u.updatedAt = nowFunc()
-	}
-
-	if email != nil {
-		u.email = *email
-		u.updatedAt = nowFunc()
+		return nil, err
 	}
 
-	if country != nil {
-		u.country = *country
-		u.updatedAt = nowFunc()
+	if err := found.Authenticate(password); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return found, nil
 }
 
 /*
@@ -207,101 +161,100 @@ other source. These should be handled by its own "constructor" methods.
 
 The reason for this is to preserve the rule of "keeping a valid state in the
 domain layer". CreateUser holds the business logic required when a user signs up
-in our platform, like validating the data against business rules, or setting
-specific properties like createdAt.
+in our platform, like validating the data against business rules (see
+validation.go), or setting specific properties like createdAt. New users always
+start with the base user role only, at the first version.
 */
 func CreateUser(
 	id string, firstName string, lastName string, nickname string, password string, email string, country string,
 ) (*User, error) {
-	var invalidFields []error
+	err := validateNewUser(id, firstName, lastName, nickname, validatePassword(password, nickname), email, country)
 
-	if id == "" {
-		invalidFields = append(
-			invalidFields, &errors.InvalidField{
-				Domain: domain,
-				Field:  "id",
-				Value:  id,
-			},
-		)
+	if err != nil {
+		return nil, err
 	}
 
-	if firstName == "" {
-		invalidFields = append(
-			invalidFields, &errors.InvalidField{
-				Domain: domain,
-				Field:  "first_name",
-				Value:  firstName,
-			},
-		)
+	hashedPassword, err := hashPassword(password)
+
+	if err != nil {
+		return nil, &errors.Unknown{
+			Tag:   domain,
+			Cause: err,
+		}
 	}
 
-	if lastName == "" {
-		invalidFields = append(
-			invalidFields, &errors.InvalidField{
-				Domain: domain,
-				Field:  "last_name",
-				Value:  lastName,
-			},
-		)
+	return newUser(id, firstName, lastName, nickname, hashedPassword, email, country), nil
+}
+
+/*
+CreateUserWithPasswordHash registers a new user whose password was already hashed with bcrypt, like the ones imported
+from another platform.
+
+It applies the same rules as CreateUser, except for the password policy, as we
+can't know the password behind the hash. The hash is only checked to be a valid
+bcrypt one, and stored as it is, whatever its cost.
+*/
+func CreateUserWithPasswordHash(
+	id string, firstName string, lastName string, nickname string, passwordHash string, email string, country string,
+) (*User, error) {
+	err := validateNewUser(id, firstName, lastName, nickname, validatePasswordHash(passwordHash), email, country)
+
+	if err != nil {
+		return nil, err
 	}
 
-	if nickname == "" {
-		invalidFields = append(
-			invalidFields, &errors.InvalidField{
-				Domain: domain,
-				Field:  "nickname",
-				Value:  nickname,
-			},
-		)
+	return newUser(id, firstName, lastName, nickname, passwordHash, email, country), nil
+}
+
+/*
+validateNewUser checks the fields of a new user against the business rules, along with the errors of its password,
+reporting them all at once.
+*/
+func validateNewUser(
+	id string, firstName string, lastName string, nickname string, passwordErrs []error, email string, country string,
+) error {
+	var invalidFields []error
+
+	if err := validateRequired("id", id); err != nil {
+		invalidFields = append(invalidFields, err)
 	}
 
-	if password == "" {
-		invalidFields = append(
-			invalidFields, &errors.InvalidField{
-				Domain: domain,
-				Field:  "password",
-				Value:  password,
-			},
-		)
+	if err := validateRequired("first_name", firstName); err != nil {
+		invalidFields = append(invalidFields, err)
 	}
 
-	if email == "" {
-		invalidFields = append(
-			invalidFields, &errors.InvalidField{
-				Domain: domain,
-				Field:  "email",
-				Value:  email,
-			},
-		)
+	if err := validateRequired("last_name", lastName); err != nil {
+		invalidFields = append(invalidFields, err)
 	}
 
-	if country == "" {
-		invalidFields = append(
-			invalidFields, &errors.InvalidField{
-				Domain: domain,
-				Field:  "country",
-				Value:  country,
-			},
-		)
+	if err := validateNickname(nickname); err != nil {
+		invalidFields = append(invalidFields, err)
 	}
 
-	hashedPassword, err := hashPassword(password)
+	invalidFields = append(invalidFields, passwordErrs...)
 
-	if err != nil {
-		return nil, &errors.Unknown{
-			Tag:   domain,
-			Cause: err,
-		}
+	if err := validateEmail(email); err != nil {
+		invalidFields = append(invalidFields, err)
+	}
+
+	if err := validateCountry(country); err != nil {
+		invalidFields = append(invalidFields, err)
 	}
 
 	if len(invalidFields) == 1 {
-		return nil, invalidFields[0]
+		return invalidFields[0]
 	}
 
 	if len(invalidFields) > 1 {
-		return nil, &errors.MultipleInvalidFields{Errors: invalidFields}
+		return &errors.MultipleInvalidFields{Errors: invalidFields}
 	}
 
+	return nil
+}
+
+func newUser(
+	id string, firstName string, lastName string, nickname string, password string, email string, country string,
+) *User {
 	now := nowFunc()
 
 	return &User{
@@ -309,12 +262,14 @@ func CreateUser(
 		firstName: firstName,
 		lastName:  lastName,
 		nickname:  nickname,
-		password:  hashedPassword,
+		password:  password,
 		email:     email,
 		country:   country,
+		roles:     []Role{RoleUser},
 		createdAt: now,
 		updatedAt: now,
-	}, nil
+		version:   1,
+	}
 }
 
 /*
@@ -338,8 +293,10 @@ func UnmarshalUserFromDB(
 	password string,
 	email string,
 	country string,
+	roles []Role,
 	createdAt time.Time,
 	updatedAt time.Time,
+	version int64,
 ) *User {
 	return &User{
 		id:        id,
@@ -349,8 +306,10 @@ func UnmarshalUserFromDB(
 		password:  password,
 		email:     email,
 		country:   country,
+		roles:     roles,
 		createdAt: createdAt,
 		updatedAt: updatedAt,
+		version:   version,
 	}
 }
 
@@ -362,7 +321,7 @@ Now, here I've been making some research, as the OWASP foundation guidelines rec
 I've run a simple benchmark on bcrypt cost values. On my computer 13 rounds take ~600ms, while 14 rounds take ~1200ms. So I'm using 14 rounds, as it's closer to the general rule of 1 second.
 */
 func hashPassword(password string) (string, error) {
-	hashedPassword, err := hashFunc([]byte(password), 14)
+	hashedPassword, err := hashFunc([]byte(password), passwordCost)
 
 	if err != nil {
 		return "", err
@@ -11,6 +11,22 @@ var User1 = User{
 	password:  "password",
 	email:     "me@john.com",
 	country:   "US",
+	roles:     []Role{RoleUser},
 	createdAt: user1Now,
 	updatedAt: user1Now,
+	version:   1,
+}
+
+var Admin1 = User{
+	id:        "2",
+	firstName: "Jane",
+	lastName:  "Doe",
+	nickname:  "jane-admin",
+	password:  "password",
+	email:     "me@jane.com",
+	country:   "ES",
+	roles:     []Role{RoleUser, RoleAdmin},
+	createdAt: user1Now,
+	updatedAt: user1Now,
+	version:   1,
 }
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"context"
+	"fmt"
 	pkgErrors "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -23,6 +25,10 @@ func setHash(h []byte, err error) {
 	}
 }
 
+func requiredField(field string, value interface{}) error {
+	return &pkgErrors.InvalidField{Domain: "User", Field: field, Value: value, Reason: pkgErrors.ReasonRequired}
+}
+
 func TestUser(t *testing.T) {
 	for name, testGroup := range map[string]map[string]func(t *testing.T){
 		"getters": {
@@ -34,11 +40,30 @@ func TestUser(t *testing.T) {
 			"create user with several fields empty": testCreateUserWithSeveralFieldsEmpty,
 			"create user with hash fail":            testCreateUserWithHashFail,
 		},
+		"create user with password hash": {
+			"create user with password hash":               testCreateUserWithPasswordHash,
+			"create user with invalid password hash":       testCreateUserWithInvalidPasswordHash,
+			"create user with password hash and no fields": testCreateUserWithPasswordHashAndNoFields,
+		},
 		"update user": {
 			"update user":                           testUpdateUser,
+			"update user partially":                 testUpdateUserPartially,
+			"update user with empty patch":          testUpdateUserWithEmptyPatch,
 			"update user with each field empty":     testUpdateUserWithEmptyFields,
 			"update user with several fields empty": testUpdateUserWithSeveralEmptyFields,
+			"update user with immutable fields":     testUpdateUserWithImmutableFields,
+			"update user with unknown fields":       testUpdateUserWithUnknownFields,
 			"update user with hash error":           testUpdateUserWithHashError,
+			"format patch without password":         testFormatPatch,
+			"list patch fields":                     testPatchFields,
+		},
+		"authenticate user": {
+			"authenticate user":                     testAuthenticateUser,
+			"authenticate user with wrong password": testAuthenticateUserWithWrongPassword,
+			"authenticate user with empty password": testAuthenticateUserWithEmptyPassword,
+			"authenticate by nickname or email":     testAuthenticateByNicknameOrEmail,
+			"authenticate unknown user":             testAuthenticateUnknownUser,
+			"hash dummy password at our cost":       testDummyPasswordHashCost,
 		},
 		"unmarshal user": {
 			"unmarshal user": testUnmarshalUser,
@@ -74,6 +99,7 @@ func testGetters(t *testing.T) {
 	assert.Equal(t, user.password, user.Password())
 	assert.Equal(t, user.email, user.Email())
 	assert.Equal(t, user.country, user.Country())
+	assert.Equal(t, user.roles, user.Roles())
 	assert.Equal(t, user.createdAt, user.CreatedAt())
 	assert.Equal(t, user.updatedAt, user.UpdatedAt())
 }
@@ -105,8 +131,10 @@ func testCreateUser(t *testing.T) {
 		password:  string(hashedPassword),
 		email:     email,
 		country:   country,
+		roles:     []Role{RoleUser},
 		createdAt: now,
 		updatedAt: now,
+		version:   1,
 	}
 
 	assert.Equal(t, expected, got)
@@ -129,37 +157,37 @@ func testCreateUserWithFieldsEmpty(t *testing.T) {
 
 	got, err := CreateUser("", firstName, lastName, nickname, password, email, country)
 
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "id", Value: ""}, err)
+	assert.Equal(t, requiredField("id", ""), err)
 	assert.Nil(t, got)
 
 	got, err = CreateUser(id, "", lastName, nickname, password, email, country)
 
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "first_name", Value: ""}, err)
+	assert.Equal(t, requiredField("first_name", ""), err)
 	assert.Nil(t, got)
 
 	got, err = CreateUser(id, firstName, "", nickname, password, email, country)
 
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "last_name", Value: ""}, err)
+	assert.Equal(t, requiredField("last_name", ""), err)
 	assert.Nil(t, got)
 
 	got, err = CreateUser(id, firstName, lastName, "", password, email, country)
 
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "nickname", Value: ""}, err)
+	assert.Equal(t, requiredField("nickname", ""), err)
 	assert.Nil(t, got)
 
 	got, err = CreateUser(id, firstName, lastName, nickname, "", email, country)
 
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "password", Value: ""}, err)
+	assert.Equal(t, requiredField("password", nil), err)
 	assert.Nil(t, got)
 
 	got, err = CreateUser(id, firstName, lastName, nickname, password, "", country)
 
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "email", Value: ""}, err)
+	assert.Equal(t, requiredField("email", ""), err)
 	assert.Nil(t, got)
 
 	got, err = CreateUser(id, firstName, lastName, nickname, password, email, "")
 
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "country", Value: ""}, err)
+	assert.Equal(t, requiredField("country", ""), err)
 	assert.Nil(t, got)
 }
 
@@ -208,15 +236,89 @@ func testCreateUserWithHashFail(t *testing.T) {
 	assert.Nil(t, got)
 }
 
+func testCreateUserWithPasswordHash(t *testing.T) {
+	id := uuid.NewString()
+	firstName := "John"
+	lastName := "Doe"
+	nickname := "john-123"
+	passwordHash := "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+	email := "me@john.com"
+	country := "US"
+
+	now := time.Now()
+	setNow(now)
+
+	hashErr := errors.New("should not hash")
+	setHash(nil, hashErr)
+
+	got, err := CreateUserWithPasswordHash(id, firstName, lastName, nickname, passwordHash, email, country)
+
+	assert.NoError(t, err)
+
+	expected := &User{
+		id:        id,
+		firstName: firstName,
+		lastName:  lastName,
+		nickname:  nickname,
+		password:  passwordHash,
+		email:     email,
+		country:   country,
+		roles:     []Role{RoleUser},
+		createdAt: now,
+		updatedAt: now,
+		version:   1,
+	}
+
+	assert.Equal(t, expected, got)
+}
+
+func testCreateUserWithInvalidPasswordHash(t *testing.T) {
+	for _, passwordHash := range []string{"password", "$2a$10$tooshort", "$5$rounds=5000$salt$hash"} {
+		got, err := CreateUserWithPasswordHash(
+			uuid.NewString(), "John", "Doe", "john-123", passwordHash, "me@john.com", "US",
+		)
+
+		assert.Equal(
+			t, &pkgErrors.InvalidField{
+				Domain: "User",
+				Field:  "password_hash",
+				Reason: pkgErrors.ReasonInvalidFormat,
+			}, err, passwordHash,
+		)
+		assert.Nil(t, got)
+	}
+}
+
+func testCreateUserWithPasswordHashAndNoFields(t *testing.T) {
+	got, err := CreateUserWithPasswordHash("", "", "", "", "", "", "")
+
+	assert.Equal(
+		t, &pkgErrors.MultipleInvalidFields{
+			Errors: []error{
+				requiredField("id", ""),
+				requiredField("first_name", ""),
+				requiredField("last_name", ""),
+				requiredField("nickname", ""),
+				requiredField("password_hash", nil),
+				requiredField("email", ""),
+				requiredField("country", ""),
+			},
+		}, err,
+	)
+	assert.Nil(t, got)
+}
+
 func testUpdateUser(t *testing.T) {
 	user := User1
 
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
+	patch := Patch{
+		"first_name": "updated",
+		"last_name":  "updated",
+		"nickname":   "updated",
+		"password":   "secret-passphrase",
+		"email":      "updated@john.com",
+		"country":    "ES",
+	}
 
 	now := time.Now()
 	setNow(now)
@@ -224,92 +326,132 @@ func testUpdateUser(t *testing.T) {
 	hashedPassword := []byte("hashed")
 	setHash(hashedPassword, nil)
 
-	err := user.Update(&firstName, &lastName, &nickname, &password, &email, &country)
+	err := user.Update(patch)
 
 	assert.NoError(t, err)
 	assert.Equal(t, User1.id, user.id)
-	assert.Equal(t, firstName, user.firstName)
-	assert.Equal(t, lastName, user.lastName)
-	assert.Equal(t, nickname, user.nickname)
+	assert.Equal(t, patch["first_name"], user.firstName)
+	assert.Equal(t, patch["last_name"], user.lastName)
+	assert.Equal(t, patch["nickname"], user.nickname)
 	assert.Equal(t, string(hashedPassword), user.password)
-	assert.Equal(t, email, user.email)
-	assert.Equal(t, country, user.country)
+	assert.Equal(t, patch["email"], user.email)
+	assert.Equal(t, patch["country"], user.country)
 	assert.Equal(t, User1.createdAt, user.createdAt)
 	assert.Equal(t, now, user.updatedAt)
 }
 
-func testUpdateUserWithEmptyFields(t *testing.T) {
+func testUpdateUserPartially(t *testing.T) {
 	user := User1
 
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
+	now := time.Now()
+	setNow(now)
 
-	empty := ""
+	err := user.Update(Patch{"country": "ES"})
 
+	assert.NoError(t, err)
+	assert.Equal(t, "ES", user.country)
+	assert.Equal(t, User1.firstName, user.firstName)
+	assert.Equal(t, User1.password, user.password)
+	assert.Equal(t, now, user.updatedAt)
+}
+
+func testUpdateUserWithEmptyPatch(t *testing.T) {
+	user := User1
+
+	err := user.Update(Patch{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, User1, user)
+}
+
+func testUpdateUserWithEmptyFields(t *testing.T) {
 	hashedPassword := []byte("hashed")
 	setHash(hashedPassword, nil)
 
-	err := user.Update(&empty, &lastName, &nickname, &password, &email, &country)
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "first_name", Value: empty}, err)
-	assert.Equal(t, User1, user)
+	for field, expected := range map[string]error{
+		"first_name": requiredField("first_name", ""),
+		"last_name":  requiredField("last_name", ""),
+		"nickname":   requiredField("nickname", ""),
+		"password":   requiredField("password", nil),
+		"email":      requiredField("email", ""),
+		"country":    requiredField("country", ""),
+	} {
+		user := User1
+		patch := Patch{
+			"first_name": "updated",
+			"last_name":  "updated",
+			"nickname":   "updated",
+			"password":   "secret-passphrase",
+			"email":      "updated@john.com",
+			"country":    "ES",
+		}
+		patch[field] = ""
+
+		err := user.Update(patch)
+		assert.Equal(t, expected, err, field)
+		assert.Equal(t, User1, user, field)
+	}
+}
 
-	err = user.Update(&firstName, &empty, &nickname, &password, &email, &country)
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "last_name", Value: empty}, err)
-	assert.Equal(t, User1, user)
+func testUpdateUserWithSeveralEmptyFields(t *testing.T) {
+	user := User1
+
+	hashedPassword := []byte("hashed")
+	setHash(hashedPassword, nil)
 
-	err = user.Update(&firstName, &lastName, &empty, &password, &email, &country)
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "nickname", Value: empty}, err)
+	err := user.Update(Patch{"first_name": "", "last_name": "", "nickname": "updated"})
+	assert.Equal(
+		t, &pkgErrors.MultipleInvalidFields{
+			Errors: []error{requiredField("first_name", ""), requiredField("last_name", "")},
+		}, err,
+	)
 	assert.Equal(t, User1, user)
+}
+
+func testUpdateUserWithImmutableFields(t *testing.T) {
+	user := User1
 
-	err = user.Update(&firstName, &lastName, &nickname, &empty, &email, &country)
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "password", Value: empty}, err)
+	err := user.Update(Patch{"id": "5678", "created_at": "", "first_name": "updated"})
+	assert.Equal(
+		t, &pkgErrors.MultipleInvalidFields{
+			Errors: []error{
+				&pkgErrors.InvalidField{Domain: "User", Field: "created_at", Reason: pkgErrors.ReasonImmutable},
+				&pkgErrors.InvalidField{Domain: "User", Field: "id", Reason: pkgErrors.ReasonImmutable},
+			},
+		}, err,
+	)
 	assert.Equal(t, User1, user)
 
-	err = user.Update(&firstName, &lastName, &nickname, &password, &empty, &country)
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "email", Value: empty}, err)
+	err = user.Update(Patch{"roles": "admin"})
+	assert.Equal(
+		t, &pkgErrors.InvalidField{Domain: "User", Field: "roles", Reason: pkgErrors.ReasonImmutable}, err,
+	)
 	assert.Equal(t, User1, user)
 
-	err = user.Update(&firstName, &lastName, &nickname, &password, &email, &empty)
-	assert.Equal(t, &pkgErrors.InvalidField{Domain: "User", Field: "country", Value: empty}, err)
+	err = user.Update(Patch{"version": ""})
+	assert.Equal(
+		t, &pkgErrors.InvalidField{Domain: "User", Field: "version", Reason: pkgErrors.ReasonImmutable}, err,
+	)
 	assert.Equal(t, User1, user)
 }
 
-func testUpdateUserWithSeveralEmptyFields(t *testing.T) {
+func testUpdateUserWithUnknownFields(t *testing.T) {
 	user := User1
 
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
-
-	empty := ""
-
-	hashedPassword := []byte("hashed")
-	setHash(hashedPassword, nil)
-
-	err := user.Update(&empty, &empty, &nickname, &password, &email, &country)
-	assert.IsType(t, &pkgErrors.MultipleInvalidFields{}, err)
+	err := user.Update(Patch{"age": "30"})
+	assert.Equal(
+		t, &pkgErrors.InvalidField{Domain: "User", Field: "age", Reason: pkgErrors.ReasonUnknownValue}, err,
+	)
 	assert.Equal(t, User1, user)
 }
 
 func testUpdateUserWithHashError(t *testing.T) {
 	user := User1
 
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
-
 	hashErr := errors.New("hash fail")
 	setHash(nil, hashErr)
 
-	err := user.Update(&firstName, &lastName, &nickname, &password, &email, &country)
+	err := user.Update(Patch{"first_name": "updated", "password": "secret-passphrase"})
 
 	assert.Equal(
 		t, &pkgErrors.Unknown{
@@ -317,6 +459,117 @@ func testUpdateUserWithHashError(t *testing.T) {
 			Cause: hashErr,
 		}, err,
 	)
+	assert.Equal(t, User1, user)
+}
+
+func testFormatPatch(t *testing.T) {
+	patch := Patch{"nickname": "updated", "password": "secret-passphrase"}
+
+	assert.Equal(t, "map[nickname:updated password:[REDACTED]]", patch.String())
+	assert.NotContains(t, fmt.Sprintf("%v", struct{ Patch Patch }{patch}), "secret-passphrase")
+	assert.Equal(t, "secret-passphrase", patch["password"])
+}
+
+func testPatchFields(t *testing.T) {
+	patch := Patch{"password": "secret-passphrase", "country": "ES", "nickname": "updated"}
+
+	assert.Equal(t, []string{"country", "nickname", "password"}, patch.Fields())
+	assert.Empty(t, Patch{}.Fields())
+}
+
+func testAuthenticateUser(t *testing.T) {
+	user := User1
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user.password = string(hashedPassword)
+
+	err := user.Authenticate("password")
+
+	assert.NoError(t, err)
+}
+
+func testAuthenticateUserWithWrongPassword(t *testing.T) {
+	user := User1
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user.password = string(hashedPassword)
+
+	err := user.Authenticate("wrong-password")
+
+	assert.Equal(t, &InvalidCredentialsError{}, err)
+	assert.Equal(t, "Invalid credentials", err.Error())
+}
+
+func testAuthenticateUserWithEmptyPassword(t *testing.T) {
+	user := User1
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(""), bcrypt.MinCost)
+	user.password = string(hashedPassword)
+
+	err := user.Authenticate("")
+
+	assert.Equal(t, &InvalidCredentialsError{}, err)
+}
+
+/*
+lookupRepository finds users by their nickname only, failing as repositories do for the rest.
+*/
+type lookupRepository struct {
+	UserRepository
+	users []*User
+	err   error
+}
+
+func (r *lookupRepository) GetUserByNicknameOrEmail(_ context.Context, nicknameOrEmail string) (*User, error) {
+	for _, u := range r.users {
+		if u.nickname == nicknameOrEmail {
+			return u, nil
+		}
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return nil, &NotFoundError{Id: nicknameOrEmail}
+}
+
+func testAuthenticateByNicknameOrEmail(t *testing.T) {
+	user := User1
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user.password = string(hashedPassword)
+	repo := &lookupRepository{users: []*User{&user}}
+
+	got, err := AuthenticateByNicknameOrEmail(context.Background(), repo, user.nickname, "password")
+
+	assert.NoError(t, err)
+	assert.Same(t, &user, got)
+
+	got, err = AuthenticateByNicknameOrEmail(context.Background(), repo, user.nickname, "wrong")
+
+	assert.Equal(t, &InvalidCredentialsError{}, err)
+	assert.Nil(t, got)
+}
+
+func testAuthenticateUnknownUser(t *testing.T) {
+	got, err := AuthenticateByNicknameOrEmail(context.Background(), &lookupRepository{}, "unknown", "password")
+
+	assert.Equal(t, &InvalidCredentialsError{}, err)
+	assert.Nil(t, got)
+
+	repoErr := errors.New("db is down")
+	got, err = AuthenticateByNicknameOrEmail(context.Background(), &lookupRepository{err: repoErr}, "unknown", "password")
+
+	assert.Equal(t, repoErr, err)
+	assert.Nil(t, got)
+}
+
+func testDummyPasswordHashCost(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+
+	assert.NoError(t, err)
+	assert.Equal(t, passwordCost, cost)
 }
 
 func testUnmarshalUser(t *testing.T) {
@@ -326,13 +579,17 @@ func testUnmarshalUser(t *testing.T) {
 	firstName := "updated"
 	lastName := "updated"
 	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
+	password := "secret-passphrase"
+	email := "updated@john.com"
+	country := "ES"
+	roles := []Role{RoleUser, RoleAdmin}
 	createdAt := now
 	updatedAt := now
+	version := int64(3)
 
-	out := UnmarshalUserFromDB(id, firstName, lastName, nickname, password, email, country, createdAt, updatedAt)
+	out := UnmarshalUserFromDB(
+		id, firstName, lastName, nickname, password, email, country, roles, createdAt, updatedAt, version,
+	)
 
 	assert.Equal(t, id, out.id)
 	assert.Equal(t, firstName, out.firstName)
@@ -341,6 +598,8 @@ func testUnmarshalUser(t *testing.T) {
 	assert.Equal(t, password, out.password)
 	assert.Equal(t, email, out.email)
 	assert.Equal(t, country, out.country)
+	assert.Equal(t, roles, out.roles)
 	assert.Equal(t, createdAt, out.createdAt)
 	assert.Equal(t, updatedAt, out.updatedAt)
+	assert.Equal(t, version, out.version)
 }
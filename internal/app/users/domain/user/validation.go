@@ -0,0 +1,182 @@
+package user
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+/*
+Reasons specific to the User domain, on top of the ones in the errors package.
+*/
+const (
+	ReasonMissingLowercase = "MISSING_LOWERCASE"
+	ReasonMissingUppercase = "MISSING_UPPERCASE"
+	ReasonMissingDigit     = "MISSING_DIGIT"
+	ReasonMissingSymbol    = "MISSING_SYMBOL"
+	ReasonContainsNickname = "CONTAINS_NICKNAME"
+	ReasonBaseRole         = "BASE_ROLE"
+)
+
+const (
+	nicknameMinLength = 3
+	nicknameMaxLength = 32
+	emailMaxLength    = 254
+)
+
+/*
+Nicknames can't contain "@", so they can never be mistaken for an email when logging in.
+*/
+var nicknameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+func invalidField(field string, value interface{}, reason string) *errors.InvalidField {
+	return &errors.InvalidField{
+		Domain: domain,
+		Field:  field,
+		Value:  value,
+		Reason: reason,
+	}
+}
+
+func validateRequired(field string, value string) error {
+	if value == "" {
+		return invalidField(field, value, errors.ReasonRequired)
+	}
+
+	return nil
+}
+
+func validateNickname(nickname string) error {
+	if nickname == "" {
+		return invalidField("nickname", nickname, errors.ReasonRequired)
+	}
+
+	if len(nickname) < nicknameMinLength {
+		return invalidField("nickname", nickname, errors.ReasonTooShort)
+	}
+
+	if len(nickname) > nicknameMaxLength {
+		return invalidField("nickname", nickname, errors.ReasonTooLong)
+	}
+
+	if !nicknameRegexp.MatchString(nickname) {
+		return invalidField("nickname", nickname, errors.ReasonInvalidCharacters)
+	}
+
+	return nil
+}
+
+/*
+validateEmail checks the email against the addr-spec syntax of RFC 5322.
+
+Display names ("John <me@john.com>") aren't allowed, and the domain must have at
+least two labels, as addresses on bare hostnames can't be reached from outside.
+*/
+func validateEmail(email string) error {
+	if email == "" {
+		return invalidField("email", email, errors.ReasonRequired)
+	}
+
+	if len(email) > emailMaxLength {
+		return invalidField("email", email, errors.ReasonTooLong)
+	}
+
+	address, err := mail.ParseAddress(email)
+
+	if err != nil || address.Address != email {
+		return invalidField("email", email, errors.ReasonInvalidFormat)
+	}
+
+	emailDomain := email[strings.LastIndex(email, "@")+1:]
+
+	if !strings.Contains(emailDomain, ".") || strings.HasPrefix(emailDomain, ".") ||
+		strings.HasSuffix(emailDomain, ".") {
+		return invalidField("email", email, errors.ReasonInvalidFormat)
+	}
+
+	return nil
+}
+
+/*
+validateCountry checks the country is an ISO 3166-1 alpha-2 code, in upper case.
+*/
+func validateCountry(country string) error {
+	if country == "" {
+		return invalidField("country", country, errors.ReasonRequired)
+	}
+
+	if _, ok := countries[country]; !ok {
+		return invalidField("country", country, errors.ReasonUnknownValue)
+	}
+
+	return nil
+}
+
+/*
+validatePassword checks the password against the current policy, reporting every rule it breaks.
+
+The password itself is never included in the errors, as they end up in the logs.
+*/
+func validatePassword(password string, nickname string) []error {
+	if password == "" {
+		return []error{invalidField("password", nil, errors.ReasonRequired)}
+	}
+
+	var invalidFields []error
+	policy := passwordPolicy
+
+	if len(password) < policy.MinLength {
+		invalidFields = append(invalidFields, invalidField("password", nil, errors.ReasonTooShort))
+	}
+
+	if policy.MaxLength > 0 && len(password) > policy.MaxLength {
+		invalidFields = append(invalidFields, invalidField("password", nil, errors.ReasonTooLong))
+	}
+
+	if policy.RequireLowercase && strings.IndexFunc(password, unicode.IsLower) < 0 {
+		invalidFields = append(invalidFields, invalidField("password", nil, ReasonMissingLowercase))
+	}
+
+	if policy.RequireUppercase && strings.IndexFunc(password, unicode.IsUpper) < 0 {
+		invalidFields = append(invalidFields, invalidField("password", nil, ReasonMissingUppercase))
+	}
+
+	if policy.RequireDigit && strings.IndexFunc(password, unicode.IsDigit) < 0 {
+		invalidFields = append(invalidFields, invalidField("password", nil, ReasonMissingDigit))
+	}
+
+	if policy.RequireSymbol && strings.IndexFunc(password, isSymbol) < 0 {
+		invalidFields = append(invalidFields, invalidField("password", nil, ReasonMissingSymbol))
+	}
+
+	if policy.ForbidNickname && nickname != "" &&
+		strings.Contains(strings.ToLower(password), strings.ToLower(nickname)) {
+		invalidFields = append(invalidFields, invalidField("password", nil, ReasonContainsNickname))
+	}
+
+	return invalidFields
+}
+
+/*
+validatePasswordHash checks the password hash is a bcrypt one, as those are the only ones we can authenticate against.
+
+The hash itself is never included in the errors either.
+*/
+func validatePasswordHash(passwordHash string) []error {
+	if passwordHash == "" {
+		return []error{invalidField("password_hash", nil, errors.ReasonRequired)}
+	}
+
+	if _, err := bcrypt.Cost([]byte(passwordHash)); err != nil {
+		return []error{invalidField("password_hash", nil, errors.ReasonInvalidFormat)}
+	}
+
+	return nil
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
@@ -0,0 +1,194 @@
+package user
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"nickname": {
+			"accept valid nicknames":  testValidNicknames,
+			"reject invalid nickname": testInvalidNicknames,
+		},
+		"email": {
+			"accept valid emails":  testValidEmails,
+			"reject invalid email": testInvalidEmails,
+		},
+		"country": {
+			"accept valid countries":   testValidCountries,
+			"reject invalid countries": testInvalidCountries,
+		},
+		"password": {
+			"accept password with default policy":     testValidPassword,
+			"reject password with default policy":     testInvalidPasswordWithDefaultPolicy,
+			"reject password with strict policy":      testInvalidPasswordWithStrictPolicy,
+			"report every broken rule":                testPasswordReportsEveryRule,
+			"reject password containing nickname":     testPasswordContainingNickname,
+			"check updated password against nickname": testUpdatePasswordContainingNickname,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+
+	// Set the policy back to its original value so it doesn't affect other tests.
+	SetPasswordPolicy(DefaultPasswordPolicy)
+}
+
+func testValidNicknames(t *testing.T) {
+	for _, nickname := range []string{"john-123", "Jane.Doe", "abc", "a_b", strings.Repeat("a", 32)} {
+		assert.NoError(t, validateNickname(nickname), nickname)
+	}
+}
+
+func testInvalidNicknames(t *testing.T) {
+	for nickname, reason := range map[string]string{
+		"":                      errors.ReasonRequired,
+		"ab":                    errors.ReasonTooShort,
+		strings.Repeat("a", 33): errors.ReasonTooLong,
+		"-john":                 errors.ReasonInvalidCharacters,
+		"me@john.com":           errors.ReasonInvalidCharacters,
+		"john doe":              errors.ReasonInvalidCharacters,
+	} {
+		assert.Equal(
+			t, &errors.InvalidField{Domain: domain, Field: "nickname", Value: nickname, Reason: reason},
+			validateNickname(nickname),
+		)
+	}
+}
+
+func testValidEmails(t *testing.T) {
+	for _, email := range []string{"me@john.com", "john.doe+test@mail.example.org", "a@b.co"} {
+		assert.NoError(t, validateEmail(email), email)
+	}
+}
+
+func testInvalidEmails(t *testing.T) {
+	for email, reason := range map[string]string{
+		"":                                     errors.ReasonRequired,
+		"foo":                                  errors.ReasonInvalidFormat,
+		"John <me@john.com>":                   errors.ReasonInvalidFormat,
+		"me@localhost":                         errors.ReasonInvalidFormat,
+		"me@john.":                             errors.ReasonInvalidFormat,
+		"me@@john.com":                         errors.ReasonInvalidFormat,
+		strings.Repeat("a", 250) + "@john.com": errors.ReasonTooLong,
+	} {
+		assert.Equal(
+			t, &errors.InvalidField{Domain: domain, Field: "email", Value: email, Reason: reason},
+			validateEmail(email),
+		)
+	}
+}
+
+func testValidCountries(t *testing.T) {
+	for _, country := range []string{"ES", "US", "GB", "JP"} {
+		assert.NoError(t, validateCountry(country), country)
+	}
+}
+
+func testInvalidCountries(t *testing.T) {
+	for country, reason := range map[string]string{
+		"":       errors.ReasonRequired,
+		"Narnia": errors.ReasonUnknownValue,
+		"UK":     errors.ReasonUnknownValue,
+		"es":     errors.ReasonUnknownValue,
+		"XX":     errors.ReasonUnknownValue,
+	} {
+		assert.Equal(
+			t, &errors.InvalidField{Domain: domain, Field: "country", Value: country, Reason: reason},
+			validateCountry(country),
+		)
+	}
+}
+
+func passwordError(reason string) error {
+	return &errors.InvalidField{Domain: domain, Field: "password", Reason: reason}
+}
+
+func testValidPassword(t *testing.T) {
+	SetPasswordPolicy(DefaultPasswordPolicy)
+
+	assert.Empty(t, validatePassword("correct horse battery staple", "john-123"))
+}
+
+func testInvalidPasswordWithDefaultPolicy(t *testing.T) {
+	SetPasswordPolicy(DefaultPasswordPolicy)
+
+	assert.Equal(t, []error{passwordError(errors.ReasonRequired)}, validatePassword("", "john-123"))
+	assert.Equal(t, []error{passwordError(errors.ReasonTooShort)}, validatePassword("short", "john-123"))
+	assert.Equal(
+		t, []error{passwordError(errors.ReasonTooLong)}, validatePassword(strings.Repeat("a", 73), "john-123"),
+	)
+}
+
+func testInvalidPasswordWithStrictPolicy(t *testing.T) {
+	SetPasswordPolicy(
+		PasswordPolicy{
+			MinLength:        8,
+			RequireLowercase: true,
+			RequireUppercase: true,
+			RequireDigit:     true,
+			RequireSymbol:    true,
+		},
+	)
+
+	assert.Empty(t, validatePassword("Sup3r-secret", "john-123"))
+	assert.Equal(t, []error{passwordError(ReasonMissingLowercase)}, validatePassword("SUP3R-SECRET", "john-123"))
+	assert.Equal(t, []error{passwordError(ReasonMissingUppercase)}, validatePassword("sup3r-secret", "john-123"))
+	assert.Equal(t, []error{passwordError(ReasonMissingDigit)}, validatePassword("Super-secret", "john-123"))
+	assert.Equal(t, []error{passwordError(ReasonMissingSymbol)}, validatePassword("Sup3rsecret", "john-123"))
+}
+
+func testPasswordReportsEveryRule(t *testing.T) {
+	SetPasswordPolicy(PasswordPolicy{MinLength: 8, RequireDigit: true, RequireSymbol: true})
+
+	assert.Equal(
+		t, []error{
+			passwordError(errors.ReasonTooShort),
+			passwordError(ReasonMissingDigit),
+			passwordError(ReasonMissingSymbol),
+		}, validatePassword("short", "john-123"),
+	)
+}
+
+func testPasswordContainingNickname(t *testing.T) {
+	SetPasswordPolicy(DefaultPasswordPolicy)
+
+	assert.Equal(
+		t, []error{passwordError(ReasonContainsNickname)}, validatePassword("my-JOHN-123-password", "john-123"),
+	)
+
+	SetPasswordPolicy(PasswordPolicy{MinLength: 8})
+
+	assert.Empty(t, validatePassword("my-JOHN-123-password", "john-123"))
+}
+
+func testUpdatePasswordContainingNickname(t *testing.T) {
+	SetPasswordPolicy(DefaultPasswordPolicy)
+	user := User1
+
+	password := "new-nickname-password"
+	nickname := "new-nickname"
+
+	err := user.Update(Patch{"nickname": nickname, "password": password})
+	assert.Equal(t, passwordError(ReasonContainsNickname), err)
+
+	password = "john-123-password"
+	err = user.Update(Patch{"password": password})
+	assert.Equal(t, passwordError(ReasonContainsNickname), err)
+	assert.Equal(t, User1, user)
+}
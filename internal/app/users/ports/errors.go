@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/*
+mapError translates the errors returned by the application into gRPC statuses, through the shared error registry.
+
+Registered errors are caused by the request, so they are only logged as info.
+The rest are logged as errors and hidden behind unknownMsg, so their details
+never reach the clients.
+*/
+func mapError(fields logrus.Fields, err error, unknownMsg string) error {
+	code := errors.CodeOf(err)
+
+	if code == errors.CodeUnknown {
+		logrus.WithFields(fields).WithError(err).Error(unknownMsg)
+
+		return status.Error(codes.Internal, unknownMsg)
+	}
+
+	logrus.WithFields(fields).WithField("code", code).WithError(err).Info("Request rejected")
+
+	return errors.ToStatus(err)
+}
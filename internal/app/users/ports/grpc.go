@@ -5,16 +5,17 @@ import (
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
-	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
-	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/grpc_utils"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
 	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"strconv"
 )
 
 type GrpcServer struct {
@@ -40,39 +41,15 @@ func (g *GrpcServer) CreateUser(ctx context.Context, request *apiV1.CreateUserRe
 	id, err := g.app.Commands.CreateUser.Handle(ctx, cmd)
 
 	if err != nil {
-		if castErr, ok := err.(*errors.InvalidField); ok {
-			logrus.WithFields(
-				logrus.Fields{
-					"tag": createUserTag,
-					"cmd": cmd,
-				},
-			).WithError(castErr).Error("Invalid field")
-
-			return nil, status.Error(codes.InvalidArgument, castErr.Error())
-		}
-
-		if castErr, ok := err.(*errors.MultipleInvalidFields); ok {
-			logrus.WithFields(
-				logrus.Fields{
-					"tag": createUserTag,
-					"cmd": cmd,
-				},
-			).WithError(castErr).Error("Invalid fields")
-
-			return nil, status.Error(codes.InvalidArgument, castErr.Error())
-		}
-
-		logrus.WithFields(
+		return nil, mapError(
 			logrus.Fields{
 				"tag": createUserTag,
 				"cmd": cmd,
-			},
-		).WithError(err).Error("Unknown error while creating user")
-
-		return nil, status.Error(codes.Internal, "Unknown error while creating user")
+			}, err, "Unknown error while creating user",
+		)
 	}
 
-	newUser, err := g.app.Queries.GetUserById.Handle(ctx, id)
+	newUser, err := g.app.Queries.GetUserById.Handle(ctx, query.GetUserById{Id: id})
 
 	if err != nil {
 		logrus.WithFields(
@@ -90,16 +67,117 @@ func (g *GrpcServer) CreateUser(ctx context.Context, request *apiV1.CreateUserRe
 		FirstName: newUser.FirstName,
 		LastName:  newUser.LastName,
 		Nickname:  newUser.Nickname,
-		Password:  newUser.Password,
 		Email:     newUser.Email,
 		Country:   newUser.Country,
+		Roles:     newUser.Roles,
 		CreatedAt: timestamppb.New(newUser.CreatedAt),
 		UpdatedAt: timestamppb.New(newUser.UpdatedAt),
+		Version:   newUser.Version,
 	}, nil
 }
 
+const getUserTag = "GetUser"
+
+func (g *GrpcServer) GetUser(ctx context.Context, request *apiV1.GetUserRequest) (*apiV1.User, error) {
+	if request.GetId() == "" {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     getUserTag,
+				"request": request,
+			},
+		).Info("Error getting user: id is required")
+
+		return nil, status.Error(codes.InvalidArgument, "Id is required")
+	}
+
+	foundUser, err := g.app.Queries.GetUserById.Handle(
+		ctx, query.GetUserById{Id: request.GetId(), ReadMask: request.GetReadMask().GetPaths()},
+	)
+
+	if err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag": getUserTag,
+				"id":  request.GetId(),
+			}, err, "Unknown error while getting user",
+		)
+	}
+
+	response := &apiV1.User{
+		Id:        foundUser.Id,
+		FirstName: foundUser.FirstName,
+		LastName:  foundUser.LastName,
+		Nickname:  foundUser.Nickname,
+		Email:     foundUser.Email,
+		Country:   foundUser.Country,
+		Roles:     foundUser.Roles,
+		CreatedAt: timestamppb.New(foundUser.CreatedAt),
+		UpdatedAt: timestamppb.New(foundUser.UpdatedAt),
+		Version:   foundUser.Version,
+	}
+
+	grpc_utils.ApplyReadMask(response, request.GetReadMask())
+
+	return response, nil
+}
+
+const batchGetUsersTag = "BatchGetUsers"
+
+func (g *GrpcServer) BatchGetUsers(
+	ctx context.Context, request *apiV1.BatchGetUsersRequest,
+) (*apiV1.BatchGetUsersResponse, error) {
+	batchQuery := query.GetUsersByIds{Ids: request.GetIds(), ReadMask: request.GetReadMask().GetPaths()}
+
+	results, err := g.app.Queries.GetUsersByIds.Handle(ctx, batchQuery)
+
+	if err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag":   batchGetUsersTag,
+				"query": batchQuery,
+			}, err, "Unknown error while getting users",
+		)
+	}
+
+	response := &apiV1.BatchGetUsersResponse{Results: make([]*apiV1.BatchGetUsersResponse_Result, len(results))}
+
+	for i, result := range results {
+		response.Results[i] = &apiV1.BatchGetUsersResponse_Result{Id: result.Id, NotFound: result.User == nil}
+
+		if result.User == nil {
+			continue
+		}
+
+		response.Results[i].User = &apiV1.User{
+			Id:        result.User.Id,
+			FirstName: result.User.FirstName,
+			LastName:  result.User.LastName,
+			Nickname:  result.User.Nickname,
+			Email:     result.User.Email,
+			Country:   result.User.Country,
+			Roles:     result.User.Roles,
+			CreatedAt: timestamppb.New(result.User.CreatedAt),
+			UpdatedAt: timestamppb.New(result.User.UpdatedAt),
+			Version:   result.User.Version,
+		}
+
+		grpc_utils.ApplyReadMask(response.Results[i].User, request.GetReadMask())
+	}
+
+	return response, nil
+}
+
 const getUsersTag = "GetUsers"
 
+/*
+The trailers of GetUsers carry the metadata of the page, as the users are streamed on their own.
+*/
+const (
+	nextPageTokenTrailer = "next-page-token"
+	hasMoreTrailer       = "has-more"
+	totalCountTrailer    = "total-count"
+)
+
 func (g *GrpcServer) GetUsers(request *apiV1.GetUsersRequest, srv apiV1.UserService_GetUsersServer) error {
 	var filters []query_utils.Filter
 	for _, filter := range request.GetFilters() {
@@ -111,42 +189,67 @@ func (g *GrpcServer) GetUsers(request *apiV1.GetUsersRequest, srv apiV1.UserServ
 		sorts = append(sorts, grpc_utils.MapGrpcSortToSort(sort))
 	}
 
+	var filter *query_utils.FilterExpression
+	if request.GetFilter() != nil {
+		expression := grpc_utils.MapGrpcFilterExpressionToFilterExpression(request.GetFilter())
+		filter = &expression
+	}
+
 	getUsersQuery := query.GetUsers{
 		Filters: filters,
+		Filter:  filter,
 		Sort:    sorts,
 		Pagination: query_utils.Pagination{
 			Limit:  request.GetPagination().GetLimit(),
 			Offset: request.GetPagination().GetOffset(),
 		},
+		PageToken:         request.GetPageToken(),
+		IncludeTotalCount: request.GetIncludeTotalCount(),
+		ReadMask:          request.GetReadMask().GetPaths(),
 	}
 
-	users, err := g.app.Queries.GetUsers.Handle(srv.Context(), getUsersQuery)
+	ctx := srv.Context()
+	users, err := g.app.Queries.GetUsers.Handle(ctx, getUsersQuery)
 
 	if err != nil {
-		logrus.WithFields(
+		return mapError(
 			logrus.Fields{
 				"tag":   getUsersTag,
 				"query": getUsersQuery,
-			},
-		).WithError(err).Error("Error retrieving users")
-
-		return status.Error(codes.Internal, "Error retrieving users")
-	}
-
-	for i, currentUser := range users {
-		if err := srv.Send(
-			&apiV1.User{
-				Id:        currentUser.Id,
-				FirstName: currentUser.FirstName,
-				LastName:  currentUser.LastName,
-				Nickname:  currentUser.Nickname,
-				Password:  currentUser.Password,
-				Email:     currentUser.Email,
-				Country:   currentUser.Country,
-				CreatedAt: timestamppb.New(currentUser.CreatedAt),
-				UpdatedAt: timestamppb.New(currentUser.UpdatedAt),
-			},
-		); err != nil {
+			}, err, "Error retrieving users",
+		)
+	}
+
+	// The stream is closed even if the client went away, so the cursor isn't left open on the database.
+	defer func() {
+		if err := users.Close(context.Background()); err != nil {
+			logrus.WithFields(
+				logrus.Fields{
+					"tag": getUsersTag,
+				},
+			).WithError(err).Error("Error closing users stream")
+		}
+	}()
+
+	// Each user is only read once the previous one has been sent, which blocks while the client can't take more.
+	for i := 0; users.Next(ctx); i++ {
+		currentUser := users.User()
+		response := &apiV1.User{
+			Id:        currentUser.Id,
+			FirstName: currentUser.FirstName,
+			LastName:  currentUser.LastName,
+			Nickname:  currentUser.Nickname,
+			Email:     currentUser.Email,
+			Country:   currentUser.Country,
+			Roles:     currentUser.Roles,
+			CreatedAt: timestamppb.New(currentUser.CreatedAt),
+			UpdatedAt: timestamppb.New(currentUser.UpdatedAt),
+			Version:   currentUser.Version,
+		}
+
+		grpc_utils.ApplyReadMask(response, request.GetReadMask())
+
+		if err := srv.Send(response); err != nil {
 			logrus.WithFields(
 				logrus.Fields{
 					"tag":   getUsersTag,
@@ -159,79 +262,109 @@ func (g *GrpcServer) GetUsers(request *apiV1.GetUsersRequest, srv apiV1.UserServ
 		}
 	}
 
+	if err := users.Err(); err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+		return mapError(
+			logrus.Fields{
+				"tag":   getUsersTag,
+				"query": getUsersQuery,
+			}, err, "Error retrieving users",
+		)
+	}
+
+	page := users.Page()
+	trailer := metadata.Pairs(hasMoreTrailer, strconv.FormatBool(page.HasMore))
+
+	if page.NextPageToken != "" {
+		trailer.Set(nextPageTokenTrailer, page.NextPageToken)
+	}
+
+	if getUsersQuery.IncludeTotalCount {
+		trailer.Set(totalCountTrailer, strconv.FormatInt(page.TotalCount, 10))
+	}
+
+	srv.SetTrailer(trailer)
+
 	return nil
 }
 
+const countUsersTag = "CountUsers"
+
+func (g *GrpcServer) CountUsers(
+	ctx context.Context, request *apiV1.CountUsersRequest,
+) (*apiV1.CountUsersResponse, error) {
+	var filters []query_utils.Filter
+	for _, filter := range request.GetFilters() {
+		filters = append(filters, grpc_utils.MapGrpcFilterToFilter(filter))
+	}
+
+	var filter *query_utils.FilterExpression
+	if request.GetFilter() != nil {
+		expression := grpc_utils.MapGrpcFilterExpressionToFilterExpression(request.GetFilter())
+		filter = &expression
+	}
+
+	countUsersQuery := query.CountUsers{
+		Filters: filters,
+		Filter:  filter,
+	}
+
+	count, err := g.app.Queries.CountUsers.Handle(ctx, countUsersQuery)
+
+	if err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag":   countUsersTag,
+				"query": countUsersQuery,
+			}, err, "Error counting users",
+		)
+	}
+
+	return &apiV1.CountUsersResponse{TotalCount: count}, nil
+}
+
 const updateUserTag = "UpdateUser"
 
 func (g *GrpcServer) UpdateUser(ctx context.Context, request *apiV1.UpdateUserRequest) (*apiV1.User, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Authentication required")
+	}
 
 	if request.GetId() == "" {
 		logrus.WithFields(
 			logrus.Fields{
 				"tag":     updateUserTag,
-				"request": request,
+				"actorId": principal.Subject,
 			},
-		).Error("Error updating user: id is required")
+		).Info("Error updating user: id is required")
 
 		return nil, status.Error(codes.InvalidArgument, "Id is required")
 	}
 
 	cmd := command.UpdateUser{
-		Id:        request.GetId(),
-		FirstName: request.FirstName,
-		LastName:  request.LastName,
-		Nickname:  request.Nickname,
-		Password:  request.Password,
-		Email:     request.Email,
-		Country:   request.Country,
+		ActorId:         principal.Subject,
+		Id:              request.GetId(),
+		ExpectedVersion: request.ExpectedVersion,
+		Patch:           grpc_utils.MapUpdateMask(request, request.GetUpdateMask(), "id", "expected_version"),
 	}
 
-	err := g.app.Commands.UpdateUser.Handle(ctx, cmd)
-
-	if err != nil {
-		if castErr, ok := err.(*user.NotFoundError); ok {
-			logrus.WithFields(
-				logrus.Fields{
-					"tag": updateUserTag,
-					"cmd": cmd,
-				},
-			).WithError(castErr).Error("Attempted to update nonexistent user")
-
-			return nil, status.Error(codes.NotFound, castErr.Error())
-		}
-		if castErr, ok := err.(*errors.InvalidField); ok {
-			logrus.WithFields(
-				logrus.Fields{
-					"tag": updateUserTag,
-					"cmd": cmd,
-				},
-			).WithError(castErr).Error("Invalid field")
-
-			return nil, status.Error(codes.InvalidArgument, castErr.Error())
-		}
-		if castErr, ok := err.(*errors.MultipleInvalidFields); ok {
-			logrus.WithFields(
-				logrus.Fields{
-					"tag": updateUserTag,
-					"cmd": cmd,
-				},
-			).WithError(castErr).Error("Invalid fields")
-
-			return nil, status.Error(codes.InvalidArgument, castErr.Error())
-		}
-
-		logrus.WithFields(
+	if err := g.app.Commands.UpdateUser.Handle(ctx, cmd); err != nil {
+		return nil, mapError(
 			logrus.Fields{
-				"tag": updateUserTag,
-				"cmd": cmd,
-			},
-		).WithError(err).Error("Unknown error while updating user")
-
-		return nil, status.Error(codes.Internal, "Unknown error while updating user")
+				"tag":     updateUserTag,
+				"actorId": cmd.ActorId,
+				"id":      cmd.Id,
+				"fields":  cmd.Patch.Fields(),
+			}, err, "Unknown error while updating user",
+		)
 	}
 
-	updatedUser, err := g.app.Queries.GetUserById.Handle(ctx, request.GetId())
+	updatedUser, err := g.app.Queries.GetUserById.Handle(ctx, query.GetUserById{Id: request.GetId()})
 
 	if err != nil {
 		logrus.WithFields(
@@ -249,51 +382,92 @@ func (g *GrpcServer) UpdateUser(ctx context.Context, request *apiV1.UpdateUserRe
 		FirstName: updatedUser.FirstName,
 		LastName:  updatedUser.LastName,
 		Nickname:  updatedUser.Nickname,
-		Password:  updatedUser.Password,
 		Email:     updatedUser.Email,
 		Country:   updatedUser.Country,
+		Roles:     updatedUser.Roles,
 		CreatedAt: timestamppb.New(updatedUser.CreatedAt),
 		UpdatedAt: timestamppb.New(updatedUser.UpdatedAt),
+		Version:   updatedUser.Version,
 	}, nil
 }
 
 const removeUserTag = "RemoveUser"
 
 func (g *GrpcServer) RemoveUser(ctx context.Context, request *apiV1.RemoveUserRequest) (*emptypb.Empty, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Authentication required")
+	}
+
 	if request.GetId() == "" {
 		logrus.WithFields(
 			logrus.Fields{
 				"tag":     removeUserTag,
 				"request": request,
 			},
-		).Error("Error removing user: id is required")
+		).Info("Error removing user: id is required")
 
 		return nil, status.Error(codes.InvalidArgument, "Id is required")
 	}
 
-	err := g.app.Commands.RemoveUser.Handle(ctx, request.GetId())
+	cmd := command.RemoveUser{
+		ActorId: principal.Subject,
+		Id:      request.GetId(),
+	}
 
-	if err != nil {
-		if castErr, ok := err.(*user.NotFoundError); ok {
-			logrus.WithFields(
-				logrus.Fields{
-					"tag": updateUserTag,
-					"id":  request.GetId(),
-				},
-			).WithError(castErr).Error("Attempted to remove nonexistent user")
+	if err := g.app.Commands.RemoveUser.Handle(ctx, cmd); err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag": removeUserTag,
+				"cmd": cmd,
+			}, err, "Unknown error while removing user",
+		)
+	}
 
-			return nil, status.Error(codes.NotFound, castErr.Error())
-		}
+	return &emptypb.Empty{}, nil
+}
+
+const authenticateTag = "Authenticate"
 
+func (g *GrpcServer) Authenticate(ctx context.Context, request *apiV1.AuthenticateRequest) (*apiV1.User, error) {
+	if request.GetNicknameOrEmail() == "" || request.GetPassword() == "" {
 		logrus.WithFields(
 			logrus.Fields{
-				"tag": removeUserTag,
-				"id":  request.GetId(),
+				"tag":             authenticateTag,
+				"nicknameOrEmail": request.GetNicknameOrEmail(),
 			},
-		).WithError(err).Error("Error removing user")
+		).Info("Error authenticating user: nickname or email and password are required")
 
-		return nil, status.Error(codes.Internal, "Unknown error while removing user")
+		return nil, status.Error(codes.InvalidArgument, "Nickname or email and password are required")
 	}
 
-	return &emptypb.Empty{}, nil
+	authQuery := query.Authenticate{
+		NicknameOrEmail: request.GetNicknameOrEmail(),
+		Password:        request.GetPassword(),
+	}
+
+	authUser, err := g.app.Queries.Authenticate.Handle(ctx, authQuery)
+
+	if err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag":             authenticateTag,
+				"nicknameOrEmail": request.GetNicknameOrEmail(),
+			}, err, "Unknown error while authenticating user",
+		)
+	}
+
+	return &apiV1.User{
+		Id:        authUser.Id,
+		FirstName: authUser.FirstName,
+		LastName:  authUser.LastName,
+		Nickname:  authUser.Nickname,
+		Email:     authUser.Email,
+		Country:   authUser.Country,
+		Roles:     authUser.Roles,
+		CreatedAt: timestamppb.New(authUser.CreatedAt),
+		UpdatedAt: timestamppb.New(authUser.UpdatedAt),
+		Version:   authUser.Version,
+	}, nil
 }
@@ -0,0 +1,139 @@
+package ports
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+)
+
+const importUsersTag = "ImportUsers"
+
+/*
+ImportUsers reads the users sent by the client in batches, importing each batch once it's full, or the client is done
+sending.
+
+This way, a single InsertMany stores up to MaxImportBatchSize users, while the
+client only needs to wait for its batch to get the results.
+*/
+func (g *GrpcServer) ImportUsers(srv apiV1.UserService_ImportUsersServer) error {
+	ctx := srv.Context()
+	principal, ok := auth.PrincipalFromContext(ctx)
+
+	if !ok {
+		return status.Error(codes.Unauthenticated, "Authentication required")
+	}
+
+	var batch []command.ImportedUser
+	var offset int64
+
+	for {
+		request, err := srv.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+
+			logrus.WithFields(
+				logrus.Fields{
+					"tag":   importUsersTag,
+					"index": offset + int64(len(batch)),
+				},
+			).WithError(err).Error("Error receiving user")
+
+			return err
+		}
+
+		batch = append(
+			batch, command.ImportedUser{
+				FirstName:    request.GetFirstName(),
+				LastName:     request.GetLastName(),
+				Nickname:     request.GetNickname(),
+				Password:     request.GetPassword(),
+				PasswordHash: request.GetPasswordHash(),
+				Email:        request.GetEmail(),
+				Country:      request.GetCountry(),
+			},
+		)
+
+		if len(batch) == command.MaxImportBatchSize {
+			if err := g.importBatch(srv, principal.Subject, batch, offset); err != nil {
+				return err
+			}
+
+			offset += int64(len(batch))
+			batch = nil
+		}
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	return g.importBatch(srv, principal.Subject, batch, offset)
+}
+
+/*
+importBatch imports a batch of users and sends their results, indexed from offset, the position of the first one.
+*/
+func (g *GrpcServer) importBatch(
+	srv apiV1.UserService_ImportUsersServer, actorId string, batch []command.ImportedUser, offset int64,
+) error {
+	cmd := command.ImportUsers{ActorId: actorId, Users: batch}
+	results, err := g.app.Commands.ImportUsers.Handle(srv.Context(), cmd)
+
+	// The command isn't logged, as it holds the passwords.
+	if err != nil {
+		if srv.Context().Err() != nil {
+			return status.FromContextError(srv.Context().Err()).Err()
+		}
+
+		return mapError(
+			logrus.Fields{
+				"tag":     importUsersTag,
+				"actorId": actorId,
+				"offset":  offset,
+			}, err, "Unknown error while importing users",
+		)
+	}
+
+	for i, result := range results {
+		index := offset + int64(i)
+		response := &apiV1.ImportUserResult{Index: index, Id: result.Id}
+
+		if result.Err != nil {
+			st := status.Convert(
+				mapError(
+					logrus.Fields{
+						"tag":      importUsersTag,
+						"index":    index,
+						"nickname": batch[i].Nickname,
+					}, result.Err, "Unknown error while importing user",
+				),
+			).Proto()
+
+			response.Error = &apiV1.Status{Code: st.GetCode(), Message: st.GetMessage(), Details: st.GetDetails()}
+		}
+
+		if err := srv.Send(response); err != nil {
+			logrus.WithFields(
+				logrus.Fields{
+					"tag":   importUsersTag,
+					"index": index,
+				},
+			).WithError(err).Error("Error sending import result")
+
+			return status.Error(codes.Internal, "Error sending import results")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package ports
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	errors2 "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/elizabeth-dev/ACME_Test/test/mocks"
+	handler_mocks2 "github.com/elizabeth-dev/ACME_Test/test/mocks/handler_mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+)
+
+func TestGrpcImport(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"call import users":                      testImportUsers,
+		"call import users in batches":           testImportUsersInBatches,
+		"call import users without users":        testImportUsersWithoutUsers,
+		"call import users without principal":    testImportUsersWithoutPrincipal,
+		"call import users with forbidden error": testImportUsersWithForbiddenError,
+		"call import users with unknown error":   testImportUsersWithUnknownError,
+		"call import users with receive error":   testImportUsersWithReceiveError,
+		"call import users with send error":      testImportUsersWithSendError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func newImportServer() (*handler_mocks2.IImportUsersHandler, *mocks.UserService_ImportUsersServer, GrpcServer) {
+	mockImportUsers := new(handler_mocks2.IImportUsersHandler)
+	mockSrv := new(mocks.UserService_ImportUsersServer)
+	application := app.Application{
+		Commands: app.Commands{ImportUsers: mockImportUsers},
+	}
+
+	return mockImportUsers, mockSrv, GrpcServer{app: application}
+}
+
+func importRequest(nickname string) *apiV1.ImportUserRequest {
+	return &apiV1.ImportUserRequest{
+		FirstName:   "John",
+		LastName:    "Doe",
+		Nickname:    nickname,
+		Credentials: &apiV1.ImportUserRequest_PasswordHash{PasswordHash: "hash"},
+		Email:       nickname + "@john.com",
+		Country:     "US",
+	}
+}
+
+func importedUser(nickname string) command.ImportedUser {
+	return command.ImportedUser{
+		FirstName:    "John",
+		LastName:     "Doe",
+		Nickname:     nickname,
+		PasswordHash: "hash",
+		Email:        nickname + "@john.com",
+		Country:      "US",
+	}
+}
+
+func testImportUsers(t *testing.T) {
+	mockImportUsers, mockSrv, server := newImportServer()
+
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+	withPassword := importRequest("jane")
+	withPassword.Credentials = &apiV1.ImportUserRequest_Password{Password: "password"}
+
+	invalidErr := &errors2.InvalidField{
+		Domain: "User", Field: "nickname", Value: "jo", Reason: errors2.ReasonTooShort,
+	}
+	cmd := command.ImportUsers{
+		ActorId: adminId,
+		Users: []command.ImportedUser{
+			importedUser("john"),
+			{
+				FirstName: "John",
+				LastName:  "Doe",
+				Nickname:  "jane",
+				Password:  "password",
+				Email:     "jane@john.com",
+				Country:   "US",
+			},
+			importedUser("jo"),
+		},
+	}
+
+	mockSrv.On("Context").Return(ctx)
+	mockSrv.On("Recv").Return(importRequest("john"), nil).Once()
+	mockSrv.On("Recv").Return(withPassword, nil).Once()
+	mockSrv.On("Recv").Return(importRequest("jo"), nil).Once()
+	mockSrv.On("Recv").Return(nil, io.EOF).Once()
+	mockImportUsers.On("Handle", ctx, cmd).Return(
+		[]command.ImportUserResult{{Id: "1"}, {Id: "2"}, {Err: invalidErr}}, nil,
+	)
+	mockSrv.On("Send", &apiV1.ImportUserResult{Index: 0, Id: "1"}).Return(nil).Once()
+	mockSrv.On("Send", &apiV1.ImportUserResult{Index: 1, Id: "2"}).Return(nil).Once()
+
+	var failed *apiV1.ImportUserResult
+	mockSrv.On("Send", mock.MatchedBy(func(result *apiV1.ImportUserResult) bool { return result.Index == 2 })).Run(
+		func(args mock.Arguments) {
+			failed = args.Get(0).(*apiV1.ImportUserResult)
+		},
+	).Return(nil).Once()
+
+	err := server.ImportUsers(mockSrv)
+
+	mockImportUsers.AssertExpectations(t)
+	mockSrv.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Empty(t, failed.GetId())
+	assert.Equal(t, int32(codes.InvalidArgument), failed.GetError().GetCode())
+	assert.Equal(t, invalidErr.Error(), failed.GetError().GetMessage())
+
+	// The details are the same a failed CreateUser would have had.
+	expected, _ := status.FromError(errors2.ToStatus(invalidErr))
+	badRequest := &errdetails.BadRequest{}
+
+	assert.Len(t, failed.GetError().GetDetails(), len(expected.Details()))
+	assert.NoError(t, failed.GetError().GetDetails()[0].UnmarshalTo(badRequest))
+	assert.Equal(t, "nickname", badRequest.GetFieldViolations()[0].GetField())
+}
+
+func testImportUsersInBatches(t *testing.T) {
+	mockImportUsers, mockSrv, server := newImportServer()
+
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+	total := command.MaxImportBatchSize + 1
+
+	mockSrv.On("Context").Return(ctx)
+	mockSrv.On("Recv").Return(importRequest("john"), nil).Times(total)
+	mockSrv.On("Recv").Return(nil, io.EOF).Once()
+
+	for _, size := range []int{command.MaxImportBatchSize, 1} {
+		size := size
+		mockImportUsers.On(
+			"Handle", ctx, mock.MatchedBy(
+				func(cmd command.ImportUsers) bool {
+					return cmd.ActorId == adminId && len(cmd.Users) == size
+				},
+			),
+		).Return(make([]command.ImportUserResult, size), nil).Once()
+	}
+
+	var indexes []int64
+	mockSrv.On("Send", mock.Anything).Run(
+		func(args mock.Arguments) {
+			indexes = append(indexes, args.Get(0).(*apiV1.ImportUserResult).GetIndex())
+		},
+	).Return(nil)
+
+	err := server.ImportUsers(mockSrv)
+
+	mockImportUsers.AssertExpectations(t)
+	mockSrv.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Len(t, indexes, total)
+
+	for i, index := range indexes {
+		assert.Equal(t, int64(i), index)
+	}
+}
+
+func testImportUsersWithoutUsers(t *testing.T) {
+	mockImportUsers, mockSrv, server := newImportServer()
+
+	mockSrv.On("Context").Return(contextWithPrincipal(adminId, "user", "admin"))
+	mockSrv.On("Recv").Return(nil, io.EOF).Once()
+
+	err := server.ImportUsers(mockSrv)
+
+	mockSrv.AssertExpectations(t)
+	mockImportUsers.AssertNotCalled(t, "Handle", mock.Anything, mock.Anything)
+	mockSrv.AssertNotCalled(t, "Send", mock.Anything)
+
+	assert.NoError(t, err)
+}
+
+func testImportUsersWithoutPrincipal(t *testing.T) {
+	mockImportUsers, mockSrv, server := newImportServer()
+
+	mockSrv.On("Context").Return(context.Background())
+
+	err := server.ImportUsers(mockSrv)
+
+	mockImportUsers.AssertNotCalled(t, "Handle", mock.Anything, mock.Anything)
+	mockSrv.AssertNotCalled(t, "Recv")
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func testImportUsersWithForbiddenError(t *testing.T) {
+	mockImportUsers, mockSrv, server := newImportServer()
+
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+	cmd := command.ImportUsers{ActorId: adminId, Users: []command.ImportedUser{importedUser("john")}}
+
+	mockSrv.On("Context").Return(ctx)
+	mockSrv.On("Recv").Return(importRequest("john"), nil).Once()
+	mockSrv.On("Recv").Return(nil, io.EOF).Once()
+	mockImportUsers.On("Handle", ctx, cmd).Return(nil, &user.ForbiddenError{ActorId: adminId})
+
+	err := server.ImportUsers(mockSrv)
+
+	mockImportUsers.AssertExpectations(t)
+	mockSrv.AssertNotCalled(t, "Send", mock.Anything)
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func testImportUsersWithUnknownError(t *testing.T) {
+	mockImportUsers, mockSrv, server := newImportServer()
+
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+	cmd := command.ImportUsers{ActorId: adminId, Users: []command.ImportedUser{importedUser("john")}}
+
+	mockSrv.On("Context").Return(ctx)
+	mockSrv.On("Recv").Return(importRequest("john"), nil).Once()
+	mockSrv.On("Recv").Return(nil, io.EOF).Once()
+	mockImportUsers.On("Handle", ctx, cmd).Return(nil, errors.New("db is down"))
+
+	err := server.ImportUsers(mockSrv)
+
+	mockImportUsers.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while importing users"))
+}
+
+func testImportUsersWithReceiveError(t *testing.T) {
+	mockImportUsers, mockSrv, server := newImportServer()
+
+	recvErr := status.Error(codes.Unavailable, "connection lost")
+
+	mockSrv.On("Context").Return(contextWithPrincipal(adminId, "user", "admin"))
+	mockSrv.On("Recv").Return(nil, recvErr).Once()
+
+	err := server.ImportUsers(mockSrv)
+
+	mockImportUsers.AssertNotCalled(t, "Handle", mock.Anything, mock.Anything)
+
+	assert.Equal(t, recvErr, err)
+}
+
+func testImportUsersWithSendError(t *testing.T) {
+	mockImportUsers, mockSrv, server := newImportServer()
+
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+	cmd := command.ImportUsers{ActorId: adminId, Users: []command.ImportedUser{importedUser("john")}}
+
+	mockSrv.On("Context").Return(ctx)
+	mockSrv.On("Recv").Return(importRequest("john"), nil).Once()
+	mockSrv.On("Recv").Return(nil, io.EOF).Once()
+	mockImportUsers.On("Handle", ctx, cmd).Return([]command.ImportUserResult{{Id: "1"}}, nil)
+	mockSrv.On("Send", mock.Anything).Return(errors.New("stream closed"))
+
+	err := server.ImportUsers(mockSrv)
+
+	mockSrv.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Error sending import results"))
+}
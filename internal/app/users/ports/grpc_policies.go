@@ -0,0 +1,33 @@
+package ports
+
+import "github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+
+const userServicePrefix = "/test.elizabeth.acme.api.v1.UserService/"
+
+/*
+AccessPolicies declares the access every UserService method requires.
+
+Signing up and everything related to getting tokens must stay public. Self
+methods check the request id against the caller, so users can only change
+their own data unless they're admins. Only admins can manage roles, or import users.
+*/
+var AccessPolicies = auth.Policies{
+	userServicePrefix + "CreateUser":    auth.Public,
+	userServicePrefix + "GetUser":       auth.Authenticated,
+	userServicePrefix + "BatchGetUsers": auth.Authenticated,
+	userServicePrefix + "GetUsers":      auth.Authenticated,
+	userServicePrefix + "CountUsers":    auth.Authenticated,
+	userServicePrefix + "UpdateUser":    auth.Self,
+	userServicePrefix + "RemoveUser":    auth.Self,
+
+	userServicePrefix + "Authenticate":   auth.Public,
+	userServicePrefix + "Login":          auth.Public,
+	userServicePrefix + "RefreshToken":   auth.Public,
+	userServicePrefix + "RevokeToken":    auth.Public,
+	userServicePrefix + "GetSigningKeys": auth.Public,
+
+	userServicePrefix + "GrantRole":  auth.Admin,
+	userServicePrefix + "RevokeRole": auth.Admin,
+
+	userServicePrefix + "ImportUsers": auth.Admin,
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccessPolicies(t *testing.T) {
+	t.Parallel()
+
+	methods := apiV1.File_user_proto.Services().ByName("UserService").Methods()
+
+	assert.Len(t, AccessPolicies, methods.Len())
+	for i := 0; i < methods.Len(); i++ {
+		assert.Contains(t, AccessPolicies, userServicePrefix+string(methods.Get(i).Name()))
+	}
+}
@@ -0,0 +1,117 @@
+package ports
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const grantRoleTag = "GrantRole"
+
+func (g *GrpcServer) GrantRole(ctx context.Context, request *apiV1.GrantRoleRequest) (*apiV1.User, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Authentication required")
+	}
+
+	if request.GetId() == "" || request.GetRole() == "" {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     grantRoleTag,
+				"request": request,
+			},
+		).Info("Error granting role: id and role are required")
+
+		return nil, status.Error(codes.InvalidArgument, "Id and role are required")
+	}
+
+	cmd := command.GrantRole{
+		ActorId: principal.Subject,
+		UserId:  request.GetId(),
+		Role:    request.GetRole(),
+	}
+
+	if err := g.app.Commands.GrantRole.Handle(ctx, cmd); err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag": grantRoleTag,
+				"cmd": cmd,
+			}, err, "Unknown error while granting role",
+		)
+	}
+
+	return g.getRoleUser(ctx, grantRoleTag, request.GetId())
+}
+
+const revokeRoleTag = "RevokeRole"
+
+func (g *GrpcServer) RevokeRole(ctx context.Context, request *apiV1.RevokeRoleRequest) (*apiV1.User, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Authentication required")
+	}
+
+	if request.GetId() == "" || request.GetRole() == "" {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":     revokeRoleTag,
+				"request": request,
+			},
+		).Info("Error revoking role: id and role are required")
+
+		return nil, status.Error(codes.InvalidArgument, "Id and role are required")
+	}
+
+	cmd := command.RevokeRole{
+		ActorId: principal.Subject,
+		UserId:  request.GetId(),
+		Role:    request.GetRole(),
+	}
+
+	if err := g.app.Commands.RevokeRole.Handle(ctx, cmd); err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag": revokeRoleTag,
+				"cmd": cmd,
+			}, err, "Unknown error while revoking role",
+		)
+	}
+
+	return g.getRoleUser(ctx, revokeRoleTag, request.GetId())
+}
+
+func (g *GrpcServer) getRoleUser(ctx context.Context, tag string, id string) (*apiV1.User, error) {
+	updatedUser, err := g.app.Queries.GetUserById.Handle(ctx, query.GetUserById{Id: id})
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": tag,
+				"id":  id,
+			},
+		).WithError(err).Error("Error retrieving updated user")
+
+		return nil, status.Error(codes.Unavailable, "The roles were changed but the user couldn't be retrieved")
+	}
+
+	return &apiV1.User{
+		Id:        updatedUser.Id,
+		FirstName: updatedUser.FirstName,
+		LastName:  updatedUser.LastName,
+		Nickname:  updatedUser.Nickname,
+		Email:     updatedUser.Email,
+		Country:   updatedUser.Country,
+		Roles:     updatedUser.Roles,
+		CreatedAt: timestamppb.New(updatedUser.CreatedAt),
+		UpdatedAt: timestamppb.New(updatedUser.UpdatedAt),
+		Version:   updatedUser.Version,
+	}, nil
+}
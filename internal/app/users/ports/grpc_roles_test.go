@@ -0,0 +1,318 @@
+package ports
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	errors2 "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	handler_mocks2 "github.com/elizabeth-dev/ACME_Test/test/mocks/handler_mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+func TestGrpcRoles(t *testing.T) {
+	t.Parallel()
+
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"grant role": {
+			"call grant role":                         testGrantRole,
+			"call grant role without principal":       testGrantRoleWithoutPrincipal,
+			"call grant role with missing parameters": testGrantRoleWithMissingParams,
+			"call grant role with not found error":    testGrantRoleWithNotFoundError,
+			"call grant role with forbidden error":    testGrantRoleWithForbiddenError,
+			"call grant role with invalid role error": testGrantRoleWithInvalidRoleError,
+			"call grant role with unknown error":      testGrantRoleWithUnknownError,
+			"call grant role with get error":          testGrantRoleWithGetError,
+		},
+		"revoke role": {
+			"call revoke role":                         testRevokeRole,
+			"call revoke role with missing parameters": testRevokeRoleWithMissingParams,
+			"call revoke role with forbidden error":    testRevokeRoleWithForbiddenError,
+			"call revoke role with unknown error":      testRevokeRoleWithUnknownError,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							t.Parallel()
+
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+}
+
+const adminId = "admin"
+
+func newRolesServer() (
+	*handler_mocks2.IGrantRoleHandler,
+	*handler_mocks2.IRevokeRoleHandler,
+	*handler_mocks2.IGetUserByIdHandler,
+	GrpcServer,
+) {
+	mockGrantRole := new(handler_mocks2.IGrantRoleHandler)
+	mockRevokeRole := new(handler_mocks2.IRevokeRoleHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{GrantRole: mockGrantRole, RevokeRole: mockRevokeRole},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+
+	return mockGrantRole, mockRevokeRole, mockGetUserById, GrpcServer{app: application}
+}
+
+func roleUser(id string, roles ...string) *query.User {
+	now := time.Now()
+
+	return &query.User{
+		Id:        id,
+		FirstName: "John",
+		LastName:  "Doe",
+		Nickname:  "john-123",
+		Email:     "me@john.com",
+		Country:   "US",
+		Roles:     roles,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func testGrantRole(t *testing.T) {
+	mockGrantRole, _, mockGetUserById, server := newRolesServer()
+
+	id := "1234"
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+	request := apiV1.GrantRoleRequest{Id: id, Role: "moderator"}
+
+	getUserResult := roleUser(id, "user", "moderator")
+
+	cmd := command.GrantRole{ActorId: adminId, UserId: id, Role: "moderator"}
+	mockGrantRole.On("Handle", ctx, cmd).Return(nil)
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: id}).Return(getUserResult, nil)
+
+	out, err := server.GrantRole(ctx, &request)
+
+	mockGrantRole.AssertExpectations(t)
+	mockGetUserById.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &apiV1.User{
+			Id:        getUserResult.Id,
+			FirstName: getUserResult.FirstName,
+			LastName:  getUserResult.LastName,
+			Nickname:  getUserResult.Nickname,
+			Email:     getUserResult.Email,
+			Country:   getUserResult.Country,
+			Roles:     []string{"user", "moderator"},
+			CreatedAt: timestamppb.New(getUserResult.CreatedAt),
+			UpdatedAt: timestamppb.New(getUserResult.UpdatedAt),
+		}, out,
+	)
+}
+
+func testGrantRoleWithoutPrincipal(t *testing.T) {
+	mockGrantRole, _, _, server := newRolesServer()
+
+	out, err := server.GrantRole(context.Background(), &apiV1.GrantRoleRequest{Id: "1234", Role: "moderator"})
+
+	mockGrantRole.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, "Authentication required"))
+	assert.Nil(t, out)
+}
+
+func testGrantRoleWithMissingParams(t *testing.T) {
+	mockGrantRole, _, _, server := newRolesServer()
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	out, err := server.GrantRole(ctx, &apiV1.GrantRoleRequest{Role: "moderator"})
+
+	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Id and role are required"))
+	assert.Nil(t, out)
+
+	out, err = server.GrantRole(ctx, &apiV1.GrantRoleRequest{Id: "1234"})
+
+	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Id and role are required"))
+	assert.Nil(t, out)
+
+	mockGrantRole.AssertNumberOfCalls(t, "Handle", 0)
+}
+
+func testGrantRoleWithNotFoundError(t *testing.T) {
+	mockGrantRole, _, mockGetUserById, server := newRolesServer()
+
+	id := "1234"
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	notFoundErr := user.NotFoundError{Id: id}
+	cmd := command.GrantRole{ActorId: adminId, UserId: id, Role: "moderator"}
+	mockGrantRole.On("Handle", ctx, cmd).Return(&notFoundErr)
+
+	out, err := server.GrantRole(ctx, &apiV1.GrantRoleRequest{Id: id, Role: "moderator"})
+
+	mockGrantRole.AssertExpectations(t)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "User not found"))
+	assert.Nil(t, out)
+}
+
+func testGrantRoleWithForbiddenError(t *testing.T) {
+	mockGrantRole, _, _, server := newRolesServer()
+
+	id := "1234"
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	forbiddenErr := user.ForbiddenError{ActorId: adminId, UserId: id}
+	cmd := command.GrantRole{ActorId: adminId, UserId: id, Role: "admin"}
+	mockGrantRole.On("Handle", ctx, cmd).Return(&forbiddenErr)
+
+	out, err := server.GrantRole(ctx, &apiV1.GrantRoleRequest{Id: id, Role: "admin"})
+
+	mockGrantRole.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Not allowed to modify the user"))
+	assert.Nil(t, out)
+}
+
+func testGrantRoleWithInvalidRoleError(t *testing.T) {
+	mockGrantRole, _, _, server := newRolesServer()
+
+	id := "1234"
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	invalidErr := errors2.InvalidField{Domain: "User", Field: "role", Value: "superuser"}
+	cmd := command.GrantRole{ActorId: adminId, UserId: id, Role: "superuser"}
+	mockGrantRole.On("Handle", ctx, cmd).Return(&invalidErr)
+
+	out, err := server.GrantRole(ctx, &apiV1.GrantRoleRequest{Id: id, Role: "superuser"})
+
+	mockGrantRole.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, errors2.MapInvalidFieldsToStatus(&invalidErr))
+	assert.Nil(t, out)
+}
+
+func testGrantRoleWithUnknownError(t *testing.T) {
+	mockGrantRole, _, _, server := newRolesServer()
+
+	id := "1234"
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	cmd := command.GrantRole{ActorId: adminId, UserId: id, Role: "moderator"}
+	mockGrantRole.On("Handle", ctx, cmd).Return(errors.New("unknown error"))
+
+	out, err := server.GrantRole(ctx, &apiV1.GrantRoleRequest{Id: id, Role: "moderator"})
+
+	mockGrantRole.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while granting role"))
+	assert.Nil(t, out)
+}
+
+func testGrantRoleWithGetError(t *testing.T) {
+	mockGrantRole, _, mockGetUserById, server := newRolesServer()
+
+	id := "1234"
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	cmd := command.GrantRole{ActorId: adminId, UserId: id, Role: "moderator"}
+	mockGrantRole.On("Handle", ctx, cmd).Return(nil)
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: id}).Return(nil, errors.New("unknown error"))
+
+	out, err := server.GrantRole(ctx, &apiV1.GrantRoleRequest{Id: id, Role: "moderator"})
+
+	mockGrantRole.AssertExpectations(t)
+	mockGetUserById.AssertExpectations(t)
+
+	assert.ErrorIs(
+		t, err, status.Error(codes.Unavailable, "The roles were changed but the user couldn't be retrieved"),
+	)
+	assert.Nil(t, out)
+}
+
+func testRevokeRole(t *testing.T) {
+	_, mockRevokeRole, mockGetUserById, server := newRolesServer()
+
+	id := "1234"
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	getUserResult := roleUser(id, "user")
+
+	cmd := command.RevokeRole{ActorId: adminId, UserId: id, Role: "moderator"}
+	mockRevokeRole.On("Handle", ctx, cmd).Return(nil)
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: id}).Return(getUserResult, nil)
+
+	out, err := server.RevokeRole(ctx, &apiV1.RevokeRoleRequest{Id: id, Role: "moderator"})
+
+	mockRevokeRole.AssertExpectations(t)
+	mockGetUserById.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, out.GetId())
+	assert.Equal(t, []string{"user"}, out.GetRoles())
+}
+
+func testRevokeRoleWithMissingParams(t *testing.T) {
+	_, mockRevokeRole, _, server := newRolesServer()
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	out, err := server.RevokeRole(ctx, &apiV1.RevokeRoleRequest{Id: "1234"})
+
+	mockRevokeRole.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Id and role are required"))
+	assert.Nil(t, out)
+}
+
+func testRevokeRoleWithForbiddenError(t *testing.T) {
+	_, mockRevokeRole, _, server := newRolesServer()
+
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	forbiddenErr := user.ForbiddenError{ActorId: adminId, UserId: adminId}
+	cmd := command.RevokeRole{ActorId: adminId, UserId: adminId, Role: "admin"}
+	mockRevokeRole.On("Handle", ctx, cmd).Return(&forbiddenErr)
+
+	out, err := server.RevokeRole(ctx, &apiV1.RevokeRoleRequest{Id: adminId, Role: "admin"})
+
+	mockRevokeRole.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Not allowed to modify the user"))
+	assert.Nil(t, out)
+}
+
+func testRevokeRoleWithUnknownError(t *testing.T) {
+	_, mockRevokeRole, _, server := newRolesServer()
+
+	id := "1234"
+	ctx := contextWithPrincipal(adminId, "user", "admin")
+
+	cmd := command.RevokeRole{ActorId: adminId, UserId: id, Role: "moderator"}
+	mockRevokeRole.On("Handle", ctx, cmd).Return(errors.New("unknown error"))
+
+	out, err := server.RevokeRole(ctx, &apiV1.RevokeRoleRequest{Id: id, Role: "moderator"})
+
+	mockRevokeRole.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while revoking role"))
+	assert.Nil(t, out)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
 	errors2 "github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
@@ -14,9 +15,13 @@ import (
 	handler_mocks2 "github.com/elizabeth-dev/ACME_Test/test/mocks/handler_mocks"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"testing"
 	"time"
@@ -32,31 +37,67 @@ func TestGrpc(t *testing.T) {
 		"create user": {
 			"call create user":                                    testCreateUser,
 			"call create user with invalid field error":           testCreateUserWithInvalidFieldError,
+			"call create user with already exists error":          testCreateUserWithAlreadyExistsError,
 			"call create user with multiple invalid fields error": testCreateUserWithMultipleInvalidFieldsError,
 			"call create user with create error":                  testCreateUserWithCreateError,
 			"call create user with get error":                     testCreateUserWithGetError,
 		},
+		"get user": {
+			"call get user":                testGetUser,
+			"call get user with no id":     testGetUserWithoutId,
+			"call get user with not found": testGetUserWithNotFoundError,
+			"call get user with get error": testGetUserWithGetError,
+			"call get user with read mask": testGetUserWithReadMask,
+		},
+		"batch get users": {
+			"call batch get users":                    testBatchGetUsers,
+			"call batch get users with invalid field": testBatchGetUsersWithInvalidFieldError,
+			"call batch get users with get error":     testBatchGetUsersWithGetError,
+			"call batch get users with read mask":     testBatchGetUsersWithReadMask,
+		},
 		"get users": {
 			"call get users":                    testGetUsers,
 			"call get users with no parameters": testGetUsersWithNoParams,
 			"call get users with get error":     testGetUsersWithGetError,
 			"call get users with send error":    testGetUsersWithSendError,
+			"call get users with stream error":  testGetUsersWithStreamError,
+			"call get users with cancellation":  testGetUsersWithCancellation,
+			"call get users with read mask":     testGetUsersWithReadMask,
+		},
+		"count users": {
+			"call count users":                    testCountUsers,
+			"call count users with invalid field": testCountUsersWithInvalidFieldError,
+			"call count users with count error":   testCountUsersWithCountError,
 		},
 		"update user": {
 			"call update user":                                   testUpdateUser,
 			"call update user with no id":                        testUpdateUserWithoutId,
+			"call update user without principal":                 testUpdateUserWithoutPrincipal,
+			"call update user with forbidden error":              testUpdateUserWithForbiddenError,
+			"call update user with already exists error":         testUpdateUserWithAlreadyExistsError,
 			"call update user with not found error":              testUpdateUserWithNotFoundError,
 			"call update user with invalid field error":          testUpdateUserWithInvalidFieldError,
 			"call update user with multiple invalidFields error": testUpdateUserWithMultipleInvalidFieldsError,
 			"call update user with update error":                 testUpdateUserWithUpdateError,
 			"call update user with get error":                    testUpdateUserWithGetError,
+			"call update user with update mask":                  testUpdateUserWithUpdateMask,
+			"call update user with version conflict":             testUpdateUserWithVersionConflict,
 		},
 		"remove user": {
 			"call remove user":                      testRemoveUser,
 			"call remove user with no id":           testRemoveUserWithoutId,
+			"call remove user without principal":    testRemoveUserWithoutPrincipal,
+			"call remove user with forbidden error": testRemoveUserWithForbiddenError,
 			"call remove user with not found error": testRemoveUserWithNotFoundError,
+			"call remove user with wrapped error":   testRemoveUserWithWrappedError,
 			"call remove user with remove error":    testRemoveUserWithRemoveError,
 		},
+		"authenticate": {
+			"call authenticate":                          testAuthenticate,
+			"call authenticate with missing credentials": testAuthenticateWithMissingCredentials,
+			"call authenticate with invalid credentials": testAuthenticateWithInvalidCredentials,
+			"call authenticate with unknown error":       testAuthenticateWithUnknownError,
+		},
 	} {
 		testGroup := testGroup
 		t.Run(
@@ -79,6 +120,10 @@ func TestGrpc(t *testing.T) {
 
 }
 
+func contextWithPrincipal(subject string, roles ...string) context.Context {
+	return auth.ContextWithPrincipal(context.Background(), &auth.Principal{Subject: subject, Roles: roles})
+}
+
 func testNewGrpcServer(t *testing.T) {
 	application := app.Application{}
 
@@ -123,7 +168,6 @@ func testCreateUser(t *testing.T) {
 		FirstName: "John",
 		LastName:  "Doe",
 		Nickname:  "john-123",
-		Password:  "password",
 		Email:     "me@john.com",
 		Country:   "US",
 		CreatedAt: now,
@@ -131,7 +175,7 @@ func testCreateUser(t *testing.T) {
 	}
 
 	mockCreateUser.On("Handle", ctx, createUserCmd).Return(id, nil)
-	mockGetUserById.On("Handle", ctx, id).Return(&getUserResult, nil)
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: id}).Return(&getUserResult, nil)
 
 	out, err := server.CreateUser(ctx, &request)
 
@@ -147,7 +191,6 @@ func testCreateUser(t *testing.T) {
 			FirstName: getUserResult.FirstName,
 			LastName:  getUserResult.LastName,
 			Nickname:  getUserResult.Nickname,
-			Password:  getUserResult.Password,
 			Email:     getUserResult.Email,
 			Country:   getUserResult.Country,
 			CreatedAt: timestamppb.New(getUserResult.CreatedAt),
@@ -197,7 +240,48 @@ func testCreateUserWithInvalidFieldError(t *testing.T) {
 	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
 	mockCreateUser.AssertExpectations(t)
 
-	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, invalidErr.Error()))
+	assert.ErrorIs(t, err, errors2.MapInvalidFieldsToStatus(&invalidErr))
+	assert.Nil(t, out)
+}
+
+func testCreateUserWithAlreadyExistsError(t *testing.T) {
+	mockCreateUser := new(handler_mocks2.ICreateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{CreateUser: mockCreateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.CreateUserRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Nickname:  "john-123",
+		Password:  "password",
+		Email:     "me@john.com",
+		Country:   "US",
+	}
+
+	createUserCmd := command.CreateUser{
+		FirstName: "John",
+		LastName:  "Doe",
+		Nickname:  "john-123",
+		Password:  "password",
+		Email:     "me@john.com",
+		Country:   "US",
+	}
+
+	existsErr := user.AlreadyExistsError{Field: "nickname", Value: "john-123"}
+	mockCreateUser.On("Handle", ctx, createUserCmd).Return("", &existsErr)
+
+	out, err := server.CreateUser(ctx, &request)
+
+	mockCreateUser.AssertNumberOfCalls(t, "Handle", 1)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+	mockCreateUser.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.AlreadyExists, "User with nickname john-123 already exists"))
 	assert.Nil(t, out)
 }
 
@@ -248,7 +332,7 @@ func testCreateUserWithMultipleInvalidFieldsError(t *testing.T) {
 	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
 	mockCreateUser.AssertExpectations(t)
 
-	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, invalidErr.Error()))
+	assert.ErrorIs(t, err, errors2.MapInvalidFieldsToStatus(&invalidErr))
 	assert.Nil(t, out)
 }
 
@@ -322,7 +406,7 @@ func testCreateUserWithGetError(t *testing.T) {
 	}
 
 	mockCreateUser.On("Handle", ctx, createUserCmd).Return(id, nil)
-	mockGetUserById.On("Handle", ctx, id).Return(nil, errors.New("unknown error"))
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: id}).Return(nil, errors.New("unknown error"))
 
 	out, err := server.CreateUser(ctx, &request)
 
@@ -363,6 +447,18 @@ func testGetUsers(t *testing.T) {
 			Limit:  0,
 			Offset: 0,
 		},
+		Filter: &apiV1.FilterExpression{
+			Operator: apiV1.FilterExpression_OR,
+			Filters: []*apiV1.Filter{
+				{
+					Field:    "nickname",
+					Operator: apiV1.Filter_EQUALS,
+					Value:    &apiV1.Filter_StringValue{StringValue: "john"},
+				},
+			},
+		},
+		PageToken:         "token",
+		IncludeTotalCount: true,
 	}
 
 	getUsersQuery := query.GetUsers{
@@ -373,13 +469,25 @@ func testGetUsers(t *testing.T) {
 				Value:    "",
 			},
 		},
+		Filter: &query_utils.FilterExpression{
+			Operator: operators.OR,
+			Filters: []query_utils.Filter{
+				{
+					Field:    "nickname",
+					Operator: operators.EQUALS,
+					Value:    "john",
+				},
+			},
+		},
 		Sort: []query_utils.Sort{
 			{
 				Field:     "id",
 				Direction: operators.ASC,
 			},
 		},
-		Pagination: query_utils.Pagination{},
+		Pagination:        query_utils.Pagination{},
+		PageToken:         "token",
+		IncludeTotalCount: true,
 	}
 
 	now := time.Now()
@@ -389,7 +497,6 @@ func testGetUsers(t *testing.T) {
 			FirstName: "John",
 			LastName:  "Doe",
 			Nickname:  "john-123",
-			Password:  "password",
 			Email:     "me@john.com",
 			Country:   "US",
 			CreatedAt: now,
@@ -398,20 +505,25 @@ func testGetUsers(t *testing.T) {
 	}
 
 	mockGetUsersSrv.On("Context").Return(ctx)
+	mockGetUsersSrv.On(
+		"SetTrailer", metadata.Pairs("has-more", "true", "next-page-token", "next", "total-count", "3"),
+	).Return()
 	mockGetUsersSrv.On(
 		"Send", &apiV1.User{
 			Id:        id,
 			FirstName: "John",
 			LastName:  "Doe",
 			Nickname:  "john-123",
-			Password:  "password",
 			Email:     "me@john.com",
 			Country:   "US",
 			CreatedAt: timestamppb.New(now),
 			UpdatedAt: timestamppb.New(now),
 		},
 	).Return(nil)
-	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(getUsersResult, nil)
+	mockUserStream := newUserStreamMock(
+		ctx, getUsersResult, query.UserPage{NextPageToken: "next", HasMore: true, TotalCount: 3}, nil,
+	)
+	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(mockUserStream, nil)
 
 	err := server.GetUsers(&request, mockGetUsersSrv)
 
@@ -420,6 +532,7 @@ func testGetUsers(t *testing.T) {
 	mockGetUsersHandler.AssertNumberOfCalls(t, "Handle", 1)
 	mockGetUsersHandler.AssertExpectations(t)
 	mockGetUsersSrv.AssertExpectations(t)
+	mockUserStream.AssertExpectations(t)
 
 	assert.NoError(t, err)
 }
@@ -456,7 +569,6 @@ func testGetUsersWithNoParams(t *testing.T) {
 			FirstName: "John",
 			LastName:  "Doe",
 			Nickname:  "john-123",
-			Password:  "password",
 			Email:     "me@john.com",
 			Country:   "US",
 			CreatedAt: now,
@@ -465,20 +577,21 @@ func testGetUsersWithNoParams(t *testing.T) {
 	}
 
 	mockGetUsersSrv.On("Context").Return(ctx)
+	mockGetUsersSrv.On("SetTrailer", metadata.Pairs("has-more", "false")).Return()
 	mockGetUsersSrv.On(
 		"Send", &apiV1.User{
 			Id:        id,
 			FirstName: "John",
 			LastName:  "Doe",
 			Nickname:  "john-123",
-			Password:  "password",
 			Email:     "me@john.com",
 			Country:   "US",
 			CreatedAt: timestamppb.New(now),
 			UpdatedAt: timestamppb.New(now),
 		},
 	).Return(nil)
-	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(getUsersResult, nil)
+	mockUserStream := newUserStreamMock(ctx, getUsersResult, query.UserPage{}, nil)
+	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(mockUserStream, nil)
 
 	err := server.GetUsers(&request, mockGetUsersSrv)
 
@@ -487,6 +600,7 @@ func testGetUsersWithNoParams(t *testing.T) {
 	mockGetUsersHandler.AssertNumberOfCalls(t, "Handle", 1)
 	mockGetUsersHandler.AssertExpectations(t)
 	mockGetUsersSrv.AssertExpectations(t)
+	mockUserStream.AssertExpectations(t)
 
 	assert.NoError(t, err)
 }
@@ -604,7 +718,6 @@ func testGetUsersWithSendError(t *testing.T) {
 			FirstName: "John",
 			LastName:  "Doe",
 			Nickname:  "john-123",
-			Password:  "password",
 			Email:     "me@john.com",
 			Country:   "US",
 			CreatedAt: now,
@@ -619,84 +732,164 @@ func testGetUsersWithSendError(t *testing.T) {
 			FirstName: "John",
 			LastName:  "Doe",
 			Nickname:  "john-123",
-			Password:  "password",
 			Email:     "me@john.com",
 			Country:   "US",
 			CreatedAt: timestamppb.New(now),
 			UpdatedAt: timestamppb.New(now),
 		},
 	).Return(errors.New("unknown error"))
-	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(getUsersResult, nil)
+	mockUserStream := new(handler_mocks2.UserStream)
+	mockUserStream.On("Next", ctx).Return(true).Once()
+	mockUserStream.On("User").Return(getUsersResult[0])
+	mockUserStream.On("Close", context.Background()).Return(nil).Once()
+	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(mockUserStream, nil)
 
 	err := server.GetUsers(&request, mockGetUsersSrv)
 
 	mockGetUsersSrv.AssertNumberOfCalls(t, "Context", 1)
 	mockGetUsersSrv.AssertNumberOfCalls(t, "Send", 1)
+	mockGetUsersSrv.AssertNotCalled(t, "SetTrailer", mock.Anything)
 	mockGetUsersHandler.AssertNumberOfCalls(t, "Handle", 1)
 	mockGetUsersHandler.AssertExpectations(t)
 	mockGetUsersSrv.AssertExpectations(t)
+	mockUserStream.AssertExpectations(t)
 
 	assert.ErrorIs(t, err, status.Error(codes.Internal, "Error sending users"))
 }
 
-func testUpdateUser(t *testing.T) {
-	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
-	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+/*
+newUserStreamMock mocks a stream yielding the given users, then failing with the given error or ending with the page.
+*/
+func newUserStreamMock(
+	ctx context.Context, users []*query.User, page query.UserPage, err error,
+) *handler_mocks2.UserStream {
+	stream := new(handler_mocks2.UserStream)
+
+	for _, u := range users {
+		stream.On("Next", ctx).Return(true).Once()
+		stream.On("User").Return(u).Once()
+	}
+
+	stream.On("Next", ctx).Return(false).Once()
+	stream.On("Err").Return(err)
+	stream.On("Close", context.Background()).Return(nil).Once()
+
+	if err == nil {
+		stream.On("Page").Return(page)
+	}
+
+	return stream
+}
+
+func testGetUsersWithStreamError(t *testing.T) {
+	mockGetUsersHandler := new(handler_mocks2.IGetUsersHandler)
+	mockGetUsersSrv := new(mocks.UserService_GetUsersServer)
 	application := app.Application{
-		Commands: app.Commands{UpdateUser: mockUpdateUser},
-		Queries:  app.Queries{GetUserById: mockGetUserById},
+		Queries: app.Queries{GetUsers: mockGetUsersHandler},
 	}
 	server := GrpcServer{app: application}
 
-	id := "1234"
 	ctx := context.Background()
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
-	request := apiV1.UpdateUserRequest{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+	request := apiV1.GetUsersRequest{}
+	getUsersQuery := query.GetUsers{}
+
+	mockGetUsersSrv.On("Context").Return(ctx)
+	mockUserStream := newUserStreamMock(ctx, nil, query.UserPage{}, errors.New("unknown error"))
+	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(mockUserStream, nil)
+
+	err := server.GetUsers(&request, mockGetUsersSrv)
+
+	mockGetUsersSrv.AssertNotCalled(t, "SetTrailer", mock.Anything)
+	mockGetUsersHandler.AssertExpectations(t)
+	mockGetUsersSrv.AssertExpectations(t)
+	mockUserStream.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Error retrieving users"))
+}
+
+func testGetUsersWithCancellation(t *testing.T) {
+	mockGetUsersHandler := new(handler_mocks2.IGetUsersHandler)
+	mockGetUsersSrv := new(mocks.UserService_GetUsersServer)
+	application := app.Application{
+		Queries: app.Queries{GetUsers: mockGetUsersHandler},
 	}
+	server := GrpcServer{app: application}
 
-	updateUserCmd := command.UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	request := apiV1.GetUsersRequest{}
+	getUsersQuery := query.GetUsers{}
+
+	mockGetUsersSrv.On("Context").Return(ctx)
+	mockUserStream := newUserStreamMock(ctx, nil, query.UserPage{}, ctx.Err())
+	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(mockUserStream, nil)
+
+	err := server.GetUsers(&request, mockGetUsersSrv)
+
+	mockGetUsersSrv.AssertNotCalled(t, "SetTrailer", mock.Anything)
+	mockGetUsersHandler.AssertExpectations(t)
+	mockGetUsersSrv.AssertExpectations(t)
+	mockUserStream.AssertExpectations(t)
+
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func testGetUsersWithReadMask(t *testing.T) {
+	mockGetUsersHandler := new(handler_mocks2.IGetUsersHandler)
+	mockGetUsersSrv := new(mocks.UserService_GetUsersServer)
+	application := app.Application{
+		Queries: app.Queries{GetUsers: mockGetUsersHandler},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.GetUsersRequest{ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "nickname"}}}
+	getUsersQuery := query.GetUsers{ReadMask: []string{"id", "nickname"}}
+
+	getUsersResult := []*query.User{{Id: "1234", Nickname: "john-123", Country: "US"}}
+
+	mockGetUsersSrv.On("Context").Return(ctx)
+	mockGetUsersSrv.On("SetTrailer", metadata.Pairs("has-more", "false")).Return()
+	mockGetUsersSrv.On("Send", &apiV1.User{Id: "1234", Nickname: "john-123"}).Return(nil)
+	mockUserStream := newUserStreamMock(ctx, getUsersResult, query.UserPage{}, nil)
+	mockGetUsersHandler.On("Handle", ctx, getUsersQuery).Return(mockUserStream, nil)
+
+	err := server.GetUsers(&request, mockGetUsersSrv)
+
+	mockGetUsersHandler.AssertExpectations(t)
+	mockGetUsersSrv.AssertExpectations(t)
+	mockUserStream.AssertExpectations(t)
+
+	assert.NoError(t, err)
+}
+
+func testGetUser(t *testing.T) {
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Queries: app.Queries{GetUserById: mockGetUserById},
 	}
+	server := GrpcServer{app: application}
 
+	ctx := context.Background()
 	now := time.Now()
 	getUserResult := query.User{
-		Id:        id,
-		FirstName: "updated",
-		LastName:  "updated",
-		Nickname:  "updated",
-		Password:  "updated",
-		Email:     "updated",
-		Country:   "updates",
+		Id:        "1234",
+		FirstName: "John",
+		LastName:  "Doe",
+		Nickname:  "john",
+		Email:     "me@john.com",
+		Country:   "US",
+		Roles:     []string{"user"},
 		CreatedAt: now,
-		UpdatedAt: now.Add(time.Hour),
+		UpdatedAt: now,
+		Version:   1,
 	}
 
-	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(nil)
-	mockGetUserById.On("Handle", ctx, id).Return(&getUserResult, nil)
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: getUserResult.Id}).Return(&getUserResult, nil)
 
-	out, err := server.UpdateUser(ctx, &request)
+	out, err := server.GetUser(ctx, &apiV1.GetUserRequest{Id: getUserResult.Id})
 
-	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 1)
-	mockGetUserById.AssertNumberOfCalls(t, "Handle", 1)
-	mockUpdateUser.AssertExpectations(t)
 	mockGetUserById.AssertExpectations(t)
 
 	assert.NoError(t, err)
@@ -706,144 +899,587 @@ func testUpdateUser(t *testing.T) {
 			FirstName: getUserResult.FirstName,
 			LastName:  getUserResult.LastName,
 			Nickname:  getUserResult.Nickname,
-			Password:  getUserResult.Password,
 			Email:     getUserResult.Email,
 			Country:   getUserResult.Country,
+			Roles:     getUserResult.Roles,
 			CreatedAt: timestamppb.New(getUserResult.CreatedAt),
 			UpdatedAt: timestamppb.New(getUserResult.UpdatedAt),
+			Version:   getUserResult.Version,
 		}, out,
 	)
 }
 
-func testUpdateUserWithoutId(t *testing.T) {
-	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+func testGetUserWithReadMask(t *testing.T) {
 	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
 	application := app.Application{
-		Commands: app.Commands{UpdateUser: mockUpdateUser},
-		Queries:  app.Queries{GetUserById: mockGetUserById},
+		Queries: app.Queries{GetUserById: mockGetUserById},
 	}
 	server := GrpcServer{app: application}
 
-	id := ""
 	ctx := context.Background()
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
-	request := apiV1.UpdateUserRequest{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+	getUserResult := query.User{Id: "1234", Nickname: "john", Country: "US"}
+
+	mockGetUserById.On(
+		"Handle", ctx, query.GetUserById{Id: getUserResult.Id, ReadMask: []string{"nickname"}},
+	).Return(&getUserResult, nil)
+
+	out, err := server.GetUser(
+		ctx, &apiV1.GetUserRequest{Id: getUserResult.Id, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"nickname"}}},
+	)
+
+	mockGetUserById.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&apiV1.User{Nickname: getUserResult.Nickname}, out))
+}
+
+func testGetUserWithoutId(t *testing.T) {
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Queries: app.Queries{GetUserById: mockGetUserById},
 	}
+	server := GrpcServer{app: application}
 
-	out, err := server.UpdateUser(ctx, &request)
+	out, err := server.GetUser(context.Background(), &apiV1.GetUserRequest{})
 
-	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 0)
 	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
 
 	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Id is required"))
 	assert.Nil(t, out)
 }
 
-func testUpdateUserWithNotFoundError(t *testing.T) {
-	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+func testGetUserWithNotFoundError(t *testing.T) {
 	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
 	application := app.Application{
-		Commands: app.Commands{UpdateUser: mockUpdateUser},
-		Queries:  app.Queries{GetUserById: mockGetUserById},
+		Queries: app.Queries{GetUserById: mockGetUserById},
 	}
 	server := GrpcServer{app: application}
 
-	id := "1234"
 	ctx := context.Background()
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
-	request := apiV1.UpdateUserRequest{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
-	}
-
-	updateUserCmd := command.UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
-	}
+	notFoundErr := user.NotFoundError{Id: "1234"}
 
-	notFoundErr := user.NotFoundError{Id: id}
-	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(&notFoundErr)
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: "1234"}).Return(nil, &notFoundErr)
 
-	out, err := server.UpdateUser(ctx, &request)
+	out, err := server.GetUser(ctx, &apiV1.GetUserRequest{Id: "1234"})
 
-	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 1)
-	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
-	mockUpdateUser.AssertExpectations(t)
+	mockGetUserById.AssertExpectations(t)
 
-	assert.ErrorIs(t, err, status.Error(codes.NotFound, notFoundErr.Error()))
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "User not found"))
 	assert.Nil(t, out)
 }
 
-func testUpdateUserWithInvalidFieldError(t *testing.T) {
-	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+func testGetUserWithGetError(t *testing.T) {
 	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
 	application := app.Application{
-		Commands: app.Commands{UpdateUser: mockUpdateUser},
-		Queries:  app.Queries{GetUserById: mockGetUserById},
+		Queries: app.Queries{GetUserById: mockGetUserById},
 	}
 	server := GrpcServer{app: application}
 
-	id := "1234"
 	ctx := context.Background()
-	firstName := "updated"
-	lastName := "updated"
-	nickname := "updated"
-	password := "updated"
-	email := "updated"
-	country := "updated"
-	request := apiV1.UpdateUserRequest{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
-	}
 
-	updateUserCmd := command.UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
-	}
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: "1234"}).Return(nil, errors.New("unknown error"))
+
+	out, err := server.GetUser(ctx, &apiV1.GetUserRequest{Id: "1234"})
+
+	mockGetUserById.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while getting user"))
+	assert.Nil(t, out)
+}
+
+func testBatchGetUsers(t *testing.T) {
+	mockGetUsersByIds := new(handler_mocks2.IGetUsersByIdsHandler)
+	application := app.Application{
+		Queries: app.Queries{GetUsersByIds: mockGetUsersByIds},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	now := time.Now()
+	foundUser := query.User{
+		Id:        "1234",
+		FirstName: "John",
+		LastName:  "Doe",
+		Nickname:  "john",
+		Email:     "me@john.com",
+		Country:   "US",
+		Roles:     []string{"user"},
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+	ids := []string{"unknown", foundUser.Id}
+
+	mockGetUsersByIds.On("Handle", ctx, query.GetUsersByIds{Ids: ids}).Return(
+		[]*query.UserResult{{Id: "unknown"}, {Id: foundUser.Id, User: &foundUser}}, nil,
+	)
+
+	out, err := server.BatchGetUsers(ctx, &apiV1.BatchGetUsersRequest{Ids: ids})
+
+	mockGetUsersByIds.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &apiV1.BatchGetUsersResponse{
+			Results: []*apiV1.BatchGetUsersResponse_Result{
+				{Id: "unknown", NotFound: true},
+				{
+					Id: foundUser.Id,
+					User: &apiV1.User{
+						Id:        foundUser.Id,
+						FirstName: foundUser.FirstName,
+						LastName:  foundUser.LastName,
+						Nickname:  foundUser.Nickname,
+						Email:     foundUser.Email,
+						Country:   foundUser.Country,
+						Roles:     foundUser.Roles,
+						CreatedAt: timestamppb.New(foundUser.CreatedAt),
+						UpdatedAt: timestamppb.New(foundUser.UpdatedAt),
+						Version:   foundUser.Version,
+					},
+				},
+			},
+		}, out,
+	)
+}
+
+func testBatchGetUsersWithReadMask(t *testing.T) {
+	mockGetUsersByIds := new(handler_mocks2.IGetUsersByIdsHandler)
+	application := app.Application{
+		Queries: app.Queries{GetUsersByIds: mockGetUsersByIds},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	foundUser := query.User{Id: "1234", Nickname: "john", Country: "US"}
+	batchQuery := query.GetUsersByIds{Ids: []string{foundUser.Id}, ReadMask: []string{"nickname"}}
+
+	mockGetUsersByIds.On("Handle", ctx, batchQuery).Return(
+		[]*query.UserResult{{Id: foundUser.Id, User: &foundUser}}, nil,
+	)
+
+	out, err := server.BatchGetUsers(
+		ctx, &apiV1.BatchGetUsersRequest{
+			Ids: batchQuery.Ids, ReadMask: &fieldmaskpb.FieldMask{Paths: batchQuery.ReadMask},
+		},
+	)
+
+	mockGetUsersByIds.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.True(
+		t, proto.Equal(
+			&apiV1.BatchGetUsersResponse{
+				Results: []*apiV1.BatchGetUsersResponse_Result{
+					{Id: foundUser.Id, User: &apiV1.User{Nickname: foundUser.Nickname}},
+				},
+			}, out,
+		),
+	)
+}
+
+func testBatchGetUsersWithInvalidFieldError(t *testing.T) {
+	mockGetUsersByIds := new(handler_mocks2.IGetUsersByIdsHandler)
+	application := app.Application{
+		Queries: app.Queries{GetUsersByIds: mockGetUsersByIds},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+
+	invalidErr := errors2.InvalidField{Domain: "User", Field: "ids[0]", Reason: errors2.ReasonRequired}
+	mockGetUsersByIds.On("Handle", ctx, query.GetUsersByIds{Ids: []string{""}}).Return(nil, &invalidErr)
+
+	out, err := server.BatchGetUsers(ctx, &apiV1.BatchGetUsersRequest{Ids: []string{""}})
+
+	mockGetUsersByIds.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, errors2.MapInvalidFieldsToStatus(&invalidErr))
+	assert.Nil(t, out)
+}
+
+func testBatchGetUsersWithGetError(t *testing.T) {
+	mockGetUsersByIds := new(handler_mocks2.IGetUsersByIdsHandler)
+	application := app.Application{
+		Queries: app.Queries{GetUsersByIds: mockGetUsersByIds},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+
+	mockGetUsersByIds.On("Handle", ctx, query.GetUsersByIds{Ids: []string{"1234"}}).Return(
+		nil, errors.New("unknown error"),
+	)
+
+	out, err := server.BatchGetUsers(ctx, &apiV1.BatchGetUsersRequest{Ids: []string{"1234"}})
+
+	mockGetUsersByIds.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while getting users"))
+	assert.Nil(t, out)
+}
+
+func testCountUsers(t *testing.T) {
+	mockCountUsersHandler := new(handler_mocks2.ICountUsersHandler)
+	application := app.Application{
+		Queries: app.Queries{CountUsers: mockCountUsersHandler},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.CountUsersRequest{
+		Filters: []*apiV1.Filter{
+			{
+				Field:    "country",
+				Operator: apiV1.Filter_EQUALS,
+				Value:    &apiV1.Filter_StringValue{StringValue: "ES"},
+			},
+		},
+		Filter: &apiV1.FilterExpression{
+			Operator: apiV1.FilterExpression_NOT,
+			Filters: []*apiV1.Filter{
+				{
+					Field:    "nickname",
+					Operator: apiV1.Filter_EQUALS,
+					Value:    &apiV1.Filter_StringValue{StringValue: "john"},
+				},
+			},
+		},
+	}
+
+	countUsersQuery := query.CountUsers{
+		Filters: []query_utils.Filter{
+			{
+				Field:    "country",
+				Operator: operators.EQUALS,
+				Value:    "ES",
+			},
+		},
+		Filter: &query_utils.FilterExpression{
+			Operator: operators.NOT,
+			Filters: []query_utils.Filter{
+				{
+					Field:    "nickname",
+					Operator: operators.EQUALS,
+					Value:    "john",
+				},
+			},
+		},
+	}
+
+	mockCountUsersHandler.On("Handle", ctx, countUsersQuery).Return(int64(2), nil)
+
+	out, err := server.CountUsers(ctx, &request)
+
+	mockCountUsersHandler.AssertNumberOfCalls(t, "Handle", 1)
+	mockCountUsersHandler.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), out.GetTotalCount())
+}
+
+func testCountUsersWithInvalidFieldError(t *testing.T) {
+	mockCountUsersHandler := new(handler_mocks2.ICountUsersHandler)
+	application := app.Application{
+		Queries: app.Queries{CountUsers: mockCountUsersHandler},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
 
 	invalidErr := errors2.InvalidField{
-		Domain: "",
-		Field:  "",
-		Value:  nil,
+		Domain: "User",
+		Field:  "filters[0].field",
+		Value:  "password",
+		Reason: errors2.ReasonUnknownValue,
+	}
+	mockCountUsersHandler.On("Handle", ctx, query.CountUsers{}).Return(int64(0), &invalidErr)
+
+	out, err := server.CountUsers(ctx, &apiV1.CountUsersRequest{})
+
+	mockCountUsersHandler.AssertNumberOfCalls(t, "Handle", 1)
+	mockCountUsersHandler.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, errors2.MapInvalidFieldsToStatus(&invalidErr))
+	assert.Nil(t, out)
+}
+
+func testCountUsersWithCountError(t *testing.T) {
+	mockCountUsersHandler := new(handler_mocks2.ICountUsersHandler)
+	application := app.Application{
+		Queries: app.Queries{CountUsers: mockCountUsersHandler},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+
+	mockCountUsersHandler.On("Handle", ctx, query.CountUsers{}).Return(int64(0), errors.New("unknown error"))
+
+	out, err := server.CountUsers(ctx, &apiV1.CountUsersRequest{})
+
+	mockCountUsersHandler.AssertNumberOfCalls(t, "Handle", 1)
+	mockCountUsersHandler.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Error counting users"))
+	assert.Nil(t, out)
+}
+
+func testUpdateUser(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	ctx := contextWithPrincipal(id)
+	firstName := "updated"
+	lastName := "updated"
+	nickname := "updated"
+	password := "updated"
+	email := "updated"
+	country := "updated"
+	request := apiV1.UpdateUserRequest{
+		Id:        id,
+		FirstName: &firstName,
+		LastName:  &lastName,
+		Nickname:  &nickname,
+		Password:  &password,
+		Email:     &email,
+		Country:   &country,
+	}
+
+	updateUserCmd := command.UpdateUser{
+		ActorId: id,
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": firstName,
+			"last_name":  lastName,
+			"nickname":   nickname,
+			"password":   password,
+			"email":      email,
+			"country":    country,
+		},
+	}
+
+	now := time.Now()
+	getUserResult := query.User{
+		Id:        id,
+		FirstName: "updated",
+		LastName:  "updated",
+		Nickname:  "updated",
+		Email:     "updated",
+		Country:   "updates",
+		Roles:     []string{"user"},
+		CreatedAt: now,
+		UpdatedAt: now.Add(time.Hour),
+		Version:   2,
+	}
+
+	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(nil)
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: id}).Return(&getUserResult, nil)
+
+	out, err := server.UpdateUser(ctx, &request)
+
+	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 1)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 1)
+	mockUpdateUser.AssertExpectations(t)
+	mockGetUserById.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &apiV1.User{
+			Id:        getUserResult.Id,
+			FirstName: getUserResult.FirstName,
+			LastName:  getUserResult.LastName,
+			Nickname:  getUserResult.Nickname,
+			Email:     getUserResult.Email,
+			Country:   getUserResult.Country,
+			Roles:     getUserResult.Roles,
+			CreatedAt: timestamppb.New(getUserResult.CreatedAt),
+			UpdatedAt: timestamppb.New(getUserResult.UpdatedAt),
+			Version:   getUserResult.Version,
+		}, out,
+	)
+}
+
+func testUpdateUserWithoutId(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	id := ""
+	ctx := contextWithPrincipal(id)
+	firstName := "updated"
+	lastName := "updated"
+	nickname := "updated"
+	password := "updated"
+	email := "updated"
+	country := "updated"
+	request := apiV1.UpdateUserRequest{
+		Id:        id,
+		FirstName: &firstName,
+		LastName:  &lastName,
+		Nickname:  &nickname,
+		Password:  &password,
+		Email:     &email,
+		Country:   &country,
+	}
+
+	out, err := server.UpdateUser(ctx, &request)
+
+	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 0)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Id is required"))
+	assert.Nil(t, out)
+}
+
+func testUpdateUserWithoutPrincipal(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	firstName := "updated"
+	request := apiV1.UpdateUserRequest{
+		Id:        "1234",
+		FirstName: &firstName,
+	}
+
+	out, err := server.UpdateUser(context.Background(), &request)
+
+	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 0)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, "Authentication required"))
+	assert.Nil(t, out)
+}
+
+func testUpdateUserWithForbiddenError(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	actorId := "5678"
+	ctx := contextWithPrincipal(actorId)
+	firstName := "updated"
+	request := apiV1.UpdateUserRequest{
+		Id:        id,
+		FirstName: &firstName,
+	}
+
+	updateUserCmd := command.UpdateUser{
+		ActorId: actorId,
+		Id:      id,
+		Patch:   user.Patch{"first_name": firstName},
+	}
+
+	forbiddenErr := user.ForbiddenError{ActorId: actorId, UserId: id}
+	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(&forbiddenErr)
+
+	out, err := server.UpdateUser(ctx, &request)
+
+	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 1)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+	mockUpdateUser.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Not allowed to modify the user"))
+	assert.Nil(t, out)
+}
+
+func testUpdateUserWithAlreadyExistsError(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	ctx := contextWithPrincipal(id)
+	email := "taken@john.com"
+	request := apiV1.UpdateUserRequest{
+		Id:    id,
+		Email: &email,
+	}
+
+	updateUserCmd := command.UpdateUser{
+		ActorId: id,
+		Id:      id,
+		Patch:   user.Patch{"email": email},
+	}
+
+	existsErr := user.AlreadyExistsError{Field: "email", Value: email}
+	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(&existsErr)
+
+	out, err := server.UpdateUser(ctx, &request)
+
+	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 1)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+	mockUpdateUser.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.AlreadyExists, existsErr.Error()))
+	assert.Nil(t, out)
+}
+
+func testUpdateUserWithNotFoundError(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	ctx := contextWithPrincipal(id)
+	firstName := "updated"
+	lastName := "updated"
+	nickname := "updated"
+	password := "updated"
+	email := "updated"
+	country := "updated"
+	request := apiV1.UpdateUserRequest{
+		Id:        id,
+		FirstName: &firstName,
+		LastName:  &lastName,
+		Nickname:  &nickname,
+		Password:  &password,
+		Email:     &email,
+		Country:   &country,
+	}
+
+	updateUserCmd := command.UpdateUser{
+		ActorId: id,
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": firstName,
+			"last_name":  lastName,
+			"nickname":   nickname,
+			"password":   password,
+			"email":      email,
+			"country":    country,
+		},
 	}
-	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(&invalidErr)
+
+	notFoundErr := user.NotFoundError{Id: id}
+	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(&notFoundErr)
 
 	out, err := server.UpdateUser(ctx, &request)
 
@@ -851,11 +1487,11 @@ func testUpdateUserWithInvalidFieldError(t *testing.T) {
 	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
 	mockUpdateUser.AssertExpectations(t)
 
-	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, invalidErr.Error()))
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "User not found"))
 	assert.Nil(t, out)
 }
 
-func testUpdateUserWithMultipleInvalidFieldsError(t *testing.T) {
+func testUpdateUserWithInvalidFieldError(t *testing.T) {
 	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
 	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
 	application := app.Application{
@@ -865,7 +1501,7 @@ func testUpdateUserWithMultipleInvalidFieldsError(t *testing.T) {
 	server := GrpcServer{app: application}
 
 	id := "1234"
-	ctx := context.Background()
+	ctx := contextWithPrincipal(id)
 	firstName := "updated"
 	lastName := "updated"
 	nickname := "updated"
@@ -883,6 +1519,129 @@ func testUpdateUserWithMultipleInvalidFieldsError(t *testing.T) {
 	}
 
 	updateUserCmd := command.UpdateUser{
+		ActorId: id,
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": firstName,
+			"last_name":  lastName,
+			"nickname":   nickname,
+			"password":   password,
+			"email":      email,
+			"country":    country,
+		},
+	}
+
+	invalidErr := errors2.InvalidField{
+		Domain: "",
+		Field:  "",
+		Value:  nil,
+	}
+	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(&invalidErr)
+
+	out, err := server.UpdateUser(ctx, &request)
+
+	mockUpdateUser.AssertNumberOfCalls(t, "Handle", 1)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+	mockUpdateUser.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, errors2.MapInvalidFieldsToStatus(&invalidErr))
+	assert.Nil(t, out)
+}
+
+func testUpdateUserWithUpdateMask(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	ctx := contextWithPrincipal(id)
+	firstName := "updated"
+	email := "updated"
+	request := apiV1.UpdateUserRequest{
+		Id:         id,
+		FirstName:  &firstName,
+		Email:      &email,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"first_name", "last_name", "created_at"}},
+	}
+
+	// The email is left out of the mask, while the last name is cleared, and the creation time rejected.
+	updateUserCmd := command.UpdateUser{
+		ActorId: id,
+		Id:      id,
+		Patch:   user.Patch{"first_name": firstName, "last_name": "", "created_at": ""},
+	}
+
+	invalidErr := errors2.InvalidField{Domain: "User", Field: "created_at", Reason: errors2.ReasonImmutable}
+	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(&invalidErr)
+
+	out, err := server.UpdateUser(ctx, &request)
+
+	mockUpdateUser.AssertExpectations(t)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Nil(t, out)
+}
+
+func testUpdateUserWithVersionConflict(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	ctx := contextWithPrincipal(id)
+	firstName := "updated"
+	version := int64(3)
+	request := apiV1.UpdateUserRequest{
+		Id:              id,
+		FirstName:       &firstName,
+		ExpectedVersion: &version,
+	}
+
+	updateUserCmd := command.UpdateUser{
+		ActorId:         id,
+		Id:              id,
+		ExpectedVersion: &version,
+		Patch:           user.Patch{"first_name": firstName},
+	}
+
+	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(&user.VersionConflictError{Id: id, Version: version})
+
+	out, err := server.UpdateUser(ctx, &request)
+
+	mockUpdateUser.AssertExpectations(t)
+	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.Nil(t, out)
+}
+
+func testUpdateUserWithMultipleInvalidFieldsError(t *testing.T) {
+	mockUpdateUser := new(handler_mocks2.IUpdateUserHandler)
+	mockGetUserById := new(handler_mocks2.IGetUserByIdHandler)
+	application := app.Application{
+		Commands: app.Commands{UpdateUser: mockUpdateUser},
+		Queries:  app.Queries{GetUserById: mockGetUserById},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	ctx := contextWithPrincipal(id)
+	firstName := "updated"
+	lastName := "updated"
+	nickname := "updated"
+	password := "updated"
+	email := "updated"
+	country := "updated"
+	request := apiV1.UpdateUserRequest{
 		Id:        id,
 		FirstName: &firstName,
 		LastName:  &lastName,
@@ -892,6 +1651,19 @@ func testUpdateUserWithMultipleInvalidFieldsError(t *testing.T) {
 		Country:   &country,
 	}
 
+	updateUserCmd := command.UpdateUser{
+		ActorId: id,
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": firstName,
+			"last_name":  lastName,
+			"nickname":   nickname,
+			"password":   password,
+			"email":      email,
+			"country":    country,
+		},
+	}
+
 	invalidErr := errors2.MultipleInvalidFields{
 		Errors: []error{
 			&errors2.InvalidField{
@@ -911,7 +1683,7 @@ func testUpdateUserWithMultipleInvalidFieldsError(t *testing.T) {
 	mockGetUserById.AssertNumberOfCalls(t, "Handle", 0)
 	mockUpdateUser.AssertExpectations(t)
 
-	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, invalidErr.Error()))
+	assert.ErrorIs(t, err, errors2.MapInvalidFieldsToStatus(&invalidErr))
 	assert.Nil(t, out)
 }
 
@@ -925,7 +1697,7 @@ func testUpdateUserWithUpdateError(t *testing.T) {
 	server := GrpcServer{app: application}
 
 	id := "1234"
-	ctx := context.Background()
+	ctx := contextWithPrincipal(id)
 	firstName := "updated"
 	lastName := "updated"
 	nickname := "updated"
@@ -943,13 +1715,16 @@ func testUpdateUserWithUpdateError(t *testing.T) {
 	}
 
 	updateUserCmd := command.UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+		ActorId: id,
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": firstName,
+			"last_name":  lastName,
+			"nickname":   nickname,
+			"password":   password,
+			"email":      email,
+			"country":    country,
+		},
 	}
 
 	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(errors.New("unknown error"))
@@ -974,7 +1749,7 @@ func testUpdateUserWithGetError(t *testing.T) {
 	server := GrpcServer{app: application}
 
 	id := "1234"
-	ctx := context.Background()
+	ctx := contextWithPrincipal(id)
 	firstName := "updated"
 	lastName := "updated"
 	nickname := "updated"
@@ -992,17 +1767,20 @@ func testUpdateUserWithGetError(t *testing.T) {
 	}
 
 	updateUserCmd := command.UpdateUser{
-		Id:        id,
-		FirstName: &firstName,
-		LastName:  &lastName,
-		Nickname:  &nickname,
-		Password:  &password,
-		Email:     &email,
-		Country:   &country,
+		ActorId: id,
+		Id:      id,
+		Patch: user.Patch{
+			"first_name": firstName,
+			"last_name":  lastName,
+			"nickname":   nickname,
+			"password":   password,
+			"email":      email,
+			"country":    country,
+		},
 	}
 
 	mockUpdateUser.On("Handle", ctx, updateUserCmd).Return(nil)
-	mockGetUserById.On("Handle", ctx, id).Return(nil, errors.New("unknown error"))
+	mockGetUserById.On("Handle", ctx, query.GetUserById{Id: id}).Return(nil, errors.New("unknown error"))
 
 	out, err := server.UpdateUser(ctx, &request)
 
@@ -1023,12 +1801,12 @@ func testRemoveUser(t *testing.T) {
 	server := GrpcServer{app: application}
 
 	id := "1234"
-	ctx := context.Background()
+	ctx := contextWithPrincipal(id)
 	request := apiV1.RemoveUserRequest{
 		Id: id,
 	}
 
-	mockRemoveUser.On("Handle", ctx, id).Return(nil)
+	mockRemoveUser.On("Handle", ctx, command.RemoveUser{ActorId: id, Id: id}).Return(nil)
 
 	out, err := server.RemoveUser(ctx, &request)
 
@@ -1049,7 +1827,7 @@ func testRemoveUserWithoutId(t *testing.T) {
 	server := GrpcServer{app: application}
 
 	id := ""
-	ctx := context.Background()
+	ctx := contextWithPrincipal(id)
 	request := apiV1.RemoveUserRequest{Id: id}
 
 	out, err := server.RemoveUser(ctx, &request)
@@ -1060,6 +1838,47 @@ func testRemoveUserWithoutId(t *testing.T) {
 	assert.Nil(t, out)
 }
 
+func testRemoveUserWithoutPrincipal(t *testing.T) {
+	mockRemoveUser := new(handler_mocks2.IRemoveUserHandler)
+	application := app.Application{
+		Commands: app.Commands{RemoveUser: mockRemoveUser},
+	}
+	server := GrpcServer{app: application}
+
+	request := apiV1.RemoveUserRequest{Id: "1234"}
+
+	out, err := server.RemoveUser(context.Background(), &request)
+
+	mockRemoveUser.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, "Authentication required"))
+	assert.Nil(t, out)
+}
+
+func testRemoveUserWithForbiddenError(t *testing.T) {
+	mockRemoveUser := new(handler_mocks2.IRemoveUserHandler)
+	application := app.Application{
+		Commands: app.Commands{RemoveUser: mockRemoveUser},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	actorId := "5678"
+	ctx := contextWithPrincipal(actorId)
+	request := apiV1.RemoveUserRequest{Id: id}
+
+	forbiddenErr := user.ForbiddenError{ActorId: actorId, UserId: id}
+	mockRemoveUser.On("Handle", ctx, command.RemoveUser{ActorId: actorId, Id: id}).Return(&forbiddenErr)
+
+	out, err := server.RemoveUser(ctx, &request)
+
+	mockRemoveUser.AssertNumberOfCalls(t, "Handle", 1)
+	mockRemoveUser.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Not allowed to modify the user"))
+	assert.Nil(t, out)
+}
+
 func testRemoveUserWithNotFoundError(t *testing.T) {
 	mockRemoveUser := new(handler_mocks2.IRemoveUserHandler)
 	application := app.Application{
@@ -1068,18 +1887,43 @@ func testRemoveUserWithNotFoundError(t *testing.T) {
 	server := GrpcServer{app: application}
 
 	id := "1234"
-	ctx := context.Background()
+	ctx := contextWithPrincipal(id)
+	request := apiV1.RemoveUserRequest{Id: id}
+
+	notFoundErr := user.NotFoundError{Id: id}
+	mockRemoveUser.On("Handle", ctx, command.RemoveUser{ActorId: id, Id: id}).Return(&notFoundErr)
+
+	out, err := server.RemoveUser(ctx, &request)
+
+	mockRemoveUser.AssertNumberOfCalls(t, "Handle", 1)
+	mockRemoveUser.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "User not found"))
+	assert.Nil(t, out)
+}
+
+func testRemoveUserWithWrappedError(t *testing.T) {
+	mockRemoveUser := new(handler_mocks2.IRemoveUserHandler)
+	application := app.Application{
+		Commands: app.Commands{RemoveUser: mockRemoveUser},
+	}
+	server := GrpcServer{app: application}
+
+	id := "1234"
+	ctx := contextWithPrincipal(id)
 	request := apiV1.RemoveUserRequest{Id: id}
 
 	notFoundErr := user.NotFoundError{Id: id}
-	mockRemoveUser.On("Handle", ctx, id).Return(&notFoundErr)
+	mockRemoveUser.On("Handle", ctx, command.RemoveUser{ActorId: id, Id: id}).Return(
+		errors.Wrap(&notFoundErr, "removing user"),
+	)
 
 	out, err := server.RemoveUser(ctx, &request)
 
 	mockRemoveUser.AssertNumberOfCalls(t, "Handle", 1)
 	mockRemoveUser.AssertExpectations(t)
 
-	assert.ErrorIs(t, err, status.Error(codes.NotFound, notFoundErr.Error()))
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "User not found"))
 	assert.Nil(t, out)
 }
 
@@ -1091,10 +1935,10 @@ func testRemoveUserWithRemoveError(t *testing.T) {
 	server := GrpcServer{app: application}
 
 	id := "1234"
-	ctx := context.Background()
+	ctx := contextWithPrincipal(id)
 	request := apiV1.RemoveUserRequest{Id: id}
 
-	mockRemoveUser.On("Handle", ctx, id).Return(errors.New("unknown error"))
+	mockRemoveUser.On("Handle", ctx, command.RemoveUser{ActorId: id, Id: id}).Return(errors.New("unknown error"))
 
 	out, err := server.RemoveUser(ctx, &request)
 
@@ -1104,3 +1948,111 @@ func testRemoveUserWithRemoveError(t *testing.T) {
 	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while removing user"))
 	assert.Nil(t, out)
 }
+
+func testAuthenticate(t *testing.T) {
+	mockAuthenticate := new(handler_mocks2.IAuthenticateHandler)
+	application := app.Application{
+		Queries: app.Queries{Authenticate: mockAuthenticate},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.AuthenticateRequest{NicknameOrEmail: "john-123", Password: "password"}
+	authQuery := query.Authenticate{NicknameOrEmail: "john-123", Password: "password"}
+
+	now := time.Now()
+	authResult := query.User{
+		Id:        "1234",
+		FirstName: "John",
+		LastName:  "Doe",
+		Nickname:  "john-123",
+		Email:     "me@john.com",
+		Country:   "US",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	mockAuthenticate.On("Handle", ctx, authQuery).Return(&authResult, nil)
+
+	out, err := server.Authenticate(ctx, &request)
+
+	mockAuthenticate.AssertNumberOfCalls(t, "Handle", 1)
+	mockAuthenticate.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &apiV1.User{
+			Id:        authResult.Id,
+			FirstName: authResult.FirstName,
+			LastName:  authResult.LastName,
+			Nickname:  authResult.Nickname,
+			Email:     authResult.Email,
+			Country:   authResult.Country,
+			CreatedAt: timestamppb.New(authResult.CreatedAt),
+			UpdatedAt: timestamppb.New(authResult.UpdatedAt),
+		}, out,
+	)
+}
+
+func testAuthenticateWithMissingCredentials(t *testing.T) {
+	mockAuthenticate := new(handler_mocks2.IAuthenticateHandler)
+	application := app.Application{
+		Queries: app.Queries{Authenticate: mockAuthenticate},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.AuthenticateRequest{NicknameOrEmail: "john-123"}
+
+	out, err := server.Authenticate(ctx, &request)
+
+	mockAuthenticate.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Nickname or email and password are required"))
+	assert.Nil(t, out)
+}
+
+func testAuthenticateWithInvalidCredentials(t *testing.T) {
+	mockAuthenticate := new(handler_mocks2.IAuthenticateHandler)
+	application := app.Application{
+		Queries: app.Queries{Authenticate: mockAuthenticate},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.AuthenticateRequest{NicknameOrEmail: "john-123", Password: "wrong"}
+	authQuery := query.Authenticate{NicknameOrEmail: "john-123", Password: "wrong"}
+
+	invalidErr := user.InvalidCredentialsError{}
+	mockAuthenticate.On("Handle", ctx, authQuery).Return(nil, &invalidErr)
+
+	out, err := server.Authenticate(ctx, &request)
+
+	mockAuthenticate.AssertNumberOfCalls(t, "Handle", 1)
+	mockAuthenticate.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, invalidErr.Error()))
+	assert.Nil(t, out)
+}
+
+func testAuthenticateWithUnknownError(t *testing.T) {
+	mockAuthenticate := new(handler_mocks2.IAuthenticateHandler)
+	application := app.Application{
+		Queries: app.Queries{Authenticate: mockAuthenticate},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.AuthenticateRequest{NicknameOrEmail: "john-123", Password: "password"}
+	authQuery := query.Authenticate{NicknameOrEmail: "john-123", Password: "password"}
+
+	mockAuthenticate.On("Handle", ctx, authQuery).Return(nil, errors.New("unknown error"))
+
+	out, err := server.Authenticate(ctx, &request)
+
+	mockAuthenticate.AssertNumberOfCalls(t, "Handle", 1)
+	mockAuthenticate.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while authenticating user"))
+	assert.Nil(t, out)
+}
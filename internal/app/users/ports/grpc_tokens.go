@@ -0,0 +1,150 @@
+package ports
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const tokenType = "Bearer"
+
+const loginTag = "Login"
+
+func (g *GrpcServer) Login(ctx context.Context, request *apiV1.LoginRequest) (*apiV1.Tokens, error) {
+	if request.GetNicknameOrEmail() == "" || request.GetPassword() == "" {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":             loginTag,
+				"nicknameOrEmail": request.GetNicknameOrEmail(),
+			},
+		).Info("Error logging in: nickname or email and password are required")
+
+		return nil, status.Error(codes.InvalidArgument, "Nickname or email and password are required")
+	}
+
+	tokens, err := g.app.Commands.Login.Handle(
+		ctx, command.Login{
+			NicknameOrEmail: request.GetNicknameOrEmail(),
+			Password:        request.GetPassword(),
+		},
+	)
+
+	if err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag":             loginTag,
+				"nicknameOrEmail": request.GetNicknameOrEmail(),
+			}, err, "Unknown error while logging in",
+		)
+	}
+
+	return mapTokensToGrpc(tokens), nil
+}
+
+const refreshTokenTag = "RefreshToken"
+
+func (g *GrpcServer) RefreshToken(ctx context.Context, request *apiV1.RefreshTokenRequest) (*apiV1.Tokens, error) {
+	if request.GetRefreshToken() == "" {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": refreshTokenTag,
+			},
+		).Info("Error refreshing tokens: refresh token is required")
+
+		return nil, status.Error(codes.InvalidArgument, "Refresh token is required")
+	}
+
+	tokens, err := g.app.Commands.RefreshTokens.Handle(
+		ctx, command.RefreshTokens{RefreshToken: request.GetRefreshToken()},
+	)
+
+	if err != nil {
+		fields := logrus.Fields{
+			"tag": refreshTokenTag,
+		}
+
+		var tokenErr *token.InvalidTokenError
+		if errors.As(err, &tokenErr) {
+			fields["reason"] = tokenErr.Reason
+		}
+
+		return nil, mapError(fields, err, "Unknown error while refreshing tokens")
+	}
+
+	return mapTokensToGrpc(tokens), nil
+}
+
+const revokeTokenTag = "RevokeToken"
+
+func (g *GrpcServer) RevokeToken(ctx context.Context, request *apiV1.RevokeTokenRequest) (*emptypb.Empty, error) {
+	if request.GetRefreshToken() == "" {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": revokeTokenTag,
+			},
+		).Info("Error revoking token: refresh token is required")
+
+		return nil, status.Error(codes.InvalidArgument, "Refresh token is required")
+	}
+
+	if err := g.app.Commands.RevokeToken.Handle(
+		ctx, command.RevokeToken{RefreshToken: request.GetRefreshToken()},
+	); err != nil {
+		return nil, mapError(
+			logrus.Fields{
+				"tag": revokeTokenTag,
+			}, err, "Unknown error while revoking token",
+		)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+const getSigningKeysTag = "GetSigningKeys"
+
+func (g *GrpcServer) GetSigningKeys(ctx context.Context, _ *emptypb.Empty) (*apiV1.SigningKeys, error) {
+	keys, err := g.app.Queries.GetSigningKeys.Handle(ctx)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag": getSigningKeysTag,
+			},
+		).WithError(err).Error("Error retrieving signing keys")
+
+		return nil, status.Error(codes.Internal, "Error retrieving signing keys")
+	}
+
+	response := &apiV1.SigningKeys{}
+	for _, key := range keys {
+		response.Keys = append(
+			response.Keys, &apiV1.SigningKey{
+				Kty: key.Kty,
+				Kid: key.Kid,
+				Use: key.Use,
+				Alg: key.Alg,
+				N:   key.N,
+				E:   key.E,
+			},
+		)
+	}
+
+	return response, nil
+}
+
+func mapTokensToGrpc(tokens *command.Tokens) *apiV1.Tokens {
+	return &apiV1.Tokens{
+		AccessToken:           tokens.AccessToken,
+		TokenType:             tokenType,
+		AccessTokenExpiresAt:  timestamppb.New(tokens.AccessTokenExpiresAt),
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(tokens.RefreshTokenExpiresAt),
+	}
+}
@@ -0,0 +1,376 @@
+package ports
+
+import (
+	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	handler_mocks2 "github.com/elizabeth-dev/ACME_Test/test/mocks/handler_mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+func TestGrpcTokens(t *testing.T) {
+	t.Parallel()
+
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"login": {
+			"call login":                          testLogin,
+			"call login with missing credentials": testLoginWithMissingCredentials,
+			"call login with invalid credentials": testLoginWithInvalidCredentials,
+			"call login with unknown error":       testLoginWithUnknownError,
+		},
+		"refresh token": {
+			"call refresh token":                    testRefreshToken,
+			"call refresh token with missing token": testRefreshTokenWithMissingToken,
+			"call refresh token with invalid token": testRefreshTokenWithInvalidToken,
+			"call refresh token with unknown error": testRefreshTokenWithUnknownError,
+		},
+		"revoke token": {
+			"call revoke token":                    testRevokeToken,
+			"call revoke token with missing token": testRevokeTokenWithMissingToken,
+			"call revoke token with unknown error": testRevokeTokenWithUnknownError,
+		},
+		"get signing keys": {
+			"call get signing keys":                testGetSigningKeys,
+			"call get signing keys with get error": testGetSigningKeysWithGetError,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							t.Parallel()
+
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+}
+
+func newTokens() command.Tokens {
+	now := time.Now()
+
+	return command.Tokens{
+		AccessToken:           "access-token",
+		AccessTokenExpiresAt:  now.Add(time.Minute),
+		RefreshToken:          "refresh-token",
+		RefreshTokenExpiresAt: now.Add(time.Hour),
+	}
+}
+
+func testLogin(t *testing.T) {
+	mockLogin := new(handler_mocks2.ILoginHandler)
+	application := app.Application{
+		Commands: app.Commands{Login: mockLogin},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.LoginRequest{NicknameOrEmail: "john-123", Password: "password"}
+	loginCmd := command.Login{NicknameOrEmail: "john-123", Password: "password"}
+	tokens := newTokens()
+
+	mockLogin.On("Handle", ctx, loginCmd).Return(&tokens, nil)
+
+	out, err := server.Login(ctx, &request)
+
+	mockLogin.AssertNumberOfCalls(t, "Handle", 1)
+	mockLogin.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &apiV1.Tokens{
+			AccessToken:           tokens.AccessToken,
+			TokenType:             "Bearer",
+			AccessTokenExpiresAt:  timestamppb.New(tokens.AccessTokenExpiresAt),
+			RefreshToken:          tokens.RefreshToken,
+			RefreshTokenExpiresAt: timestamppb.New(tokens.RefreshTokenExpiresAt),
+		}, out,
+	)
+}
+
+func testLoginWithMissingCredentials(t *testing.T) {
+	mockLogin := new(handler_mocks2.ILoginHandler)
+	application := app.Application{
+		Commands: app.Commands{Login: mockLogin},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.LoginRequest{Password: "password"}
+
+	out, err := server.Login(ctx, &request)
+
+	mockLogin.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Nickname or email and password are required"))
+	assert.Nil(t, out)
+}
+
+func testLoginWithInvalidCredentials(t *testing.T) {
+	mockLogin := new(handler_mocks2.ILoginHandler)
+	application := app.Application{
+		Commands: app.Commands{Login: mockLogin},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.LoginRequest{NicknameOrEmail: "john-123", Password: "wrong"}
+	loginCmd := command.Login{NicknameOrEmail: "john-123", Password: "wrong"}
+
+	invalidErr := user.InvalidCredentialsError{}
+	mockLogin.On("Handle", ctx, loginCmd).Return(nil, &invalidErr)
+
+	out, err := server.Login(ctx, &request)
+
+	mockLogin.AssertNumberOfCalls(t, "Handle", 1)
+	mockLogin.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, invalidErr.Error()))
+	assert.Nil(t, out)
+}
+
+func testLoginWithUnknownError(t *testing.T) {
+	mockLogin := new(handler_mocks2.ILoginHandler)
+	application := app.Application{
+		Commands: app.Commands{Login: mockLogin},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.LoginRequest{NicknameOrEmail: "john-123", Password: "password"}
+	loginCmd := command.Login{NicknameOrEmail: "john-123", Password: "password"}
+
+	mockLogin.On("Handle", ctx, loginCmd).Return(nil, errors.New("unknown error"))
+
+	out, err := server.Login(ctx, &request)
+
+	mockLogin.AssertNumberOfCalls(t, "Handle", 1)
+	mockLogin.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while logging in"))
+	assert.Nil(t, out)
+}
+
+func testRefreshToken(t *testing.T) {
+	mockRefreshTokens := new(handler_mocks2.IRefreshTokensHandler)
+	application := app.Application{
+		Commands: app.Commands{RefreshTokens: mockRefreshTokens},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.RefreshTokenRequest{RefreshToken: "old-refresh-token"}
+	refreshCmd := command.RefreshTokens{RefreshToken: "old-refresh-token"}
+	tokens := newTokens()
+
+	mockRefreshTokens.On("Handle", ctx, refreshCmd).Return(&tokens, nil)
+
+	out, err := server.RefreshToken(ctx, &request)
+
+	mockRefreshTokens.AssertNumberOfCalls(t, "Handle", 1)
+	mockRefreshTokens.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &apiV1.Tokens{
+			AccessToken:           tokens.AccessToken,
+			TokenType:             "Bearer",
+			AccessTokenExpiresAt:  timestamppb.New(tokens.AccessTokenExpiresAt),
+			RefreshToken:          tokens.RefreshToken,
+			RefreshTokenExpiresAt: timestamppb.New(tokens.RefreshTokenExpiresAt),
+		}, out,
+	)
+}
+
+func testRefreshTokenWithMissingToken(t *testing.T) {
+	mockRefreshTokens := new(handler_mocks2.IRefreshTokensHandler)
+	application := app.Application{
+		Commands: app.Commands{RefreshTokens: mockRefreshTokens},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+
+	out, err := server.RefreshToken(ctx, &apiV1.RefreshTokenRequest{})
+
+	mockRefreshTokens.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Refresh token is required"))
+	assert.Nil(t, out)
+}
+
+func testRefreshTokenWithInvalidToken(t *testing.T) {
+	mockRefreshTokens := new(handler_mocks2.IRefreshTokensHandler)
+	application := app.Application{
+		Commands: app.Commands{RefreshTokens: mockRefreshTokens},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.RefreshTokenRequest{RefreshToken: "old-refresh-token"}
+	refreshCmd := command.RefreshTokens{RefreshToken: "old-refresh-token"}
+
+	invalidErr := token.InvalidTokenError{Reason: "token reused"}
+	mockRefreshTokens.On("Handle", ctx, refreshCmd).Return(nil, &invalidErr)
+
+	out, err := server.RefreshToken(ctx, &request)
+
+	mockRefreshTokens.AssertNumberOfCalls(t, "Handle", 1)
+	mockRefreshTokens.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, invalidErr.Error()))
+	assert.Nil(t, out)
+}
+
+func testRefreshTokenWithUnknownError(t *testing.T) {
+	mockRefreshTokens := new(handler_mocks2.IRefreshTokensHandler)
+	application := app.Application{
+		Commands: app.Commands{RefreshTokens: mockRefreshTokens},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.RefreshTokenRequest{RefreshToken: "old-refresh-token"}
+	refreshCmd := command.RefreshTokens{RefreshToken: "old-refresh-token"}
+
+	mockRefreshTokens.On("Handle", ctx, refreshCmd).Return(nil, errors.New("unknown error"))
+
+	out, err := server.RefreshToken(ctx, &request)
+
+	mockRefreshTokens.AssertNumberOfCalls(t, "Handle", 1)
+	mockRefreshTokens.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while refreshing tokens"))
+	assert.Nil(t, out)
+}
+
+func testRevokeToken(t *testing.T) {
+	mockRevokeToken := new(handler_mocks2.IRevokeTokenHandler)
+	application := app.Application{
+		Commands: app.Commands{RevokeToken: mockRevokeToken},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.RevokeTokenRequest{RefreshToken: "refresh-token"}
+	revokeCmd := command.RevokeToken{RefreshToken: "refresh-token"}
+
+	mockRevokeToken.On("Handle", ctx, revokeCmd).Return(nil)
+
+	out, err := server.RevokeToken(ctx, &request)
+
+	mockRevokeToken.AssertNumberOfCalls(t, "Handle", 1)
+	mockRevokeToken.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &emptypb.Empty{}, out)
+}
+
+func testRevokeTokenWithMissingToken(t *testing.T) {
+	mockRevokeToken := new(handler_mocks2.IRevokeTokenHandler)
+	application := app.Application{
+		Commands: app.Commands{RevokeToken: mockRevokeToken},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+
+	out, err := server.RevokeToken(ctx, &apiV1.RevokeTokenRequest{})
+
+	mockRevokeToken.AssertNumberOfCalls(t, "Handle", 0)
+
+	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Refresh token is required"))
+	assert.Nil(t, out)
+}
+
+func testRevokeTokenWithUnknownError(t *testing.T) {
+	mockRevokeToken := new(handler_mocks2.IRevokeTokenHandler)
+	application := app.Application{
+		Commands: app.Commands{RevokeToken: mockRevokeToken},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	request := apiV1.RevokeTokenRequest{RefreshToken: "refresh-token"}
+	revokeCmd := command.RevokeToken{RefreshToken: "refresh-token"}
+
+	mockRevokeToken.On("Handle", ctx, revokeCmd).Return(errors.New("unknown error"))
+
+	out, err := server.RevokeToken(ctx, &request)
+
+	mockRevokeToken.AssertNumberOfCalls(t, "Handle", 1)
+	mockRevokeToken.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Unknown error while revoking token"))
+	assert.Nil(t, out)
+}
+
+func testGetSigningKeys(t *testing.T) {
+	mockGetSigningKeys := new(handler_mocks2.IGetSigningKeysHandler)
+	application := app.Application{
+		Queries: app.Queries{GetSigningKeys: mockGetSigningKeys},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+	keys := []query.SigningKey{
+		{Kty: "RSA", Kid: "key-1", Use: "sig", Alg: "RS256", N: "modulus-1", E: "AQAB"},
+		{Kty: "RSA", Kid: "key-2", Use: "sig", Alg: "RS256", N: "modulus-2", E: "AQAB"},
+	}
+
+	mockGetSigningKeys.On("Handle", ctx).Return(keys, nil)
+
+	out, err := server.GetSigningKeys(ctx, &emptypb.Empty{})
+
+	mockGetSigningKeys.AssertNumberOfCalls(t, "Handle", 1)
+	mockGetSigningKeys.AssertExpectations(t)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, &apiV1.SigningKeys{
+			Keys: []*apiV1.SigningKey{
+				{Kty: "RSA", Kid: "key-1", Use: "sig", Alg: "RS256", N: "modulus-1", E: "AQAB"},
+				{Kty: "RSA", Kid: "key-2", Use: "sig", Alg: "RS256", N: "modulus-2", E: "AQAB"},
+			},
+		}, out,
+	)
+}
+
+func testGetSigningKeysWithGetError(t *testing.T) {
+	mockGetSigningKeys := new(handler_mocks2.IGetSigningKeysHandler)
+	application := app.Application{
+		Queries: app.Queries{GetSigningKeys: mockGetSigningKeys},
+	}
+	server := GrpcServer{app: application}
+
+	ctx := context.Background()
+
+	mockGetSigningKeys.On("Handle", ctx).Return(nil, errors.New("unknown error"))
+
+	out, err := server.GetSigningKeys(ctx, &emptypb.Empty{})
+
+	mockGetSigningKeys.AssertNumberOfCalls(t, "Handle", 1)
+	mockGetSigningKeys.AssertExpectations(t)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, "Error retrieving signing keys"))
+	assert.Nil(t, out)
+}
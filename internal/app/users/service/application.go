@@ -2,40 +2,148 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"database/sql"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/adapter"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
 	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/health"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/helper/mongo_helper"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/sql_utils"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"log"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func NewApplication(ctx context.Context) (app.Application, map[string]func(ctx context.Context) error) {
-	dbClient := setupMongo(ctx)
-	userRepo := adapter.NewUserRepository(dbClient)
+/*
+NewApplication assembles the application along with its adapters.
 
-	dependencies := map[string]func(ctx context.Context) error{
-		"mongodb": func(ctx context.Context) error {
-			return dbClient.Client().Ping(ctx, nil)
-		},
-	}
+It also returns the health checks of its dependencies, and a function that
+releases them, to be called once the application isn't serving anymore.
+*/
+func NewApplication(ctx context.Context, keySet *auth.KeySet) (
+	app.Application, map[string]func(ctx context.Context) error, func(ctx context.Context) error,
+) {
+	userRepo, tokenRepo, dependencies, closeStorage := setupStorage(ctx)
+	tokenConfig := setupTokenConfig()
+
+	user.SetPasswordPolicy(setupPasswordPolicy())
+
+	bootstrapAdmins(ctx, userRepo)
 
 	return app.Application{
 		Commands: app.Commands{
-			CreateUser: command.NewCreateUserHandler(&userRepo),
-			UpdateUser: command.NewUpdateUserHandler(&userRepo),
-			RemoveUser: command.NewRemoveUserHandler(&userRepo),
+			CreateUser: command.NewCreateUserHandler(userRepo),
+			UpdateUser: command.NewUpdateUserHandler(userRepo),
+			RemoveUser: command.NewRemoveUserHandler(userRepo),
+
+			Login:         command.NewLoginHandler(userRepo, tokenRepo, keySet, tokenConfig),
+			RefreshTokens: command.NewRefreshTokensHandler(userRepo, tokenRepo, keySet, tokenConfig),
+			RevokeToken:   command.NewRevokeTokenHandler(tokenRepo),
+
+			GrantRole:  command.NewGrantRoleHandler(userRepo),
+			RevokeRole: command.NewRevokeRoleHandler(userRepo),
+
+			ImportUsers: command.NewImportUsersHandler(userRepo),
 		},
 		Queries: app.Queries{
-			GetUsers:    query.NewGetUsersHandler(&userRepo),
-			GetUserById: query.NewGetUserByIdHandler(&userRepo),
+			GetUsers:      query.NewGetUsersHandler(userRepo, setupPageTokenSigner()),
+			CountUsers:    query.NewCountUsersHandler(userRepo),
+			GetUserById:   query.NewGetUserByIdHandler(userRepo),
+			GetUsersByIds: query.NewGetUsersByIdsHandler(userRepo),
+			Authenticate:  query.NewAuthenticateHandler(userRepo),
+
+			GetSigningKeys: query.NewGetSigningKeysHandler(keySet),
 		},
-	}, dependencies
+	}, dependencies, closeStorage
+}
+
+/*
+setupStorage builds the repositories for the storage chosen in the 'STORAGE' environmental variable: 'mongodb', the
+default, 'sqlite', 'postgres' or 'memory'.
+
+The in-memory storage has no dependencies to check, and loses everything on
+restart, so it's only meant for local development and tests.
+*/
+func setupStorage(ctx context.Context) (
+	user.UserRepository, token.RefreshTokenRepository, map[string]func(ctx context.Context) error,
+	func(ctx context.Context) error,
+) {
+	switch storage := os.Getenv("STORAGE"); storage {
+	case "", "mongodb":
+		dbClient := setupMongo(ctx)
+		userRepo := adapter.NewUserRepository(ctx, dbClient)
+		tokenRepo := adapter.NewRefreshTokenRepository(dbClient)
+
+		dependencies := map[string]func(ctx context.Context) error{
+			"mongodb": func(ctx context.Context) error {
+				return dbClient.Client().Ping(ctx, nil)
+			},
+		}
+
+		return &userRepo, &tokenRepo, dependencies, func(ctx context.Context) error {
+			return dbClient.Client().Disconnect(ctx)
+		}
+	case "sqlite":
+		return setupSQL(ctx, sql_utils.SQLite)
+	case "postgres":
+		return setupSQL(ctx, sql_utils.Postgres)
+	case "memory":
+		log.Printf("Using in-memory storage, every change will be lost on restart. Don't do this in production.")
 
+		return adapter.NewMemoryUserRepository(), adapter.NewMemoryRefreshTokenRepository(),
+			map[string]func(ctx context.Context) error{}, func(context.Context) error {
+				return nil
+			}
+	default:
+		log.Fatalf("Invalid storage in 'STORAGE' environmental variable: %s", storage)
+
+		return nil, nil, nil, nil
+	}
+}
+
+/*
+setupSQL connects to the database in the 'SQL_DSN' environmental variable, migrating it before building the
+repositories.
+*/
+func setupSQL(ctx context.Context, dialect sql_utils.Dialect) (
+	user.UserRepository, token.RefreshTokenRepository, map[string]func(ctx context.Context) error,
+	func(ctx context.Context) error,
+) {
+	dsn := os.Getenv("SQL_DSN")
+
+	if dsn == "" {
+		log.Fatal("You must set your 'SQL_DSN' environmental variable.")
+	}
+
+	db, err := sql.Open(dialect.DriverName(), dsn)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := adapter.MigrateSQLDatabase(ctx, db, dialect); err != nil {
+		log.Fatalf("Couldn't migrate the database: %v", err)
+	}
+
+	dependencies := map[string]func(ctx context.Context) error{
+		dialect.String(): db.PingContext,
+	}
+
+	return adapter.NewSQLUserRepository(db, dialect), adapter.NewSQLRefreshTokenRepository(db, dialect),
+		dependencies, func(context.Context) error {
+			return db.Close()
+		}
 }
 
 func setupMongo(ctx context.Context) mongo_helper.Database {
@@ -72,3 +180,214 @@ func setupMongo(ctx context.Context) mongo_helper.Database {
 
 	return &mongo_helper.MongoDatabase{Db: client.Database(db)}
 }
+
+/*
+SetupKeySet loads the token signing keys from the PEM files listed in 'JWT_SIGNING_KEYS', separated by commas.
+
+The first file holds the active key. If no files are set, a temporary key is
+generated so the service can still run locally, but every issued token becomes
+invalid on restart.
+*/
+func SetupKeySet() *auth.KeySet {
+	keyFiles := os.Getenv("JWT_SIGNING_KEYS")
+
+	if keyFiles == "" {
+		log.Printf("'JWT_SIGNING_KEYS' is not set, generating a temporary signing key. Don't do this in production.")
+
+		keySet, err := auth.GenerateKeySet(2048)
+
+		if err != nil {
+			panic(err)
+		}
+
+		return keySet
+	}
+
+	keySet, err := auth.LoadKeySetFromPEMFiles(strings.Split(keyFiles, ",")...)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return keySet
+}
+
+/*
+setupPageTokenSigner builds the signer of the page tokens with the secret in 'PAGE_TOKEN_SECRET'.
+
+Every replica must share the same secret, so any of them can serve the next
+page. If it isn't set, a temporary one is generated, and the issued tokens
+become invalid on restart.
+*/
+func setupPageTokenSigner() *query_utils.PageTokenSigner {
+	secret := os.Getenv("PAGE_TOKEN_SECRET")
+
+	if secret == "" {
+		log.Printf("'PAGE_TOKEN_SECRET' is not set, generating a temporary secret. Don't do this in production.")
+
+		key := make([]byte, 32)
+
+		if _, err := rand.Read(key); err != nil {
+			panic(err)
+		}
+
+		return query_utils.NewPageTokenSigner("User", key)
+	}
+
+	return query_utils.NewPageTokenSigner("User", []byte(secret))
+}
+
+/*
+bootstrapAdmins grants the admin role to the existing users listed in 'ADMIN_USERS', by nickname or email, separated
+by commas.
+
+Roles can only be granted by admins, so this is how the first one gets created.
+*/
+func bootstrapAdmins(ctx context.Context, userRepo user.UserRepository) {
+	admins := os.Getenv("ADMIN_USERS")
+
+	if admins == "" {
+		return
+	}
+
+	for _, nicknameOrEmail := range strings.Split(admins, ",") {
+		nicknameOrEmail = strings.TrimSpace(nicknameOrEmail)
+
+		adminUser, err := userRepo.GetUserByNicknameOrEmail(ctx, nicknameOrEmail)
+
+		if err != nil {
+			log.Printf("Couldn't find admin user '%s': %v", nicknameOrEmail, err)
+			continue
+		}
+
+		if adminUser.IsAdmin() {
+			continue
+		}
+
+		if err := adminUser.GrantRole(user.RoleAdmin); err != nil {
+			log.Fatal(err)
+		}
+
+		if err := userRepo.UpdateUser(ctx, adminUser); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+/*
+setupPasswordPolicy builds the password policy from the 'PASSWORD_*' environmental variables, falling back to the
+default policy for the ones that aren't set.
+*/
+func setupPasswordPolicy() user.PasswordPolicy {
+	policy := user.DefaultPasswordPolicy
+
+	policy.MinLength = intFromEnv("PASSWORD_MIN_LENGTH", policy.MinLength)
+	policy.MaxLength = intFromEnv("PASSWORD_MAX_LENGTH", policy.MaxLength)
+	policy.RequireLowercase = boolFromEnv("PASSWORD_REQUIRE_LOWERCASE", policy.RequireLowercase)
+	policy.RequireUppercase = boolFromEnv("PASSWORD_REQUIRE_UPPERCASE", policy.RequireUppercase)
+	policy.RequireDigit = boolFromEnv("PASSWORD_REQUIRE_DIGIT", policy.RequireDigit)
+	policy.RequireSymbol = boolFromEnv("PASSWORD_REQUIRE_SYMBOL", policy.RequireSymbol)
+	policy.ForbidNickname = boolFromEnv("PASSWORD_FORBID_NICKNAME", policy.ForbidNickname)
+
+	if policy.MaxLength > 72 || policy.MinLength > policy.MaxLength {
+		log.Fatalf("Invalid password length range: %d-%d", policy.MinLength, policy.MaxLength)
+	}
+
+	return policy
+}
+
+/*
+NewHealthProber builds the background prober for the given dependencies, pinging them every 'HEALTH_PROBE_INTERVAL'
+and giving up on each ping after 'HEALTH_PROBE_TIMEOUT'.
+*/
+func NewHealthProber(dependencies map[string]func(ctx context.Context) error) *health.Prober {
+	return health.NewProber(
+		dependencies,
+		durationFromEnv("HEALTH_PROBE_INTERVAL", 10*time.Second),
+		durationFromEnv("HEALTH_PROBE_TIMEOUT", 5*time.Second),
+	)
+}
+
+/*
+ShutdownDrainDelay is how long the server keeps serving after reporting NOT_SERVING, taken from
+'SHUTDOWN_DRAIN_DELAY', so load balancers have time to stop routing calls to it.
+*/
+func ShutdownDrainDelay() time.Duration {
+	return durationFromEnv("SHUTDOWN_DRAIN_DELAY", 5*time.Second)
+}
+
+/*
+SetupTokenVerifier verifies the access tokens with the key set, only accepting the ones we issued.
+*/
+func SetupTokenVerifier(keySet *auth.KeySet) auth.TokenVerifier {
+	return auth.NewIssuerVerifier(keySet, tokenIssuer())
+}
+
+/*
+tokenIssuer is the issuer of our tokens, taken from 'JWT_ISSUER', or "users" if it's not set.
+*/
+func tokenIssuer() string {
+	issuer := os.Getenv("JWT_ISSUER")
+
+	if issuer == "" {
+		return "users"
+	}
+
+	return issuer
+}
+
+func setupTokenConfig() command.TokenConfig {
+	return command.TokenConfig{
+		Issuer:          tokenIssuer(),
+		AccessTokenTTL:  durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+	}
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+
+	if err != nil || duration <= 0 {
+		log.Fatalf("Invalid duration in '%s' environmental variable: %s", name, value)
+	}
+
+	return duration
+}
+
+func intFromEnv(name string, fallback int) int {
+	value := os.Getenv(name)
+
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+
+	if err != nil || number < 0 {
+		log.Fatalf("Invalid number in '%s' environmental variable: %s", name, value)
+	}
+
+	return number
+}
+
+func boolFromEnv(name string, fallback bool) bool {
+	value := os.Getenv(name)
+
+	if value == "" {
+		return fallback
+	}
+
+	flag, err := strconv.ParseBool(value)
+
+	if err != nil {
+		log.Fatalf("Invalid boolean in '%s' environmental variable: %s", name, value)
+	}
+
+	return flag
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"context"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+/*
+Access is the level of authorization a gRPC method requires.
+*/
+type Access int
+
+const (
+	// Public methods can be called without any token.
+	Public Access = iota
+	// Authenticated methods can be called by any user holding a valid token.
+	Authenticated
+	// Self methods can only be called by the user their request targets, or by an admin.
+	Self
+	// Admin methods can only be called by admins.
+	Admin
+)
+
+/*
+Policies maps full gRPC method names (as in "/package.Service/Method") to the access they require.
+
+Methods not present in the map are denied, so new endpoints can't be exposed by
+accident.
+*/
+type Policies map[string]Access
+
+/*
+TargetedRequest is implemented by the requests of Self methods, naming the user they act on.
+*/
+type TargetedRequest interface {
+	GetId() string
+}
+
+type TokenVerifier interface {
+	Verify(token string) (*Claims, error)
+}
+
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+const interceptorTag = "auth/interceptor"
+
+// invalidTokenMessage is the message clients get for every token that doesn't pass verification.
+const invalidTokenMessage = "Invalid access token"
+
+/*
+Interceptor authorizes every incoming gRPC call against its method policy.
+
+The bearer token is read from the "authorization" metadata, and the resulting
+Principal is stored in the context, so handlers can retrieve it with
+PrincipalFromContext. Health checks are always allowed.
+*/
+type Interceptor struct {
+	verifier TokenVerifier
+	policies Policies
+}
+
+func NewInterceptor(verifier TokenVerifier, policies Policies) *Interceptor {
+	if verifier == nil {
+		panic("[auth/interceptor] nil verifier")
+	}
+
+	return &Interceptor{verifier, policies}
+}
+
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		newCtx, access, err := i.authenticate(ctx, info.FullMethod)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := authorizeTarget(newCtx, access, req); err != nil {
+			return nil, err
+		}
+
+		return handler(newCtx, req)
+	}
+}
+
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		newCtx, access, err := i.authenticate(stream.Context(), info.FullMethod)
+
+		if err != nil {
+			return err
+		}
+
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = newCtx
+
+		return handler(srv, &authorizedServerStream{wrapped, access})
+	}
+}
+
+/*
+authenticate checks the method policy and, if a token is needed, verifies it and stores the principal in the context.
+*/
+func (i *Interceptor) authenticate(ctx context.Context, fullMethod string) (context.Context, Access, error) {
+	if strings.HasPrefix(fullMethod, healthServicePrefix) {
+		return ctx, Public, nil
+	}
+
+	access, ok := i.policies[fullMethod]
+
+	if !ok {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":    interceptorTag,
+				"method": fullMethod,
+			},
+		).Error("Method has no access policy")
+
+		return nil, Public, status.Error(codes.PermissionDenied, "Permission denied")
+	}
+
+	if access == Public {
+		return ctx, access, nil
+	}
+
+	rawToken, err := grpc_auth.AuthFromMD(ctx, "bearer")
+
+	if err != nil {
+		return nil, access, err
+	}
+
+	claims, err := i.verifier.Verify(rawToken)
+
+	if err != nil {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":    interceptorTag,
+				"method": fullMethod,
+			},
+		).WithError(err).Info("Invalid access token")
+
+		// Why the token is invalid is only logged, as it would tell attackers what to change.
+		return nil, access, status.Error(codes.Unauthenticated, invalidTokenMessage)
+	}
+
+	principal := &Principal{Subject: claims.Subject, Roles: claims.Roles}
+
+	if access == Admin && !principal.IsAdmin() {
+		return nil, access, status.Error(codes.PermissionDenied, "Permission denied")
+	}
+
+	return ContextWithPrincipal(ctx, principal), access, nil
+}
+
+/*
+authorizeTarget checks that the principal is allowed to act on the user targeted by a Self method request.
+*/
+func authorizeTarget(ctx context.Context, access Access, req interface{}) error {
+	if access != Self {
+		return nil
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+
+	if !ok {
+		return status.Error(codes.Unauthenticated, "Missing principal")
+	}
+
+	if principal.IsAdmin() {
+		return nil
+	}
+
+	if targeted, ok := req.(TargetedRequest); ok && targeted.GetId() == principal.Subject {
+		return nil
+	}
+
+	return status.Error(codes.PermissionDenied, "Permission denied")
+}
+
+/*
+authorizedServerStream checks the target of Self methods as soon as their request is received.
+*/
+type authorizedServerStream struct {
+	*grpc_middleware.WrappedServerStream
+	access Access
+}
+
+func (s *authorizedServerStream) RecvMsg(m interface{}) error {
+	if err := s.WrappedServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return authorizeTarget(s.Context(), s.access, m)
+}
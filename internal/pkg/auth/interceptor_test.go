@@ -0,0 +1,349 @@
+package auth
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestInterceptor(t *testing.T) {
+	t.Parallel()
+
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"interceptor": {
+			"initialize interceptor":              testNewInterceptor,
+			"initialize interceptor without keys": testNewInterceptorWithoutVerifier,
+		},
+		"unary": {
+			"call public method":                       testUnaryPublicMethod,
+			"call health check":                        testUnaryHealthCheck,
+			"call method without policy":               testUnaryMethodWithoutPolicy,
+			"call authenticated method":                testUnaryAuthenticatedMethod,
+			"call authenticated method without token":  testUnaryAuthenticatedMethodWithoutToken,
+			"call authenticated method with bad token": testUnaryAuthenticatedMethodWithInvalidToken,
+			"call self method on own data":             testUnarySelfMethod,
+			"call self method on other user data":      testUnarySelfMethodOnOtherUser,
+			"call self method on other user as admin":  testUnarySelfMethodAsAdmin,
+			"call self method with untargeted request": testUnarySelfMethodWithUntargetedRequest,
+			"call admin method as admin":               testUnaryAdminMethod,
+			"call admin method as regular user":        testUnaryAdminMethodAsUser,
+		},
+		"stream": {
+			"call authenticated stream":               testStreamAuthenticatedMethod,
+			"call authenticated stream without token": testStreamAuthenticatedMethodWithoutToken,
+			"call self stream on other user data":     testStreamSelfMethodOnOtherUser,
+		},
+		"principal": {
+			"retrieve principal from context": testPrincipalFromContext,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							t.Parallel()
+
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+}
+
+const testServicePrefix = "/test.Service/"
+
+var testPolicies = Policies{
+	testServicePrefix + "Public":        Public,
+	testServicePrefix + "Authenticated": Authenticated,
+	testServicePrefix + "Self":          Self,
+	testServicePrefix + "Admin":         Admin,
+}
+
+type targetedRequest struct {
+	id string
+}
+
+func (r *targetedRequest) GetId() string {
+	return r.id
+}
+
+func newTestInterceptor(t *testing.T) (*Interceptor, *KeySet) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	return NewInterceptor(keySet, testPolicies), keySet
+}
+
+func contextWithToken(t *testing.T, keySet *KeySet, subject string, roles ...string) context.Context {
+	now := time.Now()
+	signed, err := keySet.Sign(
+		Claims{
+			Subject:   subject,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(time.Minute).Unix(),
+			Roles:     roles,
+		},
+	)
+	require.NoError(t, err)
+
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+signed))
+}
+
+/*
+callUnary runs the interceptor for the given method, returning the principal the handler received, if called.
+*/
+func callUnary(
+	interceptor *Interceptor, ctx context.Context, method string, req interface{},
+) (principal *Principal, called bool, err error) {
+	_, err = interceptor.Unary()(
+		ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (
+			interface{}, error,
+		) {
+			called = true
+			principal, _ = PrincipalFromContext(ctx)
+
+			return nil, nil
+		},
+	)
+
+	return principal, called, err
+}
+
+func testNewInterceptor(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	out := NewInterceptor(keySet, testPolicies)
+
+	assert.Equal(t, &Interceptor{keySet, testPolicies}, out)
+}
+
+func testNewInterceptorWithoutVerifier(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[auth/interceptor] nil verifier", func() {
+			NewInterceptor(nil, testPolicies)
+		},
+	)
+}
+
+func testUnaryPublicMethod(t *testing.T) {
+	interceptor, _ := newTestInterceptor(t)
+
+	principal, called, err := callUnary(interceptor, context.Background(), testServicePrefix+"Public", nil)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Nil(t, principal)
+}
+
+func testUnaryHealthCheck(t *testing.T) {
+	interceptor, _ := newTestInterceptor(t)
+
+	_, called, err := callUnary(interceptor, context.Background(), "/grpc.health.v1.Health/Check", nil)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func testUnaryMethodWithoutPolicy(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+
+	_, called, err := callUnary(
+		interceptor, contextWithToken(t, keySet, "1234", RoleAdmin), testServicePrefix+"Unknown", nil,
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+	assert.False(t, called)
+}
+
+func testUnaryAuthenticatedMethod(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+
+	principal, called, err := callUnary(
+		interceptor, contextWithToken(t, keySet, "1234"), testServicePrefix+"Authenticated", nil,
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, &Principal{Subject: "1234"}, principal)
+}
+
+func testUnaryAuthenticatedMethodWithoutToken(t *testing.T) {
+	interceptor, _ := newTestInterceptor(t)
+
+	_, called, err := callUnary(interceptor, context.Background(), testServicePrefix+"Authenticated", nil)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.False(t, called)
+}
+
+func testUnaryAuthenticatedMethodWithInvalidToken(t *testing.T) {
+	interceptor, _ := newTestInterceptor(t)
+	otherKeySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	_, called, err := callUnary(
+		interceptor, contextWithToken(t, otherKeySet, "1234"), testServicePrefix+"Authenticated", nil,
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, "Invalid access token"))
+	assert.False(t, called)
+}
+
+func testUnarySelfMethod(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+
+	principal, called, err := callUnary(
+		interceptor, contextWithToken(t, keySet, "1234"), testServicePrefix+"Self", &targetedRequest{"1234"},
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, &Principal{Subject: "1234"}, principal)
+}
+
+func testUnarySelfMethodOnOtherUser(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+
+	_, called, err := callUnary(
+		interceptor, contextWithToken(t, keySet, "1234"), testServicePrefix+"Self", &targetedRequest{"5678"},
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+	assert.False(t, called)
+}
+
+func testUnarySelfMethodAsAdmin(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+
+	principal, called, err := callUnary(
+		interceptor, contextWithToken(t, keySet, "1234", RoleAdmin), testServicePrefix+"Self",
+		&targetedRequest{"5678"},
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, &Principal{Subject: "1234", Roles: []string{RoleAdmin}}, principal)
+}
+
+func testUnarySelfMethodWithUntargetedRequest(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+
+	_, called, err := callUnary(
+		interceptor, contextWithToken(t, keySet, "1234"), testServicePrefix+"Self", struct{}{},
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+	assert.False(t, called)
+}
+
+func testUnaryAdminMethod(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+
+	_, called, err := callUnary(
+		interceptor, contextWithToken(t, keySet, "1234", RoleAdmin), testServicePrefix+"Admin", nil,
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func testUnaryAdminMethodAsUser(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+
+	_, called, err := callUnary(
+		interceptor, contextWithToken(t, keySet, "1234", "user"), testServicePrefix+"Admin", nil,
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+	assert.False(t, called)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req interface{}
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	*m.(*targetedRequest) = *s.req.(*targetedRequest)
+
+	return nil
+}
+
+func callStream(interceptor *Interceptor, stream *fakeServerStream, method string) (*Principal, bool, error) {
+	var principal *Principal
+	called := false
+
+	err := interceptor.Stream()(
+		nil, stream, &grpc.StreamServerInfo{FullMethod: method}, func(srv interface{}, stream grpc.ServerStream) error {
+			called = true
+			principal, _ = PrincipalFromContext(stream.Context())
+
+			return stream.RecvMsg(&targetedRequest{})
+		},
+	)
+
+	return principal, called, err
+}
+
+func testStreamAuthenticatedMethod(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+	stream := &fakeServerStream{ctx: contextWithToken(t, keySet, "1234"), req: &targetedRequest{"5678"}}
+
+	principal, called, err := callStream(interceptor, stream, testServicePrefix+"Authenticated")
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, &Principal{Subject: "1234"}, principal)
+}
+
+func testStreamAuthenticatedMethodWithoutToken(t *testing.T) {
+	interceptor, _ := newTestInterceptor(t)
+	stream := &fakeServerStream{ctx: context.Background(), req: &targetedRequest{"1234"}}
+
+	_, called, err := callStream(interceptor, stream, testServicePrefix+"Authenticated")
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.False(t, called)
+}
+
+func testStreamSelfMethodOnOtherUser(t *testing.T) {
+	interceptor, keySet := newTestInterceptor(t)
+	stream := &fakeServerStream{ctx: contextWithToken(t, keySet, "1234"), req: &targetedRequest{"5678"}}
+
+	_, _, err := callStream(interceptor, stream, testServicePrefix+"Self")
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+}
+
+func testPrincipalFromContext(t *testing.T) {
+	principal := &Principal{Subject: "1234", Roles: []string{"user", RoleAdmin}}
+
+	out, ok := PrincipalFromContext(ContextWithPrincipal(context.Background(), principal))
+	_, emptyOk := PrincipalFromContext(context.Background())
+
+	assert.True(t, ok)
+	assert.Same(t, principal, out)
+	assert.True(t, out.IsAdmin())
+	assert.True(t, out.HasRole("user"))
+	assert.False(t, out.HasRole("moderator"))
+	assert.False(t, emptyOk)
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJWT(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"sign and verify a token":                testSignAndVerify,
+		"verify a token signed by a rotated key": testVerifyWithRotatedKey,
+		"verify an expired token":                testVerifyExpiredToken,
+		"verify a token with an unknown key":     testVerifyWithUnknownKey,
+		"verify a tampered token":                testVerifyTamperedToken,
+		"verify a token with another algorithm":  testVerifyWithAnotherAlgorithm,
+		"verify a malformed token":               testVerifyMalformedToken,
+		"verify the issuer of a token":           testVerifyIssuer,
+		"verify a token from another issuer":     testVerifyAnotherIssuer,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func validClaims() Claims {
+	now := time.Now()
+
+	return Claims{
+		Issuer:    "users",
+		Subject:   "1234",
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Minute).Unix(),
+		Id:        "token-id",
+		Roles:     []string{RoleAdmin},
+	}
+}
+
+func testSignAndVerify(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	claims := validClaims()
+	signed, err := keySet.Sign(claims)
+	require.NoError(t, err)
+
+	out, err := keySet.Verify(signed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &claims, out)
+}
+
+func testVerifyWithRotatedKey(t *testing.T) {
+	oldKey := generateKey(t)
+	oldKeySet, err := NewKeySet(oldKey)
+	require.NoError(t, err)
+
+	claims := validClaims()
+	signed, err := oldKeySet.Sign(claims)
+	require.NoError(t, err)
+
+	rotatedKeySet, err := NewKeySet(generateKey(t), oldKey)
+	require.NoError(t, err)
+
+	out, err := rotatedKeySet.Verify(signed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &claims, out)
+}
+
+func testVerifyExpiredToken(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims.ExpiresAt = time.Now().Add(-time.Second).Unix()
+	signed, err := keySet.Sign(claims)
+	require.NoError(t, err)
+
+	out, err := keySet.Verify(signed)
+
+	assert.Equal(t, &InvalidTokenError{Reason: "token expired"}, err)
+	assert.Nil(t, out)
+}
+
+func testVerifyWithUnknownKey(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+	otherKeySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	signed, err := otherKeySet.Sign(validClaims())
+	require.NoError(t, err)
+
+	out, err := keySet.Verify(signed)
+
+	assert.Equal(t, &InvalidTokenError{Reason: "unknown signing key"}, err)
+	assert.Nil(t, out)
+}
+
+func testVerifyTamperedToken(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	signed, err := keySet.Sign(validClaims())
+	require.NoError(t, err)
+
+	parts := strings.Split(signed, ".")
+	tamperedClaims := validClaims()
+	tamperedClaims.Subject = "admin"
+	otherSigned, err := keySet.Sign(tamperedClaims)
+	require.NoError(t, err)
+	parts[1] = strings.Split(otherSigned, ".")[1]
+
+	out, err := keySet.Verify(strings.Join(parts, "."))
+
+	assert.Equal(t, &InvalidTokenError{Reason: "invalid signature"}, err)
+	assert.Nil(t, out)
+}
+
+func testVerifyWithAnotherAlgorithm(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	signed, err := keySet.Sign(validClaims())
+	require.NoError(t, err)
+
+	parts := strings.Split(signed, ".")
+	parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	out, err := keySet.Verify(parts[0] + "." + parts[1] + ".")
+
+	assert.Equal(t, &InvalidTokenError{Reason: "unsupported algorithm"}, err)
+	assert.Nil(t, out)
+}
+
+func testVerifyMalformedToken(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	out, err := keySet.Verify("not-a-token")
+
+	assert.Equal(t, &InvalidTokenError{Reason: "malformed token"}, err)
+	assert.Equal(t, "Invalid token: malformed token", err.Error())
+	assert.Nil(t, out)
+}
+
+func testVerifyIssuer(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	claims := validClaims()
+	signed, err := keySet.Sign(claims)
+	require.NoError(t, err)
+
+	out, err := NewIssuerVerifier(keySet, "users").Verify(signed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &claims, out)
+
+	out, err = NewIssuerVerifier(keySet, "users").Verify("not-a-token")
+
+	assert.Equal(t, &InvalidTokenError{Reason: "malformed token"}, err)
+	assert.Nil(t, out)
+}
+
+func testVerifyAnotherIssuer(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t))
+	require.NoError(t, err)
+
+	for _, issuer := range []string{"billing", ""} {
+		claims := validClaims()
+		claims.Issuer = issuer
+		signed, err := keySet.Sign(claims)
+		require.NoError(t, err)
+
+		out, err := NewIssuerVerifier(keySet, "users").Verify(signed)
+
+		assert.Equal(t, &InvalidTokenError{Reason: "unknown issuer"}, err)
+		assert.Nil(t, out)
+	}
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const signingAlgorithm = "RS256"
+
+/*
+A SigningKey is an RSA private key used to sign tokens, along with its key id.
+
+The key id is the RFC 7638 thumbprint of the public key, so it's stable across
+restarts and every service instance derives the same id from the same PEM file.
+*/
+type SigningKey struct {
+	Id         string
+	privateKey *rsa.PrivateKey
+}
+
+/*
+A KeySet holds the keys the service can sign and verify tokens with.
+
+The first key is the active one, used to sign new tokens. The rest are only kept
+to verify tokens signed before a key rotation, and are published along with the
+active one so other services can keep verifying them too.
+*/
+type KeySet struct {
+	keys []*SigningKey
+}
+
+/*
+JWK is the JSON Web Key representation of a public signing key, as defined in RFC 7517.
+*/
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func NewKeySet(keys ...*rsa.PrivateKey) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("[auth] at least one signing key is required")
+	}
+
+	keySet := &KeySet{}
+	for _, key := range keys {
+		kid, err := thumbprint(&key.PublicKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		keySet.keys = append(keySet.keys, &SigningKey{Id: kid, privateKey: key})
+	}
+
+	return keySet, nil
+}
+
+/*
+LoadKeySetFromPEMFiles reads RSA private keys from the given PEM files, in PKCS #1 or PKCS #8 form.
+
+The first file holds the active signing key.
+*/
+func LoadKeySetFromPEMFiles(paths ...string) (*KeySet, error) {
+	var keys []*rsa.PrivateKey
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+
+		if err != nil {
+			return nil, fmt.Errorf("[auth] reading key file %s: %w", path, err)
+		}
+
+		key, err := ParseRSAPrivateKeyFromPEM(data)
+
+		if err != nil {
+			return nil, fmt.Errorf("[auth] parsing key file %s: %w", path, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(keys...)
+}
+
+/*
+GenerateKeySet creates a key set holding a single, freshly generated key.
+
+It's meant for local development and tests only: tokens signed with it become
+invalid as soon as the process exits.
+*/
+func GenerateKeySet(bits int) (*KeySet, error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySet(key)
+}
+
+func ParseRSAPrivateKeyFromPEM(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rsaKey, ok := key.(*rsa.PrivateKey)
+
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+
+		return rsaKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %s", block.Type)
+	}
+}
+
+/*
+PublicKeys returns the public part of every key in the set, ready to be published as a JWKS.
+*/
+func (ks *KeySet) PublicKeys() []JWK {
+	var jwks []JWK
+
+	for _, key := range ks.keys {
+		jwks = append(jwks, publicJWK(key.Id, &key.privateKey.PublicKey))
+	}
+
+	return jwks
+}
+
+func (ks *KeySet) activeKey() *SigningKey {
+	return ks.keys[0]
+}
+
+func (ks *KeySet) keyById(kid string) *SigningKey {
+	for _, key := range ks.keys {
+		if key.Id == kid {
+			return key
+		}
+	}
+
+	return nil
+}
+
+func publicJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: signingAlgorithm,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+/*
+thumbprint computes the RFC 7638 JWK thumbprint of an RSA public key.
+
+The members are serialized in lexicographical order, which encoding/json already
+does for maps.
+*/
+func thumbprint(key *rsa.PublicKey) (string, error) {
+	jwk := publicJWK("", key)
+
+	data, err := json.Marshal(map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N})
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"new key set": {
+			"create key set":             testNewKeySet,
+			"create key set with no key": testNewKeySetWithoutKeys,
+		},
+		"load key set": {
+			"load PKCS #1 and PKCS #8 keys":  testLoadKeySetFromPEMFiles,
+			"load key set with missing file": testLoadKeySetWithMissingFile,
+			"load key set with invalid file": testLoadKeySetWithInvalidFile,
+		},
+		"public keys": {
+			"publish every key of the set": testPublicKeys,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							t.Parallel()
+
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	return key
+}
+
+func writePEM(t *testing.T, dir string, name string, block *pem.Block) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0600))
+
+	return path
+}
+
+func testNewKeySet(t *testing.T) {
+	key := generateKey(t)
+
+	keySet, err := NewKeySet(key)
+
+	assert.NoError(t, err)
+	assert.Len(t, keySet.keys, 1)
+	assert.Same(t, key, keySet.activeKey().privateKey)
+	assert.NotEmpty(t, keySet.activeKey().Id)
+}
+
+func testNewKeySetWithoutKeys(t *testing.T) {
+	keySet, err := NewKeySet()
+
+	assert.Error(t, err)
+	assert.Nil(t, keySet)
+}
+
+func testLoadKeySetFromPEMFiles(t *testing.T) {
+	dir := t.TempDir()
+	pkcs1Key := generateKey(t)
+	pkcs8Key := generateKey(t)
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(pkcs8Key)
+	require.NoError(t, err)
+
+	pkcs1Path := writePEM(
+		t, dir, "pkcs1.pem", &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(pkcs1Key)},
+	)
+	pkcs8Path := writePEM(t, dir, "pkcs8.pem", &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	keySet, err := LoadKeySetFromPEMFiles(pkcs1Path, pkcs8Path)
+
+	assert.NoError(t, err)
+	assert.Len(t, keySet.keys, 2)
+	assert.True(t, pkcs1Key.Equal(keySet.keys[0].privateKey))
+	assert.True(t, pkcs8Key.Equal(keySet.keys[1].privateKey))
+}
+
+func testLoadKeySetWithMissingFile(t *testing.T) {
+	keySet, err := LoadKeySetFromPEMFiles(filepath.Join(t.TempDir(), "missing.pem"))
+
+	assert.Error(t, err)
+	assert.Nil(t, keySet)
+}
+
+func testLoadKeySetWithInvalidFile(t *testing.T) {
+	path := writePEM(t, t.TempDir(), "invalid.pem", &pem.Block{Type: "CERTIFICATE", Bytes: []byte("nope")})
+
+	keySet, err := LoadKeySetFromPEMFiles(path)
+
+	assert.Error(t, err)
+	assert.Nil(t, keySet)
+}
+
+func testPublicKeys(t *testing.T) {
+	keySet, err := NewKeySet(generateKey(t), generateKey(t))
+	require.NoError(t, err)
+
+	jwks := keySet.PublicKeys()
+
+	assert.Len(t, jwks, 2)
+	for i, jwk := range jwks {
+		assert.Equal(t, keySet.keys[i].Id, jwk.Kid)
+		assert.Equal(t, "RSA", jwk.Kty)
+		assert.Equal(t, "sig", jwk.Use)
+		assert.Equal(t, "RS256", jwk.Alg)
+		assert.Equal(t, "AQAB", jwk.E)
+		assert.NotEmpty(t, jwk.N)
+	}
+	assert.NotEqual(t, jwks[0].Kid, jwks[1].Kid)
+}
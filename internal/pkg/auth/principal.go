@@ -0,0 +1,42 @@
+package auth
+
+import "context"
+
+const RoleAdmin = "admin"
+
+/*
+Principal is the authenticated caller of a request, as stated by its access token.
+*/
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+/*
+PrincipalFromContext returns the caller of the request, if it was authenticated.
+*/
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+
+	return principal, ok
+}
@@ -0,0 +1,40 @@
+package errors
+
+/*
+Code classifies errors by what the client can do about them, independently of the transport used to report them.
+
+Every port translates codes into its own terms, like gRPC status codes, so the
+same domain error always gets the same answer.
+*/
+type Code int
+
+const (
+	// CodeUnknown is the code of every error nobody registered. Clients never see its details.
+	CodeUnknown Code = iota
+	CodeInvalidArgument
+	CodeNotFound
+	CodeAlreadyExists
+	CodePermissionDenied
+	CodeUnauthenticated
+	// CodeAborted is the code of the operations that conflicted with a concurrent one, and can be retried from scratch.
+	CodeAborted
+)
+
+func (c Code) String() string {
+	switch c {
+	case CodeInvalidArgument:
+		return "INVALID_ARGUMENT"
+	case CodeNotFound:
+		return "NOT_FOUND"
+	case CodeAlreadyExists:
+		return "ALREADY_EXISTS"
+	case CodePermissionDenied:
+		return "PERMISSION_DENIED"
+	case CodeUnauthenticated:
+		return "UNAUTHENTICATED"
+	case CodeAborted:
+		return "ABORTED"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -2,6 +2,23 @@ package errors
 
 import "fmt"
 
+/*
+Machine-readable reasons shared by every domain, telling clients why a field was rejected.
+
+Domains can define their own reasons on top of these, following the same
+UPPER_SNAKE_CASE format.
+*/
+const (
+	ReasonRequired          = "REQUIRED"
+	ReasonInvalidFormat     = "INVALID_FORMAT"
+	ReasonInvalidCharacters = "INVALID_CHARACTERS"
+	ReasonTooShort          = "TOO_SHORT"
+	ReasonTooLong           = "TOO_LONG"
+	ReasonOutOfRange        = "OUT_OF_RANGE"
+	ReasonUnknownValue      = "UNKNOWN_VALUE"
+	ReasonImmutable         = "IMMUTABLE"
+)
+
 type Unknown struct {
 	Tag   string
 	Cause error
@@ -11,14 +28,35 @@ func (e *Unknown) Error() string {
 	return fmt.Sprintf("[%s] Unknown error. Caused by: %s", e.Tag, e.Cause.Error())
 }
 
+func (e *Unknown) Unwrap() error {
+	return e.Cause
+}
+
+/*
+InvalidField is returned when a field doesn't comply with the rules of its domain.
+
+Value is left nil for sensitive fields, like passwords, so they never end up in
+the logs.
+*/
 type InvalidField struct {
 	Domain string
 	Field  string
 	Value  interface{}
+	Reason string
 }
 
 func (e *InvalidField) Error() string {
-	return fmt.Sprintf("[%s] Invalid field %s with value %v", e.Domain, e.Field, e.Value)
+	msg := fmt.Sprintf("[%s] Invalid field %s", e.Domain, e.Field)
+
+	if e.Value != nil {
+		msg += fmt.Sprintf(" with value %v", e.Value)
+	}
+
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+
+	return msg
 }
 
 type MultipleInvalidFields struct {
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"unknown error message":                   testUnknownError,
+		"invalid field message":                   testInvalidFieldError,
+		"invalid field message with hidden value": testInvalidFieldErrorWithoutValue,
+		"invalid field message without reason":    testInvalidFieldErrorWithoutReason,
+		"multiple invalid fields message":         testMultipleInvalidFieldsError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testUnknownError(t *testing.T) {
+	err := &Unknown{Tag: "User", Cause: errors.New("db is down")}
+
+	assert.Equal(t, "[User] Unknown error. Caused by: db is down", err.Error())
+}
+
+func testInvalidFieldError(t *testing.T) {
+	err := &InvalidField{Domain: "User", Field: "email", Value: "foo", Reason: ReasonInvalidFormat}
+
+	assert.Equal(t, "[User] Invalid field email with value foo: INVALID_FORMAT", err.Error())
+}
+
+func testInvalidFieldErrorWithoutValue(t *testing.T) {
+	err := &InvalidField{Domain: "User", Field: "password", Reason: ReasonTooShort}
+
+	assert.Equal(t, "[User] Invalid field password: TOO_SHORT", err.Error())
+}
+
+func testInvalidFieldErrorWithoutReason(t *testing.T) {
+	err := &InvalidField{Domain: "User", Field: "email", Value: ""}
+
+	assert.Equal(t, "[User] Invalid field email with value ", err.Error())
+}
+
+func testMultipleInvalidFieldsError(t *testing.T) {
+	err := &MultipleInvalidFields{
+		Errors: []error{
+			&InvalidField{Domain: "User", Field: "email", Value: "", Reason: ReasonRequired},
+			&InvalidField{Domain: "User", Field: "password", Reason: ReasonRequired},
+		},
+	}
+
+	assert.Equal(
+		t, "Multiple errors: [[User] Invalid field email with value : REQUIRED [User] Invalid field password: REQUIRED]",
+		err.Error(),
+	)
+}
@@ -0,0 +1,214 @@
+package errors
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+const (
+	// ReasonInvalidField is the ErrorInfo reason used when the rejected field doesn't tell its own reason.
+	ReasonInvalidField = "INVALID_FIELD"
+	// ReasonMultipleInvalidFields is the ErrorInfo reason used when more than one field is rejected at once.
+	ReasonMultipleInvalidFields = "MULTIPLE_INVALID_FIELDS"
+)
+
+// InternalMessage is the message clients get for the errors we can't tell them anything about.
+const InternalMessage = "Internal error"
+
+/*
+GRPCCode returns the gRPC status code clients get for the errors with this code.
+*/
+func (c Code) GRPCCode() codes.Code {
+	switch c {
+	case CodeInvalidArgument:
+		return codes.InvalidArgument
+	case CodeNotFound:
+		return codes.NotFound
+	case CodeAlreadyExists:
+		return codes.AlreadyExists
+	case CodePermissionDenied:
+		return codes.PermissionDenied
+	case CodeUnauthenticated:
+		return codes.Unauthenticated
+	case CodeAborted:
+		return codes.Aborted
+	default:
+		return codes.Internal
+	}
+}
+
+/*
+ToStatus translates err into a gRPC status error, using the registered error found in its chain.
+
+Errors which already are statuses are returned as they are, while unknown errors
+become an Internal status with a generic message, so their details don't leak to
+the clients. Invalid fields carry their details, as MapInvalidFieldsToStatus does,
+and the rest get their ClientMessage.
+*/
+func (r *Registry) ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	found, code := r.Find(err)
+
+	switch code {
+	case CodeUnknown:
+		return status.Error(codes.Internal, InternalMessage)
+	case CodeInvalidArgument:
+		return MapInvalidFieldsToStatus(found)
+	default:
+		return status.Error(code.GRPCCode(), r.ClientMessage(err))
+	}
+}
+
+/*
+ToStatus translates err into a gRPC status error according to the DefaultRegistry.
+*/
+func ToStatus(err error) error {
+	return DefaultRegistry.ToStatus(err)
+}
+
+/*
+MapInvalidFieldsToStatus builds an InvalidArgument status out of an InvalidField or MultipleInvalidFields error.
+
+The message stays the same as the error, while the details hold:
+  - A google.rpc.BadRequest with a violation for every rejected field, so
+    clients can point at the exact field without parsing the message.
+  - A google.rpc.ErrorInfo whose reason is the one of the field (INVALID_FIELD
+    if it has none), or MULTIPLE_INVALID_FIELDS if there are several, and whose
+    metadata maps every rejected field to its reasons, separated by commas.
+*/
+func MapInvalidFieldsToStatus(err error) error {
+	var invalidFields []*InvalidField
+
+	switch castErr := err.(type) {
+	case *InvalidField:
+		invalidFields = append(invalidFields, castErr)
+	case *MultipleInvalidFields:
+		for _, fieldErr := range castErr.Errors {
+			var invalidField *InvalidField
+
+			if As(fieldErr, &invalidField) {
+				invalidFields = append(invalidFields, invalidField)
+			}
+		}
+	}
+
+	st := status.New(codes.InvalidArgument, err.Error())
+
+	if len(invalidFields) == 0 {
+		return st.Err()
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	metadata := map[string]string{}
+
+	for _, invalidField := range invalidFields {
+		badRequest.FieldViolations = append(
+			badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       invalidField.Field,
+				Description: invalidField.Error(),
+			},
+		)
+
+		if invalidField.Reason == "" {
+			continue
+		}
+
+		if reasons, ok := metadata[invalidField.Field]; ok {
+			metadata[invalidField.Field] = strings.Join([]string{reasons, invalidField.Reason}, ",")
+		} else {
+			metadata[invalidField.Field] = invalidField.Reason
+		}
+	}
+
+	errorInfo := &errdetails.ErrorInfo{
+		Reason:   invalidFields[0].Reason,
+		Domain:   invalidFields[0].Domain,
+		Metadata: metadata,
+	}
+
+	if errorInfo.Reason == "" {
+		errorInfo.Reason = ReasonInvalidField
+	}
+
+	if len(invalidFields) > 1 {
+		errorInfo.Reason = ReasonMultipleInvalidFields
+	}
+
+	detailed, detailsErr := st.WithDetails(badRequest, errorInfo)
+
+	// This can only fail if the details can't be marshalled, so we fall back to the plain status.
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+const interceptorTag = "errors/interceptor"
+
+/*
+Interceptor translates every error returned by the gRPC handlers into a status, using its registry.
+
+This way, handlers can just return the errors of the application, and those
+nobody registered are logged and hidden from the clients.
+*/
+type Interceptor struct {
+	registry *Registry
+}
+
+func NewInterceptor(registry *Registry) *Interceptor {
+	if registry == nil {
+		panic("[errors/interceptor] nil registry")
+	}
+
+	return &Interceptor{registry}
+}
+
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+
+		return resp, i.translate(info.FullMethod, err)
+	}
+}
+
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return i.translate(info.FullMethod, handler(srv, stream))
+	}
+}
+
+func (i *Interceptor) translate(fullMethod string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if _, code := i.registry.Find(err); code == CodeUnknown {
+		logrus.WithFields(
+			logrus.Fields{
+				"tag":    interceptorTag,
+				"method": fullMethod,
+			},
+		).WithError(err).Error("Unknown error returned by handler")
+	}
+
+	return i.registry.ToStatus(err)
+}
@@ -0,0 +1,263 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestGrpcErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should map an invalid field":             testMapInvalidFieldToStatus,
+		"should map multiple invalid fields":      testMapMultipleInvalidFieldsToStatus,
+		"should map an invalid field w/o reason":  testMapInvalidFieldWithoutReasonToStatus,
+		"should map other errors without details": testMapOtherErrorToStatus,
+		"should translate registered errors":      testToStatusRegisteredError,
+		"should translate wrapped errors":         testToStatusWrappedError,
+		"should tell the registered message":      testToStatusRegisteredMessage,
+		"should translate wrapped invalid fields": testToStatusWrappedInvalidField,
+		"should hide unknown errors":              testToStatusUnknownError,
+		"should keep statuses as they are":        testToStatusStatusError,
+		"should keep nil errors":                  testToStatusNilError,
+		"should map every code to gRPC":           testGRPCCode,
+		"should create an interceptor":            testNewInterceptor,
+		"should panic without registry":           testNewInterceptorWithoutRegistry,
+		"should translate unary errors":           testUnaryInterceptor,
+		"should translate stream errors":          testStreamInterceptor,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func statusDetails(t *testing.T, err error) (*status.Status, *errdetails.BadRequest, *errdetails.ErrorInfo) {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	var badRequest *errdetails.BadRequest
+	var errorInfo *errdetails.ErrorInfo
+
+	for _, detail := range st.Details() {
+		switch castDetail := detail.(type) {
+		case *errdetails.BadRequest:
+			badRequest = castDetail
+		case *errdetails.ErrorInfo:
+			errorInfo = castDetail
+		}
+	}
+
+	return st, badRequest, errorInfo
+}
+
+func testMapInvalidFieldToStatus(t *testing.T) {
+	invalidErr := &InvalidField{
+		Domain: "User",
+		Field:  "email",
+		Value:  "foo",
+		Reason: ReasonInvalidFormat,
+	}
+
+	st, badRequest, errorInfo := statusDetails(t, MapInvalidFieldsToStatus(invalidErr))
+
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, invalidErr.Error(), st.Message())
+
+	require.NotNil(t, badRequest)
+	require.Len(t, badRequest.FieldViolations, 1)
+	assert.Equal(t, "email", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, invalidErr.Error(), badRequest.FieldViolations[0].Description)
+
+	require.NotNil(t, errorInfo)
+	assert.Equal(t, ReasonInvalidFormat, errorInfo.Reason)
+	assert.Equal(t, "User", errorInfo.Domain)
+	assert.Equal(t, map[string]string{"email": ReasonInvalidFormat}, errorInfo.Metadata)
+}
+
+func testMapMultipleInvalidFieldsToStatus(t *testing.T) {
+	invalidErr := &MultipleInvalidFields{
+		Errors: []error{
+			&InvalidField{Domain: "User", Field: "nickname", Value: "", Reason: ReasonRequired},
+			&InvalidField{Domain: "User", Field: "password", Reason: ReasonTooShort},
+			&InvalidField{Domain: "User", Field: "password", Reason: "MISSING_DIGIT"},
+		},
+	}
+
+	st, badRequest, errorInfo := statusDetails(t, MapInvalidFieldsToStatus(invalidErr))
+
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, invalidErr.Error(), st.Message())
+
+	require.NotNil(t, badRequest)
+	require.Len(t, badRequest.FieldViolations, 3)
+	assert.Equal(t, "nickname", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, "password", badRequest.FieldViolations[1].Field)
+	assert.Equal(t, "password", badRequest.FieldViolations[2].Field)
+
+	require.NotNil(t, errorInfo)
+	assert.Equal(t, ReasonMultipleInvalidFields, errorInfo.Reason)
+	assert.Equal(
+		t, map[string]string{
+			"nickname": ReasonRequired,
+			"password": ReasonTooShort + ",MISSING_DIGIT",
+		}, errorInfo.Metadata,
+	)
+}
+
+func testMapInvalidFieldWithoutReasonToStatus(t *testing.T) {
+	invalidErr := &InvalidField{Domain: "User", Field: "email", Value: ""}
+
+	st, badRequest, errorInfo := statusDetails(t, MapInvalidFieldsToStatus(invalidErr))
+
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.NotNil(t, badRequest)
+	assert.Len(t, badRequest.FieldViolations, 1)
+	require.NotNil(t, errorInfo)
+	assert.Equal(t, ReasonInvalidField, errorInfo.Reason)
+	assert.Empty(t, errorInfo.Metadata)
+}
+
+func testMapOtherErrorToStatus(t *testing.T) {
+	st, badRequest, errorInfo := statusDetails(t, MapInvalidFieldsToStatus(&MultipleInvalidFields{}))
+
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Nil(t, badRequest)
+	assert.Nil(t, errorInfo)
+}
+
+func testToStatusRegisteredError(t *testing.T) {
+	err := newTestRegistry().ToStatus(&testNotFound{Id: "1"})
+
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "Test with id 1 not found"))
+}
+
+func testToStatusWrappedError(t *testing.T) {
+	err := newTestRegistry().ToStatus(fmt.Errorf("getting user: %w", &testNotFound{Id: "1"}))
+
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "Test with id 1 not found"))
+}
+
+func testToStatusRegisteredMessage(t *testing.T) {
+	err := newTestRegistry().ToStatus(fmt.Errorf("updating test: %w", &testConflict{Id: "1"}))
+
+	assert.ErrorIs(t, err, status.Error(codes.Aborted, "Test changed"))
+}
+
+func testToStatusWrappedInvalidField(t *testing.T) {
+	invalidErr := &InvalidField{Domain: "User", Field: "email", Value: "foo", Reason: ReasonInvalidFormat}
+
+	err := newTestRegistry().ToStatus(fmt.Errorf("creating user: %w", invalidErr))
+
+	assert.ErrorIs(t, err, MapInvalidFieldsToStatus(invalidErr))
+}
+
+func testToStatusUnknownError(t *testing.T) {
+	err := newTestRegistry().ToStatus(errors.New("db is down"))
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, InternalMessage))
+}
+
+func testToStatusStatusError(t *testing.T) {
+	statusErr := status.Error(codes.Unavailable, "Try again later")
+
+	assert.Equal(t, statusErr, newTestRegistry().ToStatus(statusErr))
+}
+
+func testToStatusNilError(t *testing.T) {
+	assert.NoError(t, newTestRegistry().ToStatus(nil))
+}
+
+func testGRPCCode(t *testing.T) {
+	for code, grpcCode := range map[Code]codes.Code{
+		CodeUnknown:          codes.Internal,
+		CodeInvalidArgument:  codes.InvalidArgument,
+		CodeNotFound:         codes.NotFound,
+		CodeAlreadyExists:    codes.AlreadyExists,
+		CodePermissionDenied: codes.PermissionDenied,
+		CodeUnauthenticated:  codes.Unauthenticated,
+		CodeAborted:          codes.Aborted,
+	} {
+		assert.Equal(t, grpcCode, code.GRPCCode())
+	}
+}
+
+func testNewInterceptor(t *testing.T) {
+	registry := NewRegistry()
+
+	interceptor := NewInterceptor(registry)
+
+	assert.Equal(t, &Interceptor{registry}, interceptor)
+}
+
+func testNewInterceptorWithoutRegistry(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[errors/interceptor] nil registry", func() {
+			NewInterceptor(nil)
+		},
+	)
+}
+
+func testUnaryInterceptor(t *testing.T) {
+	interceptor := NewInterceptor(newTestRegistry()).Unary()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	resp, err := interceptor(
+		context.Background(), "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "response", nil
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "response", resp)
+
+	_, err = interceptor(
+		context.Background(), "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, &testForbidden{}
+		},
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Forbidden"))
+
+	_, err = interceptor(
+		context.Background(), "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errors.New("db is down")
+		},
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.Internal, InternalMessage))
+}
+
+func testStreamInterceptor(t *testing.T) {
+	interceptor := NewInterceptor(newTestRegistry()).Stream()
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+
+	err := interceptor(
+		nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		},
+	)
+
+	assert.NoError(t, err)
+
+	err = interceptor(
+		nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+			return fmt.Errorf("streaming: %w", &testNotFound{Id: "1"})
+		},
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "Test with id 1 not found"))
+}
@@ -0,0 +1,131 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"sync"
+)
+
+/*
+A Registry maps error types to their codes, so every port reports them the same way.
+
+Errors are matched with errors.As, so they are found even when wrapped with
+fmt.Errorf and %w. When an error chain matches several registered types, the
+first one registered wins. Types can be registered with a fixed message for the
+clients, for errors holding what they shouldn't be told back, like ids.
+*/
+type Registry struct {
+	mu       sync.RWMutex
+	matchers []matcher
+}
+
+/*
+matcher looks for its error type in the chain, returning the matching error, its code and its client message.
+*/
+type matcher func(err error) (error, Code, string, bool)
+
+/*
+NewRegistry returns a registry that already knows about the errors of this package.
+*/
+func NewRegistry() *Registry {
+	registry := &Registry{}
+
+	RegisterTo[*InvalidField](registry, CodeInvalidArgument, "")
+	RegisterTo[*MultipleInvalidFields](registry, CodeInvalidArgument, "")
+
+	return registry
+}
+
+/*
+DefaultRegistry is the registry used by the package level functions. Domains register their errors here on init.
+*/
+var DefaultRegistry = NewRegistry()
+
+/*
+Register binds the error type T, usually a pointer to a struct, to the given code in the DefaultRegistry.
+
+Clients are told the given message instead of the error's own one, unless it's
+empty.
+*/
+func Register[T error](code Code, message string) {
+	RegisterTo[T](DefaultRegistry, code, message)
+}
+
+/*
+RegisterTo binds the error type T to the given code and client message in the given registry.
+*/
+func RegisterTo[T error](registry *Registry, code Code, message string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.matchers = append(
+		registry.matchers, func(err error) (error, Code, string, bool) {
+			var target T
+
+			if stdErrors.As(err, &target) {
+				return target, code, message, true
+			}
+
+			return nil, CodeUnknown, "", false
+		},
+	)
+}
+
+/*
+Find returns the registered error found in the chain of err, along with its code.
+
+If none is found, err itself is returned with CodeUnknown.
+*/
+func (r *Registry) Find(err error) (error, Code) {
+	found, code, _ := r.find(err)
+
+	return found, code
+}
+
+/*
+ClientMessage returns the message clients are told about err: the one it was registered with, or its own otherwise.
+*/
+func (r *Registry) ClientMessage(err error) string {
+	found, _, message := r.find(err)
+
+	if message == "" {
+		return found.Error()
+	}
+
+	return message
+}
+
+func (r *Registry) find(err error) (error, Code, string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, match := range r.matchers {
+		if found, code, message, ok := match(err); ok {
+			return found, code, message
+		}
+	}
+
+	return err, CodeUnknown, ""
+}
+
+/*
+CodeOf returns the code of err according to the DefaultRegistry.
+*/
+func CodeOf(err error) Code {
+	_, code := DefaultRegistry.Find(err)
+
+	return code
+}
+
+/*
+As is errors.As from the standard library, so packages using ours don't need to alias any of them.
+*/
+func As(err error, target interface{}) bool {
+	return stdErrors.As(err, target)
+}
+
+/*
+Is is errors.Is from the standard library, so packages using ours don't need to alias any of them.
+*/
+func Is(err, target error) bool {
+	return stdErrors.Is(err, target)
+}
@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testNotFound struct {
+	Id string
+}
+
+func (e *testNotFound) Error() string {
+	return fmt.Sprintf("Test with id %s not found", e.Id)
+}
+
+type testForbidden struct{}
+
+func (e *testForbidden) Error() string {
+	return "Forbidden"
+}
+
+type testConflict struct {
+	Id string
+}
+
+func (e *testConflict) Error() string {
+	return fmt.Sprintf("Test with id %s changed", e.Id)
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should find a registered error":         testFindRegisteredError,
+		"should find a wrapped registered error": testFindWrappedError,
+		"should find the first registered error": testFindFirstRegisteredError,
+		"should not find an unregistered error":  testFindUnregisteredError,
+		"should know about invalid fields":       testFindInvalidFields,
+		"should unwrap unknown errors":           testFindUnknownCause,
+		"should tell the registered message":     testClientMessage,
+		"should tell the message of the error":   testClientMessageOfError,
+		"should name every code":                 testCodeString,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func newTestRegistry() *Registry {
+	registry := NewRegistry()
+
+	RegisterTo[*testNotFound](registry, CodeNotFound, "")
+	RegisterTo[*testForbidden](registry, CodePermissionDenied, "")
+	RegisterTo[*testConflict](registry, CodeAborted, "Test changed")
+
+	return registry
+}
+
+func testFindRegisteredError(t *testing.T) {
+	err := &testNotFound{Id: "1"}
+
+	found, code := newTestRegistry().Find(err)
+
+	assert.Equal(t, CodeNotFound, code)
+	assert.Same(t, err, found)
+}
+
+func testFindWrappedError(t *testing.T) {
+	err := &testNotFound{Id: "1"}
+
+	found, code := newTestRegistry().Find(fmt.Errorf("getting user: %w", err))
+
+	assert.Equal(t, CodeNotFound, code)
+	assert.Same(t, err, found)
+}
+
+func testFindFirstRegisteredError(t *testing.T) {
+	err := &testForbidden{}
+
+	found, code := newTestRegistry().Find(fmt.Errorf("%w: %v", err, &testNotFound{Id: "1"}))
+
+	assert.Equal(t, CodePermissionDenied, code)
+	assert.Same(t, err, found)
+}
+
+func testFindUnregisteredError(t *testing.T) {
+	err := errors.New("db is down")
+
+	found, code := newTestRegistry().Find(err)
+
+	assert.Equal(t, CodeUnknown, code)
+	assert.Equal(t, err, found)
+}
+
+func testFindInvalidFields(t *testing.T) {
+	assert.Equal(t, CodeInvalidArgument, CodeOf(&InvalidField{Domain: "User", Field: "email"}))
+	assert.Equal(t, CodeInvalidArgument, CodeOf(&MultipleInvalidFields{}))
+}
+
+func testFindUnknownCause(t *testing.T) {
+	err := &testNotFound{Id: "1"}
+
+	_, code := newTestRegistry().Find(&Unknown{Tag: "User", Cause: err})
+
+	assert.Equal(t, CodeNotFound, code)
+}
+
+func testClientMessage(t *testing.T) {
+	err := fmt.Errorf("updating test: %w", &testConflict{Id: "1"})
+
+	assert.Equal(t, "Test changed", newTestRegistry().ClientMessage(err))
+}
+
+func testClientMessageOfError(t *testing.T) {
+	registry := newTestRegistry()
+
+	assert.Equal(t, "Test with id 1 not found", registry.ClientMessage(&testNotFound{Id: "1"}))
+	assert.Equal(t, "db is down", registry.ClientMessage(errors.New("db is down")))
+}
+
+func testCodeString(t *testing.T) {
+	for code, name := range map[Code]string{
+		CodeUnknown:          "UNKNOWN",
+		CodeInvalidArgument:  "INVALID_ARGUMENT",
+		CodeNotFound:         "NOT_FOUND",
+		CodeAlreadyExists:    "ALREADY_EXISTS",
+		CodePermissionDenied: "PERMISSION_DENIED",
+		CodeUnauthenticated:  "UNAUTHENTICATED",
+		CodeAborted:          "ABORTED",
+	} {
+		assert.Equal(t, name, code.String())
+	}
+}
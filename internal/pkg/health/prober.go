@@ -0,0 +1,234 @@
+package health
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+/*
+Status is the serving status of a service, as last seen by the Prober.
+*/
+type Status int
+
+const (
+	// StatusUnknown is the status of the services that haven't been probed yet.
+	StatusUnknown Status = iota
+	StatusServing
+	StatusNotServing
+	// StatusServiceUnknown is the status of the services the Prober doesn't know about.
+	StatusServiceUnknown
+)
+
+// OverallService is the name of the service aggregating every dependency, as in the gRPC health checking protocol.
+const OverallService = ""
+
+const proberTag = "health/prober"
+
+/*
+A Prober pings the dependencies of a service in the background, keeping a registry with the status of each one.
+
+The overall status, under OverallService, is serving only if every dependency
+is. Anyone interested in the changes can subscribe to them with Watch.
+*/
+type Prober struct {
+	dependencies map[string]func(ctx context.Context) error
+	interval     time.Duration
+	timeout      time.Duration
+
+	mu           sync.Mutex
+	statuses     map[string]Status
+	watchers     map[string]map[chan Status]struct{}
+	shuttingDown bool
+}
+
+func NewProber(
+	dependencies map[string]func(ctx context.Context) error, interval time.Duration, timeout time.Duration,
+) *Prober {
+	if interval <= 0 {
+		panic("[health/prober] non-positive interval")
+	}
+
+	if timeout <= 0 {
+		panic("[health/prober] non-positive timeout")
+	}
+
+	return &Prober{
+		dependencies: dependencies,
+		interval:     interval,
+		timeout:      timeout,
+		statuses:     map[string]Status{},
+		watchers:     map[string]map[chan Status]struct{}{},
+	}
+}
+
+/*
+Run probes the dependencies right away, and then once every interval, until the context is done.
+*/
+func (p *Prober) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.Probe(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+Probe pings every dependency once, updating their statuses and notifying the watchers of the ones that changed.
+*/
+func (p *Prober) Probe(ctx context.Context) {
+	results := map[string]Status{OverallService: StatusServing}
+
+	for name, ping := range p.dependencies {
+		pingCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		err := ping(pingCtx)
+		cancel()
+
+		if err != nil {
+			logrus.WithFields(
+				logrus.Fields{
+					"tag":     proberTag,
+					"service": name,
+				},
+			).WithError(err).Warn("Dependency is not serving")
+
+			results[name] = StatusNotServing
+			results[OverallService] = StatusNotServing
+
+			continue
+		}
+
+		results[name] = StatusServing
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The statuses are frozen once shutting down, so a late probe can't bring them back.
+	if p.shuttingDown {
+		return
+	}
+
+	for name, status := range results {
+		p.setStatus(name, status)
+	}
+}
+
+/*
+Status returns the last known status of the given service.
+*/
+func (p *Prober) Status(service string) Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.status(service)
+}
+
+/*
+Watch subscribes to the status of the given service.
+
+The returned channel receives the current status right away, and then every
+change. Only the latest status is kept, so slow readers skip the intermediate
+ones. It's closed once the prober shuts down, so watchers can end their streams.
+The returned function cancels the subscription, and must always be called.
+*/
+func (p *Prober) Watch(service string) (<-chan Status, func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	watcher := make(chan Status, 1)
+	watcher <- p.status(service)
+
+	if p.shuttingDown {
+		close(watcher)
+
+		return watcher, func() {}
+	}
+
+	if p.watchers[service] == nil {
+		p.watchers[service] = map[chan Status]struct{}{}
+	}
+
+	p.watchers[service][watcher] = struct{}{}
+
+	return watcher, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		delete(p.watchers[service], watcher)
+	}
+}
+
+/*
+Shutdown sets every service as not serving for good, notifying their watchers, and closes every subscription.
+*/
+func (p *Prober) Shutdown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.shuttingDown {
+		return
+	}
+
+	p.shuttingDown = true
+
+	p.setStatus(OverallService, StatusNotServing)
+	for name := range p.dependencies {
+		p.setStatus(name, StatusNotServing)
+	}
+
+	for _, watchers := range p.watchers {
+		for watcher := range watchers {
+			close(watcher)
+		}
+	}
+
+	p.watchers = map[string]map[chan Status]struct{}{}
+}
+
+/*
+ShuttingDown tells whether Shutdown has been called.
+*/
+func (p *Prober) ShuttingDown() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.shuttingDown
+}
+
+func (p *Prober) status(service string) Status {
+	if _, ok := p.dependencies[service]; !ok && service != OverallService {
+		return StatusServiceUnknown
+	}
+
+	return p.statuses[service]
+}
+
+/*
+setStatus updates the status of the service, notifying its watchers if it changed. It must be called with the lock.
+*/
+func (p *Prober) setStatus(service string, status Status) {
+	if current, ok := p.statuses[service]; ok && current == status {
+		return
+	}
+
+	p.statuses[service] = status
+
+	for watcher := range p.watchers[service] {
+		// Drop the status the watcher didn't read yet, if any, so sending never blocks.
+		select {
+		case <-watcher:
+		default:
+		}
+
+		watcher <- status
+	}
+}
@@ -0,0 +1,283 @@
+package health
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestProber(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should create a prober":                      testNewProber,
+		"should panic without interval":               testNewProberWithoutInterval,
+		"should panic without timeout":                testNewProberWithoutTimeout,
+		"should start with unknown statuses":          testStatusBeforeProbe,
+		"should report serving dependencies":          testProbeServing,
+		"should report failing dependencies":          testProbeNotServing,
+		"should time out slow dependencies":           testProbeTimeout,
+		"should report unknown services":              testStatusUnknownService,
+		"should notify watchers of changes":           testWatchChanges,
+		"should only keep the latest status":          testWatchLatestStatus,
+		"should stop notifying canceled watchers":     testWatchCancel,
+		"should stop serving on shutdown":             testShutdown,
+		"should close watchers on shutdown":           testShutdownClosesWatchers,
+		"should ignore probes after shutdown":         testProbeAfterShutdown,
+		"should close new watchers after shutdown":    testWatchAfterShutdown,
+		"should probe periodically until it's done":   testRun,
+		"should do nothing when shutting down twice":  testShutdownTwice,
+		"should notify nothing if the status is kept": testProbeWithoutChanges,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func ping(err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return err
+	}
+}
+
+/*
+switchablePing pings with the error stored in the returned pointer, so tests can change it between probes.
+*/
+func switchablePing() (func(ctx context.Context) error, *error) {
+	var err error
+
+	return func(ctx context.Context) error {
+		return err
+	}, &err
+}
+
+func testNewProber(t *testing.T) {
+	dependencies := map[string]func(ctx context.Context) error{"test": ping(nil)}
+
+	out := NewProber(dependencies, time.Minute, time.Second)
+
+	assert.Equal(t, time.Minute, out.interval)
+	assert.Equal(t, time.Second, out.timeout)
+	assert.Len(t, out.dependencies, 1)
+	assert.Empty(t, out.statuses)
+	assert.Empty(t, out.watchers)
+}
+
+func testNewProberWithoutInterval(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[health/prober] non-positive interval", func() {
+			NewProber(nil, 0, time.Second)
+		},
+	)
+}
+
+func testNewProberWithoutTimeout(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[health/prober] non-positive timeout", func() {
+			NewProber(nil, time.Minute, 0)
+		},
+	)
+}
+
+func testStatusBeforeProbe(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	assert.Equal(t, StatusUnknown, prober.Status(OverallService))
+	assert.Equal(t, StatusUnknown, prober.Status("test"))
+}
+
+func testProbeServing(t *testing.T) {
+	prober := NewProber(
+		map[string]func(ctx context.Context) error{"test1": ping(nil), "test2": ping(nil)}, time.Minute, time.Second,
+	)
+
+	prober.Probe(context.Background())
+
+	assert.Equal(t, StatusServing, prober.Status(OverallService))
+	assert.Equal(t, StatusServing, prober.Status("test1"))
+	assert.Equal(t, StatusServing, prober.Status("test2"))
+}
+
+func testProbeNotServing(t *testing.T) {
+	prober := NewProber(
+		map[string]func(ctx context.Context) error{"test1": ping(nil), "test2": ping(errors.New("dependency down"))},
+		time.Minute, time.Second,
+	)
+
+	prober.Probe(context.Background())
+
+	assert.Equal(t, StatusNotServing, prober.Status(OverallService))
+	assert.Equal(t, StatusServing, prober.Status("test1"))
+	assert.Equal(t, StatusNotServing, prober.Status("test2"))
+}
+
+func testProbeTimeout(t *testing.T) {
+	slowPing := func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": slowPing}, time.Minute, time.Millisecond)
+
+	prober.Probe(context.Background())
+
+	assert.Equal(t, StatusNotServing, prober.Status("test"))
+}
+
+func testStatusUnknownService(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	prober.Probe(context.Background())
+
+	assert.Equal(t, StatusServiceUnknown, prober.Status("unknown"))
+}
+
+func testWatchChanges(t *testing.T) {
+	pingFunc, pingErr := switchablePing()
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": pingFunc}, time.Minute, time.Second)
+
+	statuses, cancel := prober.Watch("test")
+	defer cancel()
+
+	assert.Equal(t, StatusUnknown, <-statuses)
+
+	prober.Probe(context.Background())
+	assert.Equal(t, StatusServing, <-statuses)
+
+	*pingErr = errors.New("dependency down")
+	prober.Probe(context.Background())
+	assert.Equal(t, StatusNotServing, <-statuses)
+}
+
+func testWatchLatestStatus(t *testing.T) {
+	pingFunc, pingErr := switchablePing()
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": pingFunc}, time.Minute, time.Second)
+
+	statuses, cancel := prober.Watch(OverallService)
+	defer cancel()
+
+	prober.Probe(context.Background())
+	*pingErr = errors.New("dependency down")
+	prober.Probe(context.Background())
+
+	assert.Equal(t, StatusNotServing, <-statuses)
+	assert.Empty(t, statuses)
+}
+
+func testWatchCancel(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	statuses, cancel := prober.Watch("test")
+	<-statuses
+	cancel()
+
+	prober.Probe(context.Background())
+
+	assert.Empty(t, statuses)
+	assert.Empty(t, prober.watchers["test"])
+}
+
+func testShutdown(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	prober.Probe(context.Background())
+	prober.Shutdown()
+
+	assert.True(t, prober.ShuttingDown())
+	assert.Equal(t, StatusNotServing, prober.Status(OverallService))
+	assert.Equal(t, StatusNotServing, prober.Status("test"))
+}
+
+func testShutdownClosesWatchers(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	statuses, cancel := prober.Watch("test")
+	defer cancel()
+	<-statuses
+
+	prober.Shutdown()
+
+	status, ok := <-statuses
+	assert.True(t, ok)
+	assert.Equal(t, StatusNotServing, status)
+
+	_, ok = <-statuses
+	assert.False(t, ok)
+}
+
+func testProbeAfterShutdown(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	prober.Shutdown()
+	prober.Probe(context.Background())
+
+	assert.Equal(t, StatusNotServing, prober.Status("test"))
+}
+
+func testWatchAfterShutdown(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	prober.Shutdown()
+
+	statuses, cancel := prober.Watch("test")
+	defer cancel()
+
+	assert.Equal(t, StatusNotServing, <-statuses)
+
+	_, ok := <-statuses
+	assert.False(t, ok)
+}
+
+func testRun(t *testing.T) {
+	pings := make(chan struct{}, 10)
+	pingFunc := func(ctx context.Context) error {
+		pings <- struct{}{}
+
+		return nil
+	}
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": pingFunc}, time.Millisecond, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		prober.Run(ctx)
+		close(done)
+	}()
+
+	<-pings
+	<-pings
+	cancel()
+	<-done
+
+	assert.Equal(t, StatusServing, prober.Status("test"))
+}
+
+func testShutdownTwice(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	prober.Shutdown()
+
+	assert.NotPanics(t, prober.Shutdown)
+}
+
+func testProbeWithoutChanges(t *testing.T) {
+	prober := NewProber(map[string]func(ctx context.Context) error{"test": ping(nil)}, time.Minute, time.Second)
+
+	statuses, cancel := prober.Watch("test")
+	defer cancel()
+	<-statuses
+
+	prober.Probe(context.Background())
+	<-statuses
+	prober.Probe(context.Background())
+
+	assert.Empty(t, statuses)
+}
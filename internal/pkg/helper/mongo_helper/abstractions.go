@@ -13,10 +13,14 @@ type Database interface {
 
 type Collection interface {
 	Find(context.Context, interface{}, ...*options.FindOptions) (cur Cursor, err error)
-	FindOne(context.Context, interface{}) SingleResult
+	FindOne(context.Context, interface{}, ...*options.FindOneOptions) SingleResult
+	CountDocuments(context.Context, interface{}, ...*options.CountOptions) (int64, error)
 	InsertOne(context.Context, interface{}) (interface{}, error)
+	InsertMany(context.Context, []interface{}, ...*options.InsertManyOptions) ([]interface{}, error)
 	UpdateOne(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	UpdateMany(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	DeleteOne(context.Context, interface{}) (*mongo.DeleteResult, error)
+	CreateIndexes(context.Context, []mongo.IndexModel) error
 }
 
 type SingleResult interface {
@@ -26,6 +30,8 @@ type SingleResult interface {
 type Cursor interface {
 	Next(context.Context) bool
 	Decode(interface{}) error
+	Err() error
+	Close(context.Context) error
 }
 
 type MongoDatabase struct {
@@ -61,6 +67,14 @@ func (sr *MongoCursor) Next(ctx context.Context) bool {
 	return sr.sr.Next(ctx)
 }
 
+func (sr *MongoCursor) Err() error {
+	return sr.sr.Err()
+}
+
+func (sr *MongoCursor) Close(ctx context.Context) error {
+	return sr.sr.Close(ctx)
+}
+
 type MongoCollection struct {
 	col *mongo.Collection
 }
@@ -70,16 +84,41 @@ func (mc *MongoCollection) Find(ctx context.Context, filter interface{}, opts ..
 	return &MongoCursor{sr: cur}, err
 }
 
-func (mc *MongoCollection) FindOne(ctx context.Context, filter interface{}) SingleResult {
-	singleResult := mc.col.FindOne(ctx, filter)
+func (mc *MongoCollection) FindOne(
+	ctx context.Context, filter interface{}, opts ...*options.FindOneOptions,
+) SingleResult {
+	singleResult := mc.col.FindOne(ctx, filter, opts...)
 	return &MongoSingleResult{sr: singleResult}
 }
 
+func (mc *MongoCollection) CountDocuments(
+	ctx context.Context, filter interface{}, opts ...*options.CountOptions,
+) (int64, error) {
+	return mc.col.CountDocuments(ctx, filter, opts...)
+}
+
 func (mc *MongoCollection) InsertOne(ctx context.Context, document interface{}) (interface{}, error) {
 	id, err := mc.col.InsertOne(ctx, document)
+
+	if id == nil {
+		return nil, err
+	}
+
 	return id.InsertedID, err
 }
 
+func (mc *MongoCollection) InsertMany(
+	ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions,
+) ([]interface{}, error) {
+	res, err := mc.col.InsertMany(ctx, documents, opts...)
+
+	if res == nil {
+		return nil, err
+	}
+
+	return res.InsertedIDs, err
+}
+
 func (mc *MongoCollection) DeleteOne(ctx context.Context, filter interface{}) (*mongo.DeleteResult, error) {
 	res, err := mc.col.DeleteOne(ctx, filter)
 	return res, err
@@ -96,3 +135,20 @@ func (mc *MongoCollection) UpdateOne(
 	res, err := mc.col.UpdateOne(ctx, filter, update, opts...)
 	return res, err
 }
+
+func (mc *MongoCollection) UpdateMany(
+	ctx context.Context,
+	filter interface{},
+	update interface{},
+	opts ...*options.UpdateOptions,
+) (
+	*mongo.UpdateResult, error,
+) {
+	res, err := mc.col.UpdateMany(ctx, filter, update, opts...)
+	return res, err
+}
+
+func (mc *MongoCollection) CreateIndexes(ctx context.Context, models []mongo.IndexModel) error {
+	_, err := mc.col.Indexes().CreateMany(ctx, models)
+	return err
+}
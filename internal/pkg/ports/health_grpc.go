@@ -2,17 +2,39 @@ package ports
 
 import (
 	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/health"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 )
 
+/*
+HealthGrpcServer implements the gRPC health checking protocol.
+
+Check pings the dependencies in real time, while Watch streams the statuses kept
+by the background prober, as pinging on every subscriber wouldn't scale.
+*/
 type HealthGrpcServer struct {
 	dependencies map[string]func(ctx context.Context) error
+	prober       *health.Prober
+}
+
+func NewHealthGrpcServer(
+	dependencies map[string]func(ctx context.Context) error, prober *health.Prober,
+) HealthGrpcServer {
+	if prober == nil {
+		panic("[ports/health] nil prober")
+	}
+
+	return HealthGrpcServer{dependencies: dependencies, prober: prober}
 }
 
-func NewHealthGrpcServer(dependencies map[string]func(ctx context.Context) error) HealthGrpcServer {
-	return HealthGrpcServer{dependencies: dependencies}
+/*
+Shutdown makes every later check report NOT_SERVING, so load balancers stop routing new requests to this instance
+while the in-flight ones are drained. Watch streams get notified and end.
+*/
+func (s *HealthGrpcServer) Shutdown() {
+	s.prober.Shutdown()
 }
 
 func (s *HealthGrpcServer) Check(
@@ -20,6 +42,12 @@ func (s *HealthGrpcServer) Check(
 	in *grpc_health_v1.HealthCheckRequest,
 ) (*grpc_health_v1.HealthCheckResponse, error) {
 	if in.Service == "" {
+		if s.prober.ShuttingDown() {
+			return &grpc_health_v1.HealthCheckResponse{
+				Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+			}, nil
+		}
+
 		for _, pingFunc := range s.dependencies {
 			if err := pingFunc(ctx); err != nil {
 				return &grpc_health_v1.HealthCheckResponse{
@@ -39,6 +67,12 @@ func (s *HealthGrpcServer) Check(
 		return nil, status.Error(codes.NotFound, "[Check] unknown service")
 	}
 
+	if s.prober.ShuttingDown() {
+		return &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		}, nil
+	}
+
 	if err := pingFunc(ctx); err != nil {
 		return &grpc_health_v1.HealthCheckResponse{
 			Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
@@ -50,6 +84,42 @@ func (s *HealthGrpcServer) Check(
 	}, nil
 }
 
-func (s *HealthGrpcServer) Watch(*grpc_health_v1.HealthCheckRequest, grpc_health_v1.Health_WatchServer) error {
-	return status.Error(codes.Unimplemented, "[Watch] not implemented")
+/*
+Watch sends the current status of the service, and then a new message whenever it changes.
+
+As the protocol states, unknown services get SERVICE_UNKNOWN instead of an error,
+as they could be known later. The stream ends when the client leaves, or once the
+server shuts down, after sending the final NOT_SERVING.
+*/
+func (s *HealthGrpcServer) Watch(in *grpc_health_v1.HealthCheckRequest, srv grpc_health_v1.Health_WatchServer) error {
+	statuses, cancel := s.prober.Watch(in.Service)
+	defer cancel()
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return status.FromContextError(srv.Context().Err()).Err()
+		case probed, ok := <-statuses:
+			if !ok {
+				return nil
+			}
+
+			if err := srv.Send(&grpc_health_v1.HealthCheckResponse{Status: mapStatusToGrpc(probed)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func mapStatusToGrpc(probed health.Status) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	switch probed {
+	case health.StatusServing:
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	case health.StatusNotServing:
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	case health.StatusServiceUnknown:
+		return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	default:
+		return grpc_health_v1.HealthCheckResponse_UNKNOWN
+	}
 }
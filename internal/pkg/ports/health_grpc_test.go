@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/health"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -10,6 +11,7 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 	"testing"
+	"time"
 )
 
 func TestHealthGrpc(t *testing.T) {
@@ -17,7 +19,8 @@ func TestHealthGrpc(t *testing.T) {
 
 	for name, testGroup := range map[string]map[string]func(t *testing.T){
 		"gRPC server": {
-			"initialize health gRPC server": testNewHealthGrpcServer,
+			"initialize health gRPC server":                testNewHealthGrpcServer,
+			"initialize health gRPC server without prober": testNewHealthGrpcServerWithoutProber,
 		},
 		"check": {
 			"call check with no service":     testCheckNoService,
@@ -28,8 +31,16 @@ func TestHealthGrpc(t *testing.T) {
 			"call with service and error": testCheckServiceError,
 			"call with unknown service":   testCheckUnknownService,
 		},
+		"shutdown": {
+			"call check with no service after shutdown": testCheckNoServiceAfterShutdown,
+			"call check with service after shutdown":    testCheckServiceAfterShutdown,
+			"call with unknown service after shutdown":  testCheckUnknownServiceAfterShutdown,
+		},
 		"watch": {
-			"call watcg": testWatch,
+			"call watch":                         testWatch,
+			"call watch with unknown service":    testWatchUnknownService,
+			"call watch until the client leaves": testWatchClientLeaves,
+			"call watch with send error":         testWatchSendError,
 		},
 	} {
 		testGroup := testGroup
@@ -56,10 +67,24 @@ func TestHealthGrpc(t *testing.T) {
 func testNewHealthGrpcServer(t *testing.T) {
 	dependencies := map[string]func(ctx context.Context) error{"test": func(ctx context.Context) error { return nil }}
 
-	out := NewHealthGrpcServer(dependencies)
+	prober := newTestProber(dependencies)
+
+	out := NewHealthGrpcServer(dependencies, prober)
 
 	assert.NotNil(t, out)
-	assert.Equal(t, HealthGrpcServer{dependencies}, out)
+	assert.Equal(t, HealthGrpcServer{dependencies: dependencies, prober: prober}, out)
+}
+
+func testNewHealthGrpcServerWithoutProber(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[ports/health] nil prober", func() {
+			NewHealthGrpcServer(map[string]func(ctx context.Context) error{}, nil)
+		},
+	)
+}
+
+func newTestProber(dependencies map[string]func(ctx context.Context) error) *health.Prober {
+	return health.NewProber(dependencies, time.Minute, time.Second)
 }
 
 type dependency struct {
@@ -85,7 +110,7 @@ func testCheckNoService(t *testing.T) {
 	mockDependency1.On("Ping", ctx).Return(nil)
 	mockDependency2.On("Ping", ctx).Return(nil)
 
-	srv := NewHealthGrpcServer(dependencies)
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
 
 	out, err := srv.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
 
@@ -111,7 +136,7 @@ func testCheckNoServiceError(t *testing.T) {
 	mockDependency1.On("Ping", ctx).Return(errors.New("dependency down"))
 	mockDependency2.On("Ping", ctx).Return(nil) // This may not be called
 
-	srv := NewHealthGrpcServer(dependencies)
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
 
 	out, err := srv.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
 
@@ -134,7 +159,7 @@ func testCheckService(t *testing.T) {
 
 	mockDependency2.On("Ping", ctx).Return(nil)
 
-	srv := NewHealthGrpcServer(dependencies)
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
 
 	out, err := srv.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test2"})
 
@@ -158,7 +183,7 @@ func testCheckServiceError(t *testing.T) {
 
 	mockDependency1.On("Ping", ctx).Return(errors.New("dependency down"))
 
-	srv := NewHealthGrpcServer(dependencies)
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
 
 	out, err := srv.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test1"})
 
@@ -180,7 +205,7 @@ func testCheckUnknownService(t *testing.T) {
 
 	ctx := context.Background()
 
-	srv := NewHealthGrpcServer(dependencies)
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
 
 	out, err := srv.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test3"})
 
@@ -191,9 +216,66 @@ func testCheckUnknownService(t *testing.T) {
 	assert.ErrorIs(t, err, status.Error(codes.NotFound, "[Check] unknown service"))
 }
 
+func testCheckNoServiceAfterShutdown(t *testing.T) {
+	mockDependency := new(dependency)
+	dependencies := map[string]func(ctx context.Context) error{
+		"test1": mockDependency.Ping,
+	}
+
+	ctx := context.Background()
+
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
+	srv.Shutdown()
+
+	out, err := srv.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+
+	mockDependency.AssertNumberOfCalls(t, "Ping", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, out.Status)
+}
+
+func testCheckServiceAfterShutdown(t *testing.T) {
+	mockDependency := new(dependency)
+	dependencies := map[string]func(ctx context.Context) error{
+		"test1": mockDependency.Ping,
+	}
+
+	ctx := context.Background()
+
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
+	srv.Shutdown()
+
+	out, err := srv.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test1"})
+
+	mockDependency.AssertNumberOfCalls(t, "Ping", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, out.Status)
+}
+
+func testCheckUnknownServiceAfterShutdown(t *testing.T) {
+	dependencies := map[string]func(ctx context.Context) error{}
+
+	ctx := context.Background()
+
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
+	srv.Shutdown()
+
+	out, err := srv.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test1"})
+
+	assert.Nil(t, out)
+	assert.ErrorIs(t, err, status.Error(codes.NotFound, "[Check] unknown service"))
+}
+
 type watchServer struct {
 	mock.Mock
 	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *watchServer) Context() context.Context {
+	return w.ctx
 }
 
 func (w *watchServer) Send(resp *grpc_health_v1.HealthCheckResponse) error {
@@ -202,15 +284,99 @@ func (w *watchServer) Send(resp *grpc_health_v1.HealthCheckResponse) error {
 	return args.Error(0)
 }
 
+func servingStatus(status grpc_health_v1.HealthCheckResponse_ServingStatus) interface{} {
+	return mock.MatchedBy(
+		func(resp *grpc_health_v1.HealthCheckResponse) bool {
+			return resp.Status == status
+		},
+	)
+}
+
 func testWatch(t *testing.T) {
-	watchSrv := new(watchServer)
+	mockDependency := new(dependency)
+	dependencies := map[string]func(ctx context.Context) error{
+		"test1": mockDependency.Ping,
+	}
+	prober := newTestProber(dependencies)
+	srv := NewHealthGrpcServer(dependencies, prober)
+
+	mockDependency.On("Ping", mock.Anything).Return(nil)
+
+	sent := make(chan struct{}, 3)
+	watchSrv := &watchServer{ctx: context.Background()}
+	watchSrv.On("Send", mock.Anything).Run(func(mock.Arguments) { sent <- struct{}{} }).Return(nil)
+
+	done := make(chan error)
+	go func() {
+		done <- srv.Watch(&grpc_health_v1.HealthCheckRequest{Service: "test1"}, watchSrv)
+	}()
+
+	<-sent
+	prober.Probe(context.Background())
+	<-sent
+	srv.Shutdown()
+
+	assert.NoError(t, <-done)
+
+	watchSrv.AssertNumberOfCalls(t, "Send", 3)
+	watchSrv.AssertCalled(t, "Send", servingStatus(grpc_health_v1.HealthCheckResponse_UNKNOWN))
+	watchSrv.AssertCalled(t, "Send", servingStatus(grpc_health_v1.HealthCheckResponse_SERVING))
+	watchSrv.AssertCalled(t, "Send", servingStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+}
+
+func testWatchUnknownService(t *testing.T) {
+	dependencies := map[string]func(ctx context.Context) error{}
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
+
+	sent := make(chan struct{}, 1)
+	watchSrv := &watchServer{ctx: context.Background()}
+	watchSrv.On("Send", mock.Anything).Run(func(mock.Arguments) { sent <- struct{}{} }).Return(nil)
+
+	done := make(chan error)
+	go func() {
+		done <- srv.Watch(&grpc_health_v1.HealthCheckRequest{Service: "test1"}, watchSrv)
+	}()
+
+	<-sent
+	srv.Shutdown()
+
+	assert.NoError(t, <-done)
+
+	watchSrv.AssertNumberOfCalls(t, "Send", 1)
+	watchSrv.AssertCalled(t, "Send", servingStatus(grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN))
+}
+
+func testWatchClientLeaves(t *testing.T) {
+	dependencies := map[string]func(ctx context.Context) error{}
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sent := make(chan struct{}, 1)
+	watchSrv := &watchServer{ctx: ctx}
+	watchSrv.On("Send", mock.Anything).Run(func(mock.Arguments) { sent <- struct{}{} }).Return(nil)
+
+	done := make(chan error)
+	go func() {
+		done <- srv.Watch(&grpc_health_v1.HealthCheckRequest{}, watchSrv)
+	}()
+
+	<-sent
+	cancel()
+
+	assert.ErrorIs(t, <-done, status.Error(codes.Canceled, context.Canceled.Error()))
+}
+
+func testWatchSendError(t *testing.T) {
 	dependencies := map[string]func(ctx context.Context) error{}
+	srv := NewHealthGrpcServer(dependencies, newTestProber(dependencies))
 
-	srv := NewHealthGrpcServer(dependencies)
+	watchSrv := &watchServer{ctx: context.Background()}
+	watchSrv.On("Send", mock.Anything).Return(errors.New("stream closed"))
 
 	err := srv.Watch(&grpc_health_v1.HealthCheckRequest{}, watchSrv)
 
-	watchSrv.AssertNumberOfCalls(t, "Send", 0)
+	watchSrv.AssertNumberOfCalls(t, "Send", 1)
 
-	assert.ErrorIs(t, err, status.Error(codes.Unimplemented, "[Watch] not implemented"))
+	assert.EqualError(t, err, "stream closed")
 }
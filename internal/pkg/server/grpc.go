@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
@@ -9,45 +11,133 @@ import (
 	"log"
 	"net"
 	"os"
+	"time"
 )
 
-func RunGRPCServer(registerServer func(server *grpc.Server)) {
+// DefaultShutdownTimeout is how long in-flight calls have to finish once the server is asked to stop.
+const DefaultShutdownTimeout = 30 * time.Second
+
+/*
+Interceptor is a pair of unary and stream interceptors, like authorization, added to the server chain after logging.
+*/
+type Interceptor interface {
+	Unary() grpc.UnaryServerInterceptor
+	Stream() grpc.StreamServerInterceptor
+}
+
+/*
+unloggedMethods are the health checks, which the probes call too often for their logs to be of any use.
+*/
+var unloggedMethods = map[string]bool{
+	"/grpc.health.v1.Health/Check": true,
+	"/grpc.health.v1.Health/Watch": true,
+}
+
+func shouldLog(fullMethodName string, _ error) bool {
+	return !unloggedMethods[fullMethodName]
+}
+
+func RunGRPCServer(ctx context.Context, registerServer func(server *grpc.Server), interceptors ...Interceptor) error {
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
 	addr := fmt.Sprintf(":%s", port) // Listen on any IP address on the specified port
-	RunGRPCServerOnAddr(addr, registerServer)
+	return RunGRPCServerOnAddr(ctx, addr, registerServer, interceptors...)
 }
 
-func RunGRPCServerOnAddr(addr string, registerServer func(server *grpc.Server)) {
+/*
+RunGRPCServerOnAddr serves gRPC on the given address until the context is done, and then stops gracefully.
+
+Once stopping, the server refuses new calls and waits for the in-flight ones,
+streams included, to finish. The ones still running after 'SHUTDOWN_TIMEOUT'
+(DefaultShutdownTimeout if not set) are cancelled. It only returns once every
+call is over, so the caller can safely release the resources they use.
+*/
+func RunGRPCServerOnAddr(
+	ctx context.Context, addr string, registerServer func(server *grpc.Server), interceptors ...Interceptor,
+) error {
 	logrusEntry := logrus.NewEntry(logrus.StandardLogger())
 
-	logrusOpts := []grpc_logrus.Option{
-		grpc_logrus.WithDecider(
-			func(fullMethodName string, err error) bool {
-				if fullMethodName == "grpc.health.v1.Health.Check" {
-					return false
-				}
-				return true
-			},
-		),
+	logrusOpts := []grpc_logrus.Option{grpc_logrus.WithDecider(shouldLog)}
+
+	unaryChain := []grpc.UnaryServerInterceptor{grpc_logrus.UnaryServerInterceptor(logrusEntry, logrusOpts...)}
+	streamChain := []grpc.StreamServerInterceptor{grpc_logrus.StreamServerInterceptor(logrusEntry, logrusOpts...)}
+
+	for _, interceptor := range interceptors {
+		unaryChain = append(unaryChain, interceptor.Unary())
+		streamChain = append(streamChain, interceptor.Stream())
 	}
 
 	grpcServer := grpc.NewServer(
-		grpc_middleware.WithUnaryServerChain(
-			grpc_logrus.UnaryServerInterceptor(logrusEntry, logrusOpts...),
-		), grpc_middleware.WithStreamServerChain(
-			grpc_logrus.StreamServerInterceptor(logrusEntry, logrusOpts...),
-		),
+		grpc_middleware.WithUnaryServerChain(unaryChain...),
+		grpc_middleware.WithStreamServerChain(streamChain...),
 	)
 	registerServer(grpcServer)
 
+	timeout := shutdownTimeout()
+
 	listen, err := net.Listen("tcp", addr)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+
+	// Serve may fail before the context is done, which must release the goroutine stopping the server too.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		<-ctx.Done()
+		stopGracefully(grpcServer, timeout)
+	}()
+
 	log.Printf("Starting: gRPC Listener")
-	log.Fatal(grpcServer.Serve(listen))
+	// If the context was already done, the server may be stopped before it starts serving, which is a clean shutdown too.
+	if err := grpcServer.Serve(listen); err != nil && !(errors.Is(err, grpc.ErrServerStopped) && ctx.Err() != nil) {
+		return err
+	}
+
+	// Serve returns as soon as the listener is closed, but the in-flight calls may still be running.
+	<-stopped
+
+	return nil
+}
+
+func stopGracefully(grpcServer *grpc.Server, timeout time.Duration) {
+	log.Printf("Stopping: gRPC Listener")
+
+	drained := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Printf("Stopped: gRPC Listener")
+	case <-time.After(timeout):
+		log.Printf("Shutdown timeout of %s reached, cancelling the remaining calls", timeout)
+		grpcServer.Stop()
+		<-drained
+	}
+}
+
+func shutdownTimeout() time.Duration {
+	value := os.Getenv("SHUTDOWN_TIMEOUT")
+
+	if value == "" {
+		return DefaultShutdownTimeout
+	}
+
+	timeout, err := time.ParseDuration(value)
+
+	if err != nil || timeout <= 0 {
+		log.Fatalf("Invalid duration in 'SHUTDOWN_TIMEOUT' environmental variable: %s", value)
+	}
+
+	return timeout
 }
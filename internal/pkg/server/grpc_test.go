@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestGrpcServer(t *testing.T) {
+	for name, test := range map[string]func(t *testing.T){
+		"should drain in-flight calls before stopping": testRunGRPCServerDrainsCalls,
+		"should cancel calls after the timeout":        testRunGRPCServerTimeout,
+		"should fail on an invalid address":            testRunGRPCServerInvalidAddr,
+		"should stop cleanly if already cancelled":     testRunGRPCServerAlreadyCancelled,
+		"should not log health checks":                 testShouldLogHealthChecks,
+	} {
+		test := test
+		t.Run(name, test)
+	}
+}
+
+/*
+slowHealthServer holds every check until it's released, so the tests have in-flight calls to drain.
+*/
+type slowHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowHealthServer) Check(
+	ctx context.Context, _ *grpc_health_v1.HealthCheckRequest,
+) (*grpc_health_v1.HealthCheckResponse, error) {
+	close(s.started)
+
+	select {
+	case <-s.release:
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func freeAddr(t *testing.T) string {
+	listen, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listen.Addr().String()
+	require.NoError(t, listen.Close())
+
+	return addr
+}
+
+/*
+runSlowServer starts a server with a slow health check, and calls it, returning the channels to follow both.
+*/
+func runSlowServer(t *testing.T, ctx context.Context) (*slowHealthServer, chan error, chan error) {
+	addr := freeAddr(t)
+	healthSrv := &slowHealthServer{started: make(chan struct{}), release: make(chan struct{})}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- RunGRPCServerOnAddr(
+			ctx, addr, func(server *grpc.Server) {
+				grpc_health_v1.RegisterHealthServer(server, healthSrv)
+			},
+		)
+	}()
+
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	called := make(chan error, 1)
+	go func() {
+		_, err := grpc_health_v1.NewHealthClient(conn).Check(
+			context.Background(), &grpc_health_v1.HealthCheckRequest{}, grpc.WaitForReady(true),
+		)
+		called <- err
+	}()
+
+	<-healthSrv.started
+
+	return healthSrv, served, called
+}
+
+func testRunGRPCServerDrainsCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	healthSrv, served, called := runSlowServer(t, ctx)
+
+	cancel()
+
+	select {
+	case <-served:
+		t.Fatal("server stopped before draining the in-flight call")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(healthSrv.release)
+
+	assert.NoError(t, <-called)
+	assert.NoError(t, <-served)
+}
+
+func testRunGRPCServerTimeout(t *testing.T) {
+	t.Setenv("SHUTDOWN_TIMEOUT", "50ms")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, served, called := runSlowServer(t, ctx)
+
+	cancel()
+
+	assert.NoError(t, <-served)
+	assert.Error(t, <-called)
+}
+
+func testRunGRPCServerInvalidAddr(t *testing.T) {
+	err := RunGRPCServerOnAddr(context.Background(), "invalid", func(server *grpc.Server) {})
+
+	assert.Error(t, err)
+}
+
+func testRunGRPCServerAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The server is stopped before it starts serving, as it would be if the shutdown won the race.
+	err := RunGRPCServerOnAddr(ctx, freeAddr(t), func(server *grpc.Server) { server.Stop() })
+
+	assert.NoError(t, err)
+}
+
+func testShouldLogHealthChecks(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, shouldLog("/grpc.health.v1.Health/Check", nil))
+	assert.False(t, shouldLog("/grpc.health.v1.Health/Watch", nil))
+	assert.True(t, shouldLog("/test.elizabeth.acme.api.v1.UserService/GetUser", nil))
+}
@@ -28,6 +28,18 @@ func MapGrpcOperatorToOperator(operator apiV1.Filter_Operator) operators.Compari
 		return operators.LESS_THAN
 	case apiV1.Filter_LESS_THAN_EQ:
 		return operators.LESS_THAN_EQ
+	case apiV1.Filter_IN:
+		return operators.IN
+	case apiV1.Filter_NOT_IN:
+		return operators.NOT_IN
+	case apiV1.Filter_STARTS_WITH:
+		return operators.STARTS_WITH
+	case apiV1.Filter_CONTAINS:
+		return operators.CONTAINS
+	case apiV1.Filter_REGEX:
+		return operators.REGEX
+	case apiV1.Filter_EXISTS:
+		return operators.EXISTS
 	default:
 		return operators.EQUALS // If an invalid operator is passed, we default to EQUALS
 	}
@@ -45,11 +57,65 @@ func MapGrpcFilterValueToFilterValue(filter *apiV1.Filter) interface{} {
 		return filter.GetDoubleValue()
 	case *apiV1.Filter_TimestampValue:
 		return filter.GetTimestampValue().AsTime()
+	case *apiV1.Filter_ListValue:
+		values := make([]interface{}, len(filter.GetListValue().GetValues()))
+		for i, value := range filter.GetListValue().GetValues() {
+			values[i] = MapGrpcValueToFilterValue(value)
+		}
+
+		return values
 	default:
 		return nil
 	}
 }
 
+/*
+MapGrpcValueToFilterValue maps each of the values of a list filter, which can't be lists themselves.
+*/
+func MapGrpcValueToFilterValue(value *apiV1.FilterValue) interface{} {
+	switch value.Value.(type) {
+	case *apiV1.FilterValue_StringValue:
+		return value.GetStringValue()
+	case *apiV1.FilterValue_IntValue:
+		return value.GetIntValue()
+	case *apiV1.FilterValue_BoolValue:
+		return value.GetBoolValue()
+	case *apiV1.FilterValue_DoubleValue:
+		return value.GetDoubleValue()
+	case *apiV1.FilterValue_TimestampValue:
+		return value.GetTimestampValue().AsTime()
+	default:
+		return nil
+	}
+}
+
+func MapGrpcFilterExpressionToFilterExpression(expression *apiV1.FilterExpression) query_utils.FilterExpression {
+	mapped := query_utils.FilterExpression{Operator: MapGrpcLogicalOperatorToOperator(expression.Operator)}
+
+	for _, filter := range expression.Filters {
+		mapped.Filters = append(mapped.Filters, MapGrpcFilterToFilter(filter))
+	}
+
+	for _, nested := range expression.Expressions {
+		mapped.Expressions = append(mapped.Expressions, MapGrpcFilterExpressionToFilterExpression(nested))
+	}
+
+	return mapped
+}
+
+func MapGrpcLogicalOperatorToOperator(operator apiV1.FilterExpression_Operator) operators.Logical {
+	switch operator {
+	case apiV1.FilterExpression_AND:
+		return operators.AND
+	case apiV1.FilterExpression_OR:
+		return operators.OR
+	case apiV1.FilterExpression_NOT:
+		return operators.NOT
+	default:
+		return operators.AND // If an invalid operator is passed, we default to AND
+	}
+}
+
 func MapGrpcSortToSort(sort *apiV1.Sort) query_utils.Sort {
 	return query_utils.Sort{
 		Field:     sort.Field,
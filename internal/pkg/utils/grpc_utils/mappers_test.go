@@ -1,6 +1,7 @@
 package grpc_utils
 
 import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
 	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
 	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
 	"github.com/stretchr/testify/assert"
@@ -21,7 +22,13 @@ func TestGrpcMappers(t *testing.T) {
 			"should return the default operator when invalid": testMapGrpcOperatorToOperatorWithInvalidOperator,
 		},
 		"grpc filter value mapper": {
-			"should return the mapped value": testMapGrpcFilterValueToValue,
+			"should return the mapped value":      testMapGrpcFilterValueToValue,
+			"should return the mapped list value": testMapGrpcFilterListValueToValue,
+		},
+		"grpc filter expression mapper": {
+			"should return the mapped expression":        testMapGrpcFilterExpressionToFilterExpression,
+			"should return the mapped logical operator":  testMapGrpcLogicalOperatorToOperator,
+			"should return the default logical operator": testMapGrpcLogicalOperatorToOperatorWithInvalidOperator,
 		},
 		"grpc sort mapper": {
 			"should return the mapped sort": testMapGrpcSortToSort,
@@ -84,6 +91,24 @@ func testMapGrpcOperatorToOperator(t *testing.T) {
 
 	out = MapGrpcOperatorToOperator(apiV1.Filter_LESS_THAN_EQ)
 	assert.Equal(t, operators.LESS_THAN_EQ, out)
+
+	out = MapGrpcOperatorToOperator(apiV1.Filter_IN)
+	assert.Equal(t, operators.IN, out)
+
+	out = MapGrpcOperatorToOperator(apiV1.Filter_NOT_IN)
+	assert.Equal(t, operators.NOT_IN, out)
+
+	out = MapGrpcOperatorToOperator(apiV1.Filter_STARTS_WITH)
+	assert.Equal(t, operators.STARTS_WITH, out)
+
+	out = MapGrpcOperatorToOperator(apiV1.Filter_CONTAINS)
+	assert.Equal(t, operators.CONTAINS, out)
+
+	out = MapGrpcOperatorToOperator(apiV1.Filter_REGEX)
+	assert.Equal(t, operators.REGEX, out)
+
+	out = MapGrpcOperatorToOperator(apiV1.Filter_EXISTS)
+	assert.Equal(t, operators.EXISTS, out)
 }
 
 func testMapGrpcOperatorToOperatorWithInvalidOperator(t *testing.T) {
@@ -139,6 +164,39 @@ func testMapGrpcFilterValueToValue(t *testing.T) {
 		},
 	)
 	assert.Equal(t, time.Unix(1665913529, 328).UTC(), out)
+
+	out = MapGrpcFilterValueToFilterValue(&apiV1.Filter{})
+	assert.Nil(t, out)
+}
+
+func testMapGrpcFilterListValueToValue(t *testing.T) {
+	out := MapGrpcFilterValueToFilterValue(
+		&apiV1.Filter{
+			Value: &apiV1.Filter_ListValue{
+				ListValue: &apiV1.FilterValueList{
+					Values: []*apiV1.FilterValue{
+						{Value: &apiV1.FilterValue_StringValue{StringValue: "value"}},
+						{Value: &apiV1.FilterValue_IntValue{IntValue: 1234}},
+						{Value: &apiV1.FilterValue_BoolValue{BoolValue: true}},
+						{Value: &apiV1.FilterValue_DoubleValue{DoubleValue: 1234.1234}},
+						{
+							Value: &apiV1.FilterValue_TimestampValue{
+								TimestampValue: &timestamppb.Timestamp{Seconds: 1665913529, Nanos: 328},
+							},
+						},
+						{},
+					},
+				},
+			},
+		},
+	)
+
+	assert.Equal(
+		t, []interface{}{"value", int64(1234), true, 1234.1234, time.Unix(1665913529, 328).UTC(), nil}, out,
+	)
+
+	out = MapGrpcFilterValueToFilterValue(&apiV1.Filter{Value: &apiV1.Filter_ListValue{}})
+	assert.Equal(t, []interface{}{}, out)
 }
 
 func testMapGrpcSortToSort(t *testing.T) {
@@ -165,3 +223,53 @@ func testMapGrpcDirectionToDirectionWithInvalidDirection(t *testing.T) {
 	out := MapGrpcDirectionToDirection(-1234)
 	assert.Equal(t, operators.ASC, out)
 }
+
+func testMapGrpcFilterExpressionToFilterExpression(t *testing.T) {
+	out := MapGrpcFilterExpressionToFilterExpression(
+		&apiV1.FilterExpression{
+			Operator: apiV1.FilterExpression_OR,
+			Filters: []*apiV1.Filter{
+				{
+					Field:    "country",
+					Operator: apiV1.Filter_EQUALS,
+					Value:    &apiV1.Filter_StringValue{StringValue: "ES"},
+				},
+			},
+			Expressions: []*apiV1.FilterExpression{
+				{
+					Operator: apiV1.FilterExpression_NOT,
+					Filters: []*apiV1.Filter{
+						{
+							Field:    "roles",
+							Operator: apiV1.Filter_EQUALS,
+							Value:    &apiV1.Filter_StringValue{StringValue: "admin"},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	assert.Equal(
+		t, query_utils.FilterExpression{
+			Operator: operators.OR,
+			Filters:  []query_utils.Filter{{Field: "country", Operator: operators.EQUALS, Value: "ES"}},
+			Expressions: []query_utils.FilterExpression{
+				{
+					Operator: operators.NOT,
+					Filters:  []query_utils.Filter{{Field: "roles", Operator: operators.EQUALS, Value: "admin"}},
+				},
+			},
+		}, out,
+	)
+}
+
+func testMapGrpcLogicalOperatorToOperator(t *testing.T) {
+	assert.Equal(t, operators.AND, MapGrpcLogicalOperatorToOperator(apiV1.FilterExpression_AND))
+	assert.Equal(t, operators.OR, MapGrpcLogicalOperatorToOperator(apiV1.FilterExpression_OR))
+	assert.Equal(t, operators.NOT, MapGrpcLogicalOperatorToOperator(apiV1.FilterExpression_NOT))
+}
+
+func testMapGrpcLogicalOperatorToOperatorWithInvalidOperator(t *testing.T) {
+	assert.Equal(t, operators.AND, MapGrpcLogicalOperatorToOperator(-1234))
+}
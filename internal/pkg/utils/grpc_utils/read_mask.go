@@ -0,0 +1,34 @@
+package grpc_utils
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+/*
+ApplyReadMask clears the fields of the message left out of the read mask, so only the requested ones are sent, or
+keeps them all if the mask is empty.
+
+The mask paths are the names of the message's own fields, like "nickname", as
+read masks of nested messages aren't supported. They're expected to have been
+validated already, so unknown ones are ignored.
+*/
+func ApplyReadMask(message proto.Message, mask *fieldmaskpb.FieldMask) {
+	if len(mask.GetPaths()) == 0 {
+		return
+	}
+
+	requested := map[string]bool{}
+	for _, path := range mask.GetPaths() {
+		requested[path] = true
+	}
+
+	reflection := message.ProtoReflect()
+	fields := reflection.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		if field := fields.Get(i); !requested[string(field.Name())] {
+			reflection.Clear(field)
+		}
+	}
+}
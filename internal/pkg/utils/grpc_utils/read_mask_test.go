@@ -0,0 +1,53 @@
+package grpc_utils
+
+import (
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+func TestApplyReadMask(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should clear the fields left out": testApplyReadMask,
+		"should keep every field if empty": testApplyEmptyReadMask,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testApplyReadMask(t *testing.T) {
+	user := &apiV1.User{
+		Id:        "1234",
+		Nickname:  "john-123",
+		Email:     "me@john.com",
+		Roles:     []string{"user"},
+		CreatedAt: timestamppb.New(time.Now()),
+	}
+
+	ApplyReadMask(user, &fieldmaskpb.FieldMask{Paths: []string{"id", "nickname", "unknown"}})
+
+	assert.True(t, proto.Equal(&apiV1.User{Id: "1234", Nickname: "john-123"}, user))
+}
+
+func testApplyEmptyReadMask(t *testing.T) {
+	user := &apiV1.User{Id: "1234", Nickname: "john-123", CreatedAt: timestamppb.New(time.Now())}
+	expected := proto.Clone(user)
+
+	ApplyReadMask(user, nil)
+	ApplyReadMask(user, &fieldmaskpb.FieldMask{})
+
+	assert.True(t, proto.Equal(expected, user))
+}
@@ -0,0 +1,56 @@
+package grpc_utils
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+/*
+MapUpdateMask returns the new values of the fields named by the update mask, keyed by their names, taken from the
+string fields of the request with the same names. Without a mask, every string field set in the request is taken.
+
+Fields in the mask but unset in the request map to an empty string, so they can
+be cleared, and so do the ones the request doesn't have, or the given keys that
+identify what to update, like "id", so they can be rejected by whoever knows
+which fields can be updated.
+*/
+func MapUpdateMask(request proto.Message, mask *fieldmaskpb.FieldMask, keys ...string) map[string]string {
+	reflection := request.ProtoReflect()
+	values := map[string]string{}
+
+	isKey := map[string]bool{}
+	for _, key := range keys {
+		isKey[key] = true
+	}
+
+	isValue := func(field protoreflect.FieldDescriptor) bool {
+		return field.Kind() == protoreflect.StringKind && !field.IsList() && !isKey[string(field.Name())]
+	}
+
+	if len(mask.GetPaths()) == 0 {
+		reflection.Range(
+			func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+				if isValue(field) {
+					values[string(field.Name())] = value.String()
+				}
+
+				return true
+			},
+		)
+
+		return values
+	}
+
+	fields := reflection.Descriptor().Fields()
+
+	for _, path := range mask.GetPaths() {
+		values[path] = ""
+
+		if field := fields.ByName(protoreflect.Name(path)); field != nil && isValue(field) {
+			values[path] = reflection.Get(field).String()
+		}
+	}
+
+	return values
+}
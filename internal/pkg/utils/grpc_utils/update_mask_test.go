@@ -0,0 +1,60 @@
+package grpc_utils
+
+import (
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"testing"
+)
+
+func TestMapUpdateMask(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should map the fields of the mask":      testMapUpdateMask,
+		"should map the fields set without mask": testMapUpdateMaskWithoutMask,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testMapUpdateMask(t *testing.T) {
+	request := &apiV1.UpdateUserRequest{
+		Id:        "1234",
+		FirstName: proto.String("John"),
+		Nickname:  proto.String("john-123"),
+		UpdateMask: &fieldmaskpb.FieldMask{
+			Paths: []string{"first_name", "last_name", "id", "created_at", "update_mask"},
+		},
+	}
+
+	assert.Equal(
+		t, map[string]string{
+			"first_name":  "John",
+			"last_name":   "",
+			"id":          "",
+			"created_at":  "",
+			"update_mask": "",
+		}, MapUpdateMask(request, request.GetUpdateMask(), "id"),
+	)
+}
+
+func testMapUpdateMaskWithoutMask(t *testing.T) {
+	request := &apiV1.UpdateUserRequest{
+		Id:        "1234",
+		FirstName: proto.String("John"),
+		LastName:  proto.String(""),
+	}
+
+	assert.Equal(
+		t, map[string]string{"first_name": "John", "last_name": ""}, MapUpdateMask(request, nil, "id"),
+	)
+}
@@ -0,0 +1,413 @@
+package memory_utils
+
+import (
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+Document is the in-memory counterpart of a MongoDB document, mapping its field names to their values.
+
+Arrays must be stored as []interface{}, so their elements are compared one by
+one, as MongoDB does.
+*/
+type Document map[string]interface{}
+
+/*
+Type brackets, following the BSON comparison order. Values of different brackets are never equal, and are only ordered
+when sorting.
+*/
+const (
+	bracketEmptyArray = iota
+	bracketNull
+	bracketNumber
+	bracketString
+	bracketOther
+	bracketBool
+	bracketDate
+)
+
+/* Filtering */
+
+/*
+MatchFilters tells whether the document matches every filter, with the same semantics as the MongoDB query built by
+mongo_utils.MapFilterToBson:
+  - Every filter applies, including several on the same field, like a range.
+  - Filters with unknown operators are ignored, and filters whose value doesn't
+    fit their operator never match.
+  - Values of different types never match, except for NOT_EQUALS and NOT_IN,
+    and numbers match regardless of their Go type.
+  - Arrays match if any of their elements does, and missing fields are null.
+*/
+func MatchFilters(document Document, filters []query_utils.Filter) bool {
+	for _, filter := range filters {
+		value, present := document[filter.Field]
+
+		if !matchFilter(value, present, filter) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+MatchFilterExpression tells whether the document matches the filter expression, with the same semantics as the MongoDB
+query built by mongo_utils.MapFilterExpressionToBson.
+*/
+func MatchFilterExpression(document Document, expression query_utils.FilterExpression) bool {
+	switch expression.Operator {
+	case operators.AND:
+		if !MatchFilters(document, expression.Filters) {
+			return false
+		}
+
+		for _, nested := range expression.Expressions {
+			if !MatchFilterExpression(document, nested) {
+				return false
+			}
+		}
+
+		return true
+	case operators.OR:
+		return matchAnyOperand(document, expression)
+	case operators.NOT:
+		return !matchAnyOperand(document, expression)
+	default:
+		return true
+	}
+}
+
+/*
+matchAnyOperand tells whether the document matches any filter or nested expression of the expression.
+*/
+func matchAnyOperand(document Document, expression query_utils.FilterExpression) bool {
+	for _, filter := range expression.Filters {
+		if MatchFilters(document, []query_utils.Filter{filter}) {
+			return true
+		}
+	}
+
+	for _, nested := range expression.Expressions {
+		if MatchFilterExpression(document, nested) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchFilter(value interface{}, present bool, filter query_utils.Filter) bool {
+	switch filter.Operator {
+	case operators.EQUALS:
+		return matchAny(value, filter.Value, func(cmp int) bool { return cmp == 0 })
+	case operators.NOT_EQUALS:
+		return !matchAny(value, filter.Value, func(cmp int) bool { return cmp == 0 })
+	case operators.GREATER_THAN:
+		return matchAny(value, filter.Value, func(cmp int) bool { return cmp > 0 })
+	case operators.GREATER_THAN_EQ:
+		return matchAny(value, filter.Value, func(cmp int) bool { return cmp >= 0 })
+	case operators.LESS_THAN:
+		return matchAny(value, filter.Value, func(cmp int) bool { return cmp < 0 })
+	case operators.LESS_THAN_EQ:
+		return matchAny(value, filter.Value, func(cmp int) bool { return cmp <= 0 })
+	case operators.IN, operators.NOT_IN:
+		targets, ok := filter.Value.([]interface{})
+
+		if !ok {
+			return false
+		}
+
+		return matchIn(value, targets) == (filter.Operator == operators.IN)
+	case operators.STARTS_WITH, operators.CONTAINS, operators.REGEX:
+		return matchRegex(value, filter)
+	case operators.EXISTS:
+		exists, ok := filter.Value.(bool)
+
+		return ok && present == exists
+	default:
+		return true
+	}
+}
+
+func matchIn(value interface{}, targets []interface{}) bool {
+	for _, target := range targets {
+		if matchAny(value, target, func(cmp int) bool { return cmp == 0 }) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+matchRegex tells whether the value, or any of its elements if it's an array, is a string matching the pattern of the
+filter. Invalid patterns never match.
+*/
+func matchRegex(value interface{}, filter query_utils.Filter) bool {
+	pattern, ok := query_utils.RegexPattern(filter.Operator, filter.Value)
+
+	if !ok {
+		return false
+	}
+
+	compiled, err := regexp.Compile(pattern)
+
+	if err != nil {
+		return false
+	}
+
+	elements, isArray := value.([]interface{})
+
+	if !isArray {
+		elements = []interface{}{value}
+	}
+
+	for _, element := range elements {
+		if text, ok := element.(string); ok && compiled.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+matchAny compares the value, or each of its elements if it's an array, against the target, matching if any satisfies
+the check. Values of different brackets are skipped.
+*/
+func matchAny(value interface{}, target interface{}, check func(cmp int) bool) bool {
+	elements, isArray := value.([]interface{})
+
+	if !isArray {
+		elements = []interface{}{value}
+	}
+
+	for _, element := range elements {
+		if bracket(element) != bracket(target) {
+			continue
+		}
+
+		if check(compareSameBracket(element, target)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/* Sorting */
+
+/*
+SortDocuments sorts the documents in place, with the same semantics as the MongoDB sort built by
+mongo_utils.MapSortToBson. Ties keep their original order.
+*/
+func SortDocuments(documents []Document, sorts []query_utils.Sort) {
+	sort.SliceStable(
+		documents, func(i, j int) bool {
+			return CompareDocuments(documents[i], documents[j], sorts) < 0
+		},
+	)
+}
+
+/*
+CompareDocuments tells whether a goes before (negative), after (positive) or along with (zero) b, according to sorts.
+
+The first sort has the highest priority. Arrays are sorted by their lowest
+element when ascending, and by their highest one when descending. Values of
+different types are ordered as in BSON.
+*/
+func CompareDocuments(a Document, b Document, sorts []query_utils.Sort) int {
+	for _, s := range sorts {
+		var cmp int
+
+		switch s.Direction {
+		case operators.ASC:
+			cmp = compare(sortKey(a[s.Field], false), sortKey(b[s.Field], false))
+		case operators.DESC:
+			cmp = -compare(sortKey(a[s.Field], true), sortKey(b[s.Field], true))
+		}
+
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
+
+/*
+sortKey returns the value an array is sorted by: its lowest element when ascending, or its highest one when
+descending.
+*/
+func sortKey(value interface{}, descending bool) interface{} {
+	elements, isArray := value.([]interface{})
+
+	if !isArray {
+		return value
+	}
+
+	if len(elements) == 0 {
+		return elements
+	}
+
+	key := elements[0]
+	for _, element := range elements[1:] {
+		cmp := compare(element, key)
+
+		if (descending && cmp > 0) || (!descending && cmp < 0) {
+			key = element
+		}
+	}
+
+	return key
+}
+
+/* Pagination */
+
+/*
+Paginate returns the bounds of the page within a result of the given length, as MongoDB applies skip and limit.
+
+A zero limit means no limit, and a negative one is taken as positive. A negative
+offset is rejected, as MongoDB does.
+*/
+func Paginate(length int, pagination query_utils.Pagination) (int, int, error) {
+	if pagination.Offset < 0 {
+		return 0, 0, fmt.Errorf("negative offset %d", pagination.Offset)
+	}
+
+	start := length
+	if pagination.Offset < int64(length) {
+		start = int(pagination.Offset)
+	}
+
+	limit := pagination.Limit
+	if limit < 0 {
+		limit = -limit
+	}
+
+	end := length
+	if limit > 0 && limit < int64(end-start) {
+		end = start + int(limit)
+	}
+
+	return start, end, nil
+}
+
+/* Comparison */
+
+func bracket(value interface{}) int {
+	switch castValue := value.(type) {
+	case nil:
+		return bracketNull
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return bracketNumber
+	case string:
+		return bracketString
+	case bool:
+		return bracketBool
+	case time.Time:
+		return bracketDate
+	case []interface{}:
+		if len(castValue) == 0 {
+			return bracketEmptyArray
+		}
+
+		return bracketOther
+	default:
+		return bracketOther
+	}
+}
+
+/*
+compare orders any two values, first by their bracket, and then by their value.
+*/
+func compare(a interface{}, b interface{}) int {
+	bracketA, bracketB := bracket(a), bracket(b)
+
+	if bracketA != bracketB {
+		return bracketA - bracketB
+	}
+
+	return compareSameBracket(a, b)
+}
+
+func compareSameBracket(a interface{}, b interface{}) int {
+	switch castA := a.(type) {
+	case string:
+		return strings.Compare(castA, b.(string))
+	case bool:
+		castB := b.(bool)
+
+		if castA == castB {
+			return 0
+		}
+
+		if castB {
+			return -1
+		}
+
+		return 1
+	case time.Time:
+		castB := b.(time.Time)
+
+		switch {
+		case castA.Before(castB):
+			return -1
+		case castA.After(castB):
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	if bracket(a) == bracketNumber {
+		numberA, numberB := toFloat(a), toFloat(b)
+
+		switch {
+		case numberA < numberB:
+			return -1
+		case numberA > numberB:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	// Nulls, empty arrays and anything else we can't look into are equal among them.
+	return 0
+}
+
+func toFloat(number interface{}) float64 {
+	switch castNumber := number.(type) {
+	case int:
+		return float64(castNumber)
+	case int8:
+		return float64(castNumber)
+	case int16:
+		return float64(castNumber)
+	case int32:
+		return float64(castNumber)
+	case int64:
+		return float64(castNumber)
+	case uint:
+		return float64(castNumber)
+	case uint8:
+		return float64(castNumber)
+	case uint16:
+		return float64(castNumber)
+	case uint32:
+		return float64(castNumber)
+	case uint64:
+		return float64(castNumber)
+	case float32:
+		return float64(castNumber)
+	default:
+		return number.(float64)
+	}
+}
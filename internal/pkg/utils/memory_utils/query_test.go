@@ -0,0 +1,376 @@
+package memory_utils
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var now = time.Now()
+
+var document = Document{
+	"name":    "john",
+	"age":     int64(30),
+	"score":   7.5,
+	"active":  true,
+	"created": now,
+	"roles":   []interface{}{"user", "admin"},
+}
+
+func TestMemoryQuery(t *testing.T) {
+	t.Parallel()
+
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"match filters": {
+			"should match without filters":               testMatchWithoutFilters,
+			"should match every operator":                testMatchOperators,
+			"should compare numbers of any type":         testMatchNumbers,
+			"should not match values of different types": testMatchDifferentTypes,
+			"should match any element of an array":       testMatchArrays,
+			"should treat missing fields as null":        testMatchMissingFields,
+			"should apply every filter of a field":       testMatchEveryFilterOfAField,
+			"should ignore unknown operators":            testMatchUnknownOperator,
+			"should require every filter to match":       testMatchEveryFilter,
+			"should compare dates and booleans":          testMatchDatesAndBooleans,
+			"should match any value of a list":           testMatchIn,
+			"should match patterns against strings":      testMatchPatterns,
+			"should match the presence of a field":       testMatchExists,
+		},
+		"match filter expressions": {
+			"should match nested expressions":         testMatchFilterExpression,
+			"should apply every filter in AND":        testMatchFilterExpressionFilters,
+			"should match groups without operands":    testMatchEmptyFilterExpression,
+			"should ignore unknown logical operators": testMatchFilterExpressionUnknownOperator,
+		},
+		"sort documents": {
+			"should sort ascending and descending":          testSortDirections,
+			"should sort by several fields":                 testSortSeveralFields,
+			"should keep the order of ties":                 testSortStable,
+			"should sort arrays by their lowest or highest": testSortArrays,
+			"should sort different types in BSON order":     testSortDifferentTypes,
+		},
+		"paginate": {
+			"should return everything without pagination": testPaginateWithoutPagination,
+			"should skip and limit":                       testPaginate,
+			"should return an empty page past the end":    testPaginatePastTheEnd,
+			"should take negative limits as positive":     testPaginateNegativeLimit,
+			"should reject negative offsets":              testPaginateNegativeOffset,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							t.Parallel()
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+}
+
+func filter(field string, operator operators.Comparison, value interface{}) []query_utils.Filter {
+	return []query_utils.Filter{{Field: field, Operator: operator, Value: value}}
+}
+
+func testMatchWithoutFilters(t *testing.T) {
+	assert.True(t, MatchFilters(document, nil))
+}
+
+func testMatchOperators(t *testing.T) {
+	for operator, expected := range map[operators.Comparison][3]bool{
+		// Matches against a lower, an equal and a greater value.
+		operators.EQUALS:          {false, true, false},
+		operators.NOT_EQUALS:      {true, false, true},
+		operators.GREATER_THAN:    {true, false, false},
+		operators.GREATER_THAN_EQ: {true, true, false},
+		operators.LESS_THAN:       {false, false, true},
+		operators.LESS_THAN_EQ:    {false, true, true},
+	} {
+		for i, value := range []string{"jane", "john", "joseph"} {
+			assert.Equal(t, expected[i], MatchFilters(document, filter("name", operator, value)), operator, value)
+		}
+	}
+}
+
+func testMatchNumbers(t *testing.T) {
+	assert.True(t, MatchFilters(document, filter("age", operators.EQUALS, 30.0)))
+	assert.True(t, MatchFilters(document, filter("age", operators.GREATER_THAN, int32(29))))
+	assert.True(t, MatchFilters(document, filter("score", operators.LESS_THAN, int64(8))))
+}
+
+func testMatchDifferentTypes(t *testing.T) {
+	assert.False(t, MatchFilters(document, filter("age", operators.EQUALS, "30")))
+	assert.False(t, MatchFilters(document, filter("age", operators.GREATER_THAN, "1")))
+	assert.False(t, MatchFilters(document, filter("age", operators.LESS_THAN, "99")))
+	assert.True(t, MatchFilters(document, filter("age", operators.NOT_EQUALS, "30")))
+}
+
+func testMatchArrays(t *testing.T) {
+	assert.True(t, MatchFilters(document, filter("roles", operators.EQUALS, "admin")))
+	assert.False(t, MatchFilters(document, filter("roles", operators.NOT_EQUALS, "admin")))
+	assert.True(t, MatchFilters(document, filter("roles", operators.NOT_EQUALS, "guest")))
+	assert.True(t, MatchFilters(document, filter("roles", operators.GREATER_THAN, "guest")))
+	assert.False(t, MatchFilters(document, filter("roles", operators.GREATER_THAN, "user")))
+}
+
+func testMatchMissingFields(t *testing.T) {
+	assert.False(t, MatchFilters(document, filter("missing", operators.EQUALS, "value")))
+	assert.True(t, MatchFilters(document, filter("missing", operators.NOT_EQUALS, "value")))
+	assert.True(t, MatchFilters(document, filter("missing", operators.EQUALS, nil)))
+	assert.True(t, MatchFilters(document, filter("missing", operators.GREATER_THAN_EQ, nil)))
+	assert.False(t, MatchFilters(document, filter("missing", operators.GREATER_THAN, nil)))
+	assert.False(t, MatchFilters(document, filter("name", operators.EQUALS, nil)))
+	assert.True(t, MatchFilters(document, filter("name", operators.NOT_EQUALS, nil)))
+}
+
+func testMatchEveryFilterOfAField(t *testing.T) {
+	between := func(from int64, to int64) []query_utils.Filter {
+		return []query_utils.Filter{
+			{Field: "age", Operator: operators.GREATER_THAN, Value: from},
+			{Field: "age", Operator: operators.LESS_THAN, Value: to},
+		}
+	}
+
+	assert.True(t, MatchFilters(document, between(20, 40)))
+	assert.False(t, MatchFilters(document, between(20, 25)))
+	assert.False(t, MatchFilters(document, between(35, 40)))
+	assert.False(
+		t, MatchFilters(
+			document, []query_utils.Filter{
+				{Field: "name", Operator: operators.EQUALS, Value: "jane"},
+				{Field: "name", Operator: operators.EQUALS, Value: "john"},
+			},
+		),
+	)
+}
+
+func testMatchUnknownOperator(t *testing.T) {
+	assert.True(t, MatchFilters(document, filter("name", operators.Comparison(99), "jane")))
+}
+
+func testMatchEveryFilter(t *testing.T) {
+	filters := []query_utils.Filter{
+		{Field: "name", Operator: operators.EQUALS, Value: "john"},
+		{Field: "age", Operator: operators.GREATER_THAN, Value: int64(40)},
+	}
+
+	assert.False(t, MatchFilters(document, filters))
+}
+
+func testMatchDatesAndBooleans(t *testing.T) {
+	assert.True(t, MatchFilters(document, filter("created", operators.EQUALS, now)))
+	assert.True(t, MatchFilters(document, filter("created", operators.GREATER_THAN, now.Add(-time.Hour))))
+	assert.False(t, MatchFilters(document, filter("created", operators.GREATER_THAN, now.Add(time.Hour))))
+	assert.True(t, MatchFilters(document, filter("active", operators.EQUALS, true)))
+	assert.True(t, MatchFilters(document, filter("active", operators.GREATER_THAN, false)))
+	assert.False(t, MatchFilters(document, filter("active", operators.LESS_THAN, true)))
+}
+
+func testMatchIn(t *testing.T) {
+	assert.True(t, MatchFilters(document, filter("name", operators.IN, []interface{}{"jane", "john"})))
+	assert.False(t, MatchFilters(document, filter("name", operators.IN, []interface{}{"jane"})))
+	assert.False(t, MatchFilters(document, filter("name", operators.IN, []interface{}{})))
+	assert.True(t, MatchFilters(document, filter("roles", operators.IN, []interface{}{"admin", "guest"})))
+	assert.True(t, MatchFilters(document, filter("age", operators.IN, []interface{}{"30", 30.0})))
+	assert.True(t, MatchFilters(document, filter("missing", operators.IN, []interface{}{nil})))
+	assert.False(t, MatchFilters(document, filter("name", operators.NOT_IN, []interface{}{"jane", "john"})))
+	assert.True(t, MatchFilters(document, filter("name", operators.NOT_IN, []interface{}{"jane"})))
+	assert.False(t, MatchFilters(document, filter("roles", operators.NOT_IN, []interface{}{"admin"})))
+	assert.False(t, MatchFilters(document, filter("name", operators.IN, "john")))
+	assert.False(t, MatchFilters(document, filter("name", operators.NOT_IN, "jane")))
+}
+
+func testMatchPatterns(t *testing.T) {
+	assert.True(t, MatchFilters(document, filter("name", operators.STARTS_WITH, "jo")))
+	assert.False(t, MatchFilters(document, filter("name", operators.STARTS_WITH, "oh")))
+	assert.False(t, MatchFilters(document, filter("name", operators.STARTS_WITH, "J")))
+	assert.True(t, MatchFilters(document, filter("name", operators.CONTAINS, "OH")))
+	assert.False(t, MatchFilters(document, filter("name", operators.CONTAINS, ".")))
+	assert.True(t, MatchFilters(document, filter("name", operators.REGEX, "^j.h")))
+	assert.True(t, MatchFilters(document, filter("roles", operators.REGEX, "^adm")))
+	assert.False(t, MatchFilters(document, filter("name", operators.REGEX, "(")))
+	assert.False(t, MatchFilters(document, filter("age", operators.REGEX, "3")))
+	assert.False(t, MatchFilters(document, filter("name", operators.CONTAINS, 30)))
+}
+
+func testMatchExists(t *testing.T) {
+	assert.True(t, MatchFilters(document, filter("name", operators.EXISTS, true)))
+	assert.False(t, MatchFilters(document, filter("name", operators.EXISTS, false)))
+	assert.True(t, MatchFilters(document, filter("missing", operators.EXISTS, false)))
+	assert.False(t, MatchFilters(document, filter("missing", operators.EXISTS, true)))
+	assert.False(t, MatchFilters(document, filter("name", operators.EXISTS, "true")))
+}
+
+func testMatchFilterExpression(t *testing.T) {
+	// name = john AND (age > 40 OR NOT roles = guest)
+	expression := query_utils.FilterExpression{
+		Filters: filter("name", operators.EQUALS, "john"),
+		Expressions: []query_utils.FilterExpression{
+			{
+				Operator: operators.OR,
+				Filters:  filter("age", operators.GREATER_THAN, int64(40)),
+				Expressions: []query_utils.FilterExpression{
+					{Operator: operators.NOT, Filters: filter("roles", operators.EQUALS, "guest")},
+				},
+			},
+		},
+	}
+
+	assert.True(t, MatchFilterExpression(document, expression))
+
+	expression.Expressions[0].Expressions[0].Filters = filter("roles", operators.EQUALS, "admin")
+
+	assert.False(t, MatchFilterExpression(document, expression))
+}
+
+func testMatchFilterExpressionFilters(t *testing.T) {
+	filters := []query_utils.Filter{
+		{Field: "name", Operator: operators.EQUALS, Value: "jane"},
+		{Field: "name", Operator: operators.EQUALS, Value: "john"},
+	}
+
+	assert.False(t, MatchFilterExpression(document, query_utils.FilterExpression{Filters: filters}))
+	assert.True(t, MatchFilterExpression(document, query_utils.FilterExpression{Operator: operators.OR, Filters: filters}))
+	assert.False(
+		t, MatchFilterExpression(document, query_utils.FilterExpression{Operator: operators.NOT, Filters: filters}),
+	)
+}
+
+func testMatchEmptyFilterExpression(t *testing.T) {
+	assert.True(t, MatchFilterExpression(document, query_utils.FilterExpression{Operator: operators.AND}))
+	assert.False(t, MatchFilterExpression(document, query_utils.FilterExpression{Operator: operators.OR}))
+	assert.True(t, MatchFilterExpression(document, query_utils.FilterExpression{Operator: operators.NOT}))
+}
+
+func testMatchFilterExpressionUnknownOperator(t *testing.T) {
+	expression := query_utils.FilterExpression{
+		Operator: operators.Logical(-1),
+		Filters:  filter("name", operators.EQUALS, "jane"),
+	}
+
+	assert.True(t, MatchFilterExpression(document, expression))
+}
+
+func names(documents []Document) (out []interface{}) {
+	for _, doc := range documents {
+		out = append(out, doc["name"])
+	}
+
+	return out
+}
+
+func testSortDirections(t *testing.T) {
+	documents := []Document{{"name": "b"}, {"name": "c"}, {"name": "a"}}
+
+	SortDocuments(documents, []query_utils.Sort{{Field: "name", Direction: operators.ASC}})
+	assert.Equal(t, []interface{}{"a", "b", "c"}, names(documents))
+
+	SortDocuments(documents, []query_utils.Sort{{Field: "name", Direction: operators.DESC}})
+	assert.Equal(t, []interface{}{"c", "b", "a"}, names(documents))
+}
+
+func testSortSeveralFields(t *testing.T) {
+	documents := []Document{
+		{"name": "a", "age": 2},
+		{"name": "b", "age": 1},
+		{"name": "c", "age": 2},
+	}
+
+	SortDocuments(
+		documents, []query_utils.Sort{
+			{Field: "age", Direction: operators.DESC},
+			{Field: "name", Direction: operators.DESC},
+		},
+	)
+
+	assert.Equal(t, []interface{}{"c", "a", "b"}, names(documents))
+}
+
+func testSortStable(t *testing.T) {
+	documents := []Document{{"name": "a", "age": 1}, {"name": "b", "age": 1}, {"name": "c", "age": 0}}
+
+	SortDocuments(documents, []query_utils.Sort{{Field: "age", Direction: operators.ASC}})
+
+	assert.Equal(t, []interface{}{"c", "a", "b"}, names(documents))
+}
+
+func testSortArrays(t *testing.T) {
+	documents := []Document{
+		{"name": "a", "roles": []interface{}{"b", "c"}},
+		{"name": "b", "roles": []interface{}{"a", "d"}},
+	}
+
+	SortDocuments(documents, []query_utils.Sort{{Field: "roles", Direction: operators.ASC}})
+	assert.Equal(t, []interface{}{"b", "a"}, names(documents))
+
+	SortDocuments(documents, []query_utils.Sort{{Field: "roles", Direction: operators.DESC}})
+	assert.Equal(t, []interface{}{"b", "a"}, names(documents))
+
+	// Now "b" comes first, and its highest role is lower than the one of "a".
+	documents[0]["roles"] = []interface{}{"a"}
+	SortDocuments(documents, []query_utils.Sort{{Field: "roles", Direction: operators.DESC}})
+	assert.Equal(t, []interface{}{"a", "b"}, names(documents))
+}
+
+func testSortDifferentTypes(t *testing.T) {
+	documents := []Document{
+		{"name": "date", "value": now},
+		{"name": "bool", "value": true},
+		{"name": "string", "value": "a"},
+		{"name": "number", "value": 1},
+		{"name": "missing"},
+		{"name": "empty", "value": []interface{}{}},
+	}
+
+	SortDocuments(documents, []query_utils.Sort{{Field: "value", Direction: operators.ASC}})
+
+	assert.Equal(t, []interface{}{"empty", "missing", "number", "string", "bool", "date"}, names(documents))
+}
+
+func testPaginateWithoutPagination(t *testing.T) {
+	start, end, err := Paginate(5, query_utils.Pagination{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, 5, end)
+}
+
+func testPaginate(t *testing.T) {
+	start, end, err := Paginate(5, query_utils.Pagination{Limit: 2, Offset: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, start)
+	assert.Equal(t, 3, end)
+}
+
+func testPaginatePastTheEnd(t *testing.T) {
+	start, end, err := Paginate(5, query_utils.Pagination{Limit: 2, Offset: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, start)
+	assert.Equal(t, 5, end)
+}
+
+func testPaginateNegativeLimit(t *testing.T) {
+	start, end, err := Paginate(5, query_utils.Pagination{Limit: -2, Offset: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, start)
+	assert.Equal(t, 4, end)
+}
+
+func testPaginateNegativeOffset(t *testing.T) {
+	_, _, err := Paginate(5, query_utils.Pagination{Offset: -1})
+
+	assert.EqualError(t, err, "negative offset -1")
+}
@@ -0,0 +1,28 @@
+package mongo_utils
+
+import (
+	"go.mongodb.org/mongo-driver/mongo"
+	"regexp"
+)
+
+var duplicateKeyIndexRegexp = regexp.MustCompile(`index: (\S+) dup key`)
+
+/*
+DuplicateKeyIndex returns the name of the unique index that the given error violated, if any.
+
+MongoDB only reports the index in the error message, so that's where we take it
+from.
+*/
+func DuplicateKeyIndex(err error) (string, bool) {
+	if !mongo.IsDuplicateKeyError(err) {
+		return "", false
+	}
+
+	matches := duplicateKeyIndexRegexp.FindStringSubmatch(err.Error())
+
+	if matches == nil {
+		return "", false
+	}
+
+	return matches[1], true
+}
@@ -0,0 +1,60 @@
+package mongo_utils
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"testing"
+)
+
+func TestMongoErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should return the violated index":           testDuplicateKeyIndex,
+		"should return false for other write errors": testDuplicateKeyIndexWithOtherWriteError,
+		"should return false for other errors":       testDuplicateKeyIndexWithOtherError,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testDuplicateKeyIndex(t *testing.T) {
+	err := mongo.WriteException{
+		WriteErrors: []mongo.WriteError{
+			{
+				Code: 11000,
+				Message: `E11000 duplicate key error collection: test.user index: nickname_unique dup key: ` +
+					`{ nickname: "john-123" }`,
+			},
+		},
+	}
+
+	index, ok := DuplicateKeyIndex(err)
+
+	assert.True(t, ok)
+	assert.Equal(t, "nickname_unique", index)
+}
+
+func testDuplicateKeyIndexWithOtherWriteError(t *testing.T) {
+	err := mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{Code: 121, Message: "Document failed validation"}},
+	}
+
+	_, ok := DuplicateKeyIndex(err)
+
+	assert.False(t, ok)
+}
+
+func testDuplicateKeyIndexWithOtherError(t *testing.T) {
+	_, ok := DuplicateKeyIndex(errors.New("db is down"))
+
+	assert.False(t, ok)
+}
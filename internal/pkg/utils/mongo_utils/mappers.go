@@ -8,21 +8,166 @@ import (
 
 /* Comparison mappers */
 
+/*
+MapFilterToBson maps filters that must all be met. The conditions of the filters on the same field are merged, like
+{"$gt": x, "$lt": y} for a range, and the ones repeating an operator of the field go to $and instead.
+*/
 func MapFilterToBson(filter []query_utils.Filter) bson.M {
 	// When applying a filter, the document order for the filters doesn't matter, so we use bson.M
 	bsonFilter := bson.M{}
+	var repeated bson.A
 
 	for _, f := range filter {
-		operator := MapComparisonOperatorToBson(f.Operator)
+		condition := MapConditionToBson(f)
 
-		if operator != "" {
-			bsonFilter[f.Field] = bson.M{operator: f.Value}
+		if condition == nil {
+			continue
 		}
+
+		previous, ok := bsonFilter[f.Field].(bson.M)
+
+		if !ok {
+			bsonFilter[f.Field] = condition
+			continue
+		}
+
+		if merged, ok := mergeConditions(previous, condition); ok {
+			bsonFilter[f.Field] = merged
+		} else {
+			repeated = append(repeated, bson.M{f.Field: condition})
+		}
+	}
+
+	if len(repeated) > 0 {
+		bsonFilter["$and"] = repeated
 	}
 
 	return bsonFilter
 }
 
+/*
+mergeConditions returns a new condition with the operators of both, or false if they share any.
+*/
+func mergeConditions(a bson.M, b bson.M) (bson.M, bool) {
+	merged := bson.M{}
+
+	for operator, value := range a {
+		merged[operator] = value
+	}
+
+	for operator, value := range b {
+		if _, ok := merged[operator]; ok {
+			return nil, false
+		}
+
+		merged[operator] = value
+	}
+
+	return merged, true
+}
+
+/*
+MapFilterExpressionToBson maps a filter expression to $and, $or and $nor, mapping the filters of AND groups as
+MapFilterToBson does.
+
+An OR without operands matches nothing, while an AND or a NOT without them
+matches everything. Expressions with unknown operators are ignored.
+*/
+func MapFilterExpressionToBson(expression query_utils.FilterExpression) bson.M {
+	switch expression.Operator {
+	case operators.AND:
+		bsonFilter := MapFilterToBson(expression.Filters)
+
+		if len(expression.Expressions) > 0 {
+			repeated, _ := bsonFilter["$and"].(bson.A)
+			bsonFilter["$and"] = append(repeated, mapFilterExpressionOperandsToBson(nil, expression.Expressions)...)
+		}
+
+		return bsonFilter
+	case operators.OR:
+		operands := mapFilterExpressionOperandsToBson(expression.Filters, expression.Expressions)
+
+		if len(operands) == 0 {
+			return bson.M{"$nor": bson.A{bson.M{}}}
+		}
+
+		return bson.M{"$or": operands}
+	case operators.NOT:
+		operands := mapFilterExpressionOperandsToBson(expression.Filters, expression.Expressions)
+
+		if len(operands) == 0 {
+			return bson.M{}
+		}
+
+		return bson.M{"$nor": operands}
+	default:
+		return bson.M{}
+	}
+}
+
+/*
+mapFilterExpressionOperandsToBson maps every filter and expression to a document of its own, as MongoDB expects in the
+operands of $and, $or and $nor.
+*/
+func mapFilterExpressionOperandsToBson(
+	filters []query_utils.Filter, expressions []query_utils.FilterExpression,
+) bson.A {
+	operands := bson.A{}
+
+	for _, f := range filters {
+		operands = append(operands, MapFilterToBson([]query_utils.Filter{f}))
+	}
+
+	for _, expression := range expressions {
+		operands = append(operands, MapFilterExpressionToBson(expression))
+	}
+
+	return operands
+}
+
+/*
+neverMatches is a condition no value satisfies, for filters whose value doesn't fit their operator, as MongoDB would
+reject them otherwise.
+*/
+var neverMatches = bson.M{"$in": bson.A{}}
+
+/*
+MapConditionToBson maps the condition a filter sets on its field, or nil if its operator is unknown.
+*/
+func MapConditionToBson(f query_utils.Filter) bson.M {
+	operator := MapComparisonOperatorToBson(f.Operator)
+
+	switch f.Operator {
+	case operators.IN, operators.NOT_IN:
+		values, ok := f.Value.([]interface{})
+
+		if !ok {
+			return neverMatches
+		}
+
+		// A nil list would be stored as null, which MongoDB rejects.
+		return bson.M{operator: append(bson.A{}, values...)}
+	case operators.STARTS_WITH, operators.CONTAINS, operators.REGEX:
+		pattern, ok := query_utils.RegexPattern(f.Operator, f.Value)
+
+		if !ok {
+			return neverMatches
+		}
+
+		return bson.M{operator: pattern}
+	case operators.EXISTS:
+		if _, ok := f.Value.(bool); !ok {
+			return neverMatches
+		}
+	}
+
+	if operator == "" {
+		return nil
+	}
+
+	return bson.M{operator: f.Value}
+}
+
 func MapComparisonOperatorToBson(op operators.Comparison) string {
 	switch op {
 	case operators.EQUALS:
@@ -37,6 +182,14 @@ func MapComparisonOperatorToBson(op operators.Comparison) string {
 		return "$lt"
 	case operators.LESS_THAN_EQ:
 		return "$lte"
+	case operators.IN:
+		return "$in"
+	case operators.NOT_IN:
+		return "$nin"
+	case operators.STARTS_WITH, operators.CONTAINS, operators.REGEX:
+		return "$regex"
+	case operators.EXISTS:
+		return "$exists"
 	default:
 		return ""
 	}
@@ -69,3 +222,23 @@ func MapSortDirectionToBson(op operators.Sort) int {
 		return 0
 	}
 }
+
+/* Projection mappers */
+
+/*
+MapProjectionToBson returns the projection including just the given fields, or nil to include every field if there are
+none.
+*/
+func MapProjectionToBson(fields []string) bson.M {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	projection := bson.M{}
+
+	for _, field := range fields {
+		projection[field] = 1
+	}
+
+	return projection
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
 	"testing"
+	"time"
 )
 
 func TestMongoMappers(t *testing.T) {
@@ -15,6 +16,16 @@ func TestMongoMappers(t *testing.T) {
 		"mongo filter mapper": {
 			"should return the mapped filter": testMapFilterToBson,
 			"should return an empty filter":   testMapFilterToBsonWhenEmpty,
+			"should map the richer operators": testMapFilterWithRicherOperatorsToBson,
+			"should never match mismatches":   testMapFilterWithMismatchedValuesToBson,
+			"should merge filters of a field": testMapFilterWithRangeToBson,
+			"should keep repeated operators":  testMapFilterWithRepeatedOperatorsToBson,
+		},
+		"mongo filter expression mapper": {
+			"should map nested expressions":           testMapFilterExpressionToBson,
+			"should keep repeated operators in $and":  testMapFilterExpressionWithRepeatedOperatorsToBson,
+			"should map groups without operands":      testMapEmptyFilterExpressionToBson,
+			"should ignore unknown logical operators": testMapFilterExpressionWithUnknownOperatorToBson,
 		},
 		"mongo operator mapper": {
 			"should return the mapped operator": testMapOperatorToMongoOperator,
@@ -28,6 +39,10 @@ func TestMongoMappers(t *testing.T) {
 			"should return the mapped direction": testMapDirectionToMongoDirection,
 			"should return zero when invalid":    testMapDirectionToMongoDirectionWhenInvalid,
 		},
+		"mongo projection mapper": {
+			"should return the mapped projection": testMapProjectionToBson,
+			"should return no projection":         testMapProjectionToBsonWhenEmpty,
+		},
 	} {
 		testGroup := testGroup
 		t.Run(
@@ -66,6 +81,171 @@ func testMapFilterToBson(t *testing.T) {
 	assert.Equal(t, filter[0].Value, bsonFilter.(bson.M)[operator])
 }
 
+func testMapFilterWithRicherOperatorsToBson(t *testing.T) {
+	out := MapFilterToBson(
+		[]query_utils.Filter{
+			{Field: "country", Operator: operators.IN, Value: []interface{}{"ES", "FR"}},
+			{Field: "roles", Operator: operators.NOT_IN, Value: []interface{}{}},
+			{Field: "nickname", Operator: operators.STARTS_WITH, Value: "j.d"},
+			{Field: "email", Operator: operators.CONTAINS, Value: "(DOE)"},
+			{Field: "first_name", Operator: operators.REGEX, Value: "^J.*n$"},
+			{Field: "last_name", Operator: operators.EXISTS, Value: true},
+		},
+	)
+
+	assert.Equal(
+		t, bson.M{
+			"country":    bson.M{"$in": bson.A{"ES", "FR"}},
+			"roles":      bson.M{"$nin": bson.A{}},
+			"nickname":   bson.M{"$regex": `^j\.d`},
+			"email":      bson.M{"$regex": `(?i)\(DOE\)`},
+			"first_name": bson.M{"$regex": "^J.*n$"},
+			"last_name":  bson.M{"$exists": true},
+		}, out,
+	)
+}
+
+func testMapFilterWithMismatchedValuesToBson(t *testing.T) {
+	out := MapFilterToBson(
+		[]query_utils.Filter{
+			{Field: "country", Operator: operators.IN, Value: "ES"},
+			{Field: "nickname", Operator: operators.STARTS_WITH, Value: int64(1)},
+			{Field: "last_name", Operator: operators.EXISTS, Value: "yes"},
+			{Field: "email", Operator: -1234, Value: "me@john.com"},
+			{Field: "first_name", Operator: operators.REGEX, Value: "(a+)+$"},
+		},
+	)
+
+	assert.Equal(
+		t, bson.M{
+			"country":    bson.M{"$in": bson.A{}},
+			"nickname":   bson.M{"$in": bson.A{}},
+			"last_name":  bson.M{"$in": bson.A{}},
+			"first_name": bson.M{"$in": bson.A{}},
+		}, out,
+	)
+}
+
+func testMapFilterExpressionToBson(t *testing.T) {
+	expression := query_utils.FilterExpression{
+		Filters: []query_utils.Filter{{Field: "country", Operator: operators.EQUALS, Value: "ES"}},
+		Expressions: []query_utils.FilterExpression{
+			{
+				Operator: operators.OR,
+				Filters: []query_utils.Filter{
+					{Field: "nickname", Operator: operators.EQUALS, Value: "jane"},
+					{Field: "nickname", Operator: operators.EQUALS, Value: "john"},
+				},
+				Expressions: []query_utils.FilterExpression{
+					{
+						Operator: operators.NOT,
+						Filters:  []query_utils.Filter{{Field: "roles", Operator: operators.EQUALS, Value: "admin"}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(
+		t, bson.M{
+			"country": bson.M{"$eq": "ES"},
+			"$and": bson.A{
+				bson.M{
+					"$or": bson.A{
+						bson.M{"nickname": bson.M{"$eq": "jane"}},
+						bson.M{"nickname": bson.M{"$eq": "john"}},
+						bson.M{"$nor": bson.A{bson.M{"roles": bson.M{"$eq": "admin"}}}},
+					},
+				},
+			},
+		}, MapFilterExpressionToBson(expression),
+	)
+}
+
+func testMapFilterExpressionWithRepeatedOperatorsToBson(t *testing.T) {
+	expression := query_utils.FilterExpression{
+		Filters: []query_utils.Filter{
+			{Field: "roles", Operator: operators.EQUALS, Value: "user"},
+			{Field: "roles", Operator: operators.EQUALS, Value: "admin"},
+		},
+		Expressions: []query_utils.FilterExpression{
+			{
+				Operator: operators.NOT,
+				Filters:  []query_utils.Filter{{Field: "country", Operator: operators.EQUALS, Value: "ES"}},
+			},
+		},
+	}
+
+	assert.Equal(
+		t, bson.M{
+			"roles": bson.M{"$eq": "user"},
+			"$and": bson.A{
+				bson.M{"roles": bson.M{"$eq": "admin"}},
+				bson.M{"$nor": bson.A{bson.M{"country": bson.M{"$eq": "ES"}}}},
+			},
+		}, MapFilterExpressionToBson(expression),
+	)
+}
+
+func testMapEmptyFilterExpressionToBson(t *testing.T) {
+	assert.Equal(t, bson.M{}, MapFilterExpressionToBson(query_utils.FilterExpression{Operator: operators.AND}))
+	assert.Equal(
+		t, bson.M{"$nor": bson.A{bson.M{}}}, MapFilterExpressionToBson(query_utils.FilterExpression{Operator: operators.OR}),
+	)
+	assert.Equal(t, bson.M{}, MapFilterExpressionToBson(query_utils.FilterExpression{Operator: operators.NOT}))
+}
+
+func testMapFilterExpressionWithUnknownOperatorToBson(t *testing.T) {
+	expression := query_utils.FilterExpression{
+		Operator: operators.Logical(-1),
+		Filters:  []query_utils.Filter{{Field: "country", Operator: operators.EQUALS, Value: "ES"}},
+	}
+
+	assert.Equal(t, bson.M{}, MapFilterExpressionToBson(expression))
+}
+
+func testMapFilterWithRangeToBson(t *testing.T) {
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	out := MapFilterToBson(
+		[]query_utils.Filter{
+			{Field: "created_at", Operator: operators.GREATER_THAN_EQ, Value: from},
+			{Field: "country", Operator: operators.EQUALS, Value: "ES"},
+			{Field: "created_at", Operator: operators.LESS_THAN, Value: from.Add(time.Hour)},
+		},
+	)
+
+	assert.Equal(
+		t, bson.M{
+			"created_at": bson.M{"$gte": from, "$lt": from.Add(time.Hour)},
+			"country":    bson.M{"$eq": "ES"},
+		}, out,
+	)
+}
+
+func testMapFilterWithRepeatedOperatorsToBson(t *testing.T) {
+	out := MapFilterToBson(
+		[]query_utils.Filter{
+			{Field: "roles", Operator: operators.EQUALS, Value: "user"},
+			{Field: "roles", Operator: operators.NOT_IN, Value: "guest"},
+			{Field: "roles", Operator: operators.EQUALS, Value: "admin"},
+			{Field: "roles", Operator: operators.IN, Value: "root"},
+		},
+	)
+
+	// Conditions of mismatched values are merged as any other, without sharing the same document.
+	assert.Equal(
+		t, bson.M{
+			"roles": bson.M{"$eq": "user", "$in": bson.A{}},
+			"$and": bson.A{
+				bson.M{"roles": bson.M{"$eq": "admin"}},
+				bson.M{"roles": bson.M{"$in": bson.A{}}},
+			},
+		}, out,
+	)
+	assert.Equal(t, bson.M{"$in": bson.A{}}, MapConditionToBson(query_utils.Filter{Operator: operators.IN}))
+}
+
 func testMapFilterToBsonWhenEmpty(t *testing.T) {
 	out := MapFilterToBson([]query_utils.Filter{})
 	assert.Empty(t, out)
@@ -89,6 +269,24 @@ func testMapOperatorToMongoOperator(t *testing.T) {
 
 	out = MapComparisonOperatorToBson(operators.LESS_THAN_EQ)
 	assert.Equal(t, "$lte", out)
+
+	out = MapComparisonOperatorToBson(operators.IN)
+	assert.Equal(t, "$in", out)
+
+	out = MapComparisonOperatorToBson(operators.NOT_IN)
+	assert.Equal(t, "$nin", out)
+
+	out = MapComparisonOperatorToBson(operators.STARTS_WITH)
+	assert.Equal(t, "$regex", out)
+
+	out = MapComparisonOperatorToBson(operators.CONTAINS)
+	assert.Equal(t, "$regex", out)
+
+	out = MapComparisonOperatorToBson(operators.REGEX)
+	assert.Equal(t, "$regex", out)
+
+	out = MapComparisonOperatorToBson(operators.EXISTS)
+	assert.Equal(t, "$exists", out)
 }
 
 func testMapOperatorToEmptyWhenInvalid(t *testing.T) {
@@ -127,3 +325,13 @@ func testMapDirectionToMongoDirectionWhenInvalid(t *testing.T) {
 	out := MapSortDirectionToBson(-1234)
 	assert.Equal(t, 0, out)
 }
+
+func testMapProjectionToBson(t *testing.T) {
+	out := MapProjectionToBson([]string{"id", "nickname"})
+	assert.Equal(t, bson.M{"id": 1, "nickname": 1}, out)
+}
+
+func testMapProjectionToBsonWhenEmpty(t *testing.T) {
+	out := MapProjectionToBson(nil)
+	assert.Nil(t, out)
+}
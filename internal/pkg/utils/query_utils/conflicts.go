@@ -0,0 +1,137 @@
+package query_utils
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"strings"
+	"time"
+)
+
+/*
+checkConflicts rejects a valid filter that conflicts with a previous valid filter of the same group on the same field:
+  - Two EQUALS, or two bounds of a range in the same direction, like "age > 30"
+    and "age >= 40", are duplicates, as the filters would either be redundant or
+    never match together. Other operators can be repeated, like two NOT_IN, as
+    each filter rules out other values.
+  - Comparisons leaving no value to match, like "age > 30" and "age < 20", or
+    EQUALS and NOT_EQUALS with the same value, are contradictory.
+
+Multi-valued fields are left alone, as each filter may be met by a different value.
+*/
+func (r *FieldRegistry) checkConflicts(path string, filter Filter, previous []Filter) error {
+	if r.fields[filter.Field].MultiValued {
+		return nil
+	}
+
+	for _, other := range previous {
+		if other.Field != filter.Field {
+			continue
+		}
+
+		if duplicates(other, filter) {
+			return r.invalidField(path+".operator", nil, ReasonDuplicateOperator)
+		}
+
+		if contradicts(other, filter) {
+			return r.invalidField(path+".operator", nil, ReasonContradictoryOperator)
+		}
+	}
+
+	return nil
+}
+
+/*
+duplicates tells whether both comparisons are EQUALS, or bound a range in the same direction.
+*/
+func duplicates(a Filter, b Filter) bool {
+	switch {
+	case a.Operator == operators.EQUALS:
+		return b.Operator == operators.EQUALS
+	case a.Operator == operators.GREATER_THAN, a.Operator == operators.GREATER_THAN_EQ:
+		return b.Operator == operators.GREATER_THAN || b.Operator == operators.GREATER_THAN_EQ
+	case a.Operator == operators.LESS_THAN, a.Operator == operators.LESS_THAN_EQ:
+		return b.Operator == operators.LESS_THAN || b.Operator == operators.LESS_THAN_EQ
+	default:
+		return false
+	}
+}
+
+/*
+contradicts tells whether no value can meet both comparisons, whose values are known to be of the same type.
+*/
+func contradicts(a Filter, b Filter) bool {
+	switch {
+	case a.Operator == operators.NOT_EQUALS && b.Operator == operators.EQUALS,
+		a.Operator == operators.EQUALS && b.Operator == operators.NOT_EQUALS:
+		cmp, ok := compareValues(a.Value, b.Value)
+
+		return ok && cmp == 0
+	case isLowerBound(a.Operator) && isUpperBound(b.Operator):
+		return emptyRange(a, b)
+	case isLowerBound(b.Operator) && isUpperBound(a.Operator):
+		return emptyRange(b, a)
+	default:
+		return false
+	}
+}
+
+func isLowerBound(op operators.Comparison) bool {
+	return op == operators.EQUALS || op == operators.GREATER_THAN || op == operators.GREATER_THAN_EQ
+}
+
+func isUpperBound(op operators.Comparison) bool {
+	return op == operators.EQUALS || op == operators.LESS_THAN || op == operators.LESS_THAN_EQ
+}
+
+/*
+emptyRange tells whether no value is above the lower bound and below the upper bound at once, EQUALS being both.
+*/
+func emptyRange(lower Filter, upper Filter) bool {
+	cmp, ok := compareValues(lower.Value, upper.Value)
+
+	if !ok {
+		return false
+	}
+
+	return cmp > 0 || cmp == 0 && (lower.Operator == operators.GREATER_THAN || upper.Operator == operators.LESS_THAN)
+}
+
+/*
+compareValues compares two filter values of the same type, or returns false if they aren't.
+*/
+func compareValues(a interface{}, b interface{}) (int, bool) {
+	switch a := a.(type) {
+	case string:
+		b, ok := b.(string)
+
+		return strings.Compare(a, b), ok
+	case int64:
+		b, ok := b.(int64)
+
+		return compareOrdered(a < b, a > b), ok
+	case float64:
+		b, ok := b.(float64)
+
+		return compareOrdered(a < b, a > b), ok
+	case bool:
+		b, ok := b.(bool)
+
+		return compareOrdered(!a && b, a && !b), ok
+	case time.Time:
+		b, ok := b.(time.Time)
+
+		return compareOrdered(a.Before(b), a.After(b)), ok
+	default:
+		return 0, false
+	}
+}
+
+func compareOrdered(less bool, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,423 @@
+package query_utils
+
+import (
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"time"
+)
+
+/*
+Reasons for rejecting a filter or a sort, on top of the ones in the errors package.
+*/
+const (
+	ReasonNotFilterable   = "NOT_FILTERABLE"
+	ReasonNotSortable     = "NOT_SORTABLE"
+	ReasonInvalidType     = "INVALID_TYPE"
+	ReasonInvalidOperator = "INVALID_OPERATOR"
+	// ReasonDuplicateOperator and ReasonContradictoryOperator reject filters on the same field of an AND group that
+	// repeat EQUALS or a bound of a range, or that no value could meet along with another one, like "age > 30" and
+	// "age < 20".
+	ReasonDuplicateOperator     = "DUPLICATE_OPERATOR"
+	ReasonContradictoryOperator = "CONTRADICTORY_OPERATOR"
+)
+
+/*
+ReasonTooDeep rejects filter expressions nested deeper than MaxExpressionDepth.
+*/
+const ReasonTooDeep = "TOO_DEEP"
+
+/*
+MaxExpressionDepth and MaxExpressionSize bound the filter expressions, counting
+the root expression as the first level, and every filter and nested expression
+towards the size, so a single query can't make the database walk an arbitrarily
+large tree.
+*/
+const (
+	MaxExpressionDepth = 4
+	MaxExpressionSize  = 100
+)
+
+/*
+MaxListValues is the most values a filter with the IN or NOT_IN operators can take, so a single query can't hold an
+unbounded list.
+*/
+const MaxListValues = 100
+
+type ValueType int
+
+const (
+	StringValue ValueType = iota
+	IntValue
+	DoubleValue
+	BoolValue
+	TimestampValue
+)
+
+/*
+Field describes how a field exposed by the API can be queried, and where it's stored.
+
+MultiValued fields hold several values, like a list of roles, and match a filter
+when any of their values does, so several filters on them never conflict.
+*/
+type Field struct {
+	Storage     string
+	Type        ValueType
+	Filterable  bool
+	Sortable    bool
+	MultiValued bool
+}
+
+/*
+FieldRegistry maps the fields an entity exposes to the API to their storage fields, so callers can only filter and sort
+on the declared ones, and never need to know how they're stored.
+*/
+type FieldRegistry struct {
+	domain string
+	fields map[string]Field
+}
+
+/*
+NewFieldRegistry builds the registry of the entity of the given domain, keyed by the API field names.
+*/
+func NewFieldRegistry(domain string, fields map[string]Field) *FieldRegistry {
+	return &FieldRegistry{domain: domain, fields: fields}
+}
+
+/*
+Map validates the filters and the sort against the registry, returning them with their storage field names.
+
+Unknown fields, fields that can't be filtered or sorted on, operators that don't
+apply to the field and values that don't fit the operator are rejected with an
+invalid field error naming them by their position, like "filters[0].field". As
+every filter applies, filters on the same field duplicating or contradicting
+each other, as checkConflicts tells, are rejected too.
+*/
+func (r *FieldRegistry) Map(filters []Filter, sort []Sort) ([]Filter, []Sort, error) {
+	var invalidFields []error
+	var mappedSort []Sort
+
+	mappedFilters := r.mapFilterGroup("filters", filters, &invalidFields)
+
+	for i, s := range sort {
+		field, err := r.lookup(fmt.Sprintf("sort[%d].field", i), s.Field)
+
+		if err == nil && !field.Sortable {
+			err = r.invalidField(fmt.Sprintf("sort[%d].field", i), s.Field, ReasonNotSortable)
+		}
+
+		if err != nil {
+			invalidFields = append(invalidFields, err)
+			continue
+		}
+
+		s.Field = field.Storage
+		mappedSort = append(mappedSort, s)
+	}
+
+	if err := joinInvalidFields(invalidFields); err != nil {
+		return nil, nil, err
+	}
+
+	return mappedFilters, mappedSort, nil
+}
+
+/*
+MapExpression validates a filter expression against the registry as Map does with the filters, returning it with the
+storage field names.
+
+The expression is also rejected as a whole when it's nested deeper than
+MaxExpressionDepth or holds more than MaxExpressionSize filters and expressions.
+Invalid fields are named by their path from the root, which is "filter", like
+"filter.expressions[0].filters[1].field".
+*/
+func (r *FieldRegistry) MapExpression(expression FilterExpression) (FilterExpression, error) {
+	if expressionDepth(expression) > MaxExpressionDepth {
+		return FilterExpression{}, r.invalidField("filter", nil, ReasonTooDeep)
+	}
+
+	if expressionSize(expression) > MaxExpressionSize {
+		return FilterExpression{}, r.invalidField("filter", nil, errors.ReasonTooLong)
+	}
+
+	var invalidFields []error
+	mapped := r.mapExpression("filter", expression, &invalidFields)
+
+	if err := joinInvalidFields(invalidFields); err != nil {
+		return FilterExpression{}, err
+	}
+
+	return mapped, nil
+}
+
+/*
+MapProjection validates the fields to read against the registry, returning their storage field names, or nil to read
+every field if there are none.
+
+Every declared field can be read. Unknown fields are rejected as Map does, named
+by their position under the given path, like "read_mask.paths[0]", and repeated
+ones are only returned once.
+*/
+func (r *FieldRegistry) MapProjection(path string, fields []string) ([]string, error) {
+	var invalidFields []error
+	var projection []string
+	seen := map[string]bool{}
+
+	for i, name := range fields {
+		field, err := r.lookup(fmt.Sprintf("%s[%d]", path, i), name)
+
+		if err != nil {
+			invalidFields = append(invalidFields, err)
+			continue
+		}
+
+		if !seen[field.Storage] {
+			seen[field.Storage] = true
+			projection = append(projection, field.Storage)
+		}
+	}
+
+	if err := joinInvalidFields(invalidFields); err != nil {
+		return nil, err
+	}
+
+	return projection, nil
+}
+
+func (r *FieldRegistry) mapExpression(
+	path string, expression FilterExpression, invalidFields *[]error,
+) FilterExpression {
+	mapped := FilterExpression{Operator: expression.Operator}
+
+	switch expression.Operator {
+	case operators.AND, operators.OR, operators.NOT:
+	default:
+		*invalidFields = append(*invalidFields, r.invalidField(path+".operator", nil, errors.ReasonUnknownValue))
+	}
+
+	if expression.Operator == operators.AND {
+		mapped.Filters = r.mapFilterGroup(path+".filters", expression.Filters, invalidFields)
+	} else {
+		for i, filter := range expression.Filters {
+			mappedFilter, err := r.mapFilter(fmt.Sprintf("%s.filters[%d]", path, i), filter)
+
+			if err != nil {
+				*invalidFields = append(*invalidFields, err)
+				continue
+			}
+
+			mapped.Filters = append(mapped.Filters, mappedFilter)
+		}
+	}
+
+	for i, nested := range expression.Expressions {
+		mapped.Expressions = append(
+			mapped.Expressions, r.mapExpression(fmt.Sprintf("%s.expressions[%d]", path, i), nested, invalidFields),
+		)
+	}
+
+	return mapped
+}
+
+/*
+mapFilterGroup validates filters that must all be met, like the ones of a list or an AND group, found at the given path,
+like "filters". Besides validating each of them, it rejects the ones conflicting with a previous filter of the same
+field.
+*/
+func (r *FieldRegistry) mapFilterGroup(path string, filters []Filter, invalidFields *[]error) []Filter {
+	var mappedFilters []Filter
+	var previous []Filter
+
+	for i, filter := range filters {
+		filterPath := fmt.Sprintf("%s[%d]", path, i)
+		mapped, err := r.mapFilter(filterPath, filter)
+
+		if err == nil {
+			err = r.checkConflicts(filterPath, filter, previous)
+		}
+
+		if err != nil {
+			*invalidFields = append(*invalidFields, err)
+			continue
+		}
+
+		previous = append(previous, filter)
+		mappedFilters = append(mappedFilters, mapped)
+	}
+
+	return mappedFilters
+}
+
+/*
+mapFilter validates the filter found at the given path, like "filters[0]", returning it with its storage field name.
+*/
+func (r *FieldRegistry) mapFilter(path string, filter Filter) (Filter, error) {
+	field, err := r.lookup(path+".field", filter.Field)
+
+	if err == nil && !field.Filterable {
+		err = r.invalidField(path+".field", filter.Field, ReasonNotFilterable)
+	}
+
+	if err == nil {
+		err = r.checkFilter(path, field, filter)
+	}
+
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Field = field.Storage
+
+	return filter, nil
+}
+
+func (r *FieldRegistry) lookup(path string, name string) (Field, error) {
+	field, ok := r.fields[name]
+
+	if !ok {
+		return field, r.invalidField(path, name, errors.ReasonUnknownValue)
+	}
+
+	return field, nil
+}
+
+/*
+checkFilter validates the operator and the value of the filter at the given path against the field:
+  - IN and NOT_IN take a list of up to MaxListValues values of the field's type.
+  - STARTS_WITH, CONTAINS and REGEX only apply to string fields, and REGEX takes
+    a regular expression CheckRegex accepts.
+  - EXISTS takes a bool, whatever the field's type.
+  - Every other operator takes a single value of the field's type.
+*/
+func (r *FieldRegistry) checkFilter(filterPath string, field Field, filter Filter) error {
+	path := filterPath + ".value"
+
+	if filter.Value == nil {
+		return r.invalidField(path, nil, errors.ReasonRequired)
+	}
+
+	switch filter.Operator {
+	case operators.IN, operators.NOT_IN:
+		values, ok := filter.Value.([]interface{})
+
+		if !ok {
+			return r.invalidField(path, filter.Value, ReasonInvalidType)
+		}
+
+		if len(values) > MaxListValues {
+			return r.invalidField(path, nil, errors.ReasonTooLong)
+		}
+
+		for j, value := range values {
+			if err := r.checkValue(fmt.Sprintf("%s[%d]", path, j), field, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case operators.STARTS_WITH, operators.CONTAINS, operators.REGEX:
+		if field.Type != StringValue {
+			return r.invalidField(filterPath+".operator", nil, ReasonInvalidOperator)
+		}
+
+		if err := r.checkValue(path, field, filter.Value); err != nil {
+			return err
+		}
+
+		if filter.Operator != operators.REGEX {
+			return nil
+		}
+
+		if reason := CheckRegex(filter.Value.(string)); reason != "" {
+			return r.invalidField(path, filter.Value, reason)
+		}
+
+		return nil
+	case operators.EXISTS:
+		if _, ok := filter.Value.(bool); !ok {
+			return r.invalidField(path, filter.Value, ReasonInvalidType)
+		}
+
+		return nil
+	default:
+		return r.checkValue(path, field, filter.Value)
+	}
+}
+
+func (r *FieldRegistry) checkValue(path string, field Field, value interface{}) error {
+	if value == nil {
+		return r.invalidField(path, nil, errors.ReasonRequired)
+	}
+
+	if valueType(value) != field.Type {
+		return r.invalidField(path, value, ReasonInvalidType)
+	}
+
+	return nil
+}
+
+func (r *FieldRegistry) invalidField(path string, value interface{}, reason string) *errors.InvalidField {
+	return &errors.InvalidField{
+		Domain: r.domain,
+		Field:  path,
+		Value:  value,
+		Reason: reason,
+	}
+}
+
+/*
+joinInvalidFields returns the only invalid field error, or all of them at once if there are more, or nil if there are
+none.
+*/
+func joinInvalidFields(invalidFields []error) error {
+	switch len(invalidFields) {
+	case 0:
+		return nil
+	case 1:
+		return invalidFields[0]
+	default:
+		return &errors.MultipleInvalidFields{Errors: invalidFields}
+	}
+}
+
+func expressionDepth(expression FilterExpression) int {
+	depth := 0
+
+	for _, nested := range expression.Expressions {
+		if nestedDepth := expressionDepth(nested); nestedDepth > depth {
+			depth = nestedDepth
+		}
+	}
+
+	return depth + 1
+}
+
+func expressionSize(expression FilterExpression) int {
+	size := len(expression.Filters)
+
+	for _, nested := range expression.Expressions {
+		size += 1 + expressionSize(nested)
+	}
+
+	return size
+}
+
+/*
+valueType returns the type of a filter value, as mapped from the API, or -1 if it isn't one of them.
+*/
+func valueType(value interface{}) ValueType {
+	switch value.(type) {
+	case string:
+		return StringValue
+	case int64:
+		return IntValue
+	case float64:
+		return DoubleValue
+	case bool:
+		return BoolValue
+	case time.Time:
+		return TimestampValue
+	default:
+		return -1
+	}
+}
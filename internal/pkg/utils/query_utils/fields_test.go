@@ -0,0 +1,579 @@
+package query_utils
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFields = NewFieldRegistry(
+	"Test", map[string]Field{
+		"name":       {Storage: "full_name", Type: StringValue, Filterable: true, Sortable: true},
+		"age":        {Storage: "age", Type: IntValue, Filterable: true},
+		"tags":       {Storage: "tags", Type: StringValue, Filterable: true, MultiValued: true},
+		"created_at": {Storage: "created", Type: TimestampValue, Sortable: true},
+	},
+)
+
+func TestFieldRegistry(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should map the fields to their storage":      testFieldRegistryMap,
+		"should map nothing":                          testFieldRegistryMapNothing,
+		"should reject unknown fields":                testFieldRegistryUnknownField,
+		"should reject fields not filterable":         testFieldRegistryNotFilterable,
+		"should reject fields not sortable":           testFieldRegistryNotSortable,
+		"should reject values of the wrong type":      testFieldRegistryInvalidType,
+		"should reject filters without value":         testFieldRegistryMissingValue,
+		"should report every invalid field at once":   testFieldRegistryMultipleInvalidFields,
+		"should map the richer operators":             testFieldRegistryRicherOperators,
+		"should reject invalid lists of values":       testFieldRegistryInvalidLists,
+		"should reject patterns on other types":       testFieldRegistryPatternOnOtherType,
+		"should reject invalid regular expressions":   testFieldRegistryInvalidRegex,
+		"should reject unsafe regular expressions":    testFieldRegistryUnsafeRegex,
+		"should reject exists without a bool":         testFieldRegistryExistsWithoutBool,
+		"should map ranges on a field":                testFieldRegistryRange,
+		"should reject duplicate operators":           testFieldRegistryDuplicateOperator,
+		"should reject duplicate range bounds":        testFieldRegistryDuplicateRangeBounds,
+		"should allow other repeated operators":       testFieldRegistryRepeatedOperators,
+		"should reject contradictory operators":       testFieldRegistryContradictoryOperators,
+		"should allow conflicts on multiple values":   testFieldRegistryMultiValuedConflicts,
+		"should only check conflicts in AND groups":   testFieldRegistryExpressionConflicts,
+		"should map expressions":                      testFieldRegistryMapExpression,
+		"should reject invalid fields of expressions": testFieldRegistryMapExpressionInvalidFields,
+		"should reject unknown logical operators":     testFieldRegistryMapExpressionUnknownOperator,
+		"should reject expressions too deep":          testFieldRegistryMapExpressionTooDeep,
+		"should reject expressions too large":         testFieldRegistryMapExpressionTooLarge,
+		"should map projections":                      testFieldRegistryMapProjection,
+		"should reject unknown projected fields":      testFieldRegistryMapProjectionUnknownField,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testFieldRegistryMap(t *testing.T) {
+	filters, sort, err := testFields.Map(
+		[]Filter{
+			{Field: "name", Operator: operators.EQUALS, Value: "John"},
+			{Field: "age", Operator: operators.GREATER_THAN, Value: int64(18)},
+		},
+		[]Sort{
+			{Field: "created_at", Direction: operators.DESC},
+			{Field: "name", Direction: operators.ASC},
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []Filter{
+			{Field: "full_name", Operator: operators.EQUALS, Value: "John"},
+			{Field: "age", Operator: operators.GREATER_THAN, Value: int64(18)},
+		}, filters,
+	)
+	assert.Equal(
+		t, []Sort{
+			{Field: "created", Direction: operators.DESC},
+			{Field: "full_name", Direction: operators.ASC},
+		}, sort,
+	)
+}
+
+func testFieldRegistryMapNothing(t *testing.T) {
+	filters, sort, err := testFields.Map(nil, nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, filters)
+	assert.Nil(t, sort)
+}
+
+func testFieldRegistryUnknownField(t *testing.T) {
+	filters, sort, err := testFields.Map(
+		[]Filter{{Field: "$where", Operator: operators.EQUALS, Value: "1"}}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{
+			Domain: "Test", Field: "filters[0].field", Value: "$where", Reason: errors.ReasonUnknownValue,
+		}, err,
+	)
+	assert.Nil(t, filters)
+	assert.Nil(t, sort)
+}
+
+func testFieldRegistryNotFilterable(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{{Field: "created_at", Operator: operators.EQUALS, Value: time.Now()}}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{
+			Domain: "Test", Field: "filters[0].field", Value: "created_at", Reason: ReasonNotFilterable,
+		}, err,
+	)
+}
+
+func testFieldRegistryNotSortable(t *testing.T) {
+	_, _, err := testFields.Map(nil, []Sort{{Field: "age", Direction: operators.ASC}})
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: "Test", Field: "sort[0].field", Value: "age", Reason: ReasonNotSortable}, err,
+	)
+}
+
+func testFieldRegistryInvalidType(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{{Field: "age", Operator: operators.EQUALS, Value: "18"}}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: "Test", Field: "filters[0].value", Value: "18", Reason: ReasonInvalidType}, err,
+	)
+}
+
+func testFieldRegistryMissingValue(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{{Field: "name", Operator: operators.EQUALS}}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: "Test", Field: "filters[0].value", Reason: errors.ReasonRequired}, err,
+	)
+}
+
+func testFieldRegistryMultipleInvalidFields(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{
+			{Field: "name", Operator: operators.EQUALS, Value: "John"},
+			{Field: "password", Operator: operators.EQUALS, Value: "secret"},
+		},
+		[]Sort{{Field: "age", Direction: operators.ASC}},
+	)
+
+	assert.Equal(
+		t, &errors.MultipleInvalidFields{
+			Errors: []error{
+				&errors.InvalidField{
+					Domain: "Test", Field: "filters[1].field", Value: "password", Reason: errors.ReasonUnknownValue,
+				},
+				&errors.InvalidField{Domain: "Test", Field: "sort[0].field", Value: "age", Reason: ReasonNotSortable},
+			},
+		}, err,
+	)
+}
+
+func testFieldRegistryRicherOperators(t *testing.T) {
+	filters, _, err := testFields.Map(
+		[]Filter{
+			{Field: "name", Operator: operators.IN, Value: []interface{}{"John", "Jane"}},
+			{Field: "age", Operator: operators.NOT_IN, Value: []interface{}{}},
+			{Field: "name", Operator: operators.CONTAINS, Value: "(oh"},
+			{Field: "name", Operator: operators.REGEX, Value: "^J(o|a)"},
+			{Field: "age", Operator: operators.EXISTS, Value: false},
+		}, nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []Filter{
+			{Field: "full_name", Operator: operators.IN, Value: []interface{}{"John", "Jane"}},
+			{Field: "age", Operator: operators.NOT_IN, Value: []interface{}{}},
+			{Field: "full_name", Operator: operators.CONTAINS, Value: "(oh"},
+			{Field: "full_name", Operator: operators.REGEX, Value: "^J(o|a)"},
+			{Field: "age", Operator: operators.EXISTS, Value: false},
+		}, filters,
+	)
+}
+
+func testFieldRegistryInvalidLists(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{
+			{Field: "name", Operator: operators.IN, Value: "John"},
+			{Field: "age", Operator: operators.NOT_IN, Value: []interface{}{int64(18), "19"}},
+			{Field: "name", Operator: operators.IN, Value: make([]interface{}, MaxListValues+1)},
+		}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.MultipleInvalidFields{
+			Errors: []error{
+				&errors.InvalidField{Domain: "Test", Field: "filters[0].value", Value: "John", Reason: ReasonInvalidType},
+				&errors.InvalidField{Domain: "Test", Field: "filters[1].value[1]", Value: "19", Reason: ReasonInvalidType},
+				&errors.InvalidField{Domain: "Test", Field: "filters[2].value", Reason: errors.ReasonTooLong},
+			},
+		}, err,
+	)
+}
+
+func testFieldRegistryPatternOnOtherType(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{{Field: "age", Operator: operators.STARTS_WITH, Value: "1"}}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: "Test", Field: "filters[0].operator", Reason: ReasonInvalidOperator}, err,
+	)
+}
+
+func testFieldRegistryInvalidRegex(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{{Field: "name", Operator: operators.REGEX, Value: "(oh"}}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{
+			Domain: "Test", Field: "filters[0].value", Value: "(oh", Reason: errors.ReasonInvalidFormat,
+		}, err,
+	)
+}
+
+func testFieldRegistryUnsafeRegex(t *testing.T) {
+	tooLong := strings.Repeat("a", MaxRegexLength+1)
+
+	for pattern, reason := range map[string]string{
+		"(a+)+$":       ReasonUnsafePattern,
+		"(?:a*)*":      ReasonUnsafePattern,
+		`(\w+\s?){2,}`: ReasonUnsafePattern,
+		"(a?)*":        ReasonUnsafePattern,
+		`(a)\1`:        errors.ReasonInvalidFormat,
+		"(?=a)":        errors.ReasonInvalidFormat,
+		tooLong:        errors.ReasonTooLong,
+	} {
+		_, _, err := testFields.Map(
+			[]Filter{{Field: "name", Operator: operators.REGEX, Value: pattern}}, nil,
+		)
+
+		assert.Equal(
+			t, &errors.InvalidField{Domain: "Test", Field: "filters[0].value", Value: pattern, Reason: reason}, err,
+			pattern,
+		)
+	}
+
+	// Quantifiers that don't repeat another one are safe.
+	for _, pattern := range []string{"^J(o|a)+n$", "(ab?)?c", "(a+){1}", strings.Repeat("a", MaxRegexLength)} {
+		_, _, err := testFields.Map(
+			[]Filter{{Field: "name", Operator: operators.REGEX, Value: pattern}}, nil,
+		)
+
+		assert.NoError(t, err, pattern)
+	}
+}
+
+func testFieldRegistryExistsWithoutBool(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{{Field: "name", Operator: operators.EXISTS, Value: "true"}}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: "Test", Field: "filters[0].value", Value: "true", Reason: ReasonInvalidType}, err,
+	)
+}
+
+/*
+nestedExpression returns an expression with the given number of levels.
+*/
+func nestedExpression(levels int) FilterExpression {
+	expression := FilterExpression{Filters: []Filter{{Field: "name", Operator: operators.EQUALS, Value: "John"}}}
+
+	for i := 1; i < levels; i++ {
+		expression = FilterExpression{Operator: operators.NOT, Expressions: []FilterExpression{expression}}
+	}
+
+	return expression
+}
+
+func testFieldRegistryMapExpression(t *testing.T) {
+	expression, err := testFields.MapExpression(
+		FilterExpression{
+			Operator: operators.OR,
+			Filters:  []Filter{{Field: "name", Operator: operators.EQUALS, Value: "John"}},
+			Expressions: []FilterExpression{
+				{
+					Operator: operators.NOT,
+					Filters:  []Filter{{Field: "age", Operator: operators.LESS_THAN, Value: int64(18)}},
+				},
+			},
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, FilterExpression{
+			Operator: operators.OR,
+			Filters:  []Filter{{Field: "full_name", Operator: operators.EQUALS, Value: "John"}},
+			Expressions: []FilterExpression{
+				{
+					Operator: operators.NOT,
+					Filters:  []Filter{{Field: "age", Operator: operators.LESS_THAN, Value: int64(18)}},
+				},
+			},
+		}, expression,
+	)
+}
+
+func testFieldRegistryMapExpressionInvalidFields(t *testing.T) {
+	_, err := testFields.MapExpression(
+		FilterExpression{
+			Filters: []Filter{{Field: "password", Operator: operators.EQUALS, Value: "secret"}},
+			Expressions: []FilterExpression{
+				{
+					Operator: operators.OR,
+					Filters: []Filter{
+						{Field: "name", Operator: operators.EQUALS, Value: "John"},
+						{Field: "age", Operator: operators.EQUALS, Value: "18"},
+					},
+				},
+			},
+		},
+	)
+
+	assert.Equal(
+		t, &errors.MultipleInvalidFields{
+			Errors: []error{
+				&errors.InvalidField{
+					Domain: "Test", Field: "filter.filters[0].field", Value: "password", Reason: errors.ReasonUnknownValue,
+				},
+				&errors.InvalidField{
+					Domain: "Test", Field: "filter.expressions[0].filters[1].value", Value: "18", Reason: ReasonInvalidType,
+				},
+			},
+		}, err,
+	)
+}
+
+func testFieldRegistryMapExpressionUnknownOperator(t *testing.T) {
+	_, err := testFields.MapExpression(FilterExpression{Operator: operators.Logical(-1)})
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: "Test", Field: "filter.operator", Reason: errors.ReasonUnknownValue}, err,
+	)
+}
+
+func testFieldRegistryMapExpressionTooDeep(t *testing.T) {
+	_, err := testFields.MapExpression(nestedExpression(MaxExpressionDepth))
+
+	assert.NoError(t, err)
+
+	_, err = testFields.MapExpression(nestedExpression(MaxExpressionDepth + 1))
+
+	assert.Equal(t, &errors.InvalidField{Domain: "Test", Field: "filter", Reason: ReasonTooDeep}, err)
+}
+
+func testFieldRegistryMapExpressionTooLarge(t *testing.T) {
+	expression := FilterExpression{Operator: operators.OR}
+
+	for len(expression.Filters) < MaxExpressionSize {
+		expression.Filters = append(expression.Filters, Filter{Field: "name", Operator: operators.EQUALS, Value: "John"})
+	}
+
+	_, err := testFields.MapExpression(expression)
+
+	assert.NoError(t, err)
+
+	expression.Expressions = append(expression.Expressions, FilterExpression{})
+	_, err = testFields.MapExpression(expression)
+
+	assert.Equal(t, &errors.InvalidField{Domain: "Test", Field: "filter", Reason: errors.ReasonTooLong}, err)
+}
+
+func testFieldRegistryRange(t *testing.T) {
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	registry := NewFieldRegistry(
+		"Test", map[string]Field{
+			"created_at": {Storage: "created", Type: TimestampValue, Filterable: true},
+		},
+	)
+
+	filters, _, err := registry.Map(
+		[]Filter{
+			{Field: "created_at", Operator: operators.GREATER_THAN_EQ, Value: from},
+			{Field: "created_at", Operator: operators.LESS_THAN, Value: from.Add(time.Hour)},
+			{Field: "created_at", Operator: operators.NOT_EQUALS, Value: from.Add(time.Minute)},
+		}, nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []Filter{
+			{Field: "created", Operator: operators.GREATER_THAN_EQ, Value: from},
+			{Field: "created", Operator: operators.LESS_THAN, Value: from.Add(time.Hour)},
+			{Field: "created", Operator: operators.NOT_EQUALS, Value: from.Add(time.Minute)},
+		}, filters,
+	)
+}
+
+func testFieldRegistryDuplicateOperator(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{
+			{Field: "name", Operator: operators.EQUALS, Value: "John"},
+			{Field: "age", Operator: operators.EQUALS, Value: int64(18)},
+			{Field: "name", Operator: operators.EQUALS, Value: "Jane"},
+		}, nil,
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{Domain: "Test", Field: "filters[2].operator", Reason: ReasonDuplicateOperator}, err,
+	)
+}
+
+func testFieldRegistryDuplicateRangeBounds(t *testing.T) {
+	for _, filters := range [][]Filter{
+		{
+			{Field: "age", Operator: operators.GREATER_THAN, Value: int64(30)},
+			{Field: "age", Operator: operators.GREATER_THAN_EQ, Value: int64(40)},
+		},
+		{
+			{Field: "age", Operator: operators.LESS_THAN_EQ, Value: int64(30)},
+			{Field: "age", Operator: operators.LESS_THAN_EQ, Value: int64(40)},
+		},
+	} {
+		_, _, err := testFields.Map(filters, nil)
+
+		assert.Equal(
+			t, &errors.InvalidField{Domain: "Test", Field: "filters[1].operator", Reason: ReasonDuplicateOperator}, err,
+		)
+	}
+}
+
+func testFieldRegistryRepeatedOperators(t *testing.T) {
+	for _, filters := range [][]Filter{
+		{
+			{Field: "name", Operator: operators.NOT_EQUALS, Value: "John"},
+			{Field: "name", Operator: operators.NOT_EQUALS, Value: "Jane"},
+		},
+		{
+			{Field: "name", Operator: operators.NOT_IN, Value: []interface{}{"John"}},
+			{Field: "name", Operator: operators.NOT_IN, Value: []interface{}{"Jane"}},
+		},
+		{
+			{Field: "name", Operator: operators.CONTAINS, Value: "oh"},
+			{Field: "name", Operator: operators.CONTAINS, Value: "n"},
+		},
+		{
+			{Field: "name", Operator: operators.STARTS_WITH, Value: "J"},
+			{Field: "name", Operator: operators.REGEX, Value: "n$"},
+		},
+	} {
+		mapped, _, err := testFields.Map(filters, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, mapped, 2)
+	}
+}
+
+func testFieldRegistryContradictoryOperators(t *testing.T) {
+	for _, filters := range [][]Filter{
+		{
+			{Field: "age", Operator: operators.GREATER_THAN, Value: int64(30)},
+			{Field: "age", Operator: operators.LESS_THAN, Value: int64(20)},
+		},
+		{
+			{Field: "age", Operator: operators.LESS_THAN, Value: int64(20)},
+			{Field: "age", Operator: operators.GREATER_THAN_EQ, Value: int64(20)},
+		},
+		{
+			{Field: "age", Operator: operators.GREATER_THAN, Value: int64(20)},
+			{Field: "age", Operator: operators.LESS_THAN_EQ, Value: int64(20)},
+		},
+		{
+			{Field: "name", Operator: operators.EQUALS, Value: "John"},
+			{Field: "name", Operator: operators.LESS_THAN, Value: "Jane"},
+		},
+		{
+			{Field: "name", Operator: operators.NOT_EQUALS, Value: "John"},
+			{Field: "name", Operator: operators.EQUALS, Value: "John"},
+		},
+	} {
+		_, _, err := testFields.Map(filters, nil)
+
+		assert.Equal(
+			t, &errors.InvalidField{Domain: "Test", Field: "filters[1].operator", Reason: ReasonContradictoryOperator},
+			err, filters,
+		)
+	}
+
+	for _, filters := range [][]Filter{
+		{
+			{Field: "age", Operator: operators.GREATER_THAN_EQ, Value: int64(20)},
+			{Field: "age", Operator: operators.LESS_THAN_EQ, Value: int64(20)},
+		},
+		{
+			{Field: "name", Operator: operators.EQUALS, Value: "John"},
+			{Field: "name", Operator: operators.NOT_EQUALS, Value: "Jane"},
+		},
+		{
+			{Field: "name", Operator: operators.EQUALS, Value: "John"},
+			{Field: "name", Operator: operators.STARTS_WITH, Value: "Ja"},
+		},
+	} {
+		_, _, err := testFields.Map(filters, nil)
+
+		assert.NoError(t, err, filters)
+	}
+}
+
+func testFieldRegistryMultiValuedConflicts(t *testing.T) {
+	_, _, err := testFields.Map(
+		[]Filter{
+			{Field: "tags", Operator: operators.EQUALS, Value: "a"},
+			{Field: "tags", Operator: operators.EQUALS, Value: "b"},
+			{Field: "tags", Operator: operators.LESS_THAN, Value: "a"},
+		}, nil,
+	)
+
+	assert.NoError(t, err)
+}
+
+func testFieldRegistryExpressionConflicts(t *testing.T) {
+	filters := []Filter{
+		{Field: "name", Operator: operators.EQUALS, Value: "John"},
+		{Field: "name", Operator: operators.EQUALS, Value: "Jane"},
+	}
+
+	_, err := testFields.MapExpression(FilterExpression{Operator: operators.OR, Filters: filters})
+
+	assert.NoError(t, err)
+
+	_, err = testFields.MapExpression(
+		FilterExpression{Operator: operators.NOT, Expressions: []FilterExpression{{Filters: filters}}},
+	)
+
+	assert.Equal(
+		t, &errors.InvalidField{
+			Domain: "Test", Field: "filter.expressions[0].filters[1].operator", Reason: ReasonDuplicateOperator,
+		}, err,
+	)
+}
+
+func testFieldRegistryMapProjection(t *testing.T) {
+	projection, err := testFields.MapProjection("read_mask", []string{"name", "created_at", "name"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"full_name", "created"}, projection)
+
+	projection, err = testFields.MapProjection("read_mask", nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, projection)
+}
+
+func testFieldRegistryMapProjectionUnknownField(t *testing.T) {
+	projection, err := testFields.MapProjection("read_mask", []string{"name", "password"})
+
+	assert.Equal(
+		t, &errors.InvalidField{
+			Domain: "Test", Field: "read_mask[1]", Value: "password", Reason: errors.ReasonUnknownValue,
+		}, err,
+	)
+	assert.Nil(t, projection)
+}
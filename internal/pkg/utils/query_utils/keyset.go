@@ -0,0 +1,47 @@
+package query_utils
+
+import "github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+
+/*
+WithTiebreaker appends an ascending sort on the given unique field, unless the sort already holds it, so every result
+has a fixed position and pages never overlap nor skip results with the same sort values.
+*/
+func WithTiebreaker(sort []Sort, field string) []Sort {
+	for _, s := range sort {
+		if s.Field == field {
+			return sort
+		}
+	}
+
+	return append(append([]Sort{}, sort...), Sort{Field: field, Direction: operators.ASC})
+}
+
+/*
+KeysetFilter returns the filter expression matching the results that come after the one with the given values of the
+sort fields, in the order of the sort.
+
+For a sort on "a" and "b", both ascending, that's "a > x OR (a = x AND b > y)",
+which databases can resolve through an index instead of skipping the previous
+results. The values must be in the same order as the sort fields.
+*/
+func KeysetFilter(sort []Sort, values []interface{}) FilterExpression {
+	expression := FilterExpression{Operator: operators.OR}
+
+	for i, s := range sort {
+		group := FilterExpression{Operator: operators.AND}
+
+		for j := 0; j < i; j++ {
+			group.Filters = append(group.Filters, Filter{Field: sort[j].Field, Operator: operators.EQUALS, Value: values[j]})
+		}
+
+		operator := operators.GREATER_THAN
+		if s.Direction == operators.DESC {
+			operator = operators.LESS_THAN
+		}
+
+		group.Filters = append(group.Filters, Filter{Field: s.Field, Operator: operator, Value: values[i]})
+		expression.Expressions = append(expression.Expressions, group)
+	}
+
+	return expression
+}
@@ -0,0 +1,66 @@
+package query_utils
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKeyset(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should append the tiebreaker":               testWithTiebreaker,
+		"should keep a sort already on tiebreaker":   testWithTiebreakerAlreadySorted,
+		"should filter the results after the keyset": testKeysetFilter,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testWithTiebreaker(t *testing.T) {
+	sort := []Sort{{Field: "name", Direction: operators.DESC}}
+
+	assert.Equal(
+		t, []Sort{{Field: "name", Direction: operators.DESC}, {Field: "id", Direction: operators.ASC}},
+		WithTiebreaker(sort, "id"),
+	)
+	assert.Len(t, sort, 1)
+	assert.Equal(t, []Sort{{Field: "id", Direction: operators.ASC}}, WithTiebreaker(nil, "id"))
+}
+
+func testWithTiebreakerAlreadySorted(t *testing.T) {
+	sort := []Sort{{Field: "id", Direction: operators.DESC}, {Field: "name", Direction: operators.ASC}}
+
+	assert.Equal(t, sort, WithTiebreaker(sort, "id"))
+}
+
+func testKeysetFilter(t *testing.T) {
+	sort := []Sort{{Field: "name", Direction: operators.DESC}, {Field: "id", Direction: operators.ASC}}
+
+	assert.Equal(
+		t, FilterExpression{
+			Operator: operators.OR,
+			Expressions: []FilterExpression{
+				{
+					Operator: operators.AND,
+					Filters:  []Filter{{Field: "name", Operator: operators.LESS_THAN, Value: "john"}},
+				},
+				{
+					Operator: operators.AND,
+					Filters: []Filter{
+						{Field: "name", Operator: operators.EQUALS, Value: "john"},
+						{Field: "id", Operator: operators.GREATER_THAN, Value: "1"},
+					},
+				},
+			},
+		}, KeysetFilter(sort, []interface{}{"john", "1"}),
+	)
+}
@@ -9,4 +9,13 @@ const (
 	GREATER_THAN_EQ
 	LESS_THAN
 	LESS_THAN_EQ
+	// IN and NOT_IN take a []interface{} of values.
+	IN
+	NOT_IN
+	// STARTS_WITH, CONTAINS and REGEX take a string, and only match strings.
+	STARTS_WITH
+	CONTAINS
+	REGEX
+	// EXISTS takes a bool, telling whether the field must be set or not.
+	EXISTS
 )
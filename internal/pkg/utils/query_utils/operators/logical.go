@@ -0,0 +1,10 @@
+package operators
+
+type Logical int
+
+const (
+	AND Logical = iota
+	OR
+	// NOT matches when none of its operands does.
+	NOT
+)
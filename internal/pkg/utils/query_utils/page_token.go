@@ -0,0 +1,278 @@
+package query_utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"strings"
+	"time"
+)
+
+/*
+ReasonQueryMismatch rejects a page token issued for a query with other filters or sort.
+*/
+const ReasonQueryMismatch = "QUERY_MISMATCH"
+
+/*
+PageToken marks where a page of results ends, holding the values of the sort fields of its last result, so the next
+page can be found with KeysetFilter instead of skipping the previous ones.
+
+Query is the fingerprint of the query the token was issued for, as returned by
+QueryFingerprint, as the values only make sense for the same filters and sort.
+*/
+type PageToken struct {
+	Query  string
+	Values []interface{}
+}
+
+/*
+PageTokenSigner encodes the page tokens into opaque strings, signed with HMAC-SHA256 so callers can't forge them.
+*/
+type PageTokenSigner struct {
+	domain string
+	key    []byte
+}
+
+/*
+NewPageTokenSigner builds the signer of the page tokens of the entity of the given domain, which names it in the errors
+of the tokens it rejects.
+*/
+func NewPageTokenSigner(domain string, key []byte) *PageTokenSigner {
+	if len(key) == 0 {
+		panic("[query_utils/page_token] empty key")
+	}
+
+	return &PageTokenSigner{domain: domain, key: key}
+}
+
+type pageTokenPayload struct {
+	Query  string           `json:"q"`
+	Values []pageTokenValue `json:"v"`
+}
+
+/*
+pageTokenValue keeps the type of a value through JSON, which would otherwise turn timestamps into strings and integers
+into floats.
+*/
+type pageTokenValue struct {
+	String    *string    `json:"s,omitempty"`
+	Int       *int64     `json:"i,omitempty"`
+	Double    *float64   `json:"d,omitempty"`
+	Bool      *bool      `json:"b,omitempty"`
+	Timestamp *time.Time `json:"t,omitempty"`
+}
+
+/*
+Sign encodes the token, failing if any of its values isn't one of the filter value types.
+*/
+func (s *PageTokenSigner) Sign(token PageToken) (string, error) {
+	payload := pageTokenPayload{Query: token.Query, Values: make([]pageTokenValue, len(token.Values))}
+
+	for i, value := range token.Values {
+		encodedValue, err := encodePageTokenValue(value)
+
+		if err != nil {
+			return "", err
+		}
+
+		payload.Values[i] = encodedValue
+	}
+
+	encoded, err := json.Marshal(payload)
+
+	if err != nil {
+		return "", fmt.Errorf("encoding page token: %w", err)
+	}
+
+	body := base64.RawURLEncoding.EncodeToString(encoded)
+
+	return body + "." + base64.RawURLEncoding.EncodeToString(s.sign(body)), nil
+}
+
+func encodePageTokenValue(value interface{}) (pageTokenValue, error) {
+	var encoded pageTokenValue
+
+	switch castValue := value.(type) {
+	case string:
+		encoded.String = &castValue
+	case int64:
+		encoded.Int = &castValue
+	case float64:
+		encoded.Double = &castValue
+	case bool:
+		encoded.Bool = &castValue
+	case time.Time:
+		encoded.Timestamp = &castValue
+	default:
+		return encoded, fmt.Errorf("unsupported page token value %#v", value)
+	}
+
+	return encoded, nil
+}
+
+/*
+Verify decodes a token encoded by Sign, rejecting it as an invalid "page_token" field if it's malformed or its signature
+doesn't match.
+*/
+func (s *PageTokenSigner) Verify(token string) (PageToken, error) {
+	body, signature, ok := strings.Cut(token, ".")
+
+	if !ok {
+		return PageToken{}, s.invalidToken()
+	}
+
+	decodedSignature, err := base64.RawURLEncoding.DecodeString(signature)
+
+	if err != nil || !hmac.Equal(decodedSignature, s.sign(body)) {
+		return PageToken{}, s.invalidToken()
+	}
+
+	decodedBody, err := base64.RawURLEncoding.DecodeString(body)
+
+	if err != nil {
+		return PageToken{}, s.invalidToken()
+	}
+
+	var payload pageTokenPayload
+
+	if err := json.Unmarshal(decodedBody, &payload); err != nil {
+		return PageToken{}, s.invalidToken()
+	}
+
+	pageToken := PageToken{Query: payload.Query, Values: make([]interface{}, len(payload.Values))}
+
+	for i, value := range payload.Values {
+		switch {
+		case value.String != nil:
+			pageToken.Values[i] = *value.String
+		case value.Int != nil:
+			pageToken.Values[i] = *value.Int
+		case value.Double != nil:
+			pageToken.Values[i] = *value.Double
+		case value.Bool != nil:
+			pageToken.Values[i] = *value.Bool
+		case value.Timestamp != nil:
+			pageToken.Values[i] = *value.Timestamp
+		default:
+			return PageToken{}, s.invalidToken()
+		}
+	}
+
+	return pageToken, nil
+}
+
+func (s *PageTokenSigner) invalidToken() *errors.InvalidField {
+	return &errors.InvalidField{Domain: s.domain, Field: "page_token", Reason: errors.ReasonInvalidFormat}
+}
+
+func (s *PageTokenSigner) sign(body string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(body))
+
+	return mac.Sum(nil)
+}
+
+/*
+QueryFingerprint identifies a query by its filter and sort, so a page token can't be used to page through another one.
+
+The query is hashed in a canonical JSON encoding, keeping the type of every
+value, with timestamps in UTC, and no difference between missing and empty
+lists, so the same query always has the same fingerprint. It fails if any value
+isn't one of the filter value types.
+*/
+func QueryFingerprint(filter FilterExpression, sort []Sort) (string, error) {
+	expression, err := fingerprintExpression(filter)
+
+	if err != nil {
+		return "", err
+	}
+
+	query := fingerprintQuery{Filter: expression}
+
+	for _, s := range sort {
+		query.Sort = append(query.Sort, fingerprintSort{Field: s.Field, Direction: s.Direction})
+	}
+
+	encoded, err := json.Marshal(query)
+
+	if err != nil {
+		return "", fmt.Errorf("encoding query fingerprint: %w", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
+
+/*
+fingerprintQuery and the types it holds are the canonical encoding of a query hashed by QueryFingerprint.
+*/
+type fingerprintQuery struct {
+	Filter fingerprintFilterExpression `json:"f"`
+	Sort   []fingerprintSort           `json:"s,omitempty"`
+}
+
+type fingerprintFilterExpression struct {
+	Operator    operators.Logical             `json:"o"`
+	Filters     []fingerprintFilter           `json:"f,omitempty"`
+	Expressions []fingerprintFilterExpression `json:"e,omitempty"`
+}
+
+type fingerprintFilter struct {
+	Field    string               `json:"f"`
+	Operator operators.Comparison `json:"o"`
+	// List tells Values holds the values of a list, rather than a single value.
+	List   bool             `json:"l,omitempty"`
+	Values []pageTokenValue `json:"v,omitempty"`
+}
+
+type fingerprintSort struct {
+	Field     string         `json:"f"`
+	Direction operators.Sort `json:"d"`
+}
+
+func fingerprintExpression(expression FilterExpression) (fingerprintFilterExpression, error) {
+	encoded := fingerprintFilterExpression{Operator: expression.Operator}
+
+	for _, filter := range expression.Filters {
+		encodedFilter := fingerprintFilter{Field: filter.Field, Operator: filter.Operator}
+		values := []interface{}{filter.Value}
+
+		if list, ok := filter.Value.([]interface{}); ok {
+			encodedFilter.List = true
+			values = list
+		}
+
+		for _, value := range values {
+			if timestamp, ok := value.(time.Time); ok {
+				value = timestamp.UTC()
+			}
+
+			encodedValue, err := encodePageTokenValue(value)
+
+			if err != nil {
+				return encoded, err
+			}
+
+			encodedFilter.Values = append(encodedFilter.Values, encodedValue)
+		}
+
+		encoded.Filters = append(encoded.Filters, encodedFilter)
+	}
+
+	for _, nested := range expression.Expressions {
+		encodedNested, err := fingerprintExpression(nested)
+
+		if err != nil {
+			return encoded, err
+		}
+
+		encoded.Expressions = append(encoded.Expressions, encodedNested)
+	}
+
+	return encoded, nil
+}
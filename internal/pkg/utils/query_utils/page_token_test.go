@@ -0,0 +1,165 @@
+package query_utils
+
+import (
+	"encoding/base64"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageTokenSigner(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should verify the tokens it signs":       testPageTokenRoundTrip,
+		"should reject tampered tokens":           testPageTokenTampered,
+		"should reject tokens of another key":     testPageTokenOtherKey,
+		"should reject malformed tokens":          testPageTokenMalformed,
+		"should refuse unsupported values":        testPageTokenUnsupportedValue,
+		"should panic without key":                testNewPageTokenSignerWithoutKey,
+		"should fingerprint queries consistently": testQueryFingerprint,
+		"should fingerprint queries canonically":  testQueryFingerprintCanonical,
+		"should not fingerprint unknown values":   testQueryFingerprintUnsupportedValue,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+var testSigner = NewPageTokenSigner("User", []byte("secret"))
+
+var invalidPageToken = &errors.InvalidField{Domain: "User", Field: "page_token", Reason: errors.ReasonInvalidFormat}
+
+func testPageTokenRoundTrip(t *testing.T) {
+	token := PageToken{
+		Query: "query",
+		Values: []interface{}{
+			"john", int64(30), 1.5, true, time.Date(2022, 10, 1, 12, 0, 0, 123456789, time.UTC),
+		},
+	}
+
+	signed, err := testSigner.Sign(token)
+	require.NoError(t, err)
+
+	verified, err := testSigner.Verify(signed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, token, verified)
+}
+
+func testPageTokenTampered(t *testing.T) {
+	signed, err := testSigner.Sign(PageToken{Query: "query", Values: []interface{}{"john"}})
+	require.NoError(t, err)
+
+	forged, err := testSigner.Sign(PageToken{Query: "query", Values: []interface{}{"jane"}})
+	require.NoError(t, err)
+
+	body, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(signed, ".")
+
+	_, err = testSigner.Verify(body + "." + signature)
+
+	assert.Equal(t, invalidPageToken, err)
+}
+
+func testPageTokenOtherKey(t *testing.T) {
+	signed, err := NewPageTokenSigner("User", []byte("other")).Sign(PageToken{Query: "query"})
+	require.NoError(t, err)
+
+	_, err = testSigner.Verify(signed)
+
+	assert.Equal(t, invalidPageToken, err)
+}
+
+func testPageTokenMalformed(t *testing.T) {
+	for _, token := range []string{
+		"",
+		"no-signature",
+		"body.!!!",
+		"!!!." + signBody("!!!"),
+		"bm90LWpzb24." + signBody("bm90LWpzb24"),
+		// {"q":"query","v":[{}]}
+		"eyJxIjoicXVlcnkiLCJ2Ijpbe31dfQ." + signBody("eyJxIjoicXVlcnkiLCJ2Ijpbe31dfQ"),
+	} {
+		_, err := testSigner.Verify(token)
+
+		assert.Equal(t, invalidPageToken, err, token)
+	}
+}
+
+func signBody(body string) string {
+	return base64.RawURLEncoding.EncodeToString(testSigner.sign(body))
+}
+
+func testPageTokenUnsupportedValue(t *testing.T) {
+	_, err := testSigner.Sign(PageToken{Query: "query", Values: []interface{}{nil}})
+
+	assert.ErrorContains(t, err, "unsupported page token value")
+}
+
+func testNewPageTokenSignerWithoutKey(t *testing.T) {
+	assert.PanicsWithValue(
+		t, "[query_utils/page_token] empty key", func() {
+			NewPageTokenSigner("User", nil)
+		},
+	)
+}
+
+func testQueryFingerprint(t *testing.T) {
+	filter := FilterExpression{Filters: []Filter{{Field: "age", Operator: operators.GREATER_THAN, Value: int64(30)}}}
+	sort := []Sort{{Field: "name", Direction: operators.DESC}}
+
+	assert.Equal(t, fingerprint(t, filter, sort), fingerprint(t, filter, sort))
+	assert.NotEqual(t, fingerprint(t, filter, sort), fingerprint(t, filter, nil))
+	assert.NotEqual(t, fingerprint(t, filter, sort), fingerprint(t, FilterExpression{}, sort))
+}
+
+func testQueryFingerprintCanonical(t *testing.T) {
+	at := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	filter := func(createdAt time.Time, countries []interface{}) FilterExpression {
+		return FilterExpression{
+			Filters: []Filter{
+				{Field: "created_at", Operator: operators.GREATER_THAN, Value: createdAt},
+				{Field: "country", Operator: operators.NOT_IN, Value: countries},
+			},
+			Expressions: []FilterExpression{},
+		}
+	}
+
+	// The same instant in another zone, and missing and empty lists, are the same query.
+	assert.Equal(
+		t, fingerprint(t, filter(at, nil), nil),
+		fingerprint(t, filter(at.In(time.FixedZone("CEST", 2*60*60)), []interface{}{}), []Sort{}),
+	)
+
+	// Values of different types, or a list of a single value, aren't.
+	age := func(value interface{}) FilterExpression {
+		return FilterExpression{Filters: []Filter{{Field: "age", Operator: operators.IN, Value: value}}}
+	}
+
+	assert.NotEqual(t, fingerprint(t, age([]interface{}{int64(1)}), nil), fingerprint(t, age([]interface{}{1.0}), nil))
+	assert.NotEqual(t, fingerprint(t, age([]interface{}{"1"}), nil), fingerprint(t, age("1"), nil))
+}
+
+func testQueryFingerprintUnsupportedValue(t *testing.T) {
+	_, err := QueryFingerprint(FilterExpression{Filters: []Filter{{Field: "age", Value: int32(1)}}}, nil)
+
+	assert.ErrorContains(t, err, "unsupported page token value")
+}
+
+func fingerprint(t *testing.T, filter FilterExpression, sort []Sort) string {
+	fingerprint, err := QueryFingerprint(filter, sort)
+	require.NoError(t, err)
+
+	return fingerprint
+}
@@ -0,0 +1,100 @@
+package query_utils
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/errors"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"regexp"
+	"regexp/syntax"
+)
+
+/*
+MaxRegexLength is the longest pattern the REGEX operator takes.
+*/
+const MaxRegexLength = 100
+
+/*
+ReasonUnsafePattern rejects REGEX patterns nesting a quantifier inside another one, like "(a+)+".
+*/
+const ReasonUnsafePattern = "UNSAFE_PATTERN"
+
+/*
+RegexPattern returns the regular expression a string must match for the STARTS_WITH, CONTAINS or REGEX operators, or
+false if the operator or the value don't take one.
+
+The values of STARTS_WITH and CONTAINS are escaped, so they're matched
+literally. The pattern only uses the syntax shared by RE2, PCRE and Postgres,
+so it works the same in every storage.
+*/
+func RegexPattern(op operators.Comparison, value interface{}) (string, bool) {
+	text, ok := value.(string)
+
+	if !ok {
+		return "", false
+	}
+
+	switch op {
+	case operators.STARTS_WITH:
+		return "^" + regexp.QuoteMeta(text), true
+	case operators.CONTAINS:
+		return "(?i)" + regexp.QuoteMeta(text), true
+	case operators.REGEX:
+		if CheckRegex(text) != "" {
+			return "", false
+		}
+
+		return text, true
+	default:
+		return "", false
+	}
+}
+
+/*
+CheckRegex tells why a REGEX pattern can't be run, or returns an empty string if it can.
+
+Patterns are checked with RE2, which runs in linear time, but MongoDB runs them
+with PCRE, which backtracks, so a pattern RE2 takes can still take exponential
+time there. Besides being valid for RE2, which already rules out backreferences
+and lookarounds, the pattern must be at most MaxRegexLength long, and mustn't
+nest a quantifier inside another one that repeats it, which is what makes PCRE
+backtrack exponentially.
+*/
+func CheckRegex(pattern string) string {
+	if len(pattern) > MaxRegexLength {
+		return errors.ReasonTooLong
+	}
+
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+
+	if err != nil {
+		return errors.ReasonInvalidFormat
+	}
+
+	if hasNestedQuantifier(parsed, false) {
+		return ReasonUnsafePattern
+	}
+
+	return ""
+}
+
+/*
+hasNestedQuantifier tells whether there's a quantifier in the parsed pattern inside another one repeating it more than
+once.
+*/
+func hasNestedQuantifier(re *syntax.Regexp, repeated bool) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		if repeated {
+			return true
+		}
+
+		repeated = re.Op != syntax.OpQuest && (re.Op != syntax.OpRepeat || re.Max != 1)
+	}
+
+	for _, sub := range re.Sub {
+		if hasNestedQuantifier(sub, repeated) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -17,3 +17,15 @@ type Pagination struct {
 	Limit  int64
 	Offset int64
 }
+
+/*
+FilterExpression is a boolean expression of filters, combining its filters and nested expressions with its operator.
+
+The zero value is an AND group, so an expression holding just a list of
+filters behaves as the list does.
+*/
+type FilterExpression struct {
+	Operator    operators.Logical
+	Filters     []Filter
+	Expressions []FilterExpression
+}
@@ -0,0 +1,72 @@
+package sql_utils
+
+import (
+	"strconv"
+)
+
+/*
+Dialect is the flavour of SQL spoken by the database, deciding the driver, the placeholders and a few clauses.
+*/
+type Dialect int
+
+const (
+	SQLite Dialect = iota
+	Postgres
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case SQLite:
+		return "sqlite"
+	case Postgres:
+		return "postgres"
+	default:
+		return "Dialect(" + strconv.Itoa(int(d)) + ")"
+	}
+}
+
+/*
+DriverName returns the name the dialect's driver is registered with in database/sql.
+*/
+func (d Dialect) DriverName() string {
+	return d.String()
+}
+
+/*
+placeholder returns the placeholder of the n-th argument of a query, starting at 1.
+*/
+func (d Dialect) placeholder(n int) string {
+	if d == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+
+	return "?"
+}
+
+/*
+Args collects the arguments of a query, handing out the placeholder of each of them in the dialect.
+*/
+type Args struct {
+	dialect Dialect
+	values  []interface{}
+}
+
+func NewArgs(dialect Dialect) *Args {
+	return &Args{dialect: dialect}
+}
+
+/*
+Add appends an argument, returning the placeholder to use for it in the query.
+*/
+func (a *Args) Add(value interface{}) string {
+	a.values = append(a.values, value)
+
+	return a.dialect.placeholder(len(a.values))
+}
+
+/*
+Values returns the arguments in the order they were added, to be passed along with the query.
+*/
+func (a *Args) Values() []interface{} {
+	return a.values
+}
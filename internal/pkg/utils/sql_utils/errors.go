@@ -0,0 +1,42 @@
+package sql_utils
+
+import (
+	"errors"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"regexp"
+)
+
+const pqUniqueViolation = "23505"
+
+var sqliteUniqueIndexRegexp = regexp.MustCompile(`UNIQUE constraint failed: index '([^']+)'`)
+
+/*
+UniqueViolation returns the name of the unique index that the given error violated, if any.
+
+Postgres reports the index along with the error, while SQLite only names it in
+the message, and only for indexes on expressions, like lower(email), so that's
+where we take it from.
+*/
+func UniqueViolation(err error) (string, bool) {
+	var pqErr *pq.Error
+
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint, pqErr.Code == pqUniqueViolation
+	}
+
+	var sqliteErr *sqlite.Error
+
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		matches := sqliteUniqueIndexRegexp.FindStringSubmatch(sqliteErr.Error())
+
+		if matches == nil {
+			return "", false
+		}
+
+		return matches[1], true
+	}
+
+	return "", false
+}
@@ -0,0 +1,104 @@
+package sql_utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (version TEXT PRIMARY KEY)`
+
+/*
+Migrate applies, in name order, every '.sql' file of the migrations that hasn't been applied to the database yet.
+
+Each migration runs in its own transaction along with its record in the
+'schema_migrations' table, so a failed migration leaves nothing behind, and when
+two instances start at once, the primary key keeps the slower one from applying
+it again.
+*/
+func Migrate(ctx context.Context, db *sql.DB, dialect Dialect, migrations fs.FS) error {
+	names, err := fs.Glob(migrations, "*.sql")
+
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(names)
+
+	if _, err := db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("creating migrations table: %w", err)
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if applied[name] {
+			continue
+		}
+
+		if err := applyMigration(ctx, db, dialect, migrations, name); err != nil {
+			return fmt.Errorf("applying migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+
+	if err != nil {
+		return nil, fmt.Errorf("reading applied migrations: %w", err)
+	}
+
+	defer rows.Close()
+
+	applied := map[string]bool{}
+
+	for rows.Next() {
+		var name string
+
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("reading applied migrations: %w", err)
+		}
+
+		applied[name] = true
+	}
+
+	return applied, rows.Err()
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, dialect Dialect, migrations fs.FS, name string) error {
+	statements, err := fs.ReadFile(migrations, name)
+
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(statements)); err != nil {
+		return err
+	}
+
+	args := NewArgs(dialect)
+	insert := `INSERT INTO schema_migrations (version) VALUES (` + args.Add(name) + `)`
+
+	if _, err := tx.ExecContext(ctx, insert, args.Values()...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
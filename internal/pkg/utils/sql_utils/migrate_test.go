@@ -0,0 +1,169 @@
+package sql_utils
+
+import (
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSQLDatabase(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should apply the migrations in order":                testMigrate,
+		"should skip the applied migrations":                  testMigrateTwice,
+		"should roll back a failed migration":                 testMigrateWithError,
+		"should return the violated index of sqlite":          testUniqueViolationSQLite,
+		"should return the violated index of postgres":        testUniqueViolationPostgres,
+		"should return false for other postgres errors":       testUniqueViolationWithOtherPostgresError,
+		"should return false for other errors":                testUniqueViolationWithOtherError,
+		"should return false for other sqlite constraint err": testUniqueViolationWithOtherSQLiteError,
+		"should match regular expressions in sqlite":          testSQLiteRegexp,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func openTestDatabase(t *testing.T) *sql.DB {
+	db, err := sql.Open(SQLite.DriverName(), "file:"+filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(
+		func() {
+			_ = db.Close()
+		},
+	)
+
+	return db
+}
+
+var testMigrations = fstest.MapFS{
+	"0002_insert_users.sql": {Data: []byte(`INSERT INTO users (name) VALUES ('john');`)},
+	"0001_create_users.sql": {
+		Data: []byte(
+			`CREATE TABLE users (name TEXT NOT NULL); CREATE UNIQUE INDEX name_unique ON users (lower(name));`,
+		),
+	},
+	"README.md": {Data: []byte(`Not a migration`)},
+}
+
+func countUsers(t *testing.T, db *sql.DB) int {
+	var count int
+
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM users`).Scan(&count))
+
+	return count
+}
+
+func testMigrate(t *testing.T) {
+	db := openTestDatabase(t)
+
+	assert.NoError(t, Migrate(context.Background(), db, SQLite, testMigrations))
+
+	assert.Equal(t, 1, countUsers(t, db))
+}
+
+func testMigrateTwice(t *testing.T) {
+	db := openTestDatabase(t)
+
+	require.NoError(t, Migrate(context.Background(), db, SQLite, testMigrations))
+	assert.NoError(t, Migrate(context.Background(), db, SQLite, testMigrations))
+
+	assert.Equal(t, 1, countUsers(t, db))
+}
+
+func testMigrateWithError(t *testing.T) {
+	db := openTestDatabase(t)
+	migrations := fstest.MapFS{
+		"0001_create_users.sql": testMigrations["0001_create_users.sql"],
+		"0002_insert_users.sql": {Data: []byte(`INSERT INTO users (name) VALUES ('john'); INSERT INTO nowhere;`)},
+	}
+
+	err := Migrate(context.Background(), db, SQLite, migrations)
+
+	assert.ErrorContains(t, err, "applying migration 0002_insert_users.sql")
+	assert.Equal(t, 0, countUsers(t, db))
+
+	// The failed migration isn't recorded, so it's applied once fixed.
+	assert.NoError(t, Migrate(context.Background(), db, SQLite, testMigrations))
+	assert.Equal(t, 1, countUsers(t, db))
+}
+
+func testUniqueViolationSQLite(t *testing.T) {
+	db := openTestDatabase(t)
+	require.NoError(t, Migrate(context.Background(), db, SQLite, testMigrations))
+
+	_, err := db.Exec(`INSERT INTO users (name) VALUES ('john')`)
+
+	index, ok := UniqueViolation(errors.Wrap(err, "inserting user"))
+
+	assert.True(t, ok)
+	assert.Equal(t, "name_unique", index)
+}
+
+func testUniqueViolationWithOtherSQLiteError(t *testing.T) {
+	db := openTestDatabase(t)
+	require.NoError(t, Migrate(context.Background(), db, SQLite, testMigrations))
+
+	_, err := db.Exec(`INSERT INTO users (name) VALUES (NULL)`)
+	require.Error(t, err)
+
+	_, ok := UniqueViolation(err)
+
+	assert.False(t, ok)
+}
+
+func testUniqueViolationPostgres(t *testing.T) {
+	index, ok := UniqueViolation(&pq.Error{Code: "23505", Constraint: "nickname_unique"})
+
+	assert.True(t, ok)
+	assert.Equal(t, "nickname_unique", index)
+}
+
+func testUniqueViolationWithOtherPostgresError(t *testing.T) {
+	_, ok := UniqueViolation(&pq.Error{Code: "23502", Column: "nickname"})
+
+	assert.False(t, ok)
+}
+
+func testUniqueViolationWithOtherError(t *testing.T) {
+	_, ok := UniqueViolation(errors.New("db is down"))
+
+	assert.False(t, ok)
+}
+
+func testSQLiteRegexp(t *testing.T) {
+	db := openTestDatabase(t)
+
+	for _, test := range []struct {
+		text     interface{}
+		pattern  interface{}
+		expected bool
+	}{
+		{"john", "^jo", true},
+		{"john", "(?i)OH", true},
+		{"john", "^oh", false},
+		{"john", "(", false},
+		{int64(1), "1", false},
+		{nil, "", false},
+	} {
+		var matches sql.NullBool
+
+		require.NoError(t, db.QueryRow(`SELECT ? REGEXP ?`, test.text, test.pattern).Scan(&matches))
+
+		assert.Equal(t, test.expected, matches.Bool, test.text, test.pattern)
+	}
+}
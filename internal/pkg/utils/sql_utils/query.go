@@ -0,0 +1,515 @@
+package sql_utils
+
+import (
+	"fmt"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"strings"
+	"time"
+)
+
+type ColumnType int
+
+const (
+	Text ColumnType = iota
+	// Timestamp columns hold unix milliseconds, so they're ordered and rounded the same everywhere, as in MongoDB.
+	Timestamp
+	Integer
+)
+
+/*
+Column describes where a query field is stored. Columns are expected to be NOT NULL.
+
+Fields holding several values, like arrays in MongoDB, live in a separate table
+with a row per value, described by Values, and Name is then the column of that
+table holding the value.
+*/
+type Column struct {
+	Name   string
+	Type   ColumnType
+	Values *ValuesTable
+}
+
+type ValuesTable struct {
+	Name string
+	// ForeignKey is the column referencing the key of the main table.
+	ForeignKey string
+	// Order is the column holding the position of each value among the ones of its row.
+	Order string
+}
+
+/*
+Table describes how an entity is stored, mapping the query fields to their columns.
+
+InsertionOrder is a column increasing with each insert, which breaks ties when
+sorting, so rows keep the order they were added in, as in MongoDB.
+*/
+type Table struct {
+	Name           string
+	Key            string
+	InsertionOrder string
+	Columns        map[string]Column
+}
+
+/* Comparison mappers */
+
+/*
+MapFiltersToWhere builds the WHERE clause for the filters, or an empty string if there's nothing to filter, with the
+same semantics as mongo_utils.MapFilterToBson:
+  - Every filter applies, including several on the same field, like a range.
+  - Filters with unknown operators are ignored, and filters whose value doesn't
+    fit their operator never match.
+  - Values of a different type than the column never match, except for
+    NOT_EQUALS and NOT_IN, and unknown fields behave as null.
+  - Multi-valued fields match if any of their values does.
+
+The pattern operators rely on the REGEXP operator in SQLite, which this package
+registers, and on POSIX regular expressions in Postgres.
+*/
+func MapFiltersToWhere(table Table, filters []query_utils.Filter, args *Args) string {
+	conditions := mapFiltersToConditions(table, filters, args)
+
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+/*
+MapFilterExpressionToWhere builds the WHERE clause for a filter expression, or an empty string if there's nothing to
+filter, with the same semantics as mongo_utils.MapFilterExpressionToBson. The filters of AND groups are mapped as
+MapFiltersToWhere does.
+*/
+func MapFilterExpressionToWhere(table Table, expression query_utils.FilterExpression, args *Args) string {
+	condition := mapFilterExpressionToCondition(table, expression, args)
+
+	if condition == "" {
+		return ""
+	}
+
+	return "WHERE " + condition
+}
+
+/*
+mapFilterExpressionToCondition returns the condition of the expression, or an empty string if every row matches it.
+*/
+func mapFilterExpressionToCondition(table Table, expression query_utils.FilterExpression, args *Args) string {
+	switch expression.Operator {
+	case operators.AND:
+		conditions := mapFiltersToConditions(table, expression.Filters, args)
+
+		for _, nested := range expression.Expressions {
+			if condition := mapFilterExpressionToCondition(table, nested, args); condition != "" {
+				conditions = append(conditions, "("+condition+")")
+			}
+		}
+
+		return strings.Join(conditions, " AND ")
+	case operators.OR:
+		conditions := mapOperandsToConditions(table, expression, args)
+
+		if len(conditions) == 0 {
+			return falseCondition
+		}
+
+		return strings.Join(conditions, " OR ")
+	case operators.NOT:
+		conditions := mapOperandsToConditions(table, expression, args)
+
+		if len(conditions) == 0 {
+			return ""
+		}
+
+		return "NOT (" + strings.Join(conditions, " OR ") + ")"
+	default:
+		return ""
+	}
+}
+
+/*
+mapOperandsToConditions maps every filter and nested expression of the expression to a condition of its own, for OR
+and NOT. The ones every row matches are kept as a true condition, as their arguments may already be in args.
+*/
+func mapOperandsToConditions(table Table, expression query_utils.FilterExpression, args *Args) []string {
+	var conditions []string
+
+	for _, filter := range expression.Filters {
+		condition := mapFilterToCondition(table, filter, args)
+
+		if condition == "" {
+			condition = trueCondition
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	for _, nested := range expression.Expressions {
+		condition := mapFilterExpressionToCondition(table, nested, args)
+
+		if condition == "" {
+			condition = trueCondition
+		}
+
+		conditions = append(conditions, "("+condition+")")
+	}
+
+	return conditions
+}
+
+/*
+mapFiltersToConditions maps the filters to the conditions they must all meet.
+*/
+func mapFiltersToConditions(table Table, filters []query_utils.Filter, args *Args) []string {
+	var conditions []string
+
+	for _, filter := range filters {
+		if condition := mapFilterToCondition(table, filter, args); condition != "" {
+			conditions = append(conditions, condition)
+		}
+	}
+
+	return conditions
+}
+
+func mapFilterToCondition(table Table, filter query_utils.Filter, args *Args) string {
+	operator := MapComparisonOperatorToSQL(filter.Operator, args.dialect)
+	column, known := table.Columns[filter.Field]
+
+	switch filter.Operator {
+	case operators.IN, operators.NOT_IN:
+		return mapListFilterToCondition(table, column, known, filter, args)
+	case operators.STARTS_WITH, operators.CONTAINS, operators.REGEX:
+		pattern, ok := query_utils.RegexPattern(filter.Operator, filter.Value)
+
+		if !ok || !known || column.Type != Text {
+			return falseCondition
+		}
+
+		return compareColumn(table, column, false, operator, args.Add(pattern))
+	case operators.EXISTS:
+		// Every known field is stored for every row, and unknown ones never are.
+		exists, ok := filter.Value.(bool)
+
+		return booleanCondition(ok && exists == known)
+	}
+
+	if operator == "" {
+		return ""
+	}
+
+	if !known {
+		return constantCondition(filter.Operator, filter.Value == nil)
+	}
+
+	value, ok := column.Type.convert(filter.Value)
+
+	if !ok {
+		return constantCondition(filter.Operator, false)
+	}
+
+	if filter.Operator == operators.NOT_EQUALS && column.Values != nil {
+		// A multi-valued field is different from a value when none of its values is equal to it.
+		return compareColumn(table, column, true, "=", args.Add(value))
+	}
+
+	return compareColumn(table, column, false, operator, args.Add(value))
+}
+
+/*
+mapListFilterToCondition maps IN and NOT_IN, leaving out the values of a different type than the column, which could
+never be equal to it.
+*/
+func mapListFilterToCondition(
+	table Table, column Column, known bool, filter query_utils.Filter, args *Args,
+) string {
+	values, ok := filter.Value.([]interface{})
+
+	if !ok {
+		return falseCondition
+	}
+
+	in := filter.Operator == operators.IN
+
+	if !known {
+		// Unknown fields are null, so they're only in lists holding null.
+		for _, value := range values {
+			if value == nil {
+				return booleanCondition(in)
+			}
+		}
+
+		return booleanCondition(!in)
+	}
+
+	var placeholders []string
+
+	for _, value := range values {
+		if converted, ok := column.Type.convert(value); ok {
+			placeholders = append(placeholders, args.Add(converted))
+		}
+	}
+
+	if len(placeholders) == 0 {
+		return booleanCondition(!in)
+	}
+
+	list := "(" + strings.Join(placeholders, ", ") + ")"
+
+	if column.Values != nil {
+		// As with NOT_EQUALS, a multi-valued field isn't in the list when none of its values is.
+		return compareColumn(table, column, !in, "IN", list)
+	}
+
+	if in {
+		return compareColumn(table, column, false, "IN", list)
+	}
+
+	return compareColumn(table, column, false, "NOT IN", list)
+}
+
+/*
+compareColumn builds the condition comparing a column with the operand. Multi-valued columns match if any of their
+values does, or if none does when negated.
+*/
+func compareColumn(table Table, column Column, negated bool, operator string, operand string) string {
+	if column.Values == nil {
+		return fmt.Sprintf("%s.%s %s %s", table.Name, column.Name, operator, operand)
+	}
+
+	exists := "EXISTS"
+	if negated {
+		exists = "NOT EXISTS"
+	}
+
+	return fmt.Sprintf(
+		"%s (SELECT 1 FROM %s WHERE %s AND %s.%s %s %s)",
+		exists, column.Values.Name, joinValues(table, column), column.Values.Name, column.Name, operator, operand,
+	)
+}
+
+const (
+	trueCondition  = "1 = 1"
+	falseCondition = "1 = 0"
+)
+
+func booleanCondition(matches bool) string {
+	if matches {
+		return trueCondition
+	}
+
+	return falseCondition
+}
+
+/*
+constantCondition is the outcome of comparing values that don't depend on the row, like a value of another type, or
+null for unknown fields, which are only equal among them.
+*/
+func constantCondition(op operators.Comparison, equal bool) string {
+	switch op {
+	case operators.NOT_EQUALS:
+		return booleanCondition(!equal)
+	case operators.EQUALS, operators.GREATER_THAN_EQ, operators.LESS_THAN_EQ:
+		return booleanCondition(equal)
+	default:
+		return falseCondition
+	}
+}
+
+/*
+MapComparisonOperatorToSQL returns the SQL operator comparing a column with a single value, or an empty string if there
+isn't one. The pattern operators compare with a regular expression.
+*/
+func MapComparisonOperatorToSQL(op operators.Comparison, dialect Dialect) string {
+	switch op {
+	case operators.EQUALS:
+		return "="
+	case operators.NOT_EQUALS:
+		return "<>"
+	case operators.GREATER_THAN:
+		return ">"
+	case operators.GREATER_THAN_EQ:
+		return ">="
+	case operators.LESS_THAN:
+		return "<"
+	case operators.LESS_THAN_EQ:
+		return "<="
+	case operators.STARTS_WITH, operators.CONTAINS, operators.REGEX:
+		if dialect == Postgres {
+			return "~"
+		}
+
+		return "REGEXP"
+	default:
+		return ""
+	}
+}
+
+/*
+convert returns the value as stored in a column of this type, or false if it's of another type.
+*/
+func (t ColumnType) convert(value interface{}) (interface{}, bool) {
+	switch castValue := value.(type) {
+	case string:
+		return castValue, t == Text
+	case time.Time:
+		return castValue.UnixMilli(), t == Timestamp
+	case int64:
+		return castValue, t == Integer
+	default:
+		return nil, false
+	}
+}
+
+/* Projection mappers */
+
+/*
+MapProjectionToColumns returns the columns selecting the given fields, in their order, with the same semantics as
+mongo_utils.MapProjectionToBson, except that every field to read must be given, as a SELECT can't leave them out.
+
+Multi-valued fields are selected as their values joined with commas, in their
+order, or null if there are none, so each row is read whole by a single query,
+which never needs a second connection. Their values can't hold commas then.
+Unknown fields are null for every row.
+*/
+func MapProjectionToColumns(table Table, fields []string, dialect Dialect) string {
+	columns := make([]string, len(fields))
+
+	for i, field := range fields {
+		column, ok := table.Columns[field]
+
+		switch {
+		case !ok:
+			columns[i] = "NULL"
+		case column.Values == nil:
+			columns[i] = fmt.Sprintf("%s.%s", table.Name, column.Name)
+		default:
+			columns[i] = aggregateValues(table, column, dialect)
+		}
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+/*
+aggregateValues returns the subquery joining the values of a multi-valued column with commas, in their order. SQLite
+has no ordered aggregates, but joins the values in the order they're read, so they're read from an ordered subquery.
+*/
+func aggregateValues(table Table, column Column, dialect Dialect) string {
+	values := column.Values
+
+	if dialect == Postgres {
+		return fmt.Sprintf(
+			"(SELECT string_agg(%s.%s, ',' ORDER BY %s.%s) FROM %s WHERE %s)",
+			values.Name, column.Name, values.Name, values.Order, values.Name, joinValues(table, column),
+		)
+	}
+
+	return fmt.Sprintf(
+		"(SELECT group_concat(%s, ',') FROM (SELECT %s.%s FROM %s WHERE %s ORDER BY %s.%s))",
+		column.Name, values.Name, column.Name, values.Name, joinValues(table, column), values.Name, values.Order,
+	)
+}
+
+/* Sorting mappers */
+
+/*
+MapSortToOrderBy builds the ORDER BY clause for the sort, with the same semantics as mongo_utils.MapSortToBson.
+
+Unknown fields are null for every row, so they're skipped. Multi-valued fields
+are sorted by their lowest value when ascending, and by their highest one when
+descending, with the ones without values first.
+*/
+func MapSortToOrderBy(table Table, sort []query_utils.Sort) string {
+	var terms []string
+
+	for _, s := range sort {
+		column, ok := table.Columns[s.Field]
+		direction := MapSortDirectionToSQL(s.Direction)
+
+		if !ok || direction == "" {
+			continue
+		}
+
+		if column.Values == nil {
+			terms = append(terms, fmt.Sprintf("%s.%s %s", table.Name, column.Name, direction))
+			continue
+		}
+
+		aggregate, nulls := "MIN", "NULLS FIRST"
+		if s.Direction == operators.DESC {
+			aggregate, nulls = "MAX", "NULLS LAST"
+		}
+
+		terms = append(
+			terms, fmt.Sprintf(
+				"(SELECT %s(%s.%s) FROM %s WHERE %s) %s %s",
+				aggregate, column.Values.Name, column.Name, column.Values.Name, joinValues(table, column), direction,
+				nulls,
+			),
+		)
+	}
+
+	if table.InsertionOrder != "" {
+		terms = append(terms, fmt.Sprintf("%s.%s ASC", table.Name, table.InsertionOrder))
+	}
+
+	if len(terms) == 0 {
+		return ""
+	}
+
+	return "ORDER BY " + strings.Join(terms, ", ")
+}
+
+func MapSortDirectionToSQL(op operators.Sort) string {
+	switch op {
+	case operators.ASC:
+		return "ASC"
+	case operators.DESC:
+		return "DESC"
+	default:
+		return ""
+	}
+}
+
+/* Pagination mappers */
+
+/*
+MapPaginationToLimit builds the LIMIT and OFFSET clauses, with the same semantics as the MongoDB options.
+
+A zero limit means no limit, and a negative one is taken as positive. A negative
+offset is rejected, as MongoDB does.
+*/
+func MapPaginationToLimit(pagination query_utils.Pagination, args *Args) (string, error) {
+	if pagination.Offset < 0 {
+		return "", fmt.Errorf("negative offset %d", pagination.Offset)
+	}
+
+	limit := pagination.Limit
+	if limit < 0 {
+		limit = -limit
+	}
+
+	var clauses []string
+
+	switch {
+	case limit > 0:
+		clauses = append(clauses, "LIMIT "+args.Add(limit))
+	case pagination.Offset > 0 && args.dialect == SQLite:
+		// SQLite doesn't allow an offset without a limit, and takes a negative one as no limit.
+		clauses = append(clauses, "LIMIT -1")
+	}
+
+	if pagination.Offset > 0 {
+		clauses = append(clauses, "OFFSET "+args.Add(pagination.Offset))
+	}
+
+	return strings.Join(clauses, " "), nil
+}
+
+/*
+joinValues is the condition relating the values of a multi-valued column to the row of the main table.
+*/
+func joinValues(table Table, column Column) string {
+	return fmt.Sprintf("%s.%s = %s.%s", column.Values.Name, column.Values.ForeignKey, table.Name, table.Key)
+}
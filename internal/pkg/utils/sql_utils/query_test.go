@@ -0,0 +1,615 @@
+package sql_utils
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils"
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/utils/query_utils/operators"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var testTable = Table{
+	Name:           "users",
+	Key:            "id",
+	InsertionOrder: "seq",
+	Columns: map[string]Column{
+		"first_name": {Name: "first_name", Type: Text},
+		"country":    {Name: "country", Type: Text},
+		"roles": {
+			Name: "role", Type: Text, Values: &ValuesTable{Name: "user_roles", ForeignKey: "user_id", Order: "position"},
+		},
+		"created_at": {Name: "created_at", Type: Timestamp},
+		"version":    {Name: "version", Type: Integer},
+	},
+}
+
+func TestSQLMappers(t *testing.T) {
+	t.Parallel()
+
+	for name, testGroup := range map[string]map[string]func(t *testing.T){
+		"filters": {
+			"should map filters":                                testMapFiltersToWhere,
+			"should map no filters":                             testMapNoFiltersToWhere,
+			"should map every filter of a field":                testMapFiltersToWhereSameField,
+			"should ignore unknown operators":                   testMapFiltersToWhereUnknownOperator,
+			"should map multi-valued fields":                    testMapFiltersToWhereMultiValued,
+			"should map values of another type as constants":    testMapFiltersToWhereTypeMismatch,
+			"should map unknown fields as null":                 testMapFiltersToWhereUnknownField,
+			"should number the placeholders of postgres":        testMapFiltersToWherePostgres,
+			"should map lists of values":                        testMapFiltersToWhereIn,
+			"should map lists of values of multi-valued fields": testMapFiltersToWhereInMultiValued,
+			"should map lists of values of unknown fields":      testMapFiltersToWhereInUnknownField,
+			"should map patterns to regular expressions":        testMapFiltersToWherePatterns,
+			"should map the presence of fields":                 testMapFiltersToWhereExists,
+			"should map values not fitting the operator":        testMapFiltersToWhereInvalidValues,
+			"should map every comparison operator":              testMapComparisonOperatorToSQL,
+			"should map an unknown comparison operator to nil":  testMapUnknownComparisonOperatorToSQL,
+		},
+		"filter expressions": {
+			"should map nested expressions":             testMapFilterExpressionToWhere,
+			"should map AND groups as lists of filters": testMapFilterExpressionToWhereFilters,
+			"should map groups without operands":        testMapEmptyFilterExpressionToWhere,
+			"should keep operands matching every row":   testMapFilterExpressionToWhereAlwaysTrue,
+			"should ignore unknown logical operators":   testMapFilterExpressionToWhereUnknownOperator,
+		},
+		"sort": {
+			"should map sort":                            testMapSortToOrderBy,
+			"should keep the insertion order only":       testMapNoSortToOrderBy,
+			"should map multi-valued fields":             testMapSortToOrderByMultiValued,
+			"should skip unknown fields and directions":  testMapSortToOrderByUnknown,
+			"should map no sort without insertion order": testMapSortToOrderByWithoutInsertionOrder,
+		},
+		"projection": {
+			"should map projection":                     testMapProjectionToColumns,
+			"should map multi-valued fields":            testMapProjectionToColumnsMultiValued,
+			"should map multi-valued fields for pg":     testMapProjectionToColumnsMultiValuedPostgres,
+			"should map unknown fields as null":         testMapProjectionToColumnsUnknownField,
+			"should filter and project integer columns": testMapIntegerColumns,
+		},
+		"pagination": {
+			"should map pagination":                     testMapPaginationToLimit,
+			"should map no pagination":                  testMapNoPaginationToLimit,
+			"should map a negative limit as positive":   testMapPaginationToLimitNegativeLimit,
+			"should map an offset without limit":        testMapPaginationToLimitOnlyOffset,
+			"should reject a negative offset":           testMapPaginationToLimitNegativeOffset,
+			"should map an offset without limit for pg": testMapPaginationToLimitOnlyOffsetPostgres,
+		},
+	} {
+		testGroup := testGroup
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				for name, test := range testGroup {
+					test := test
+					t.Run(
+						name, func(t *testing.T) {
+							t.Parallel()
+
+							test(t)
+						},
+					)
+				}
+			},
+		)
+	}
+}
+
+/* Filters */
+
+func testMapFiltersToWhere(t *testing.T) {
+	createdAt := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	args := NewArgs(SQLite)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{
+			{Field: "country", Operator: operators.EQUALS, Value: "US"},
+			{Field: "created_at", Operator: operators.LESS_THAN, Value: createdAt},
+		}, args,
+	)
+
+	assert.Equal(t, "WHERE users.country = ? AND users.created_at < ?", where)
+	assert.Equal(t, []interface{}{"US", createdAt.UnixMilli()}, args.Values())
+}
+
+func testMapNoFiltersToWhere(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	assert.Equal(t, "", MapFiltersToWhere(testTable, nil, args))
+	assert.Empty(t, args.Values())
+}
+
+func testMapFiltersToWhereSameField(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{
+			{Field: "first_name", Operator: operators.GREATER_THAN_EQ, Value: "J"},
+			{Field: "country", Operator: operators.NOT_EQUALS, Value: "US"},
+			{Field: "first_name", Operator: operators.LESS_THAN, Value: "K"},
+		}, args,
+	)
+
+	assert.Equal(t, "WHERE users.first_name >= ? AND users.country <> ? AND users.first_name < ?", where)
+	assert.Equal(t, []interface{}{"J", "US", "K"}, args.Values())
+}
+
+func testMapFiltersToWhereUnknownOperator(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{{Field: "country", Operator: operators.Comparison(-1), Value: "US"}}, args,
+	)
+
+	assert.Equal(t, "", where)
+	assert.Empty(t, args.Values())
+}
+
+func testMapFiltersToWhereMultiValued(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	assert.Equal(
+		t,
+		"WHERE EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role >= ?)",
+		MapFiltersToWhere(
+			testTable, []query_utils.Filter{{Field: "roles", Operator: operators.GREATER_THAN_EQ, Value: "b"}}, args,
+		),
+	)
+	assert.Equal(
+		t,
+		"WHERE NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role = ?)",
+		MapFiltersToWhere(
+			testTable, []query_utils.Filter{{Field: "roles", Operator: operators.NOT_EQUALS, Value: "admin"}}, args,
+		),
+	)
+	assert.Equal(t, []interface{}{"b", "admin"}, args.Values())
+}
+
+func testMapFiltersToWhereTypeMismatch(t *testing.T) {
+	for operator, expected := range map[operators.Comparison]string{
+		operators.EQUALS:          "WHERE 1 = 0",
+		operators.NOT_EQUALS:      "WHERE 1 = 1",
+		operators.GREATER_THAN:    "WHERE 1 = 0",
+		operators.GREATER_THAN_EQ: "WHERE 1 = 0",
+		operators.LESS_THAN:       "WHERE 1 = 0",
+		operators.LESS_THAN_EQ:    "WHERE 1 = 0",
+	} {
+		args := NewArgs(SQLite)
+
+		for _, value := range []interface{}{int64(1), time.Now(), nil} {
+			where := MapFiltersToWhere(
+				testTable, []query_utils.Filter{{Field: "country", Operator: operator, Value: value}}, args,
+			)
+
+			assert.Equal(t, expected, where, operator)
+		}
+
+		assert.Empty(t, args.Values())
+	}
+}
+
+func testMapFiltersToWhereUnknownField(t *testing.T) {
+	for operator, expected := range map[operators.Comparison][2]string{
+		operators.EQUALS:          {"WHERE 1 = 1", "WHERE 1 = 0"},
+		operators.NOT_EQUALS:      {"WHERE 1 = 0", "WHERE 1 = 1"},
+		operators.GREATER_THAN:    {"WHERE 1 = 0", "WHERE 1 = 0"},
+		operators.GREATER_THAN_EQ: {"WHERE 1 = 1", "WHERE 1 = 0"},
+		operators.LESS_THAN:       {"WHERE 1 = 0", "WHERE 1 = 0"},
+		operators.LESS_THAN_EQ:    {"WHERE 1 = 1", "WHERE 1 = 0"},
+	} {
+		args := NewArgs(SQLite)
+
+		// Unknown fields are null, so they're only equal to null.
+		assert.Equal(
+			t, expected[0], MapFiltersToWhere(
+				testTable, []query_utils.Filter{{Field: "unknown", Operator: operator, Value: nil}}, args,
+			), operator,
+		)
+		assert.Equal(
+			t, expected[1], MapFiltersToWhere(
+				testTable, []query_utils.Filter{{Field: "unknown", Operator: operator, Value: "US"}}, args,
+			), operator,
+		)
+	}
+}
+
+func testMapFiltersToWherePostgres(t *testing.T) {
+	args := NewArgs(Postgres)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{
+			{Field: "country", Operator: operators.EQUALS, Value: "US"},
+			{Field: "roles", Operator: operators.EQUALS, Value: "admin"},
+		}, args,
+	)
+
+	assert.Equal(
+		t,
+		"WHERE users.country = $1 AND "+
+			"EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role = $2)",
+		where,
+	)
+	assert.Equal(t, []interface{}{"US", "admin"}, args.Values())
+}
+
+func testMapFiltersToWhereIn(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{
+			{Field: "country", Operator: operators.IN, Value: []interface{}{"US", int64(1), "ES"}},
+			{Field: "first_name", Operator: operators.NOT_IN, Value: []interface{}{"John"}},
+			{Field: "created_at", Operator: operators.IN, Value: []interface{}{int64(1)}},
+			{Field: "roles", Operator: operators.NOT_IN, Value: []interface{}{int64(1)}},
+		}, args,
+	)
+
+	// Values of another type are left out, and lists left empty are constant.
+	assert.Equal(
+		t, "WHERE users.country IN (?, ?) AND users.first_name NOT IN (?) AND 1 = 0 AND 1 = 1", where,
+	)
+	assert.Equal(t, []interface{}{"US", "ES", "John"}, args.Values())
+}
+
+func testMapFiltersToWhereInMultiValued(t *testing.T) {
+	args := NewArgs(Postgres)
+
+	assert.Equal(
+		t,
+		"WHERE EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role IN ($1, $2))",
+		MapFiltersToWhere(
+			testTable, []query_utils.Filter{
+				{Field: "roles", Operator: operators.IN, Value: []interface{}{"admin", "user"}},
+			}, args,
+		),
+	)
+	assert.Equal(
+		t,
+		"WHERE NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role IN ($3))",
+		MapFiltersToWhere(
+			testTable, []query_utils.Filter{{Field: "roles", Operator: operators.NOT_IN, Value: []interface{}{"admin"}}},
+			args,
+		),
+	)
+	assert.Equal(t, []interface{}{"admin", "user", "admin"}, args.Values())
+}
+
+func testMapFiltersToWhereInUnknownField(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	for _, test := range []struct {
+		operator operators.Comparison
+		value    []interface{}
+		expected string
+	}{
+		{operators.IN, []interface{}{"US", nil}, "WHERE 1 = 1"},
+		{operators.IN, []interface{}{"US"}, "WHERE 1 = 0"},
+		{operators.NOT_IN, []interface{}{"US", nil}, "WHERE 1 = 0"},
+		{operators.NOT_IN, []interface{}{"US"}, "WHERE 1 = 1"},
+	} {
+		assert.Equal(
+			t, test.expected, MapFiltersToWhere(
+				testTable, []query_utils.Filter{{Field: "unknown", Operator: test.operator, Value: test.value}}, args,
+			), test.operator, test.value,
+		)
+	}
+
+	assert.Empty(t, args.Values())
+}
+
+func testMapFiltersToWherePatterns(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{
+			{Field: "first_name", Operator: operators.STARTS_WITH, Value: "J.n"},
+			{Field: "country", Operator: operators.CONTAINS, Value: "s"},
+			{Field: "roles", Operator: operators.REGEX, Value: "^ad"},
+			{Field: "created_at", Operator: operators.REGEX, Value: "1"},
+			{Field: "unknown", Operator: operators.CONTAINS, Value: "1"},
+		}, args,
+	)
+
+	assert.Equal(
+		t,
+		"WHERE users.first_name REGEXP ? AND users.country REGEXP ? AND "+
+			"EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role REGEXP ?) AND "+
+			"1 = 0 AND 1 = 0",
+		where,
+	)
+	assert.Equal(t, []interface{}{`^J\.n`, "(?i)s", "^ad"}, args.Values())
+}
+
+func testMapFiltersToWhereExists(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{
+			{Field: "first_name", Operator: operators.EXISTS, Value: true},
+			{Field: "country", Operator: operators.EXISTS, Value: false},
+			{Field: "unknown", Operator: operators.EXISTS, Value: false},
+			{Field: "missing", Operator: operators.EXISTS, Value: true},
+		}, args,
+	)
+
+	assert.Equal(t, "WHERE 1 = 1 AND 1 = 0 AND 1 = 1 AND 1 = 0", where)
+	assert.Empty(t, args.Values())
+}
+
+func testMapFiltersToWhereInvalidValues(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{
+			{Field: "first_name", Operator: operators.IN, Value: "John"},
+			{Field: "last_name", Operator: operators.NOT_IN, Value: "John"},
+			{Field: "country", Operator: operators.CONTAINS, Value: int64(1)},
+			{Field: "roles", Operator: operators.EXISTS, Value: "true"},
+		}, args,
+	)
+
+	assert.Equal(t, "WHERE 1 = 0 AND 1 = 0 AND 1 = 0 AND 1 = 0", where)
+	assert.Empty(t, args.Values())
+}
+
+func testMapComparisonOperatorToSQL(t *testing.T) {
+	assert.Equal(t, "=", MapComparisonOperatorToSQL(operators.EQUALS, SQLite))
+	assert.Equal(t, "<>", MapComparisonOperatorToSQL(operators.NOT_EQUALS, SQLite))
+	assert.Equal(t, ">", MapComparisonOperatorToSQL(operators.GREATER_THAN, SQLite))
+	assert.Equal(t, ">=", MapComparisonOperatorToSQL(operators.GREATER_THAN_EQ, SQLite))
+	assert.Equal(t, "<", MapComparisonOperatorToSQL(operators.LESS_THAN, SQLite))
+	assert.Equal(t, "<=", MapComparisonOperatorToSQL(operators.LESS_THAN_EQ, SQLite))
+	assert.Equal(t, "REGEXP", MapComparisonOperatorToSQL(operators.REGEX, SQLite))
+	assert.Equal(t, "~", MapComparisonOperatorToSQL(operators.REGEX, Postgres))
+	assert.Equal(t, "", MapComparisonOperatorToSQL(operators.IN, SQLite))
+}
+
+func testMapUnknownComparisonOperatorToSQL(t *testing.T) {
+	assert.Equal(t, "", MapComparisonOperatorToSQL(operators.Comparison(-1), SQLite))
+}
+
+/* Filter expressions */
+
+func testMapFilterExpressionToWhere(t *testing.T) {
+	args := NewArgs(Postgres)
+
+	where := MapFilterExpressionToWhere(
+		testTable, query_utils.FilterExpression{
+			Filters: []query_utils.Filter{{Field: "country", Operator: operators.EQUALS, Value: "ES"}},
+			Expressions: []query_utils.FilterExpression{
+				{
+					Operator: operators.OR,
+					Filters: []query_utils.Filter{
+						{Field: "first_name", Operator: operators.EQUALS, Value: "Jane"},
+						{Field: "first_name", Operator: operators.EQUALS, Value: "John"},
+					},
+					Expressions: []query_utils.FilterExpression{
+						{
+							Operator: operators.NOT,
+							Filters: []query_utils.Filter{
+								{Field: "roles", Operator: operators.EQUALS, Value: "admin"},
+							},
+						},
+					},
+				},
+			},
+		}, args,
+	)
+
+	assert.Equal(
+		t,
+		"WHERE users.country = $1 AND (users.first_name = $2 OR users.first_name = $3 OR "+
+			"(NOT (EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role = $4))))",
+		where,
+	)
+	assert.Equal(t, []interface{}{"ES", "Jane", "John", "admin"}, args.Values())
+}
+
+func testMapFilterExpressionToWhereFilters(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFilterExpressionToWhere(
+		testTable, query_utils.FilterExpression{
+			Filters: []query_utils.Filter{
+				{Field: "country", Operator: operators.EQUALS, Value: "ES"},
+				{Field: "first_name", Operator: operators.GREATER_THAN, Value: "J"},
+				{Field: "country", Operator: operators.NOT_EQUALS, Value: "US"},
+			},
+		}, args,
+	)
+
+	assert.Equal(t, "WHERE users.country = ? AND users.first_name > ? AND users.country <> ?", where)
+	assert.Equal(t, []interface{}{"ES", "J", "US"}, args.Values())
+}
+
+func testMapEmptyFilterExpressionToWhere(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	assert.Equal(t, "", MapFilterExpressionToWhere(testTable, query_utils.FilterExpression{}, args))
+	assert.Equal(
+		t, "WHERE 1 = 0", MapFilterExpressionToWhere(
+			testTable, query_utils.FilterExpression{Operator: operators.OR}, args,
+		),
+	)
+	assert.Equal(
+		t, "", MapFilterExpressionToWhere(testTable, query_utils.FilterExpression{Operator: operators.NOT}, args),
+	)
+	assert.Empty(t, args.Values())
+}
+
+func testMapFilterExpressionToWhereAlwaysTrue(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFilterExpressionToWhere(
+		testTable, query_utils.FilterExpression{
+			Operator: operators.NOT,
+			Filters: []query_utils.Filter{
+				{Field: "country", Operator: operators.EQUALS, Value: "ES"},
+				{Field: "country", Operator: operators.Comparison(-1), Value: "US"},
+			},
+			Expressions: []query_utils.FilterExpression{{}},
+		}, args,
+	)
+
+	assert.Equal(t, "WHERE NOT (users.country = ? OR 1 = 1 OR (1 = 1))", where)
+	assert.Equal(t, []interface{}{"ES"}, args.Values())
+}
+
+func testMapFilterExpressionToWhereUnknownOperator(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFilterExpressionToWhere(
+		testTable, query_utils.FilterExpression{
+			Operator: operators.Logical(-1),
+			Filters:  []query_utils.Filter{{Field: "country", Operator: operators.EQUALS, Value: "ES"}},
+		}, args,
+	)
+
+	assert.Equal(t, "", where)
+	assert.Empty(t, args.Values())
+}
+
+/* Sort */
+
+func testMapSortToOrderBy(t *testing.T) {
+	orderBy := MapSortToOrderBy(
+		testTable, []query_utils.Sort{
+			{Field: "country", Direction: operators.DESC},
+			{Field: "first_name", Direction: operators.ASC},
+		},
+	)
+
+	assert.Equal(t, "ORDER BY users.country DESC, users.first_name ASC, users.seq ASC", orderBy)
+}
+
+func testMapNoSortToOrderBy(t *testing.T) {
+	assert.Equal(t, "ORDER BY users.seq ASC", MapSortToOrderBy(testTable, nil))
+}
+
+func testMapProjectionToColumns(t *testing.T) {
+	assert.Equal(
+		t, "users.country, users.first_name, users.created_at",
+		MapProjectionToColumns(testTable, []string{"country", "first_name", "created_at"}, SQLite),
+	)
+}
+
+func testMapProjectionToColumnsMultiValued(t *testing.T) {
+	assert.Equal(
+		t,
+		"users.first_name, (SELECT group_concat(role, ',') FROM (SELECT user_roles.role FROM user_roles "+
+			"WHERE user_roles.user_id = users.id ORDER BY user_roles.position))",
+		MapProjectionToColumns(testTable, []string{"first_name", "roles"}, SQLite),
+	)
+}
+
+func testMapProjectionToColumnsMultiValuedPostgres(t *testing.T) {
+	assert.Equal(
+		t,
+		"(SELECT string_agg(user_roles.role, ',' ORDER BY user_roles.position) FROM user_roles "+
+			"WHERE user_roles.user_id = users.id)",
+		MapProjectionToColumns(testTable, []string{"roles"}, Postgres),
+	)
+}
+
+func testMapProjectionToColumnsUnknownField(t *testing.T) {
+	assert.Equal(t, "users.country, NULL", MapProjectionToColumns(testTable, []string{"country", "age"}, SQLite))
+}
+
+func testMapIntegerColumns(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	where := MapFiltersToWhere(
+		testTable, []query_utils.Filter{{Field: "version", Operator: operators.GREATER_THAN, Value: int64(2)}}, args,
+	)
+
+	assert.Equal(t, "WHERE users.version > ?", where)
+	assert.Equal(t, []interface{}{int64(2)}, args.Values())
+	assert.Equal(t, "users.version", MapProjectionToColumns(testTable, []string{"version"}, SQLite))
+}
+
+func testMapSortToOrderByMultiValued(t *testing.T) {
+	assert.Equal(
+		t,
+		"ORDER BY (SELECT MIN(user_roles.role) FROM user_roles WHERE user_roles.user_id = users.id) ASC NULLS FIRST, "+
+			"users.seq ASC",
+		MapSortToOrderBy(testTable, []query_utils.Sort{{Field: "roles", Direction: operators.ASC}}),
+	)
+	assert.Equal(
+		t,
+		"ORDER BY (SELECT MAX(user_roles.role) FROM user_roles WHERE user_roles.user_id = users.id) DESC NULLS LAST, "+
+			"users.seq ASC",
+		MapSortToOrderBy(testTable, []query_utils.Sort{{Field: "roles", Direction: operators.DESC}}),
+	)
+}
+
+func testMapSortToOrderByUnknown(t *testing.T) {
+	orderBy := MapSortToOrderBy(
+		testTable, []query_utils.Sort{
+			{Field: "unknown", Direction: operators.ASC},
+			{Field: "country", Direction: operators.Sort(-1)},
+		},
+	)
+
+	assert.Equal(t, "ORDER BY users.seq ASC", orderBy)
+}
+
+func testMapSortToOrderByWithoutInsertionOrder(t *testing.T) {
+	assert.Equal(t, "", MapSortToOrderBy(Table{Name: "users"}, nil))
+}
+
+/* Pagination */
+
+func testMapPaginationToLimit(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	limit, err := MapPaginationToLimit(query_utils.Pagination{Limit: 10, Offset: 20}, args)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "LIMIT ? OFFSET ?", limit)
+	assert.Equal(t, []interface{}{int64(10), int64(20)}, args.Values())
+}
+
+func testMapNoPaginationToLimit(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	limit, err := MapPaginationToLimit(query_utils.Pagination{}, args)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", limit)
+	assert.Empty(t, args.Values())
+}
+
+func testMapPaginationToLimitNegativeLimit(t *testing.T) {
+	args := NewArgs(Postgres)
+
+	limit, err := MapPaginationToLimit(query_utils.Pagination{Limit: -10}, args)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "LIMIT $1", limit)
+	assert.Equal(t, []interface{}{int64(10)}, args.Values())
+}
+
+func testMapPaginationToLimitOnlyOffset(t *testing.T) {
+	args := NewArgs(SQLite)
+
+	limit, err := MapPaginationToLimit(query_utils.Pagination{Offset: 20}, args)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "LIMIT -1 OFFSET ?", limit)
+	assert.Equal(t, []interface{}{int64(20)}, args.Values())
+}
+
+func testMapPaginationToLimitOnlyOffsetPostgres(t *testing.T) {
+	args := NewArgs(Postgres)
+
+	limit, err := MapPaginationToLimit(query_utils.Pagination{Offset: 20}, args)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "OFFSET $1", limit)
+}
+
+func testMapPaginationToLimitNegativeOffset(t *testing.T) {
+	_, err := MapPaginationToLimit(query_utils.Pagination{Offset: -1}, NewArgs(SQLite))
+
+	assert.EqualError(t, err, "negative offset -1")
+}
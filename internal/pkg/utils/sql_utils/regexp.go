@@ -0,0 +1,79 @@
+package sql_utils
+
+import (
+	"database/sql/driver"
+	"modernc.org/sqlite"
+	"regexp"
+	"sync"
+)
+
+/*
+maxCachedPatterns bounds the patterns kept compiled, which come from the callers, so they can't grow the cache without
+limit.
+*/
+const maxCachedPatterns = 64
+
+/*
+patternCache keeps the patterns compiled across the rows of a query, and the queries after it, so they're only compiled
+once. It's emptied when it fills up. Invalid patterns are kept too, as nil.
+*/
+var patternCache = struct {
+	sync.Mutex
+	compiled map[string]*regexp.Regexp
+}{compiled: map[string]*regexp.Regexp{}}
+
+/*
+SQLite parses the REGEXP operator but leaves it to the application to define, so we register it on the driver, with
+Go's regular expressions, for every connection opened from then on.
+*/
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("regexp", 2, sqliteRegexp)
+}
+
+/*
+sqliteRegexp implements "text REGEXP pattern", which SQLite calls as regexp(pattern, text).
+
+Non-text values and invalid patterns never match, as in memory_utils.MatchFilters.
+*/
+func sqliteRegexp(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	pattern, ok := args[0].(string)
+	text, isText := args[1].(string)
+
+	if !ok || !isText {
+		return false, nil
+	}
+
+	compiled := compilePattern(pattern)
+
+	if compiled == nil {
+		return false, nil
+	}
+
+	return compiled.MatchString(text), nil
+}
+
+/*
+compilePattern returns the compiled pattern from patternCache, compiling it if it isn't there, or nil if it's invalid.
+*/
+func compilePattern(pattern string) *regexp.Regexp {
+	patternCache.Lock()
+	defer patternCache.Unlock()
+
+	if compiled, ok := patternCache.compiled[pattern]; ok {
+		return compiled
+	}
+
+	compiled, err := regexp.Compile(pattern)
+
+	if err != nil {
+		compiled = nil
+	}
+
+	if len(patternCache.compiled) >= maxCachedPatterns {
+		patternCache.compiled = map[string]*regexp.Regexp{}
+	}
+
+	patternCache.compiled[pattern] = compiled
+
+	return compiled
+}
@@ -0,0 +1,50 @@
+package sql_utils
+
+import (
+	"database/sql/driver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSQLiteRegexp(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]func(t *testing.T){
+		"should match text against the pattern": testSQLiteRegexpMatch,
+		"should never match invalid patterns":   testSQLiteRegexpInvalidPattern,
+		"should compile every pattern once":     testSQLiteRegexpCache,
+	} {
+		test := test
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				test(t)
+			},
+		)
+	}
+}
+
+func testSQLiteRegexpMatch(t *testing.T) {
+	matched, err := sqliteRegexp(nil, []driver.Value{"^j.h", "john"})
+	assert.NoError(t, err)
+	assert.Equal(t, true, matched)
+
+	matched, err = sqliteRegexp(nil, []driver.Value{"^j.h", int64(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, false, matched)
+}
+
+func testSQLiteRegexpInvalidPattern(t *testing.T) {
+	matched, err := sqliteRegexp(nil, []driver.Value{"(oh", "(oh"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, false, matched)
+}
+
+func testSQLiteRegexpCache(t *testing.T) {
+	compiled := compilePattern("^cached$")
+
+	assert.NotNil(t, compiled)
+	assert.Same(t, compiled, compilePattern("^cached$"))
+}
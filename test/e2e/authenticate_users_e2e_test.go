@@ -0,0 +1,63 @@
+package e2e
+
+import (
+	"context"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func testAuthenticateUsersE2E(t *testing.T, client apiV1.UserServiceClient) {
+
+	t.Run(
+		"authenticate updated user 0 by nickname", func(t *testing.T) {
+			t.Parallel()
+
+			testAuthenticateUser(t, client, UpdatedUser0.Nickname, UpdatedUser0)
+		},
+	)
+
+	t.Run(
+		"authenticate user 1 by email", func(t *testing.T) {
+			t.Parallel()
+
+			testAuthenticateUser(t, client, User1.Email, User1)
+		},
+	)
+
+	t.Run(
+		"authenticate user 2 with wrong password", func(t *testing.T) {
+			t.Parallel()
+
+			testAuthenticateWithWrongPassword(t, client, User2)
+		},
+	)
+}
+
+func testAuthenticateUser(t *testing.T, client apiV1.UserServiceClient, nicknameOrEmail string, user User) {
+	out, err := client.Authenticate(
+		context.Background(), &apiV1.AuthenticateRequest{
+			NicknameOrEmail: nicknameOrEmail,
+			Password:        user.Password,
+		},
+	)
+
+	require.NoError(t, err)
+
+	assertUserEquality(t, &user, out)
+}
+
+func testAuthenticateWithWrongPassword(t *testing.T, client apiV1.UserServiceClient, user User) {
+	out, err := client.Authenticate(
+		context.Background(), &apiV1.AuthenticateRequest{
+			NicknameOrEmail: user.Nickname,
+			Password:        user.Password + "-wrong",
+		},
+	)
+
+	assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, "Invalid credentials"))
+	assert.Nil(t, out)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
 	"github.com/stretchr/testify/assert"
-	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"testing"
@@ -44,6 +43,36 @@ func testCreateUsersE2E(t *testing.T, client apiV1.UserServiceClient) {
 			testCreateInvalidUser1(t, client, InvalidUser1)
 		},
 	)
+
+	t.Run(
+		"create user with taken nickname", func(t *testing.T) {
+			testCreateDuplicatedUser(t, client, DuplicatedNicknameUser, "nickname", DuplicatedNicknameUser.Nickname)
+		},
+	)
+
+	t.Run(
+		"create user with taken email", func(t *testing.T) {
+			testCreateDuplicatedUser(t, client, DuplicatedEmailUser, "email", DuplicatedEmailUser.Email)
+		},
+	)
+}
+
+func testCreateDuplicatedUser(t *testing.T, client apiV1.UserServiceClient, user User, field string, value string) {
+	out, err := client.CreateUser(
+		context.Background(), &apiV1.CreateUserRequest{
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Nickname:  user.Nickname,
+			Password:  user.Password,
+			Email:     user.Email,
+			Country:   user.Country,
+		},
+	)
+
+	assert.ErrorIs(
+		t, err, status.Error(codes.AlreadyExists, "User with "+field+" "+value+" already exists"),
+	)
+	assert.Nil(t, out)
 }
 
 func testCreateUser(t *testing.T, client apiV1.UserServiceClient, user User) {
@@ -66,7 +95,7 @@ func testCreateUser(t *testing.T, client apiV1.UserServiceClient, user User) {
 	assert.Equal(t, user.FirstName, out.FirstName)
 	assert.Equal(t, user.LastName, out.LastName)
 	assert.Equal(t, user.Nickname, out.Nickname)
-	assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(out.Password), []byte(user.Password)))
+	assert.Empty(t, out.Password)
 	assert.Equal(t, user.Email, out.Email)
 	assert.Equal(t, user.Country, out.Country)
 	assert.True(t, before.Before(out.CreatedAt.AsTime()))
@@ -85,8 +114,9 @@ func testCreateInvalidUser0(t *testing.T, client apiV1.UserServiceClient, user U
 		},
 	)
 
-	assert.ErrorIs(
-		t, err, status.Error(codes.InvalidArgument, "[User] Invalid field last_name with value "+user.LastName),
+	assertInvalidFields(
+		t, err, "[User] Invalid field last_name with value "+user.LastName+": REQUIRED",
+		map[string]string{"last_name": "REQUIRED"},
 	)
 	assert.Nil(t, out)
 }
@@ -103,13 +133,11 @@ func testCreateInvalidUser1(t *testing.T, client apiV1.UserServiceClient, user U
 		},
 	)
 
-	assert.ErrorIs(
+	assertInvalidFields(
 		t,
 		err,
-		status.Error(
-			codes.InvalidArgument,
-			"Multiple errors: [[User] Invalid field last_name with value "+user.LastName+" [User] Invalid field nickname with value "+user.Nickname+"]",
-		),
+		"Multiple errors: [[User] Invalid field last_name with value "+user.LastName+": REQUIRED [User] Invalid field nickname with value "+user.Nickname+": REQUIRED]",
+		map[string]string{"last_name": "REQUIRED", "nickname": "REQUIRED"},
 	)
 	assert.Nil(t, out)
 }
@@ -4,9 +4,13 @@ import (
 	"context"
 	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
 	"github.com/stretchr/testify/assert"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"io"
 	"os"
 	"testing"
@@ -61,6 +65,30 @@ func TestE2E(t *testing.T) {
 		},
 	)
 
+	t.Run(
+		"authenticate users", func(t *testing.T) {
+			testAuthenticateUsersE2E(t, client)
+		},
+	)
+
+	t.Run(
+		"tokens", func(t *testing.T) {
+			testTokensE2E(t, client)
+		},
+	)
+
+	t.Run(
+		"roles", func(t *testing.T) {
+			testRolesE2E(t, client)
+		},
+	)
+
+	t.Run(
+		"import users", func(t *testing.T) {
+			testImportUsersE2E(t, client)
+		},
+	)
+
 	t.Run(
 		"remove users", func(t *testing.T) {
 			testRemoveUsersE2E(t, client)
@@ -80,9 +108,10 @@ func assertUserEquality(t *testing.T, expected *User, actual *apiV1.User) {
 	assert.Equal(t, expected.FirstName, actual.FirstName)
 	assert.Equal(t, expected.LastName, actual.LastName)
 	assert.Equal(t, expected.Nickname, actual.Nickname)
-	assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(actual.Password), []byte(expected.Password)))
+	assert.Empty(t, actual.Password)
 	assert.Equal(t, expected.Email, actual.Email)
 	assert.Equal(t, expected.Country, actual.Country)
+	assert.Equal(t, []string{"user"}, actual.Roles)
 }
 
 func collectUsers(t *testing.T, client apiV1.UserService_GetUsersClient) (users []*apiV1.User) {
@@ -104,10 +133,10 @@ func collectUsers(t *testing.T, client apiV1.UserService_GetUsersClient) (users
 
 func getSortedUsers(t *testing.T, client apiV1.UserServiceClient) (users []*apiV1.User) {
 	prepareOut, _ := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Sort: []*apiV1.Sort{
 				{
-					Field:     "timestamp",
+					Field:     "created_at",
 					Direction: apiV1.Sort_ASC,
 				},
 			},
@@ -116,3 +145,51 @@ func getSortedUsers(t *testing.T, client apiV1.UserServiceClient) (users []*apiV
 
 	return collectUsers(t, prepareOut)
 }
+
+/*
+assertInvalidFields checks the error is an InvalidArgument status with the given message, whose details report the
+given fields along with their reasons.
+*/
+func assertInvalidFields(t *testing.T, err error, message string, reasons map[string]string) {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, message, st.Message())
+
+	var fields []string
+	var errorInfo *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		switch castDetail := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, violation := range castDetail.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		case *errdetails.ErrorInfo:
+			errorInfo = castDetail
+		}
+	}
+
+	for field := range reasons {
+		assert.Contains(t, fields, field)
+	}
+
+	require.NotNil(t, errorInfo)
+	assert.Equal(t, reasons, errorInfo.Metadata)
+}
+
+/*
+loginAs logs in with the credentials of the given user, returning a context that sends the access token.
+*/
+func loginAs(t *testing.T, client apiV1.UserServiceClient, user User) context.Context {
+	out, err := client.Login(
+		context.Background(), &apiV1.LoginRequest{
+			NicknameOrEmail: user.Nickname,
+			Password:        user.Password,
+		},
+	)
+
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+out.AccessToken)
+}
@@ -1,11 +1,14 @@
 package e2e
 
 import (
-	"context"
 	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"strconv"
 	"testing"
 )
 
@@ -38,6 +41,15 @@ func testGetCreatedUsersE2E(t *testing.T, client apiV1.UserServiceClient) {
 		},
 	)
 
+	// Tests: Filter expression with OR
+	t.Run(
+		"get created users by either nickname", func(t *testing.T) {
+			t.Parallel()
+
+			testGetCreatedUsersByEitherNickname(t, client)
+		},
+	)
+
 	// Tests: Limit + Filter by timestamp + Sort timestamp ASC
 	t.Run(
 		"get first created user only", func(t *testing.T) {
@@ -56,6 +68,33 @@ func testGetCreatedUsersE2E(t *testing.T, client apiV1.UserServiceClient) {
 		},
 	)
 
+	// Tests: Limit + Page token + Sort timestamp ASC
+	t.Run(
+		"get created users page by page", func(t *testing.T) {
+			t.Parallel()
+
+			testGetCreatedUsersByPage(t, client)
+		},
+	)
+
+	// Tests: Read mask + Filter by string
+	t.Run(
+		"get created user's nickname only", func(t *testing.T) {
+			t.Parallel()
+
+			testGetCreatedUserNicknameOnly(t, client)
+		},
+	)
+
+	// Tests: Count + Filter by string list
+	t.Run(
+		"count created users by nickname", func(t *testing.T) {
+			t.Parallel()
+
+			testCountCreatedUsersByNickname(t, client)
+		},
+	)
+
 	t.Run(
 		"check invalid user doesn't exist", func(t *testing.T) {
 			t.Parallel()
@@ -63,11 +102,47 @@ func testGetCreatedUsersE2E(t *testing.T, client apiV1.UserServiceClient) {
 			testGetInvalidUser(t, client)
 		},
 	)
+
+	// Tests: Contradictory filters on the same field
+	t.Run(
+		"get users with contradictory filters", func(t *testing.T) {
+			t.Parallel()
+
+			testGetUsersWithContradictoryFilters(t, client)
+		},
+	)
+
+	// Tests: GetUser
+	t.Run(
+		"get created user by id", func(t *testing.T) {
+			t.Parallel()
+
+			testGetCreatedUserById(t, client)
+		},
+	)
+
+	// Tests: BatchGetUsers in request order, with unknown ids
+	t.Run(
+		"batch get created users", func(t *testing.T) {
+			t.Parallel()
+
+			testBatchGetCreatedUsers(t, client)
+		},
+	)
+
+	// Tests: Filter by a field that isn't exposed
+	t.Run(
+		"get users by password", func(t *testing.T) {
+			t.Parallel()
+
+			testGetUsersByPassword(t, client)
+		},
+	)
 }
 
 func testGetCreatedUsers(t *testing.T, client apiV1.UserServiceClient) {
 	out, err := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Sort: []*apiV1.Sort{
 				{
 					Field:     "created_at",
@@ -99,7 +174,7 @@ func testGetCreatedUsers(t *testing.T, client apiV1.UserServiceClient) {
 
 func testGetCreatedUsersDescending(t *testing.T, client apiV1.UserServiceClient) {
 	out, err := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Sort: []*apiV1.Sort{
 				{
 					Field:     "created_at",
@@ -122,7 +197,7 @@ func testGetCreatedUsersDescending(t *testing.T, client apiV1.UserServiceClient)
 
 func testGetCreatedUserByNickname(t *testing.T, client apiV1.UserServiceClient) {
 	out, err := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Filters: []*apiV1.Filter{
 				{
 					Field:    "nickname",
@@ -141,9 +216,45 @@ func testGetCreatedUserByNickname(t *testing.T, client apiV1.UserServiceClient)
 	assertUserEquality(t, &User1, users[0])
 }
 
+func testGetCreatedUsersByEitherNickname(t *testing.T, client apiV1.UserServiceClient) {
+	out, err := client.GetUsers(
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
+			Filter: &apiV1.FilterExpression{
+				Operator: apiV1.FilterExpression_OR,
+				Filters: []*apiV1.Filter{
+					{
+						Field:    "nickname",
+						Operator: apiV1.Filter_EQUALS,
+						Value:    &apiV1.Filter_StringValue{StringValue: User1.Nickname},
+					},
+					{
+						Field:    "nickname",
+						Operator: apiV1.Filter_EQUALS,
+						Value:    &apiV1.Filter_StringValue{StringValue: User2.Nickname},
+					},
+				},
+			},
+			Sort: []*apiV1.Sort{
+				{
+					Field:     "created_at",
+					Direction: apiV1.Sort_ASC,
+				},
+			},
+		},
+	)
+
+	assert.NoError(t, err)
+
+	users := collectUsers(t, out)
+
+	require.Equal(t, 2, len(users))
+	assertUserEquality(t, &User1, users[0])
+	assertUserEquality(t, &User2, users[1])
+}
+
 func testGetFirstUserOnly(t *testing.T, client apiV1.UserServiceClient) {
 	prepareOut, _ := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Sort: []*apiV1.Sort{
 				{
 					Field:     "created_at",
@@ -156,7 +267,7 @@ func testGetFirstUserOnly(t *testing.T, client apiV1.UserServiceClient) {
 	prepareUsers := collectUsers(t, prepareOut)
 
 	out, err := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Filters: []*apiV1.Filter{
 				{
 					Field:    "created_at",
@@ -191,7 +302,7 @@ func testGetFirstUserOnly(t *testing.T, client apiV1.UserServiceClient) {
 
 func testGetThirdUserOnly(t *testing.T, client apiV1.UserServiceClient) {
 	prepareOut, err := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Sort: []*apiV1.Sort{
 				{
 					Field:     "created_at",
@@ -204,7 +315,7 @@ func testGetThirdUserOnly(t *testing.T, client apiV1.UserServiceClient) {
 	prepareUsers := collectUsers(t, prepareOut)
 
 	out, err := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Filters: []*apiV1.Filter{
 				{
 					Field:    "created_at",
@@ -238,9 +349,138 @@ func testGetThirdUserOnly(t *testing.T, client apiV1.UserServiceClient) {
 	assertUserEquality(t, &User2, users[0])
 }
 
+func testGetCreatedUsersByPage(t *testing.T, client apiV1.UserServiceClient) {
+	ctx := loginAs(t, client, User2)
+	var users []*apiV1.User
+	pageToken := ""
+
+	for page := 0; page < 3; page++ {
+		out, err := client.GetUsers(
+			ctx, &apiV1.GetUsersRequest{
+				Sort: []*apiV1.Sort{
+					{
+						Field:     "created_at",
+						Direction: apiV1.Sort_ASC,
+					},
+				},
+				Pagination: &apiV1.Pagination{
+					Limit: 2,
+				},
+				PageToken:         pageToken,
+				IncludeTotalCount: true,
+			},
+		)
+
+		require.NoError(t, err)
+
+		users = append(users, collectUsers(t, out)...)
+		tokens := out.Trailer().Get("next-page-token")
+
+		assert.Equal(t, []string{"3"}, out.Trailer().Get("total-count"))
+		assert.Equal(t, []string{strconv.FormatBool(len(tokens) > 0)}, out.Trailer().Get("has-more"))
+
+		if len(tokens) == 0 {
+			break
+		}
+
+		pageToken = tokens[0]
+	}
+
+	require.Equal(t, 3, len(users))
+	assertUserEquality(t, &User0, users[0])
+	assertUserEquality(t, &User1, users[1])
+	assertUserEquality(t, &User2, users[2])
+}
+
+func testGetCreatedUserNicknameOnly(t *testing.T, client apiV1.UserServiceClient) {
+	out, err := client.GetUsers(
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
+			Filters: []*apiV1.Filter{
+				{
+					Field:    "nickname",
+					Operator: apiV1.Filter_EQUALS,
+					Value:    &apiV1.Filter_StringValue{StringValue: User1.Nickname},
+				},
+			},
+			ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "nickname"}},
+		},
+	)
+
+	assert.NoError(t, err)
+
+	users := collectUsers(t, out)
+
+	require.Equal(t, 1, len(users))
+	assert.NotEmpty(t, users[0].Id)
+	assert.Equal(t, User1.Nickname, users[0].Nickname)
+	assert.Empty(t, users[0].Email)
+	assert.Nil(t, users[0].CreatedAt)
+}
+
+func testGetCreatedUserById(t *testing.T, client apiV1.UserServiceClient) {
+	sortedUsers := getSortedUsers(t, client)
+	ctx := loginAs(t, client, User2)
+
+	out, err := client.GetUser(ctx, &apiV1.GetUserRequest{Id: sortedUsers[1].Id})
+
+	require.NoError(t, err)
+	assert.Equal(t, sortedUsers[1].Id, out.Id)
+	assert.Equal(t, User1.Nickname, out.Nickname)
+
+	out, err = client.GetUser(ctx, &apiV1.GetUserRequest{Id: "nonexistent"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Nil(t, out)
+}
+
+func testBatchGetCreatedUsers(t *testing.T, client apiV1.UserServiceClient) {
+	sortedUsers := getSortedUsers(t, client)
+
+	out, err := client.BatchGetUsers(
+		loginAs(t, client, User2), &apiV1.BatchGetUsersRequest{
+			Ids: []string{sortedUsers[1].Id, "nonexistent", sortedUsers[0].Id},
+		},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, out.Results, 3)
+
+	assert.Equal(t, sortedUsers[1].Id, out.Results[0].Id)
+	assert.Equal(t, User1.Nickname, out.Results[0].GetUser().GetNickname())
+	assert.Equal(t, "nonexistent", out.Results[1].Id)
+	assert.True(t, out.Results[1].NotFound)
+	assert.Nil(t, out.Results[1].User)
+	assert.Equal(t, sortedUsers[0].Id, out.Results[2].GetUser().GetId())
+}
+
+func testCountCreatedUsersByNickname(t *testing.T, client apiV1.UserServiceClient) {
+	out, err := client.CountUsers(
+		loginAs(t, client, User2), &apiV1.CountUsersRequest{
+			Filters: []*apiV1.Filter{
+				{
+					Field:    "nickname",
+					Operator: apiV1.Filter_IN,
+					Value: &apiV1.Filter_ListValue{
+						ListValue: &apiV1.FilterValueList{
+							Values: []*apiV1.FilterValue{
+								{Value: &apiV1.FilterValue_StringValue{StringValue: User0.Nickname}},
+								{Value: &apiV1.FilterValue_StringValue{StringValue: User1.Nickname}},
+								{Value: &apiV1.FilterValue_StringValue{StringValue: "nobody"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), out.GetTotalCount())
+}
+
 func testGetInvalidUser(t *testing.T, client apiV1.UserServiceClient) {
 	out, err := client.GetUsers(
-		context.Background(), &apiV1.GetUsersRequest{
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
 			Filters: []*apiV1.Filter{
 				{
 					Field:    "nickname",
@@ -257,3 +497,62 @@ func testGetInvalidUser(t *testing.T, client apiV1.UserServiceClient) {
 
 	assert.Equal(t, 0, len(users))
 }
+
+func testGetUsersByPassword(t *testing.T, client apiV1.UserServiceClient) {
+	out, err := client.GetUsers(
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
+			Filters: []*apiV1.Filter{
+				{
+					Field:    "password",
+					Operator: apiV1.Filter_EQUALS,
+					Value:    &apiV1.Filter_StringValue{StringValue: User2.Password},
+				},
+			},
+		},
+	)
+
+	require.NoError(t, err)
+
+	user, err := out.Recv()
+
+	assertInvalidFields(
+		t,
+		err,
+		"[User] Invalid field filters[0].field with value password: UNKNOWN_VALUE",
+		map[string]string{"filters[0].field": "UNKNOWN_VALUE"},
+	)
+	assert.Nil(t, user)
+}
+
+func testGetUsersWithContradictoryFilters(t *testing.T, client apiV1.UserServiceClient) {
+	now := timestamppb.Now()
+
+	out, err := client.GetUsers(
+		loginAs(t, client, User2), &apiV1.GetUsersRequest{
+			Filters: []*apiV1.Filter{
+				{
+					Field:    "created_at",
+					Operator: apiV1.Filter_GREATER_THAN,
+					Value:    &apiV1.Filter_TimestampValue{TimestampValue: now},
+				},
+				{
+					Field:    "created_at",
+					Operator: apiV1.Filter_LESS_THAN,
+					Value:    &apiV1.Filter_TimestampValue{TimestampValue: now},
+				},
+			},
+		},
+	)
+
+	require.NoError(t, err)
+
+	user, err := out.Recv()
+
+	assertInvalidFields(
+		t,
+		err,
+		"[User] Invalid field filters[1].operator: CONTRADICTORY_OPERATOR",
+		map[string]string{"filters[1].operator": "CONTRADICTORY_OPERATOR"},
+	)
+	assert.Nil(t, user)
+}
@@ -0,0 +1,58 @@
+package e2e
+
+import (
+	"context"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func testImportUsersE2E(t *testing.T, client apiV1.UserServiceClient) {
+	t.Run(
+		"import users as regular user", func(t *testing.T) {
+			t.Parallel()
+
+			out, err := importUsers(t, loginAs(t, client, User2), client)
+
+			assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+			assert.Nil(t, out)
+		},
+	)
+
+	t.Run(
+		"import users without token", func(t *testing.T) {
+			t.Parallel()
+
+			out, err := importUsers(t, context.Background(), client)
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			assert.Nil(t, out)
+		},
+	)
+}
+
+/*
+importUsers sends a single user to import, returning the first result.
+*/
+func importUsers(t *testing.T, ctx context.Context, client apiV1.UserServiceClient) (*apiV1.ImportUserResult, error) {
+	stream, err := client.ImportUsers(ctx)
+	require.NoError(t, err)
+
+	// The stream may already be rejected, which is then reported by Recv.
+	_ = stream.Send(
+		&apiV1.ImportUserRequest{
+			FirstName:   "Imported",
+			LastName:    "User",
+			Nickname:    "imported-user",
+			Credentials: &apiV1.ImportUserRequest_Password{Password: "password"},
+			Email:       "imported@user.com",
+			Country:     "US",
+		},
+	)
+	require.NoError(t, stream.CloseSend())
+
+	return stream.Recv()
+}
@@ -28,6 +28,14 @@ func testRemoveUsersE2E(t *testing.T, client apiV1.UserServiceClient) {
 		},
 	)
 
+	t.Run(
+		"remove user 0 without token", func(t *testing.T) {
+			t.Parallel()
+
+			testRemoveUserWithoutToken(t, client)
+		},
+	)
+
 	t.Run(
 		"remove nonexistent user", func(t *testing.T) {
 			t.Parallel()
@@ -41,7 +49,7 @@ func testRemoveUser1(t *testing.T, client apiV1.UserServiceClient) {
 	sortedUsers := getSortedUsers(t, client)
 
 	out, err := client.RemoveUser(
-		context.Background(), &apiV1.RemoveUserRequest{
+		loginAs(t, client, User1), &apiV1.RemoveUserRequest{
 			Id: sortedUsers[1].Id,
 		},
 	)
@@ -52,27 +60,36 @@ func testRemoveUser1(t *testing.T, client apiV1.UserServiceClient) {
 
 func testRemoveInvalidUser(t *testing.T, client apiV1.UserServiceClient) {
 	out, err := client.RemoveUser(
-		context.Background(), &apiV1.RemoveUserRequest{
+		loginAs(t, client, User2), &apiV1.RemoveUserRequest{
 			Id: "",
 		},
 	)
 
-	assert.ErrorIs(t, err, status.Error(codes.InvalidArgument, "Id is required"))
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
 	assert.Nil(t, out)
 }
 
 func testRemoveNonexistentUser(t *testing.T, client apiV1.UserServiceClient) {
 	id := "nonexistent"
 	out, err := client.RemoveUser(
-		context.Background(), &apiV1.RemoveUserRequest{
+		loginAs(t, client, User2), &apiV1.RemoveUserRequest{
 			Id: id,
 		},
 	)
 
-	assert.ErrorIs(
-		t, err, status.Error(
-			codes.NotFound, "User with id nonexistent not found",
-		),
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+	assert.Nil(t, out)
+}
+
+func testRemoveUserWithoutToken(t *testing.T, client apiV1.UserServiceClient) {
+	sortedUsers := getSortedUsers(t, client)
+
+	out, err := client.RemoveUser(
+		context.Background(), &apiV1.RemoveUserRequest{
+			Id: sortedUsers[0].Id,
+		},
 	)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
 	assert.Nil(t, out)
 }
@@ -0,0 +1,46 @@
+package e2e
+
+import (
+	"context"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func testRolesE2E(t *testing.T, client apiV1.UserServiceClient) {
+	users := getSortedUsers(t, client)
+
+	t.Run(
+		"grant role as regular user", func(t *testing.T) {
+			t.Parallel()
+
+			out, err := client.GrantRole(
+				loginAs(t, client, User2), &apiV1.GrantRoleRequest{
+					Id:   users[2].Id,
+					Role: "admin",
+				},
+			)
+
+			assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+			assert.Nil(t, out)
+		},
+	)
+
+	t.Run(
+		"revoke role without token", func(t *testing.T) {
+			t.Parallel()
+
+			out, err := client.RevokeRole(
+				context.Background(), &apiV1.RevokeRoleRequest{
+					Id:   users[2].Id,
+					Role: "moderator",
+				},
+			)
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			assert.Nil(t, out)
+		},
+	)
+}
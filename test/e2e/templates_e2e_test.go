@@ -24,7 +24,7 @@ var User1 = User{
 	Nickname:  "user2",
 	Password:  "password2",
 	Email:     "two@user.com",
-	Country:   "UK",
+	Country:   "GB",
 }
 
 var User2 = User{
@@ -42,7 +42,7 @@ var InvalidUser0 = User{
 	Nickname:  "userInvalid",
 	Password:  "passwordInvalid",
 	Email:     "invalid@user.com",
-	Country:   "UK",
+	Country:   "GB",
 }
 
 var InvalidUser1 = User{
@@ -51,7 +51,27 @@ var InvalidUser1 = User{
 	Nickname:  "",
 	Password:  "passwordInvalid",
 	Email:     "invalid@user.com",
-	Country:   "UK",
+	Country:   "GB",
+}
+
+// Same nickname as User0, in a different case
+var DuplicatedNicknameUser = User{
+	FirstName: "User",
+	LastName:  "Duplicated",
+	Nickname:  "USER1",
+	Password:  "passwordDuplicated",
+	Email:     "duplicated@users.com",
+	Country:   "US",
+}
+
+// Same email as User1, in a different case
+var DuplicatedEmailUser = User{
+	FirstName: "User",
+	LastName:  "Duplicated",
+	Nickname:  "userDuplicated",
+	Password:  "passwordDuplicated",
+	Email:     "Two@User.com",
+	Country:   "GB",
 }
 
 var UpdatedUser0 = User{
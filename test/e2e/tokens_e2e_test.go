@@ -0,0 +1,102 @@
+package e2e
+
+import (
+	"context"
+	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"testing"
+)
+
+func testTokensE2E(t *testing.T, client apiV1.UserServiceClient) {
+
+	t.Run(
+		"get signing keys", func(t *testing.T) {
+			t.Parallel()
+
+			out, err := client.GetSigningKeys(context.Background(), &emptypb.Empty{})
+
+			require.NoError(t, err)
+			require.NotEmpty(t, out.Keys)
+			assert.Equal(t, "RS256", out.Keys[0].Alg)
+		},
+	)
+
+	t.Run(
+		"login, refresh and revoke tokens of user 1", func(t *testing.T) {
+			t.Parallel()
+
+			testTokenLifecycle(t, client, User1)
+		},
+	)
+
+	t.Run(
+		"login user 2 with wrong password", func(t *testing.T) {
+			t.Parallel()
+
+			out, err := client.Login(
+				context.Background(), &apiV1.LoginRequest{
+					NicknameOrEmail: User2.Nickname,
+					Password:        User2.Password + "-wrong",
+				},
+			)
+
+			assert.ErrorIs(t, err, status.Error(codes.Unauthenticated, "Invalid credentials"))
+			assert.Nil(t, out)
+		},
+	)
+}
+
+func testTokenLifecycle(t *testing.T, client apiV1.UserServiceClient, user User) {
+	ctx := context.Background()
+
+	loginOut, err := client.Login(
+		ctx, &apiV1.LoginRequest{
+			NicknameOrEmail: user.Email,
+			Password:        user.Password,
+		},
+	)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, loginOut.AccessToken)
+	assert.Equal(t, "Bearer", loginOut.TokenType)
+
+	refreshOut, err := client.RefreshToken(ctx, &apiV1.RefreshTokenRequest{RefreshToken: loginOut.RefreshToken})
+
+	require.NoError(t, err)
+	assert.NotEqual(t, loginOut.RefreshToken, refreshOut.RefreshToken)
+
+	// Reusing the rotated token revokes the whole family, including the new token.
+	reuseOut, err := client.RefreshToken(ctx, &apiV1.RefreshTokenRequest{RefreshToken: loginOut.RefreshToken})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Nil(t, reuseOut)
+
+	familyOut, err := client.RefreshToken(ctx, &apiV1.RefreshTokenRequest{RefreshToken: refreshOut.RefreshToken})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Nil(t, familyOut)
+
+	secondLoginOut, err := client.Login(
+		ctx, &apiV1.LoginRequest{
+			NicknameOrEmail: user.Nickname,
+			Password:        user.Password,
+		},
+	)
+
+	require.NoError(t, err)
+
+	_, err = client.RevokeToken(ctx, &apiV1.RevokeTokenRequest{RefreshToken: secondLoginOut.RefreshToken})
+
+	require.NoError(t, err)
+
+	revokedOut, err := client.RefreshToken(
+		ctx, &apiV1.RefreshTokenRequest{RefreshToken: secondLoginOut.RefreshToken},
+	)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Nil(t, revokedOut)
+}
@@ -1,12 +1,12 @@
 package e2e
 
 import (
-	"context"
 	apiV1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"testing"
 )
 
@@ -36,6 +36,30 @@ func testUpdateUsersE2E(t *testing.T, client apiV1.UserServiceClient) {
 		},
 	)
 
+	t.Run(
+		"update user 1 with update mask", func(t *testing.T) {
+			t.Parallel()
+
+			testUpdateUserWithUpdateMask(t, client)
+		},
+	)
+
+	t.Run(
+		"update user 1 from a stale version", func(t *testing.T) {
+			t.Parallel()
+
+			testUpdateStaleUser(t, client)
+		},
+	)
+
+	t.Run(
+		"update user 1 as user 2", func(t *testing.T) {
+			t.Parallel()
+
+			testUpdateOtherUser(t, client)
+		},
+	)
+
 	t.Run(
 		"update nonexistent user", func(t *testing.T) {
 			t.Parallel()
@@ -49,7 +73,7 @@ func testUpdateUser0(t *testing.T, client apiV1.UserServiceClient) {
 	sortedUsers := getSortedUsers(t, client)
 
 	out, err := client.UpdateUser(
-		context.Background(), &apiV1.UpdateUserRequest{
+		loginAs(t, client, User0), &apiV1.UpdateUserRequest{
 			Id:        sortedUsers[0].Id,
 			FirstName: &UpdatedUser0.FirstName,
 			LastName:  &UpdatedUser0.LastName,
@@ -70,7 +94,7 @@ func testUpdateInvalidUser(t *testing.T, client apiV1.UserServiceClient) {
 
 	id := sortedUsers[1].Id
 	out, err := client.UpdateUser(
-		context.Background(), &apiV1.UpdateUserRequest{
+		loginAs(t, client, User1), &apiV1.UpdateUserRequest{
 			Id:        id,
 			FirstName: &InvalidUpdatedUser0.FirstName,
 			LastName:  &InvalidUpdatedUser0.LastName,
@@ -81,10 +105,8 @@ func testUpdateInvalidUser(t *testing.T, client apiV1.UserServiceClient) {
 		},
 	)
 
-	assert.ErrorIs(
-		t,
-		err,
-		status.Error(codes.InvalidArgument, "[User] Invalid field password with value "+InvalidUpdatedUser0.Password),
+	assertInvalidFields(
+		t, err, "[User] Invalid field password: REQUIRED", map[string]string{"password": "REQUIRED"},
 	)
 	assert.Nil(t, out)
 }
@@ -94,7 +116,7 @@ func testUpdateUserWithSeveralErrors(t *testing.T, client apiV1.UserServiceClien
 
 	id := sortedUsers[1].Id
 	out, err := client.UpdateUser(
-		context.Background(), &apiV1.UpdateUserRequest{
+		loginAs(t, client, User1), &apiV1.UpdateUserRequest{
 			Id:        id,
 			FirstName: &InvalidUpdatedUser1.FirstName,
 			LastName:  &InvalidUpdatedUser1.LastName,
@@ -105,21 +127,58 @@ func testUpdateUserWithSeveralErrors(t *testing.T, client apiV1.UserServiceClien
 		},
 	)
 
-	assert.ErrorIs(
+	assertInvalidFields(
+		t,
+		err,
+		"Multiple errors: [[User] Invalid field nickname with value "+InvalidUpdatedUser1.Nickname+": REQUIRED [User] Invalid field password: REQUIRED]",
+		map[string]string{"nickname": "REQUIRED", "password": "REQUIRED"},
+	)
+	assert.Nil(t, out)
+}
+
+func testUpdateUserWithUpdateMask(t *testing.T, client apiV1.UserServiceClient) {
+	sortedUsers := getSortedUsers(t, client)
+
+	id := sortedUsers[1].Id
+	out, err := client.UpdateUser(
+		loginAs(t, client, User1), &apiV1.UpdateUserRequest{
+			Id:         id,
+			Nickname:   &InvalidUpdatedUser1.Nickname,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "created_at", "last_name"}},
+		},
+	)
+
+	// The nickname is left out of the mask, while the last name is cleared, which it can't be.
+	assertInvalidFields(
 		t,
 		err,
-		status.Error(
-			codes.InvalidArgument,
-			"Multiple errors: [[User] Invalid field nickname with value "+InvalidUpdatedUser1.Nickname+" [User] Invalid field password with value "+InvalidUpdatedUser1.Password+"]",
-		),
+		"Multiple errors: [[User] Invalid field created_at: IMMUTABLE [User] Invalid field id: IMMUTABLE "+
+			"[User] Invalid field last_name with value : REQUIRED]",
+		map[string]string{"created_at": "IMMUTABLE", "id": "IMMUTABLE", "last_name": "REQUIRED"},
+	)
+	assert.Nil(t, out)
+}
+
+func testUpdateStaleUser(t *testing.T, client apiV1.UserServiceClient) {
+	sortedUsers := getSortedUsers(t, client)
+
+	staleVersion := sortedUsers[1].Version - 1
+	out, err := client.UpdateUser(
+		loginAs(t, client, User1), &apiV1.UpdateUserRequest{
+			Id:              sortedUsers[1].Id,
+			FirstName:       &User1.FirstName,
+			ExpectedVersion: &staleVersion,
+		},
 	)
+
+	assert.Equal(t, codes.Aborted, status.Code(err))
 	assert.Nil(t, out)
 }
 
 func testUpdateNonexistentUser(t *testing.T, client apiV1.UserServiceClient) {
 	id := "nonexistent"
 	out, err := client.UpdateUser(
-		context.Background(), &apiV1.UpdateUserRequest{
+		loginAs(t, client, User2), &apiV1.UpdateUserRequest{
 			Id:        id,
 			FirstName: &User2.FirstName,
 			LastName:  &User2.LastName,
@@ -130,13 +189,21 @@ func testUpdateNonexistentUser(t *testing.T, client apiV1.UserServiceClient) {
 		},
 	)
 
-	assert.ErrorIs(
-		t,
-		err,
-		status.Error(
-			codes.NotFound,
-			"User with id nonexistent not found",
-		),
+	// Without being an admin, there's no way to tell whether another user exists.
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
+	assert.Nil(t, out)
+}
+
+func testUpdateOtherUser(t *testing.T, client apiV1.UserServiceClient) {
+	sortedUsers := getSortedUsers(t, client)
+
+	out, err := client.UpdateUser(
+		loginAs(t, client, User2), &apiV1.UpdateUserRequest{
+			Id:       sortedUsers[1].Id,
+			LastName: &User2.LastName,
+		},
 	)
+
+	assert.ErrorIs(t, err, status.Error(codes.PermissionDenied, "Permission denied"))
 	assert.Nil(t, out)
 }
@@ -18,6 +18,48 @@ type Collection struct {
 	mock.Mock
 }
 
+// CountDocuments provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Collection) CountDocuments(_a0 context.Context, _a1 interface{}, _a2 ...*options.CountOptions) (int64, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, ...*options.CountOptions) int64); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, ...*options.CountOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateIndexes provides a mock function with given fields: _a0, _a1
+func (_m *Collection) CreateIndexes(_a0 context.Context, _a1 []mongo.IndexModel) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []mongo.IndexModel) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteOne provides a mock function with given fields: _a0, _a1
 func (_m *Collection) DeleteOne(_a0 context.Context, _a1 interface{}) (*mongo.DeleteResult, error) {
 	ret := _m.Called(_a0, _a1)
@@ -71,13 +113,20 @@ func (_m *Collection) Find(_a0 context.Context, _a1 interface{}, _a2 ...*options
 	return r0, r1
 }
 
-// FindOne provides a mock function with given fields: _a0, _a1
-func (_m *Collection) FindOne(_a0 context.Context, _a1 interface{}) mongo_helper.SingleResult {
-	ret := _m.Called(_a0, _a1)
+// FindOne provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Collection) FindOne(_a0 context.Context, _a1 interface{}, _a2 ...*options.FindOneOptions) mongo_helper.SingleResult {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 mongo_helper.SingleResult
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) mongo_helper.SingleResult); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, ...*options.FindOneOptions) mongo_helper.SingleResult); ok {
+		r0 = rf(_a0, _a1, _a2...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(mongo_helper.SingleResult)
@@ -87,6 +136,36 @@ func (_m *Collection) FindOne(_a0 context.Context, _a1 interface{}) mongo_helper
 	return r0
 }
 
+// InsertMany provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Collection) InsertMany(_a0 context.Context, _a1 []interface{}, _a2 ...*options.InsertManyOptions) ([]interface{}, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, []interface{}, ...*options.InsertManyOptions) []interface{}); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []interface{}, ...*options.InsertManyOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InsertOne provides a mock function with given fields: _a0, _a1
 func (_m *Collection) InsertOne(_a0 context.Context, _a1 interface{}) (interface{}, error) {
 	ret := _m.Called(_a0, _a1)
@@ -110,6 +189,36 @@ func (_m *Collection) InsertOne(_a0 context.Context, _a1 interface{}) (interface
 	return r0, r1
 }
 
+// UpdateMany provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Collection) UpdateMany(_a0 context.Context, _a1 interface{}, _a2 interface{}, _a3 ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	_va := make([]interface{}, len(_a3))
+	for _i := range _a3 {
+		_va[_i] = _a3[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1, _a2)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *mongo.UpdateResult
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, ...*options.UpdateOptions) *mongo.UpdateResult); ok {
+		r0 = rf(_a0, _a1, _a2, _a3...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mongo.UpdateResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, interface{}, ...*options.UpdateOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateOne provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *Collection) UpdateOne(_a0 context.Context, _a1 interface{}, _a2 interface{}, _a3 ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
 	_va := make([]interface{}, len(_a3))
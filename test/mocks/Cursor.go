@@ -13,6 +13,20 @@ type Cursor struct {
 	mock.Mock
 }
 
+// Close provides a mock function with given fields: _a0
+func (_m *Cursor) Close(_a0 context.Context) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Decode provides a mock function with given fields: _a0
 func (_m *Cursor) Decode(_a0 interface{}) error {
 	ret := _m.Called(_a0)
@@ -27,6 +41,20 @@ func (_m *Cursor) Decode(_a0 interface{}) error {
 	return r0
 }
 
+// Err provides a mock function with given fields:
+func (_m *Cursor) Err() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Next provides a mock function with given fields: _a0
 func (_m *Cursor) Next(_a0 context.Context) bool {
 	ret := _m.Called(_a0)
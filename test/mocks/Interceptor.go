@@ -0,0 +1,60 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+)
+
+// Interceptor is an autogenerated mock type for the Interceptor type
+type Interceptor struct {
+	mock.Mock
+}
+
+// Stream provides a mock function with given fields:
+func (_m *Interceptor) Stream() grpc.StreamServerInterceptor {
+	ret := _m.Called()
+
+	var r0 grpc.StreamServerInterceptor
+	if rf, ok := ret.Get(0).(func() grpc.StreamServerInterceptor); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(grpc.StreamServerInterceptor)
+		}
+	}
+
+	return r0
+}
+
+// Unary provides a mock function with given fields:
+func (_m *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	ret := _m.Called()
+
+	var r0 grpc.UnaryServerInterceptor
+	if rf, ok := ret.Get(0).(func() grpc.UnaryServerInterceptor); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(grpc.UnaryServerInterceptor)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewInterceptor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewInterceptor creates a new instance of Interceptor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewInterceptor(t mockConstructorTestingTNewInterceptor) *Interceptor {
+	mock := &Interceptor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
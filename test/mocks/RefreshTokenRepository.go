@@ -0,0 +1,97 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/token"
+)
+
+// RefreshTokenRepository is an autogenerated mock type for the RefreshTokenRepository type
+type RefreshTokenRepository struct {
+	mock.Mock
+}
+
+// AddRefreshToken provides a mock function with given fields: ctx, _a1
+func (_m *RefreshTokenRepository) AddRefreshToken(ctx context.Context, _a1 *token.RefreshToken) error {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *token.RefreshToken) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRefreshTokenById provides a mock function with given fields: ctx, tokenId
+func (_m *RefreshTokenRepository) GetRefreshTokenById(ctx context.Context, tokenId string) (*token.RefreshToken, error) {
+	ret := _m.Called(ctx, tokenId)
+
+	var r0 *token.RefreshToken
+	if rf, ok := ret.Get(0).(func(context.Context, string) *token.RefreshToken); ok {
+		r0 = rf(ctx, tokenId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*token.RefreshToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRefreshToken provides a mock function with given fields: ctx, tokenId, revokedAt
+func (_m *RefreshTokenRepository) RevokeRefreshToken(ctx context.Context, tokenId string, revokedAt time.Time) error {
+	ret := _m.Called(ctx, tokenId, revokedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, tokenId, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshTokenFamily provides a mock function with given fields: ctx, familyId, revokedAt
+func (_m *RefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyId string, revokedAt time.Time) error {
+	ret := _m.Called(ctx, familyId, revokedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, familyId, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewRefreshTokenRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRefreshTokenRepository(t mockConstructorTestingTNewRefreshTokenRepository) *RefreshTokenRepository {
+	mock := &RefreshTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
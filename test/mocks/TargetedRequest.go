@@ -0,0 +1,39 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+// TargetedRequest is an autogenerated mock type for the TargetedRequest type
+type TargetedRequest struct {
+	mock.Mock
+}
+
+// GetId provides a mock function with given fields:
+func (_m *TargetedRequest) GetId() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTargetedRequest interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTargetedRequest creates a new instance of TargetedRequest. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTargetedRequest(t mockConstructorTestingTNewTargetedRequest) *TargetedRequest {
+	mock := &TargetedRequest{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
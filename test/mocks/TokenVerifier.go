@@ -0,0 +1,51 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	"github.com/elizabeth-dev/ACME_Test/internal/pkg/auth"
+	"github.com/stretchr/testify/mock"
+)
+
+// TokenVerifier is an autogenerated mock type for the TokenVerifier type
+type TokenVerifier struct {
+	mock.Mock
+}
+
+// Verify provides a mock function with given fields: token
+func (_m *TokenVerifier) Verify(token string) (*auth.Claims, error) {
+	ret := _m.Called(token)
+
+	var r0 *auth.Claims
+	if rf, ok := ret.Get(0).(func(string) *auth.Claims); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.Claims)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTokenVerifier interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTokenVerifier creates a new instance of TokenVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTokenVerifier(t mockConstructorTestingTNewTokenVerifier) *TokenVerifier {
+	mock := &TokenVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
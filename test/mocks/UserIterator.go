@@ -0,0 +1,88 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/domain/user"
+	"github.com/stretchr/testify/mock"
+)
+
+// UserIterator is an autogenerated mock type for the UserIterator type
+type UserIterator struct {
+	mock.Mock
+}
+
+// Close provides a mock function with given fields: ctx
+func (_m *UserIterator) Close(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Err provides a mock function with given fields:
+func (_m *UserIterator) Err() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Next provides a mock function with given fields: ctx
+func (_m *UserIterator) Next(ctx context.Context) bool {
+	ret := _m.Called(ctx)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// User provides a mock function with given fields:
+func (_m *UserIterator) User() *user.User {
+	ret := _m.Called()
+
+	var r0 *user.User
+	if rf, ok := ret.Get(0).(func() *user.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*user.User)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewUserIterator interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewUserIterator creates a new instance of UserIterator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewUserIterator(t mockConstructorTestingTNewUserIterator) *UserIterator {
+	mock := &UserIterator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -30,13 +30,80 @@ func (_m *UserRepository) AddUser(ctx context.Context, _a1 *user.User) error {
 	return r0
 }
 
-// GetUserById provides a mock function with given fields: ctx, userId
-func (_m *UserRepository) GetUserById(ctx context.Context, userId string) (*user.User, error) {
-	ret := _m.Called(ctx, userId)
+// AddUsers provides a mock function with given fields: ctx, users
+func (_m *UserRepository) AddUsers(ctx context.Context, users []*user.User) ([]error, error) {
+	ret := _m.Called(ctx, users)
+
+	var r0 []error
+	if rf, ok := ret.Get(0).(func(context.Context, []*user.User) []error); ok {
+		r0 = rf(ctx, users)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]error)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []*user.User) error); ok {
+		r1 = rf(ctx, users)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountUsers provides a mock function with given fields: ctx, filter
+func (_m *UserRepository) CountUsers(ctx context.Context, filter query_utils.FilterExpression) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, query_utils.FilterExpression) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, query_utils.FilterExpression) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserById provides a mock function with given fields: ctx, userId, projection
+func (_m *UserRepository) GetUserById(ctx context.Context, userId string, projection []string) (*user.User, error) {
+	ret := _m.Called(ctx, userId, projection)
+
+	var r0 *user.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *user.User); ok {
+		r0 = rf(ctx, userId, projection)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*user.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, userId, projection)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByNicknameOrEmail provides a mock function with given fields: ctx, nicknameOrEmail
+func (_m *UserRepository) GetUserByNicknameOrEmail(ctx context.Context, nicknameOrEmail string) (*user.User, error) {
+	ret := _m.Called(ctx, nicknameOrEmail)
 
 	var r0 *user.User
 	if rf, ok := ret.Get(0).(func(context.Context, string) *user.User); ok {
-		r0 = rf(ctx, userId)
+		r0 = rf(ctx, nicknameOrEmail)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*user.User)
@@ -45,7 +112,30 @@ func (_m *UserRepository) GetUserById(ctx context.Context, userId string) (*user
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userId)
+		r1 = rf(ctx, nicknameOrEmail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields: ctx, filter, sort, pagination, projection
+func (_m *UserRepository) GetUsers(ctx context.Context, filter query_utils.FilterExpression, sort []query_utils.Sort, pagination query_utils.Pagination, projection []string) (user.UserIterator, error) {
+	ret := _m.Called(ctx, filter, sort, pagination, projection)
+
+	var r0 user.UserIterator
+	if rf, ok := ret.Get(0).(func(context.Context, query_utils.FilterExpression, []query_utils.Sort, query_utils.Pagination, []string) user.UserIterator); ok {
+		r0 = rf(ctx, filter, sort, pagination, projection)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(user.UserIterator)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, query_utils.FilterExpression, []query_utils.Sort, query_utils.Pagination, []string) error); ok {
+		r1 = rf(ctx, filter, sort, pagination, projection)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -53,13 +143,13 @@ func (_m *UserRepository) GetUserById(ctx context.Context, userId string) (*user
 	return r0, r1
 }
 
-// GetUsers provides a mock function with given fields: ctx, filters, sort, pagination
-func (_m *UserRepository) GetUsers(ctx context.Context, filters []query_utils.Filter, sort []query_utils.Sort, pagination query_utils.Pagination) ([]*user.User, error) {
-	ret := _m.Called(ctx, filters, sort, pagination)
+// GetUsersByIds provides a mock function with given fields: ctx, userIds, projection
+func (_m *UserRepository) GetUsersByIds(ctx context.Context, userIds []string, projection []string) ([]*user.User, error) {
+	ret := _m.Called(ctx, userIds, projection)
 
 	var r0 []*user.User
-	if rf, ok := ret.Get(0).(func(context.Context, []query_utils.Filter, []query_utils.Sort, query_utils.Pagination) []*user.User); ok {
-		r0 = rf(ctx, filters, sort, pagination)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []string) []*user.User); ok {
+		r0 = rf(ctx, userIds, projection)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*user.User)
@@ -67,8 +157,8 @@ func (_m *UserRepository) GetUsers(ctx context.Context, filters []query_utils.Fi
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, []query_utils.Filter, []query_utils.Sort, query_utils.Pagination) error); ok {
-		r1 = rf(ctx, filters, sort, pagination)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, []string) error); ok {
+		r1 = rf(ctx, userIds, projection)
 	} else {
 		r1 = ret.Error(1)
 	}
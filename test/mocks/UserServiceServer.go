@@ -16,6 +16,75 @@ type UserServiceServer struct {
 	mock.Mock
 }
 
+// Authenticate provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) Authenticate(_a0 context.Context, _a1 *v1.AuthenticateRequest) (*v1.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.User
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.AuthenticateRequest) *v1.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.AuthenticateRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BatchGetUsers provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) BatchGetUsers(_a0 context.Context, _a1 *v1.BatchGetUsersRequest) (*v1.BatchGetUsersResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.BatchGetUsersResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.BatchGetUsersRequest) *v1.BatchGetUsersResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.BatchGetUsersResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.BatchGetUsersRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountUsers provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) CountUsers(_a0 context.Context, _a1 *v1.CountUsersRequest) (*v1.CountUsersResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.CountUsersResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.CountUsersRequest) *v1.CountUsersResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.CountUsersResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.CountUsersRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateUser provides a mock function with given fields: _a0, _a1
 func (_m *UserServiceServer) CreateUser(_a0 context.Context, _a1 *v1.CreateUserRequest) (*v1.User, error) {
 	ret := _m.Called(_a0, _a1)
@@ -39,6 +108,52 @@ func (_m *UserServiceServer) CreateUser(_a0 context.Context, _a1 *v1.CreateUserR
 	return r0, r1
 }
 
+// GetSigningKeys provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) GetSigningKeys(_a0 context.Context, _a1 *emptypb.Empty) (*v1.SigningKeys, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.SigningKeys
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *v1.SigningKeys); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.SigningKeys)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) GetUser(_a0 context.Context, _a1 *v1.GetUserRequest) (*v1.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.User
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.GetUserRequest) *v1.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.GetUserRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUsers provides a mock function with given fields: _a0, _a1
 func (_m *UserServiceServer) GetUsers(_a0 *v1.GetUsersRequest, _a1 v1.UserService_GetUsersServer) error {
 	ret := _m.Called(_a0, _a1)
@@ -53,6 +168,89 @@ func (_m *UserServiceServer) GetUsers(_a0 *v1.GetUsersRequest, _a1 v1.UserServic
 	return r0
 }
 
+// GrantRole provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) GrantRole(_a0 context.Context, _a1 *v1.GrantRoleRequest) (*v1.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.User
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.GrantRoleRequest) *v1.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.GrantRoleRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportUsers provides a mock function with given fields: _a0
+func (_m *UserServiceServer) ImportUsers(_a0 v1.UserService_ImportUsersServer) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(v1.UserService_ImportUsersServer) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Login provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) Login(_a0 context.Context, _a1 *v1.LoginRequest) (*v1.Tokens, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.Tokens
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.LoginRequest) *v1.Tokens); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Tokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.LoginRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) RefreshToken(_a0 context.Context, _a1 *v1.RefreshTokenRequest) (*v1.Tokens, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.Tokens
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RefreshTokenRequest) *v1.Tokens); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Tokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RefreshTokenRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveUser provides a mock function with given fields: _a0, _a1
 func (_m *UserServiceServer) RemoveUser(_a0 context.Context, _a1 *v1.RemoveUserRequest) (*emptypb.Empty, error) {
 	ret := _m.Called(_a0, _a1)
@@ -76,6 +274,52 @@ func (_m *UserServiceServer) RemoveUser(_a0 context.Context, _a1 *v1.RemoveUserR
 	return r0, r1
 }
 
+// RevokeRole provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) RevokeRole(_a0 context.Context, _a1 *v1.RevokeRoleRequest) (*v1.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *v1.User
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RevokeRoleRequest) *v1.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RevokeRoleRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeToken provides a mock function with given fields: _a0, _a1
+func (_m *UserServiceServer) RevokeToken(_a0 context.Context, _a1 *v1.RevokeTokenRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RevokeTokenRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RevokeTokenRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateUser provides a mock function with given fields: _a0, _a1
 func (_m *UserServiceServer) UpdateUser(_a0 context.Context, _a1 *v1.UpdateUserRequest) (*v1.User, error) {
 	ret := _m.Called(_a0, _a1)
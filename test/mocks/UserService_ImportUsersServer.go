@@ -0,0 +1,146 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+
+	v1 "github.com/elizabeth-dev/ACME_Test/pkg/api/v1"
+)
+
+// UserService_ImportUsersServer is an autogenerated mock type for the UserService_ImportUsersServer type
+type UserService_ImportUsersServer struct {
+	mock.Mock
+}
+
+// Context provides a mock function with given fields:
+func (_m *UserService_ImportUsersServer) Context() context.Context {
+	ret := _m.Called()
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func() context.Context); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	return r0
+}
+
+// Recv provides a mock function with given fields:
+func (_m *UserService_ImportUsersServer) Recv() (*v1.ImportUserRequest, error) {
+	ret := _m.Called()
+
+	var r0 *v1.ImportUserRequest
+	if rf, ok := ret.Get(0).(func() *v1.ImportUserRequest); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ImportUserRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecvMsg provides a mock function with given fields: m
+func (_m *UserService_ImportUsersServer) RecvMsg(m interface{}) error {
+	ret := _m.Called(m)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(interface{}) error); ok {
+		r0 = rf(m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Send provides a mock function with given fields: _a0
+func (_m *UserService_ImportUsersServer) Send(_a0 *v1.ImportUserResult) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.ImportUserResult) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendHeader provides a mock function with given fields: _a0
+func (_m *UserService_ImportUsersServer) SendHeader(_a0 metadata.MD) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(metadata.MD) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendMsg provides a mock function with given fields: m
+func (_m *UserService_ImportUsersServer) SendMsg(m interface{}) error {
+	ret := _m.Called(m)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(interface{}) error); ok {
+		r0 = rf(m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetHeader provides a mock function with given fields: _a0
+func (_m *UserService_ImportUsersServer) SetHeader(_a0 metadata.MD) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(metadata.MD) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTrailer provides a mock function with given fields: _a0
+func (_m *UserService_ImportUsersServer) SetTrailer(_a0 metadata.MD) {
+	_m.Called(_a0)
+}
+
+type mockConstructorTestingTNewUserService_ImportUsersServer interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewUserService_ImportUsersServer creates a new instance of UserService_ImportUsersServer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewUserService_ImportUsersServer(t mockConstructorTestingTNewUserService_ImportUsersServer) *UserService_ImportUsersServer {
+	mock := &UserService_ImportUsersServer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,53 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/stretchr/testify/mock"
+)
+
+// IAuthenticateHandler is an autogenerated mock type for the IAuthenticateHandler type
+type IAuthenticateHandler struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, _a1
+func (_m *IAuthenticateHandler) Handle(ctx context.Context, _a1 query.Authenticate) (*query.User, error) {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 *query.User
+	if rf, ok := ret.Get(0).(func(context.Context, query.Authenticate) *query.User); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*query.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, query.Authenticate) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIAuthenticateHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIAuthenticateHandler creates a new instance of IAuthenticateHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIAuthenticateHandler(t mockConstructorTestingTNewIAuthenticateHandler) *IAuthenticateHandler {
+	mock := &IAuthenticateHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,51 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/stretchr/testify/mock"
+)
+
+// ICountUsersHandler is an autogenerated mock type for the ICountUsersHandler type
+type ICountUsersHandler struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, _a1
+func (_m *ICountUsersHandler) Handle(ctx context.Context, _a1 query.CountUsers) (int64, error) {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, query.CountUsers) int64); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, query.CountUsers) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewICountUsersHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewICountUsersHandler creates a new instance of ICountUsersHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewICountUsersHandler(t mockConstructorTestingTNewICountUsersHandler) *ICountUsersHandler {
+	mock := &ICountUsersHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
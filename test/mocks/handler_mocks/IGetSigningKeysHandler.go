@@ -0,0 +1,53 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/stretchr/testify/mock"
+)
+
+// IGetSigningKeysHandler is an autogenerated mock type for the IGetSigningKeysHandler type
+type IGetSigningKeysHandler struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx
+func (_m *IGetSigningKeysHandler) Handle(ctx context.Context) ([]query.SigningKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []query.SigningKey
+	if rf, ok := ret.Get(0).(func(context.Context) []query.SigningKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]query.SigningKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIGetSigningKeysHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIGetSigningKeysHandler creates a new instance of IGetSigningKeysHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIGetSigningKeysHandler(t mockConstructorTestingTNewIGetSigningKeysHandler) *IGetSigningKeysHandler {
+	mock := &IGetSigningKeysHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,13 +14,13 @@ type IGetUserByIdHandler struct {
 	mock.Mock
 }
 
-// Handle provides a mock function with given fields: ctx, userId
-func (_m *IGetUserByIdHandler) Handle(ctx context.Context, userId string) (*query.User, error) {
-	ret := _m.Called(ctx, userId)
+// Handle provides a mock function with given fields: ctx, _a1
+func (_m *IGetUserByIdHandler) Handle(ctx context.Context, _a1 query.GetUserById) (*query.User, error) {
+	ret := _m.Called(ctx, _a1)
 
 	var r0 *query.User
-	if rf, ok := ret.Get(0).(func(context.Context, string) *query.User); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, query.GetUserById) *query.User); ok {
+		r0 = rf(ctx, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*query.User)
@@ -28,8 +28,8 @@ func (_m *IGetUserByIdHandler) Handle(ctx context.Context, userId string) (*quer
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userId)
+	if rf, ok := ret.Get(1).(func(context.Context, query.GetUserById) error); ok {
+		r1 = rf(ctx, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
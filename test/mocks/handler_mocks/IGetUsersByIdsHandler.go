@@ -0,0 +1,53 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/stretchr/testify/mock"
+)
+
+// IGetUsersByIdsHandler is an autogenerated mock type for the IGetUsersByIdsHandler type
+type IGetUsersByIdsHandler struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, _a1
+func (_m *IGetUsersByIdsHandler) Handle(ctx context.Context, _a1 query.GetUsersByIds) ([]*query.UserResult, error) {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 []*query.UserResult
+	if rf, ok := ret.Get(0).(func(context.Context, query.GetUsersByIds) []*query.UserResult); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*query.UserResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, query.GetUsersByIds) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIGetUsersByIdsHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIGetUsersByIdsHandler creates a new instance of IGetUsersByIdsHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIGetUsersByIdsHandler(t mockConstructorTestingTNewIGetUsersByIdsHandler) *IGetUsersByIdsHandler {
+	mock := &IGetUsersByIdsHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
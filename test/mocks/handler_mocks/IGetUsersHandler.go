@@ -15,15 +15,15 @@ type IGetUsersHandler struct {
 }
 
 // Handle provides a mock function with given fields: ctx, _a1
-func (_m *IGetUsersHandler) Handle(ctx context.Context, _a1 query.GetUsers) ([]*query.User, error) {
+func (_m *IGetUsersHandler) Handle(ctx context.Context, _a1 query.GetUsers) (query.UserStream, error) {
 	ret := _m.Called(ctx, _a1)
 
-	var r0 []*query.User
-	if rf, ok := ret.Get(0).(func(context.Context, query.GetUsers) []*query.User); ok {
+	var r0 query.UserStream
+	if rf, ok := ret.Get(0).(func(context.Context, query.GetUsers) query.UserStream); ok {
 		r0 = rf(ctx, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*query.User)
+			r0 = ret.Get(0).(query.UserStream)
 		}
 	}
 
@@ -0,0 +1,54 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// IImportUsersHandler is an autogenerated mock type for the IImportUsersHandler type
+type IImportUsersHandler struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, cmd
+func (_m *IImportUsersHandler) Handle(ctx context.Context, cmd command.ImportUsers) ([]command.ImportUserResult, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 []command.ImportUserResult
+	if rf, ok := ret.Get(0).(func(context.Context, command.ImportUsers) []command.ImportUserResult); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]command.ImportUserResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, command.ImportUsers) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIImportUsersHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIImportUsersHandler creates a new instance of IImportUsersHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIImportUsersHandler(t mockConstructorTestingTNewIImportUsersHandler) *IImportUsersHandler {
+	mock := &IImportUsersHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,54 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ILoginHandler is an autogenerated mock type for the ILoginHandler type
+type ILoginHandler struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, cmd
+func (_m *ILoginHandler) Handle(ctx context.Context, cmd command.Login) (*command.Tokens, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *command.Tokens
+	if rf, ok := ret.Get(0).(func(context.Context, command.Login) *command.Tokens); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*command.Tokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, command.Login) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewILoginHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewILoginHandler creates a new instance of ILoginHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewILoginHandler(t mockConstructorTestingTNewILoginHandler) *ILoginHandler {
+	mock := &ILoginHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,54 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// IRefreshTokensHandler is an autogenerated mock type for the IRefreshTokensHandler type
+type IRefreshTokensHandler struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, cmd
+func (_m *IRefreshTokensHandler) Handle(ctx context.Context, cmd command.RefreshTokens) (*command.Tokens, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *command.Tokens
+	if rf, ok := ret.Get(0).(func(context.Context, command.RefreshTokens) *command.Tokens); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*command.Tokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, command.RefreshTokens) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIRefreshTokensHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIRefreshTokensHandler creates a new instance of IRefreshTokensHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIRefreshTokensHandler(t mockConstructorTestingTNewIRefreshTokensHandler) *IRefreshTokensHandler {
+	mock := &IRefreshTokensHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
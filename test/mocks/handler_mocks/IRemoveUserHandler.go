@@ -5,6 +5,8 @@ package handler_mocks
 import (
 	"context"
 
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -13,13 +15,13 @@ type IRemoveUserHandler struct {
 	mock.Mock
 }
 
-// Handle provides a mock function with given fields: ctx, userId
-func (_m *IRemoveUserHandler) Handle(ctx context.Context, userId string) error {
-	ret := _m.Called(ctx, userId)
+// Handle provides a mock function with given fields: ctx, cmd
+func (_m *IRemoveUserHandler) Handle(ctx context.Context, cmd command.RemoveUser) error {
+	ret := _m.Called(ctx, cmd)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, command.RemoveUser) error); ok {
+		r0 = rf(ctx, cmd)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,45 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/command"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// IRevokeRoleHandler is an autogenerated mock type for the IRevokeRoleHandler type
+type IRevokeRoleHandler struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, cmd
+func (_m *IRevokeRoleHandler) Handle(ctx context.Context, cmd command.RevokeRole) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, command.RevokeRole) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewIRevokeRoleHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIRevokeRoleHandler creates a new instance of IRevokeRoleHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIRevokeRoleHandler(t mockConstructorTestingTNewIRevokeRoleHandler) *IRevokeRoleHandler {
+	mock := &IRevokeRoleHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,102 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package handler_mocks
+
+import (
+	"context"
+
+	"github.com/elizabeth-dev/ACME_Test/internal/app/users/app/query"
+	"github.com/stretchr/testify/mock"
+)
+
+// UserStream is an autogenerated mock type for the UserStream type
+type UserStream struct {
+	mock.Mock
+}
+
+// Close provides a mock function with given fields: ctx
+func (_m *UserStream) Close(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Err provides a mock function with given fields:
+func (_m *UserStream) Err() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Next provides a mock function with given fields: ctx
+func (_m *UserStream) Next(ctx context.Context) bool {
+	ret := _m.Called(ctx)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Page provides a mock function with given fields:
+func (_m *UserStream) Page() query.UserPage {
+	ret := _m.Called()
+
+	var r0 query.UserPage
+	if rf, ok := ret.Get(0).(func() query.UserPage); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(query.UserPage)
+	}
+
+	return r0
+}
+
+// User provides a mock function with given fields:
+func (_m *UserStream) User() *query.User {
+	ret := _m.Called()
+
+	var r0 *query.User
+	if rf, ok := ret.Get(0).(func() *query.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*query.User)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewUserStream interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewUserStream creates a new instance of UserStream. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewUserStream(t mockConstructorTestingTNewUserStream) *UserStream {
+	mock := &UserStream{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
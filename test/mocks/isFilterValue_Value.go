@@ -0,0 +1,30 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+// isFilterValue_Value is an autogenerated mock type for the isFilterValue_Value type
+type isFilterValue_Value struct {
+	mock.Mock
+}
+
+// isFilterValue_Value provides a mock function with given fields:
+func (_m *isFilterValue_Value) isFilterValue_Value() {
+	_m.Called()
+}
+
+type mockConstructorTestingTnewIsFilterValue_Value interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newIsFilterValue_Value creates a new instance of isFilterValue_Value. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newIsFilterValue_Value(t mockConstructorTestingTnewIsFilterValue_Value) *isFilterValue_Value {
+	mock := &isFilterValue_Value{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
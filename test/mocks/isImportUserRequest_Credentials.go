@@ -0,0 +1,30 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+// isImportUserRequest_Credentials is an autogenerated mock type for the isImportUserRequest_Credentials type
+type isImportUserRequest_Credentials struct {
+	mock.Mock
+}
+
+// isImportUserRequest_Credentials provides a mock function with given fields:
+func (_m *isImportUserRequest_Credentials) isImportUserRequest_Credentials() {
+	_m.Called()
+}
+
+type mockConstructorTestingTnewIsImportUserRequest_Credentials interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newIsImportUserRequest_Credentials creates a new instance of isImportUserRequest_Credentials. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newIsImportUserRequest_Credentials(t mockConstructorTestingTnewIsImportUserRequest_Credentials) *isImportUserRequest_Credentials {
+	mock := &isImportUserRequest_Credentials{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}